@@ -14,6 +14,9 @@ type Config struct {
 	AuthServerToken      string
 	RentalServerCode     string
 	RentalServerPasscode string
+	// AuthProvider 是用于登录的验证服务器。
+	// 为空时基于 AuthServerAddress 创建
+	AuthProvider auth.Provider
 }
 
 // ------------------------- Client -------------------------
@@ -21,7 +24,7 @@ type Config struct {
 // Client ..
 type Client struct {
 	connection            *minecraft.Conn
-	authClient            *auth.Client
+	authClient            auth.Provider
 	getCheckNumEverPassed bool
 	cachedPacket          chan packet.Packet
 }
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
+
+	"github.com/google/uuid"
+)
+
+// LoginLocalServer 通过 authenticator 登录到本地服务器。
+// 本地服务器不存在 MCPC 检查挑战，因此它只会等待服务器
+// 响应第一条命令，并缓存在此之前收到的全部数据包
+func LoginLocalServer(authenticator minecraft.Authenticator) (client *Client, err error) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancelFunc()
+
+	conn, err := openConnection(ctx, authenticator)
+	if err != nil {
+		return nil, fmt.Errorf("LoginLocalServer: %v", err)
+	}
+
+	err = conn.WritePacket(&packet.CommandRequest{
+		CommandLine: "list",
+		CommandOrigin: protocol.CommandOrigin{
+			Origin:    protocol.CommandOriginAutomationPlayer,
+			UUID:      uuid.New(),
+			RequestID: "96045347-a6a3-4114-94c0-1bc4cc561694",
+		},
+		Version: 39,
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("LoginLocalServer: %v", err)
+	}
+
+	cachedPkt := make([]packet.Packet, 0)
+	for {
+		pk, err := conn.ReadPacket()
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("LoginLocalServer: %v", err)
+		}
+		if _, ok := pk.(*packet.CommandOutput); ok {
+			break
+		}
+		cachedPkt = append(cachedPkt, pk)
+	}
+
+	client = &Client{
+		connection:   conn,
+		cachedPacket: make(chan packet.Packet, len(cachedPkt)+1),
+	}
+	for _, pk := range cachedPkt {
+		client.cachedPacket <- pk
+	}
+	close(client.cachedPacket)
+
+	return client, nil
+}
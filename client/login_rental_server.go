@@ -10,11 +10,14 @@ import (
 
 // LoginRentalServer ..
 func LoginRentalServer(cfg Config) (client *Client, err error) {
-	authClient, err := auth.CreateClient(&auth.ClientOptions{
-		AuthServer: cfg.AuthServerAddress,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("LoginRentalServer: %v", err)
+	authClient := cfg.AuthProvider
+	if authClient == nil {
+		authClient, err = auth.CreateClient(&auth.ClientOptions{
+			AuthServer: cfg.AuthServerAddress,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("LoginRentalServer: %v", err)
+		}
 	}
 
 	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*30)
@@ -14,12 +14,12 @@ type AccessWrapper struct {
 	ServerCode     string
 	ServerPassword string
 	Token          string
-	Client         *Client
+	Client         Provider
 	Username       string
 	Password       string
 }
 
-func NewAccessWrapper(Client *Client, ServerCode, ServerPassword, Token, username, password string) *AccessWrapper {
+func NewAccessWrapper(Client Provider, ServerCode, ServerPassword, Token, username, password string) *AccessWrapper {
 	return &AccessWrapper{
 		Client:         Client,
 		ServerCode:     ServerCode,
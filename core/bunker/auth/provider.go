@@ -0,0 +1,40 @@
+package auth
+
+import "context"
+
+// Provider 是验证服务器的抽象。
+// Client 是它基于 HTTP 的默认实现，而本地的模拟
+// 实现可以用于在没有网络的情况下测试登录流程和
+// MCPC 检查挑战的处理
+//
+// 实现者应遵守以下约定。
+//
+// Auth 使用 serverCode 和 serverPassword 请求登录租赁服。
+// key 是客户端公钥的 DER 编码的 Base64 形式；fbtoken 与
+// username 和 password 二者只需提供其一。成功时，返回的
+// AuthResponse 的 SuccessStates 为真，并且 RentalServerIP
+// 和 ChainInfo 非空；其中，ChainInfo 必须是包含以 key 为
+// 最终身份公钥的 JWT 链的 JSON 字符串。失败时返回错误，
+// 此时 AuthResponse 为零值。
+//
+// TransferData 回答租赁服发出的 GetStartType 挑战，
+// content 是挑战的内容，返回值将原样作为 SetStartType
+// 的内容发回租赁服。
+//
+// TransferCheckNum 回答租赁服发出的 GetMCPCheckNum 挑战。
+// data 是 JSON 数组 [FirstArg, SecondArg.Arg, EntityUniqueID]，
+// 返回值则是作为 SetMCPCheckNum 发回租赁服的 JSON 数组。
+//
+// 所有方法都可能被并发调用
+type Provider interface {
+	Auth(
+		ctx context.Context,
+		serverCode string, serverPassword string,
+		key string,
+		fbtoken string, username string, password string,
+	) (AuthResponse, error)
+	TransferData(content string) (string, error)
+	TransferCheckNum(data string) (string, error)
+}
+
+var _ Provider = (*Client)(nil)
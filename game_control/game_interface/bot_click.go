@@ -13,13 +13,17 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-const (
+// 以下设置可以在与租赁服建立连接前修改
+var (
 	// 描述 Pick Block 请求的最长截止时间。
 	// 这与 packet.BlockPickRequest 相关。
 	// 当超过此时间后，将视为该请求未被接受
 	DefaultTimeoutBlockPick = time.Second
 	// 描述 Pick Block 失败后要重试的最大次数
 	MaxRetryBlockPick = 3
+)
+
+const (
 	// 用作放置方块时的依赖性方块。
 	//
 	// 部分方块需要客户端以点击方块的形式来放置，
@@ -38,6 +42,8 @@ type UseItemOnBlocks struct {
 	BlockPos     protocol.BlockPos        // 指代被操作方块的位置
 	BlockName    string                   // 指代被操作方块的名称
 	BlockStates  map[string]any           // 指代被操作方块的方块状态
+	BotYaw       float32                  // 指代机器人操作该方块时的偏航角，它会影响床等方块被放置时的朝向
+	ClickPos     mgl32.Vec3               // 指代点击位置相对于被操作方块的坐标，它会影响雕纹书架等方块的交互槽位
 }
 
 // BotClick 是基于 ResourcesWrapper
@@ -100,8 +106,21 @@ func (b *BotClick) clickBlock(
 		return fmt.Errorf("clickBlock: Should never happened")
 	}
 
-	// Step 3: 发送点击操作
-	err := b.r.WritePacket(&packet.InventoryTransaction{
+	// Step 3: 同步机器人的朝向。
+	// 上一次点击可能使用了不同的偏航角，
+	// 因此即便偏航角为 0 也需要同步
+	err := b.r.WritePacket(&packet.PlayerAuthInput{
+		InputData: packet.InputFlagStartFlying,
+		Position:  request.BotPos,
+		Yaw:       request.BotYaw,
+		HeadYaw:   request.BotYaw,
+	})
+	if err != nil {
+		return fmt.Errorf("clickBlock: %v", err)
+	}
+
+	// Step 4: 发送点击操作
+	err = b.r.WritePacket(&packet.InventoryTransaction{
 		LegacyRequestID:    0,
 		LegacySetItemSlots: []protocol.LegacySetItemSlot(nil),
 		Actions:            []protocol.InventoryAction{},
@@ -115,6 +134,7 @@ func (b *BotClick) clickBlock(
 			HotBarSlot:         int32(request.HotbarSlotID),
 			HeldItem:           *item,
 			Position:           position,
+			ClickedPosition:    request.ClickPos,
 			BlockRuntimeID:     blockRuntimeID,
 		},
 	})
@@ -122,7 +142,7 @@ func (b *BotClick) clickBlock(
 		return fmt.Errorf("clickBlock: %v", err)
 	}
 
-	// Step 4: 额外操作 (自 v1.20.50 以来的必须更改)
+	// Step 5: 额外操作 (自 v1.20.50 以来的必须更改)
 	//
 	// !!! NOTE - MUST SEND AUTH INPUT TWICE !!!
 	// await changes and send auth
@@ -135,6 +155,8 @@ func (b *BotClick) clickBlock(
 		err = b.r.WritePacket(&packet.PlayerAuthInput{
 			InputData: packet.InputFlagStartFlying,
 			Position:  request.BotPos,
+			Yaw:       request.BotYaw,
+			HeadYaw:   request.BotYaw,
 		})
 		if err != nil {
 			return fmt.Errorf("clickBlock: %v", err)
@@ -186,6 +208,23 @@ func (b *BotClick) ClickBlock(request UseItemOnBlocks) error {
 	return nil
 }
 
+/*
+让客户端点击 request 所指代的方块的 blockFace 面，
+并且点击的具体位置由 request 的 ClickPos 指定。
+
+这通常被用于方块的不同位置具有不同交互效果的情况，
+例如将书放入雕纹书架的特定槽位。
+
+此函数不会自动切换物品栏，但会等待租赁服响应更改
+*/
+func (b *BotClick) ClickBlockFace(request UseItemOnBlocks, blockFace int32) error {
+	err := b.clickBlock(request, blockFace, mgl32.Vec3{})
+	if err != nil {
+		return fmt.Errorf("ClickBlockFace: %v", err)
+	}
+	return nil
+}
+
 // 使用快捷栏 hotbarSlotID 进行一次空点击操作。
 // realPosition 指示机器人在操作时的实际位置。
 // 此函数不会自动切换物品栏，但会等待租赁服响应更改
@@ -13,9 +13,11 @@ import (
 
 // ------------------------- Define -------------------------
 
+// DefaultTimeoutCommandRequest 是默认的指令超时设置。
+// 它可以在与租赁服建立连接前修改
+var DefaultTimeoutCommandRequest = time.Second * 5
+
 const (
-	// DefaultTimeoutCommandRequest 是默认的指令超时设置
-	DefaultTimeoutCommandRequest = time.Second * 5
 	// DefaultAwaitChangesCount 是 Await Chanegs 需要等待的游戏刻数
 	DefaultAwaitChangesCount = 2
 )
@@ -126,6 +128,7 @@ func (c *Commands) sendCommandWithResp(command string, origin uint32, timeout ti
 	api := c.api
 	requestID := uuid.New()
 	channel := make(chan struct{})
+	startTime := time.Now()
 
 	api.Resources.Commands().SetCommandRequestCallback(
 		requestID,
@@ -154,14 +157,22 @@ func (c *Commands) sendCommandWithResp(command string, origin uint32, timeout ti
 		select {
 		case <-channel:
 		case <-timer.C:
+			commandTimeouts.WithLabelValues(commandOriginLabel(origin)).Inc()
 			return nil, true, fmt.Errorf(
 				"sendCommandWithResp: Command request %#v (origin = %d) is time out (timeout = %v seconds)",
 				command, origin, float64(timeout)/float64(time.Second),
 			)
+		case <-api.Closed():
+			return nil, false, fmt.Errorf("sendCommandWithResp: Connection closed; err = %v", api.CloseError())
 		}
 	}
 
-	<-channel
+	select {
+	case <-channel:
+	case <-api.Closed():
+		return nil, false, fmt.Errorf("sendCommandWithResp: Connection closed; err = %v", api.CloseError())
+	}
+	commandDuration.WithLabelValues(commandOriginLabel(origin)).ObserveSince(startTime)
 	return resp, false, nil
 }
 
@@ -12,7 +12,8 @@ import (
 	"github.com/OmineDev/flowers-for-machines/mapping"
 )
 
-const (
+// 以下设置可以在与租赁服建立连接前修改
+var (
 	// 描述容器打开的最长截止时间。
 	// 当超过此时间后，将不再等待
 	DefaultTimeoutContainerOpen = time.Second / 20 * 3
@@ -206,7 +207,12 @@ func (c *ContainerOpenAndClose) CloseContainer() error {
 		return fmt.Errorf("CloseContainer: %v", err)
 	}
 
-	<-channel
+	select {
+	case <-channel:
+	case <-c.api.Closed():
+		c.occupy.Unlock()
+		return fmt.Errorf("CloseContainer: Connection closed; err = %v", c.api.CloseError())
+	}
 	c.occupy.Unlock()
 	return nil
 }
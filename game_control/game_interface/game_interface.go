@@ -40,7 +40,7 @@ func NewGameInterface(resources *resources_control.Resources) *GameInterface {
 
 	result.wrapper = NewResourcesWrapper(resources)
 	result.commands = NewCommands(result.wrapper)
-	result.structureBackup = NewStructureBackup(result.commands)
+	result.structureBackup = NewStructureBackup(result.wrapper, result.commands)
 	result.querytarget = NewQuerytarget(result.commands)
 	result.setblock = NewSetBlock(result.commands)
 	result.replaceitem = NewReplaceitem(result.commands)
@@ -73,3 +73,20 @@ type LoomingRuntime struct {
 	MoveDyeSrcContainerID    byte
 	MoveDyeSrcStackNetworkID int32
 }
+
+// SmithingRuntime 是将锻造台操作内联为物品堆栈操作请求的运行时结构体
+type SmithingRuntime struct {
+	RequestID int32
+
+	SmithingTemplateStackNetworkID int32
+	MoveTemplateSrcContainerID     byte
+	MoveTemplateSrcStackNetworkID  int32
+
+	SmithingBaseStackNetworkID int32
+	MoveBaseSrcContainerID     byte
+	MoveBaseSrcStackNetworkID  int32
+
+	SmithingAdditionStackNetworkID int32
+	MoveAdditionSrcContainerID     byte
+	MoveAdditionSrcStackNetworkID  int32
+}
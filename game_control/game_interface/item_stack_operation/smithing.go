@@ -0,0 +1,107 @@
+package item_stack_operation
+
+import (
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+)
+
+// Smithing 指示锻造台操作
+type Smithing struct {
+	RecipeNetworkID uint32
+
+	TemplatePath resources_control.SlotLocation
+	BasePath     resources_control.SlotLocation
+	AdditionPath resources_control.SlotLocation
+
+	ResultItem resources_control.ExpectedNewItem
+}
+
+func (Smithing) ID() uint8 {
+	return IDItemStackOperationHighLevelSmithing
+}
+
+func (Smithing) CanInline() bool {
+	return false
+}
+
+func (s Smithing) Make(runtiemData MakingRuntime) []protocol.StackRequestAction {
+	data := runtiemData.(SmithingRuntime)
+
+	requestID := data.RequestID
+	moveTemplate := protocol.PlaceStackRequestAction{}
+	moveBase := protocol.PlaceStackRequestAction{}
+	moveAddition := protocol.PlaceStackRequestAction{}
+	moveResult := protocol.TakeStackRequestAction{}
+
+	moveTemplate.Count = 1
+	moveTemplate.Source = protocol.StackRequestSlotInfo{
+		ContainerID:    data.MoveTemplateSrcContainerID,
+		Slot:           byte(s.TemplatePath.SlotID),
+		StackNetworkID: data.MoveTemplateSrcStackNetworkID,
+	}
+	moveTemplate.Destination = protocol.StackRequestSlotInfo{
+		ContainerID:    protocol.ContainerSmithingTableTemplate,
+		Slot:           53,
+		StackNetworkID: data.SmithingTemplateStackNetworkID,
+	}
+
+	moveBase.Count = 1
+	moveBase.Source = protocol.StackRequestSlotInfo{
+		ContainerID:    data.MoveBaseSrcContainerID,
+		Slot:           byte(s.BasePath.SlotID),
+		StackNetworkID: data.MoveBaseSrcStackNetworkID,
+	}
+	moveBase.Destination = protocol.StackRequestSlotInfo{
+		ContainerID:    protocol.ContainerSmithingTableInput,
+		Slot:           51,
+		StackNetworkID: data.SmithingBaseStackNetworkID,
+	}
+
+	moveAddition.Count = 1
+	moveAddition.Source = protocol.StackRequestSlotInfo{
+		ContainerID:    data.MoveAdditionSrcContainerID,
+		Slot:           byte(s.AdditionPath.SlotID),
+		StackNetworkID: data.MoveAdditionSrcStackNetworkID,
+	}
+	moveAddition.Destination = protocol.StackRequestSlotInfo{
+		ContainerID:    protocol.ContainerSmithingTableMaterial,
+		Slot:           52,
+		StackNetworkID: data.SmithingAdditionStackNetworkID,
+	}
+
+	moveResult.Count = 1
+	moveResult.Source = protocol.StackRequestSlotInfo{
+		ContainerID:    protocol.ContainerCreatedOutput,
+		Slot:           0x32,
+		StackNetworkID: requestID,
+	}
+	moveResult.Destination = protocol.StackRequestSlotInfo{
+		ContainerID:    data.MoveBaseSrcContainerID,
+		Slot:           byte(s.BasePath.SlotID),
+		StackNetworkID: data.RequestID,
+	}
+
+	consume := func(containerID byte, slot byte) *protocol.ConsumeStackRequestAction {
+		return &protocol.ConsumeStackRequestAction{
+			DestroyStackRequestAction: protocol.DestroyStackRequestAction{
+				Count: 1,
+				Source: protocol.StackRequestSlotInfo{
+					ContainerID:    containerID,
+					Slot:           slot,
+					StackNetworkID: requestID,
+				},
+			},
+		}
+	}
+
+	return []protocol.StackRequestAction{
+		&moveTemplate,
+		&moveBase,
+		&moveAddition,
+		&protocol.CraftRecipeStackRequestAction{RecipeNetworkID: s.RecipeNetworkID},
+		consume(protocol.ContainerSmithingTableTemplate, 53),
+		consume(protocol.ContainerSmithingTableInput, 51),
+		consume(protocol.ContainerSmithingTableMaterial, 52),
+		&moveResult,
+	}
+}
@@ -147,6 +147,8 @@ func (i *ItemStackTransaction) Commit() (
 				result, err = handler.handleLooming(op, requestID)
 			case item_stack_operation.Crafting:
 				result, err = handler.handleCrafting(op, requestID)
+			case item_stack_operation.Smithing:
+				result, err = handler.handleSmithing(op, requestID)
 			}
 			if err != nil {
 				return false, nil, nil, fmt.Errorf("Commit: %v", err)
@@ -190,7 +192,12 @@ func (i *ItemStackTransaction) Commit() (
 
 	// Step 4: Wait changes
 	for _, waiter := range waiters {
-		<-waiter
+		select {
+		case <-waiter:
+		case <-api.Closed():
+			_ = i.Discord()
+			return false, nil, nil, fmt.Errorf("Commit: Connection closed; err = %v", api.CloseError())
+		}
 	}
 
 	// Setp 5.1: Return unsuccess
@@ -583,3 +583,128 @@ func (i *itemStackOperationHandler) handleCrafting(
 	// Make runtime data
 	return op.Make(runtimeData), nil
 }
+
+// handleSmithing ..
+func (i *itemStackOperationHandler) handleSmithing(
+	op item_stack_operation.Smithing,
+	requestID resources_control.ItemStackRequestID,
+) (result []protocol.StackRequestAction, err error) {
+	// Prepare
+	runtimeData := item_stack_operation.SmithingRuntime{
+		RequestID: int32(requestID),
+	}
+
+	// Basic check
+	if op.TemplatePath == op.BasePath {
+		return nil, fmt.Errorf("handleSmithing: TemplatePath is equal to BasePath")
+	}
+	if op.TemplatePath == op.AdditionPath {
+		return nil, fmt.Errorf("handleSmithing: TemplatePath is equal to AdditionPath")
+	}
+	if op.BasePath == op.AdditionPath {
+		return nil, fmt.Errorf("handleSmithing: BasePath is equal to AdditionPath")
+	}
+
+	// Get opening container data
+	containerData, _, existed := i.api.ContainerData()
+	if !existed {
+		return nil, fmt.Errorf("handleSmithing: Smithing table is not opened")
+	}
+
+	// placeItem load the stack network ID of the item at path and the
+	// smithing table slot smithingSlotID, and then bind their container ID
+	placeItem := func(
+		path resources_control.SlotLocation,
+		smithingSlotID resources_control.SlotID,
+		smithingContainerID resources_control.ContainerID,
+	) (smithingRID int32, srcCID uint8, srcRID int32, err error) {
+		smithingSlot := resources_control.SlotLocation{
+			WindowID: resources_control.WindowID(containerData.WindowID),
+			SlotID:   smithingSlotID,
+		}
+
+		// Get item runtime ID
+		srcRID, err = i.virtualInventories.loadAndSetStackNetworkID(path, requestID)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		smithingRID, err = i.virtualInventories.loadAndSetStackNetworkID(smithingSlot, requestID)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+
+		// Get container ID
+		cid, found := slotLocationToContainerID(i.api, path)
+		if !found {
+			return 0, 0, 0, fmt.Errorf("Can not find the container ID of given item whose at %#v", path)
+		}
+
+		// Bind container ID
+		i.responseMapping.bind(path.WindowID, cid)
+		i.responseMapping.bind(resources_control.WindowID(containerData.WindowID), smithingContainerID)
+
+		return smithingRID, uint8(cid), srcRID, nil
+	}
+
+	// consumeItem reduce the count of the item at path by one
+	consumeItem := func(path resources_control.SlotLocation) error {
+		_, err := i.virtualInventories.loadAndAddItemCount(path, -1, false)
+		if err != nil {
+			return err
+		}
+		resultCount, err := i.virtualInventories.loadItemCount(path)
+		if err != nil {
+			return err
+		}
+		if resultCount == 0 {
+			return i.virtualInventories.setAir(path)
+		}
+		return nil
+	}
+
+	// Process template
+	{
+		smithingRID, cid, rid, err := placeItem(op.TemplatePath, 53, protocol.ContainerSmithingTableTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("handleSmithing: %v", err)
+		}
+		if err = consumeItem(op.TemplatePath); err != nil {
+			return nil, fmt.Errorf("handleSmithing: %v", err)
+		}
+		runtimeData.SmithingTemplateStackNetworkID = smithingRID
+		runtimeData.MoveTemplateSrcContainerID = cid
+		runtimeData.MoveTemplateSrcStackNetworkID = rid
+	}
+
+	// Process base
+	{
+		smithingRID, cid, rid, err := placeItem(op.BasePath, 51, protocol.ContainerSmithingTableInput)
+		if err != nil {
+			return nil, fmt.Errorf("handleSmithing: %v", err)
+		}
+		err = i.virtualInventories.updateFromUpdater(op.BasePath, op.ResultItem)
+		if err != nil {
+			return nil, fmt.Errorf("handleSmithing: %v", err)
+		}
+		runtimeData.SmithingBaseStackNetworkID = smithingRID
+		runtimeData.MoveBaseSrcContainerID = cid
+		runtimeData.MoveBaseSrcStackNetworkID = rid
+	}
+
+	// Process addition
+	{
+		smithingRID, cid, rid, err := placeItem(op.AdditionPath, 52, protocol.ContainerSmithingTableMaterial)
+		if err != nil {
+			return nil, fmt.Errorf("handleSmithing: %v", err)
+		}
+		if err = consumeItem(op.AdditionPath); err != nil {
+			return nil, fmt.Errorf("handleSmithing: %v", err)
+		}
+		runtimeData.SmithingAdditionStackNetworkID = smithingRID
+		runtimeData.MoveAdditionSrcContainerID = cid
+		runtimeData.MoveAdditionSrcStackNetworkID = rid
+	}
+
+	// Make runtime data
+	return op.Make(runtimeData), nil
+}
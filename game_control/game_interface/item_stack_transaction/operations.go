@@ -467,3 +467,62 @@ func (i *ItemStackTransaction) Crafting(
 	})
 	return i
 }
+
+// Smithing 将 templateSlot 处的锻造模板、baseSlot 处的物品
+// 以及 additionSlot 处的材料放入锻造台中，并使用网络 ID 为
+// recipeNetworkID 的配方锻造出新物品。
+//
+// resultItem 指示期望得到的物品的部分数据。
+// 如果操作成功，则新物品将回到 baseSlot 处。
+//
+// 该操作不支持内联，但它仍然可以被紧缩在单个的物品
+// 堆栈操作请求的数据包中
+func (i *ItemStackTransaction) Smithing(
+	recipeNetworkID uint32,
+	templateSlot resources_control.SlotLocation,
+	baseSlot resources_control.SlotLocation,
+	additionSlot resources_control.SlotLocation,
+	resultItem resources_control.ExpectedNewItem,
+) *ItemStackTransaction {
+	i.operations = append(i.operations, item_stack_operation.Smithing{
+		RecipeNetworkID: recipeNetworkID,
+		TemplatePath:    templateSlot,
+		BasePath:        baseSlot,
+		AdditionPath:    additionSlot,
+		ResultItem:      resultItem,
+	})
+	return i
+}
+
+// SmithingFromInventory 将背包中 templateSlot 处的锻造模板、
+// baseSlot 处的物品以及 additionSlot 处的材料放入锻造台中，
+// 并使用网络 ID 为 recipeNetworkID 的配方锻造出新物品。
+//
+// resultItem 指示期望得到的物品的部分数据。
+// 如果操作成功，则新物品将回到 baseSlot 处。
+//
+// 该操作不支持内联，但它仍然可以被紧缩在单个的物品堆栈操作请求的数据包中
+func (i *ItemStackTransaction) SmithingFromInventory(
+	recipeNetworkID uint32,
+	templateSlot resources_control.SlotID,
+	baseSlot resources_control.SlotID,
+	additionSlot resources_control.SlotID,
+	resultItem resources_control.ExpectedNewItem,
+) *ItemStackTransaction {
+	return i.Smithing(
+		recipeNetworkID,
+		resources_control.SlotLocation{
+			WindowID: protocol.WindowIDInventory,
+			SlotID:   templateSlot,
+		},
+		resources_control.SlotLocation{
+			WindowID: protocol.WindowIDInventory,
+			SlotID:   baseSlot,
+		},
+		resources_control.SlotLocation{
+			WindowID: protocol.WindowIDInventory,
+			SlotID:   additionSlot,
+		},
+		resultItem,
+	)
+}
@@ -0,0 +1,35 @@
+package game_interface
+
+import (
+	"strconv"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/metrics"
+)
+
+var (
+	// commandDuration 记录命令从发出到收到响应的耗时
+	commandDuration = metrics.NewHistogramVec(
+		"command_duration_seconds",
+		"Round-trip latency of command requests that received a response.",
+		nil,
+		"origin",
+	)
+	// commandTimeouts 记录超时的命令请求的数量
+	commandTimeouts = metrics.NewCounterVec(
+		"command_timeouts_total",
+		"Number of command requests that timed out.",
+		"origin",
+	)
+)
+
+// commandOriginLabel 返回命令来源 origin 的标签值
+func commandOriginLabel(origin uint32) string {
+	switch origin {
+	case protocol.CommandOriginPlayer:
+		return "player"
+	case protocol.CommandOriginAutomationPlayer:
+		return "automation_player"
+	}
+	return strconv.FormatUint(uint64(origin), 10)
+}
@@ -2,21 +2,26 @@ package game_interface
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
 	"github.com/OmineDev/flowers-for-machines/utils"
 
 	"github.com/google/uuid"
 )
 
-// StructureBackup 是基于 Commands 包装的结构备份与恢复相关的实现
+// StructureBackup 是基于 ResourcesWrapper
+// 和 Commands 包装的结构备份与恢复相关的实现
 type StructureBackup struct {
+	r   *ResourcesWrapper
 	api *Commands
 }
 
-// NewStructureBackup 根据 api 返回并创建一个新的 StructureBackup
-func NewStructureBackup(api *Commands) *StructureBackup {
-	return &StructureBackup{api: api}
+// NewStructureBackup 根据 wrapper 和 api 返回并创建一个新的 StructureBackup
+func NewStructureBackup(wrapper *ResourcesWrapper, api *Commands) *StructureBackup {
+	return &StructureBackup{r: wrapper, api: api}
 }
 
 // backupStructure 是一个内部实现细节，
@@ -140,3 +145,64 @@ func (s *StructureBackup) DeleteStructure(uniqueID uuid.UUID) error {
 	}
 	return nil
 }
+
+// ExportStructure 导出标识符为 uniqueID 的结构的模板数据。
+// exist 指示目标结构是否存在，并且只有在其存在时，
+// template 才指示该结构的模板数据
+func (s *StructureBackup) ExportStructure(uniqueID uuid.UUID) (exist bool, template map[string]any, err error) {
+	var resp *packet.StructureTemplateDataResponse
+	api := s.r
+
+	doOnce := new(sync.Once)
+	channel := make(chan struct{})
+	listenerID := api.PacketListener().ListenPacket(
+		[]uint32{packet.IDStructureTemplateDataResponse},
+		func(p packet.Packet) {
+			doOnce.Do(func() {
+				resp = p.(*packet.StructureTemplateDataResponse)
+				close(channel)
+			})
+		},
+	)
+	defer api.PacketListener().DestroyListener(listenerID)
+
+	err = api.WritePacket(
+		&packet.StructureTemplateDataRequest{
+			StructureName: "mystructure:" + utils.MakeUUIDSafeString(uniqueID),
+			Position:      protocol.BlockPos{0, 0, 0},
+			Settings: protocol.StructureSettings{
+				PaletteName:               "default",
+				IgnoreEntities:            true,
+				IgnoreBlocks:              false,
+				Size:                      protocol.BlockPos{0, 0, 0},
+				Offset:                    protocol.BlockPos{0, 0, 0},
+				LastEditingPlayerUniqueID: api.EntityUniqueID,
+				Rotation:                  0,
+				Mirror:                    0,
+				Integrity:                 100,
+				Seed:                      0,
+				AllowNonTickingChunks:     false,
+			},
+			RequestType: packet.StructureTemplateRequestExportFromLoad,
+		},
+	)
+	if err != nil {
+		return false, nil, fmt.Errorf("ExportStructure: %v", err)
+	}
+
+	timer := time.NewTimer(DefaultTimeoutCommandRequest)
+	defer timer.Stop()
+	select {
+	case <-channel:
+	case <-timer.C:
+		return false, nil, fmt.Errorf(
+			"ExportStructure: Export structure %#v failed due to time out",
+			utils.MakeUUIDSafeString(uniqueID),
+		)
+	}
+
+	if !resp.Success {
+		return false, nil, nil
+	}
+	return true, resp.StructureTemplate, nil
+}
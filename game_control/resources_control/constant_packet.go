@@ -7,6 +7,7 @@ import (
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
 
+	"github.com/google/uuid"
 	"github.com/pterm/pterm"
 )
 
@@ -29,20 +30,41 @@ type ConstantPacket struct {
 	// 所有可通过指令获得的物品
 	commandItems        []string
 	commandItemsMapping map[string]bool
+	// 特殊配方 (如烟花) 的 UUID 到其网络 ID 的映射
+	multiRecipeMapping map[uuid.UUID]uint32
+	// 工作台配方及其产物到配方的映射
+	craftingRecipes       []CraftingRecipe
+	craftingRecipeMapping map[protocol.ItemType][]int
+	// 锻造台盔甲纹饰配方的网络 ID
+	smithingTrimRecipeNetworkID *uint32
+}
+
+// CraftingRecipe 是可在工作台上使用的有序或无序配方
+type CraftingRecipe struct {
+	RecipeNetworkID uint32                         // 配方的网络 ID
+	Shaped          bool                           // 配方是否是有序配方
+	Width           int32                          // 有序配方的宽度
+	Height          int32                          // 有序配方的高度
+	Input           []protocol.ItemDescriptorCount // 配方所需的材料
+	Output          []protocol.ItemStack           // 配方的产物
 }
 
 // NewConstantPacket 创建并返回一个新的 ConstantPacket
 func NewConstantPacket() *ConstantPacket {
 	return &ConstantPacket{
-		availableItems:       nil,
-		itemNetworkIDMapping: make(map[int32]int),
-		itemNameMapping:      make(map[string]int),
-		itemNameMappingInv:   nil,
-		creativeContent:      nil,
-		creativeNIMapping:    make(map[int32][]int),
-		creativeCNIMapping:   make(map[uint32]int),
-		commandItems:         nil,
-		commandItemsMapping:  make(map[string]bool),
+		availableItems:              nil,
+		itemNetworkIDMapping:        make(map[int32]int),
+		itemNameMapping:             make(map[string]int),
+		itemNameMappingInv:          nil,
+		creativeContent:             nil,
+		creativeNIMapping:           make(map[int32][]int),
+		creativeCNIMapping:          make(map[uint32]int),
+		commandItems:                nil,
+		commandItemsMapping:         make(map[string]bool),
+		multiRecipeMapping:          make(map[uuid.UUID]uint32),
+		craftingRecipes:             nil,
+		craftingRecipeMapping:       make(map[protocol.ItemType][]int),
+		smithingTrimRecipeNetworkID: nil,
 	}
 }
 
@@ -172,3 +194,84 @@ func (c *ConstantPacket) onAvailableCommands(p *packet.AvailableCommands) {
 
 	panic("onAvailableCommands: Should nerver happened")
 }
+
+// MultiRecipeNetworkID 返回 UUID 为 id 的特殊配方的网络 ID。
+// 特殊配方是指烟花、地图复制等不由固定材料构成的配方。
+// found 为假指示租赁服没有下发该配方
+func (c ConstantPacket) MultiRecipeNetworkID(id uuid.UUID) (networkID uint32, found bool) {
+	networkID, found = c.multiRecipeMapping[id]
+	return
+}
+
+// SmithingTrimRecipeNetworkID 返回锻造台盔甲纹饰配方的网络 ID。
+// found 为假指示租赁服没有下发该配方
+func (c ConstantPacket) SmithingTrimRecipeNetworkID() (networkID uint32, found bool) {
+	if c.smithingTrimRecipeNetworkID == nil {
+		return 0, false
+	}
+	return *c.smithingTrimRecipeNetworkID, true
+}
+
+// CraftingRecipesByOutput 返回第一个产物为 output 的所有工作台配方。
+// 使用者不应修改返回的值，否则不保证程序的行为是正确的
+func (c ConstantPacket) CraftingRecipesByOutput(output protocol.ItemType) []CraftingRecipe {
+	result := make([]CraftingRecipe, 0)
+	for _, index := range c.craftingRecipeMapping[output] {
+		result = append(result, c.craftingRecipes[index])
+	}
+	return result
+}
+
+// onCraftingData ..
+func (c *ConstantPacket) onCraftingData(p *packet.CraftingData) {
+	if p.ClearRecipes {
+		c.multiRecipeMapping = make(map[uuid.UUID]uint32)
+		c.craftingRecipes = nil
+		c.craftingRecipeMapping = make(map[protocol.ItemType][]int)
+		c.smithingTrimRecipeNetworkID = nil
+	}
+
+	for _, recipe := range p.Recipes {
+		var craftingRecipe CraftingRecipe
+
+		switch r := recipe.(type) {
+		case *protocol.MultiRecipe:
+			c.multiRecipeMapping[r.UUID] = r.RecipeNetworkID
+			continue
+		case *protocol.SmithingTrimRecipe:
+			networkID := r.RecipeNetworkID
+			c.smithingTrimRecipeNetworkID = &networkID
+			continue
+		case *protocol.ShapelessRecipe:
+			if r.Block != "crafting_table" {
+				continue
+			}
+			craftingRecipe = CraftingRecipe{
+				RecipeNetworkID: r.RecipeNetworkID,
+				Input:           r.Input,
+				Output:          r.Output,
+			}
+		case *protocol.ShapedRecipe:
+			if r.Block != "crafting_table" {
+				continue
+			}
+			craftingRecipe = CraftingRecipe{
+				RecipeNetworkID: r.RecipeNetworkID,
+				Shaped:          true,
+				Width:           r.Width,
+				Height:          r.Height,
+				Input:           r.Input,
+				Output:          r.Output,
+			}
+		default:
+			continue
+		}
+
+		if len(craftingRecipe.Output) == 0 {
+			continue
+		}
+		output := craftingRecipe.Output[0].ItemType
+		c.craftingRecipeMapping[output] = append(c.craftingRecipeMapping[output], len(c.craftingRecipes))
+		c.craftingRecipes = append(c.craftingRecipes, craftingRecipe)
+	}
+}
@@ -0,0 +1,11 @@
+package resources_control
+
+import "github.com/OmineDev/flowers-for-machines/metrics"
+
+// itemStackRejections 记录被服务器拒绝的物品堆栈请求的数量，
+// 其标签 status 是服务器返回的状态码
+var itemStackRejections = metrics.NewCounterVec(
+	"item_stack_request_rejections_total",
+	"Number of item stack requests rejected by the server, by response status code.",
+	"status",
+)
@@ -2,6 +2,7 @@ package resources_control
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
@@ -127,6 +128,7 @@ func (r *Resources) handleItemStackResponse(p *packet.ItemStackResponse) {
 		itemUpdater, _ := r.itemStack.itemStackUpdater.LoadAndDelete(requestID)
 
 		if response.Status != protocol.ItemStackResponseStatusOK {
+			itemStackRejections.WithLabelValues(strconv.Itoa(int(response.Status))).Inc()
 			resp := response
 			go callback(&resp)
 			continue
@@ -220,6 +222,8 @@ func (r *Resources) handlePacket(pk packet.Packet) {
 		r.constant.onCreativeContent(p)
 	case *packet.AvailableCommands:
 		r.constant.onAvailableCommands(p)
+	case *packet.CraftingData:
+		r.constant.onCraftingData(p)
 	}
 	// for other implements
 	r.listener.onPacket(pk)
@@ -30,6 +30,10 @@ type Resources struct {
 	listener *PacketListener
 	// constant 是常量数据包的简要记录实现
 	constant *ConstantPacket
+	// closed 在与租赁服的连接断开后被关闭，
+	// 而 closeErr 是导致连接断开的错误
+	closed   chan struct{}
+	closeErr error
 }
 
 // NewResourcesControl 基于 client 创建一个新的资源中心。
@@ -45,6 +49,7 @@ func NewResourcesControl(client *client.Client) *Resources {
 		itemStack: NewItemStackOperationManager(),
 		container: NewContainerManager(),
 		listener:  NewPacketListener(),
+		closed:    make(chan struct{}),
 	}
 
 	inventory := NewInventories()
@@ -72,12 +77,31 @@ func (r *Resources) listenPacket() {
 	for {
 		pk, err := r.client.Conn().ReadPacket()
 		if err != nil {
+			r.closeErr = err
+			close(r.closed)
 			return
 		}
 		r.handlePacket(pk)
 	}
 }
 
+// Closed 返回一个在与租赁服的连接断开 (包括机器人被踢出) 后被关闭的通道。
+// 连接断开后，资源中心不再可用，应当重新登录并创建新的资源中心
+func (r *Resources) Closed() <-chan struct{} {
+	return r.closed
+}
+
+// CloseError 返回导致与租赁服的连接断开的错误。
+// 如果连接尚未断开，则返回 nil
+func (r *Resources) CloseError() error {
+	select {
+	case <-r.closed:
+		return r.closeErr
+	default:
+		return nil
+	}
+}
+
 // BotInfo 返回机器人的基本信息
 func (r *Resources) BotInfo() BotInfo {
 	return BotInfo{
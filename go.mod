@@ -9,6 +9,8 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/ugorji/go/codec v1.2.14
 	golang.org/x/text v0.26.0
+	google.golang.org/grpc v1.65.0
+	google.golang.org/protobuf v1.34.1
 )
 
 require (
@@ -43,8 +45,7 @@ require (
 	golang.org/x/arch v0.8.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/term v0.32.0 // indirect
-	google.golang.org/protobuf v1.34.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157 // indirect
 )
 
 require (
@@ -58,4 +59,5 @@ require (
 	github.com/pterm/pterm v0.12.80
 	golang.org/x/crypto v0.39.0 // indirect
 	golang.org/x/net v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,114 @@
+package mapping
+
+// 此表描述了可以被染色的皮革盔甲
+var LeatherArmor = map[string]bool{
+	"minecraft:leather_helmet":     true, // 皮革帽子
+	"minecraft:leather_chestplate": true, // 皮革外套
+	"minecraft:leather_leggings":   true, // 皮革裤子
+	"minecraft:leather_boots":      true, // 皮革靴子
+}
+
+// 此表描述了皮革盔甲在炼药锅中被单一染料染色后的 RGB 颜色到 染料物品名 的映射
+var LeatherArmorColorToDyeName = map[[3]uint8]string{
+	{249, 255, 254}: "minecraft:white_dye",      // 白色染料
+	{157, 157, 151}: "minecraft:light_gray_dye", // 淡灰色染料
+	{71, 79, 82}:    "minecraft:gray_dye",       // 灰色染料
+	{29, 29, 33}:    "minecraft:black_dye",      // 黑色染料
+	{131, 84, 50}:   "minecraft:brown_dye",      // 棕色染料
+	{176, 46, 38}:   "minecraft:red_dye",        // 红色染料
+	{249, 128, 29}:  "minecraft:orange_dye",     // 橙色染料
+	{254, 216, 61}:  "minecraft:yellow_dye",     // 黄色染料
+	{128, 199, 31}:  "minecraft:lime_dye",       // 黄绿色染料
+	{94, 124, 22}:   "minecraft:green_dye",      // 绿色染料
+	{22, 156, 156}:  "minecraft:cyan_dye",       // 青色染料
+	{58, 179, 218}:  "minecraft:light_blue_dye", // 淡蓝色染料
+	{60, 68, 170}:   "minecraft:blue_dye",       // 蓝色染料
+	{137, 50, 184}:  "minecraft:purple_dye",     // 紫色染料
+	{199, 78, 189}:  "minecraft:magenta_dye",    // 品红色染料
+	{243, 139, 170}: "minecraft:pink_dye",       // 粉红色染料
+}
+
+// 此表描述了皮革盔甲在炼药锅中被单一染料染色后的所有 RGB 颜色
+var LeatherArmorDyeColor [][3]uint8 = [][3]uint8{
+	{249, 255, 254}, // 白色
+	{157, 157, 151}, // 淡灰色
+	{71, 79, 82},    // 灰色
+	{29, 29, 33},    // 黑色
+	{131, 84, 50},   // 棕色
+	{176, 46, 38},   // 红色
+	{249, 128, 29},  // 橙色
+	{254, 216, 61},  // 黄色
+	{128, 199, 31},  // 黄绿色
+	{94, 124, 22},   // 绿色
+	{22, 156, 156},  // 青色
+	{58, 179, 218},  // 淡蓝色
+	{60, 68, 170},   // 蓝色
+	{137, 50, 184},  // 紫色
+	{199, 78, 189},  // 品红色
+	{243, 139, 170}, // 粉红色
+}
+
+// 此表描述了可以在锻造台上添加纹饰的盔甲
+var TrimmableArmor = map[string]bool{
+	"minecraft:leather_helmet":       true,
+	"minecraft:leather_chestplate":   true,
+	"minecraft:leather_leggings":     true,
+	"minecraft:leather_boots":        true,
+	"minecraft:chainmail_helmet":     true,
+	"minecraft:chainmail_chestplate": true,
+	"minecraft:chainmail_leggings":   true,
+	"minecraft:chainmail_boots":      true,
+	"minecraft:iron_helmet":          true,
+	"minecraft:iron_chestplate":      true,
+	"minecraft:iron_leggings":        true,
+	"minecraft:iron_boots":           true,
+	"minecraft:golden_helmet":        true,
+	"minecraft:golden_chestplate":    true,
+	"minecraft:golden_leggings":      true,
+	"minecraft:golden_boots":         true,
+	"minecraft:diamond_helmet":       true,
+	"minecraft:diamond_chestplate":   true,
+	"minecraft:diamond_leggings":     true,
+	"minecraft:diamond_boots":        true,
+	"minecraft:netherite_helmet":     true,
+	"minecraft:netherite_chestplate": true,
+	"minecraft:netherite_leggings":   true,
+	"minecraft:netherite_boots":      true,
+	"minecraft:turtle_helmet":        true,
+}
+
+// 此表描述了盔甲纹饰中 Pattern 字段到 锻造模板物品名 的映射
+var ArmorTrimPatternToTemplateName = map[string]string{
+	"bolt":      "minecraft:bolt_armor_trim_smithing_template",      // 镶铆盔甲纹饰
+	"coast":     "minecraft:coast_armor_trim_smithing_template",     // 海岸盔甲纹饰
+	"dune":      "minecraft:dune_armor_trim_smithing_template",      // 沙丘盔甲纹饰
+	"eye":       "minecraft:eye_armor_trim_smithing_template",       // 眼眸盔甲纹饰
+	"flow":      "minecraft:flow_armor_trim_smithing_template",      // 涡流盔甲纹饰
+	"host":      "minecraft:host_armor_trim_smithing_template",      // 雇主盔甲纹饰
+	"raiser":    "minecraft:raiser_armor_trim_smithing_template",    // 牧民盔甲纹饰
+	"rib":       "minecraft:rib_armor_trim_smithing_template",       // 肋骨盔甲纹饰
+	"sentry":    "minecraft:sentry_armor_trim_smithing_template",    // 哨兵盔甲纹饰
+	"shaper":    "minecraft:shaper_armor_trim_smithing_template",    // 塑造盔甲纹饰
+	"silence":   "minecraft:silence_armor_trim_smithing_template",   // 幽静盔甲纹饰
+	"snout":     "minecraft:snout_armor_trim_smithing_template",     // 猪鼻盔甲纹饰
+	"spire":     "minecraft:spire_armor_trim_smithing_template",     // 尖塔盔甲纹饰
+	"tide":      "minecraft:tide_armor_trim_smithing_template",      // 潮汐盔甲纹饰
+	"vex":       "minecraft:vex_armor_trim_smithing_template",       // 恼鬼盔甲纹饰
+	"ward":      "minecraft:ward_armor_trim_smithing_template",      // 监守盔甲纹饰
+	"wayfinder": "minecraft:wayfinder_armor_trim_smithing_template", // 向导盔甲纹饰
+	"wild":      "minecraft:wild_armor_trim_smithing_template",      // 荒野盔甲纹饰
+}
+
+// 此表描述了盔甲纹饰中 Material 字段到 纹饰材料物品名 的映射
+var ArmorTrimMaterialToItemName = map[string]string{
+	"amethyst":  "minecraft:amethyst_shard",  // 紫水晶碎片
+	"copper":    "minecraft:copper_ingot",    // 铜锭
+	"diamond":   "minecraft:diamond",         // 钻石
+	"emerald":   "minecraft:emerald",         // 绿宝石
+	"gold":      "minecraft:gold_ingot",      // 金锭
+	"iron":      "minecraft:iron_ingot",      // 铁锭
+	"lapis":     "minecraft:lapis_lazuli",    // 青金石
+	"netherite": "minecraft:netherite_ingot", // 下界合金锭
+	"quartz":    "minecraft:quartz",          // 下界石英
+	"redstone":  "minecraft:redstone",        // 红石粉
+}
@@ -0,0 +1,18 @@
+package mapping
+
+// CampfireSlotCount 是营火可以放置的物品的数量
+const CampfireSlotCount = 4
+
+// CampfireCookableItems 是可以放置在营火上烹饪的物品。
+// 只有这些物品可以通过交互放置到营火上
+var CampfireCookableItems = map[string]bool{
+	"minecraft:beef":     true,
+	"minecraft:porkchop": true,
+	"minecraft:chicken":  true,
+	"minecraft:mutton":   true,
+	"minecraft:rabbit":   true,
+	"minecraft:cod":      true,
+	"minecraft:salmon":   true,
+	"minecraft:potato":   true,
+	"minecraft:kelp":     true,
+}
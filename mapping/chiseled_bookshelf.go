@@ -0,0 +1,6 @@
+package mapping
+
+// ChiseledBookshelfSlotCount 是雕纹书架的槽位数量。
+// 槽位 0 至 2 位于上层，而 3 至 5 位于下层，
+// 并且面向书架正面时，它们都是从左到右排列的
+const ChiseledBookshelfSlotCount = 6
@@ -0,0 +1,12 @@
+package mapping
+
+// DecoratedPotDefaultSherd 是饰纹陶罐未使用陶片的面所对应的物品
+const DecoratedPotDefaultSherd = "minecraft:brick"
+
+// MultiRecipeDecoratedPot 是饰纹陶罐的特殊配方的 UUID。
+// 带有陶片的饰纹陶罐由这一配方合成
+const MultiRecipeDecoratedPot = "685a742a-c42e-4a4e-88ea-5eb83fc98e5b"
+
+// DecoratedPotSherdGrid 是饰纹陶罐的各个面在工作台合成栏中的位置。
+// 其索引依次为后、左、右和前，这与方块实体数据中 sherds 的顺序相同
+var DecoratedPotSherdGrid = [4]uint8{1, 3, 5, 7}
@@ -0,0 +1,45 @@
+package mapping
+
+// 烟花之星的形状
+const (
+	FireworkTypeSmallBall   byte = iota // 小型球状
+	FireworkTypeLargeBall               // 大型球状
+	FireworkTypeStar                    // 星形
+	FireworkTypeCreeperHead             // 苦力怕状
+	FireworkTypeBurst                   // 爆裂状
+)
+
+// MultiRecipeFireworks 是烟花相关合成的特殊配方的 UUID。
+// 烟花火箭、烟花之星及其淡出颜色都由这一配方合成
+const MultiRecipeFireworks = "00000000-0000-0000-0000-000000000002"
+
+// 合成烟花火箭时可以使用的火药数量，
+// 它也就是烟花火箭的飞行时间
+const (
+	FireworkMinFlight byte = 1
+	FireworkMaxFlight byte = 3
+)
+
+// 此表描述了烟花之星的形状到 形状中文名 的映射
+var FireworkTypeFormat = map[byte]string{
+	FireworkTypeSmallBall:   "小型球状",
+	FireworkTypeLargeBall:   "大型球状",
+	FireworkTypeStar:        "星形",
+	FireworkTypeCreeperHead: "苦力怕状",
+	FireworkTypeBurst:       "爆裂状",
+}
+
+// FireworkShapeItem 是合成特定形状的烟花之星所需的物品
+type FireworkShapeItem struct {
+	Name     string
+	Metadata int16
+}
+
+// 此表描述了烟花之星的形状到 合成所需物品 的映射。
+// 小型球状的烟花之星无需额外的物品，因此不在此表中
+var FireworkTypeToShapeItem = map[byte]FireworkShapeItem{
+	FireworkTypeLargeBall:   {Name: "minecraft:fire_charge"},        // 火焰弹
+	FireworkTypeStar:        {Name: "minecraft:gold_nugget"},        // 金粒
+	FireworkTypeCreeperHead: {Name: "minecraft:skull", Metadata: 4}, // 苦力怕的头
+	FireworkTypeBurst:       {Name: "minecraft:feather"},            // 羽毛
+}
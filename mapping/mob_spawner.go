@@ -0,0 +1,20 @@
+package mapping
+
+// MobSpawnerField 描述了刷怪笼的一个数值字段
+type MobSpawnerField struct {
+	Name         string // 字段在方块实体数据中的名称
+	Format       string // 字段的中文名称
+	DefaultValue int16  // 使用刷怪蛋后该字段的默认值
+}
+
+// MobSpawnerFields 是刷怪笼的各个数值字段。
+// 它们无法通过游戏内的操作修改，因此制作时
+// 总是使用默认值
+var MobSpawnerFields = []MobSpawnerField{
+	{Name: "MinSpawnDelay", Format: "最小生成间隔", DefaultValue: 200},
+	{Name: "MaxSpawnDelay", Format: "最大生成间隔", DefaultValue: 800},
+	{Name: "SpawnCount", Format: "单次生成数量", DefaultValue: 4},
+	{Name: "MaxNearbyEntities", Format: "附近实体上限", DefaultValue: 6},
+	{Name: "RequiredPlayerRange", Format: "玩家激活距离", DefaultValue: 16},
+	{Name: "SpawnRange", Format: "生成范围", DefaultValue: 4},
+}
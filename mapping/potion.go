@@ -0,0 +1,111 @@
+package mapping
+
+// PotionEffect 描述药水效果及其等级 (从 0 开始)
+type PotionEffect struct {
+	ID        int32
+	Amplifier int32
+}
+
+// 此表描述了 Java 版药水名称 (不含命名空间) 到 基岩版药水 ID 的映射。
+// 基岩版的药水、喷溅药水和滞留药水的数据值即为药水 ID，
+// 而药箭的数据值为药水 ID 加 1
+var PotionJavaNameToID = map[string]int16{
+	"water":                0,
+	"mundane":              1,
+	"thick":                3,
+	"awkward":              4,
+	"night_vision":         5,
+	"long_night_vision":    6,
+	"invisibility":         7,
+	"long_invisibility":    8,
+	"leaping":              9,
+	"long_leaping":         10,
+	"strong_leaping":       11,
+	"fire_resistance":      12,
+	"long_fire_resistance": 13,
+	"swiftness":            14,
+	"long_swiftness":       15,
+	"strong_swiftness":     16,
+	"slowness":             17,
+	"long_slowness":        18,
+	"water_breathing":      19,
+	"long_water_breathing": 20,
+	"healing":              21,
+	"strong_healing":       22,
+	"harming":              23,
+	"strong_harming":       24,
+	"poison":               25,
+	"long_poison":          26,
+	"strong_poison":        27,
+	"regeneration":         28,
+	"long_regeneration":    29,
+	"strong_regeneration":  30,
+	"strength":             31,
+	"long_strength":        32,
+	"strong_strength":      33,
+	"weakness":             34,
+	"long_weakness":        35,
+	"turtle_master":        37,
+	"long_turtle_master":   38,
+	"strong_turtle_master": 39,
+	"slow_falling":         40,
+	"long_slow_falling":    41,
+	"strong_slowness":      42,
+}
+
+// 此表描述了 Java 版单个药水效果 到 基岩版药水 ID 的映射。
+// 它只用于识别仅含一个自定义效果的药水，
+// 因此无法区分效果时长不同的同种药水
+var PotionEffectToID = map[PotionEffect]int16{
+	{ID: 16, Amplifier: 0}: 5,  // 夜视
+	{ID: 14, Amplifier: 0}: 7,  // 隐身
+	{ID: 8, Amplifier: 0}:  9,  // 跳跃提升
+	{ID: 8, Amplifier: 1}:  11, // 跳跃提升 II
+	{ID: 12, Amplifier: 0}: 12, // 抗火
+	{ID: 1, Amplifier: 0}:  14, // 速度
+	{ID: 1, Amplifier: 1}:  16, // 速度 II
+	{ID: 2, Amplifier: 0}:  17, // 缓慢
+	{ID: 2, Amplifier: 3}:  42, // 缓慢 IV
+	{ID: 13, Amplifier: 0}: 19, // 水下呼吸
+	{ID: 6, Amplifier: 0}:  21, // 瞬间治疗
+	{ID: 6, Amplifier: 1}:  22, // 瞬间治疗 II
+	{ID: 7, Amplifier: 0}:  23, // 瞬间伤害
+	{ID: 7, Amplifier: 1}:  24, // 瞬间伤害 II
+	{ID: 19, Amplifier: 0}: 25, // 中毒
+	{ID: 19, Amplifier: 1}: 27, // 中毒 II
+	{ID: 10, Amplifier: 0}: 28, // 生命恢复
+	{ID: 10, Amplifier: 1}: 30, // 生命恢复 II
+	{ID: 5, Amplifier: 0}:  31, // 力量
+	{ID: 5, Amplifier: 1}:  33, // 力量 II
+	{ID: 18, Amplifier: 0}: 34, // 虚弱
+	{ID: 20, Amplifier: 1}: 36, // 衰变
+	{ID: 28, Amplifier: 0}: 40, // 缓降
+}
+
+// 此表描述了 Java 版药水效果名称 (不含命名空间) 到 效果 ID 的映射。
+// 它只包含谜之炖菜可能具有的效果
+var EffectJavaNameToID = map[string]int32{
+	"fire_resistance": 12,
+	"blindness":       15,
+	"jump_boost":      8,
+	"night_vision":    16,
+	"poison":          19,
+	"regeneration":    10,
+	"saturation":      23,
+	"weakness":        18,
+	"wither":          20,
+}
+
+// 此表描述了谜之炖菜的效果 ID 到 基岩版谜之炖菜数据值 的映射。
+// 同一效果可能对应多种花，此处只取其中一种
+var SuspiciousStewEffectToMetadata = map[int32]int16{
+	16: 0, // 夜视 (虞美人)
+	8:  1, // 跳跃提升 (矢车菊)
+	18: 2, // 虚弱 (郁金香)
+	15: 3, // 失明 (蓝花美耳草)
+	19: 4, // 中毒 (铃兰)
+	23: 5, // 饱和 (蒲公英)
+	20: 7, // 凋零 (凋零玫瑰)
+	10: 8, // 生命恢复 (滨菊)
+	12: 9, // 抗火 (绒球葱)
+}
@@ -0,0 +1,23 @@
+package mapping
+
+// 头颅的种类
+const (
+	SkullTypeSkeleton       byte = iota // 骷髅头颅
+	SkullTypeWitherSkeleton             // 凋灵骷髅头颅
+	SkullTypeZombie                     // 僵尸的头
+	SkullTypePlayer                     // 玩家的头
+	SkullTypeCreeper                    // 苦力怕的头
+	SkullTypeDragon                     // 龙首
+	SkullTypePiglin                     // 猪灵的头
+)
+
+// 此表描述了头颅的种类到 头颅中文名 的映射
+var SkullTypeFormat = map[byte]string{
+	SkullTypeSkeleton:       "骷髅头颅",
+	SkullTypeWitherSkeleton: "凋灵骷髅头颅",
+	SkullTypeZombie:         "僵尸的头",
+	SkullTypePlayer:         "玩家的头",
+	SkullTypeCreeper:        "苦力怕的头",
+	SkullTypeDragon:         "龙首",
+	SkullTypePiglin:         "猪灵的头",
+}
@@ -11,6 +11,14 @@ const (
 	SupportNBTBlockTypeJukeBox
 	SupportNBTBlockTypeBrewingStand
 	SupportNBTBlockTypeCrafter
+	SupportNBTBlockTypeBed
+	SupportNBTBlockTypeSkull
+	SupportNBTBlockTypeFlowerPot
+	SupportNBTBlockTypeDecoratedPot
+	SupportNBTBlockTypeChiseledBookshelf
+	SupportNBTBlockTypeMobSpawner
+	SupportNBTBlockTypeCampfire
+	SupportNBTBlockTypeBrushableBlock
 )
 
 // 此表描述了现阶段已经支持了的方块实体。
@@ -87,7 +95,7 @@ var SupportBlocksPool = map[string]uint8{
 	// 物品展示框
 	"minecraft:frame":      SupportNBTBlockTypeFrame,
 	"minecraft:glow_frame": SupportNBTBlockTypeFrame,
-	// 结构方块, 旗帜, 讲台, 唱片机, 酿造台 和 合成器
+	// 结构方块, 旗帜, 讲台, 唱片机, 酿造台, 合成器 和 床
 	"minecraft:structure_block": SupportNBTBlockTypeStructureBlock,
 	"minecraft:standing_banner": SupportNBTBlockTypeBanner,
 	"minecraft:wall_banner":     SupportNBTBlockTypeBanner,
@@ -95,4 +103,19 @@ var SupportBlocksPool = map[string]uint8{
 	"minecraft:jukebox":         SupportNBTBlockTypeJukeBox,
 	"minecraft:brewing_stand":   SupportNBTBlockTypeBrewingStand,
 	"minecraft:crafter":         SupportNBTBlockTypeCrafter,
+	"minecraft:bed":             SupportNBTBlockTypeBed,
+	// 头颅, 花盆 和 饰纹陶罐
+	"minecraft:skull":         SupportNBTBlockTypeSkull,
+	"minecraft:flower_pot":    SupportNBTBlockTypeFlowerPot,
+	"minecraft:decorated_pot": SupportNBTBlockTypeDecoratedPot,
+	// 雕纹书架
+	"minecraft:chiseled_bookshelf": SupportNBTBlockTypeChiseledBookshelf,
+	// 刷怪笼
+	"minecraft:mob_spawner": SupportNBTBlockTypeMobSpawner,
+	// 营火
+	"minecraft:campfire":      SupportNBTBlockTypeCampfire,
+	"minecraft:soul_campfire": SupportNBTBlockTypeCampfire,
+	// 可疑的方块
+	"minecraft:suspicious_sand":   SupportNBTBlockTypeBrushableBlock,
+	"minecraft:suspicious_gravel": SupportNBTBlockTypeBrushableBlock,
 }
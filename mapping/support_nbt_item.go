@@ -4,6 +4,13 @@ const (
 	SupportNBTItemTypeBook uint8 = iota
 	SupportNBTItemTypeBanner
 	SupportNBTItemTypeShield
+	SupportNBTItemTypeFirework
+	SupportNBTItemTypeFireworkStar
+	SupportNBTItemTypePotion
+	SupportNBTItemTypeTippedArrow
+	SupportNBTItemTypeSuspiciousStew
+	SupportNBTItemTypeArmor
+	SupportNBTItemTypeFilledMap
 )
 
 // 此表描述了现阶段已经支持了的特殊物品，如烟花等物品。
@@ -16,4 +23,44 @@ var SupportItemsPool = map[string]uint8{
 	"minecraft:banner": SupportNBTItemTypeBanner,
 	// 盾牌
 	"minecraft:shield": SupportNBTItemTypeShield,
+	// 烟花火箭
+	"minecraft:firework_rocket": SupportNBTItemTypeFirework,
+	// 烟花之星
+	"minecraft:firework_star": SupportNBTItemTypeFireworkStar,
+	// 药水
+	"minecraft:potion":           SupportNBTItemTypePotion,
+	"minecraft:splash_potion":    SupportNBTItemTypePotion,
+	"minecraft:lingering_potion": SupportNBTItemTypePotion,
+	// 药箭
+	"minecraft:arrow": SupportNBTItemTypeTippedArrow,
+	// 谜之炖菜
+	"minecraft:suspicious_stew": SupportNBTItemTypeSuspiciousStew,
+	// 盔甲 (皮革盔甲的颜色和盔甲纹饰)
+	"minecraft:leather_helmet":       SupportNBTItemTypeArmor,
+	"minecraft:leather_chestplate":   SupportNBTItemTypeArmor,
+	"minecraft:leather_leggings":     SupportNBTItemTypeArmor,
+	"minecraft:leather_boots":        SupportNBTItemTypeArmor,
+	"minecraft:chainmail_helmet":     SupportNBTItemTypeArmor,
+	"minecraft:chainmail_chestplate": SupportNBTItemTypeArmor,
+	"minecraft:chainmail_leggings":   SupportNBTItemTypeArmor,
+	"minecraft:chainmail_boots":      SupportNBTItemTypeArmor,
+	"minecraft:iron_helmet":          SupportNBTItemTypeArmor,
+	"minecraft:iron_chestplate":      SupportNBTItemTypeArmor,
+	"minecraft:iron_leggings":        SupportNBTItemTypeArmor,
+	"minecraft:iron_boots":           SupportNBTItemTypeArmor,
+	"minecraft:golden_helmet":        SupportNBTItemTypeArmor,
+	"minecraft:golden_chestplate":    SupportNBTItemTypeArmor,
+	"minecraft:golden_leggings":      SupportNBTItemTypeArmor,
+	"minecraft:golden_boots":         SupportNBTItemTypeArmor,
+	"minecraft:diamond_helmet":       SupportNBTItemTypeArmor,
+	"minecraft:diamond_chestplate":   SupportNBTItemTypeArmor,
+	"minecraft:diamond_leggings":     SupportNBTItemTypeArmor,
+	"minecraft:diamond_boots":        SupportNBTItemTypeArmor,
+	"minecraft:netherite_helmet":     SupportNBTItemTypeArmor,
+	"minecraft:netherite_chestplate": SupportNBTItemTypeArmor,
+	"minecraft:netherite_leggings":   SupportNBTItemTypeArmor,
+	"minecraft:netherite_boots":      SupportNBTItemTypeArmor,
+	"minecraft:turtle_helmet":        SupportNBTItemTypeArmor,
+	// 地图
+	"minecraft:filled_map": SupportNBTItemTypeFilledMap,
 }
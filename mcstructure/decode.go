@@ -0,0 +1,83 @@
+package mcstructure
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+)
+
+// paletteFile 是调色板在 .mcstructure 文件中的格式
+type paletteFile struct {
+	BlockPalette      []PaletteBlock            `nbt:"block_palette"`
+	BlockPositionData map[string]map[string]any `nbt:"block_position_data"`
+}
+
+// structureFile 是 .mcstructure 文件的顶层格式
+type structureFile struct {
+	FormatVersion int32   `nbt:"format_version"`
+	Size          []int32 `nbt:"size"`
+	Structure     struct {
+		BlockIndices [][]int32              `nbt:"block_indices"`
+		Entities     []map[string]any       `nbt:"entities"`
+		Palette      map[string]paletteFile `nbt:"palette"`
+	} `nbt:"structure"`
+	StructureWorldOrigin []int32 `nbt:"structure_world_origin"`
+}
+
+// Decode 从 r 读取并解码一个 .mcstructure 文件
+func Decode(r io.Reader) (result *Structure, err error) {
+	var file structureFile
+
+	err = nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&file)
+	if err != nil {
+		return nil, fmt.Errorf("Decode: %v", err)
+	}
+
+	if len(file.Size) != 3 {
+		return nil, fmt.Errorf("Decode: Invalid structure size %v", file.Size)
+	}
+	result = NewStructure(protocol.BlockPos{file.Size[0], file.Size[1], file.Size[2]})
+	result.FormatVersion = file.FormatVersion
+	if len(file.StructureWorldOrigin) == 3 {
+		result.Origin = protocol.BlockPos{
+			file.StructureWorldOrigin[0],
+			file.StructureWorldOrigin[1],
+			file.StructureWorldOrigin[2],
+		}
+	}
+	if file.Structure.Entities != nil {
+		result.Entities = file.Structure.Entities
+	}
+
+	volume := result.Volume()
+	for layer, indices := range file.Structure.BlockIndices {
+		if layer >= LayerCount {
+			break
+		}
+		if int32(len(indices)) != volume {
+			return nil, fmt.Errorf(
+				"Decode: Layer %d has %d block indices but the structure volume is %d",
+				layer, len(indices), volume,
+			)
+		}
+		result.BlockIndices[layer] = indices
+	}
+
+	palette, ok := file.Structure.Palette[DefaultPaletteName]
+	if !ok {
+		return result, nil
+	}
+	result.Palette = palette.BlockPalette
+	for key, value := range palette.BlockPositionData {
+		index, err := strconv.ParseInt(key, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Decode: Invalid block position data index %#v", key)
+		}
+		result.BlockPositionData[int32(index)] = value
+	}
+
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package mcstructure
+
+import (
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+)
+
+const (
+	// DefaultFormatVersion 是 .mcstructure 文件的格式版本
+	DefaultFormatVersion int32 = 1
+	// DefaultPaletteName 是 .mcstructure 文件使用的默认调色板名称
+	DefaultPaletteName string = "default"
+	// BlockIndexNotExist 指示某个方块层在对应位置上没有方块
+	BlockIndexNotExist int32 = -1
+)
+
+const (
+	LayerPrimary   = iota // 主方块层
+	LayerSecondary        // 副方块层，通常是含水方块中的水
+	LayerCount            // 方块层的数量
+)
+
+// PaletteBlock 是调色板中的单个方块
+type PaletteBlock struct {
+	Name    string         `nbt:"name"`
+	States  map[string]any `nbt:"states"`
+	Version int32          `nbt:"version"`
+}
+
+// Structure 是已解码的 .mcstructure 文件
+type Structure struct {
+	// FormatVersion 是该文件的格式版本
+	FormatVersion int32
+	// Size 是该结构的尺寸
+	Size protocol.BlockPos
+	// Origin 是该结构在被保存时的世界坐标
+	Origin protocol.BlockPos
+	// Palette 是该结构的调色板
+	Palette []PaletteBlock
+	// BlockIndices 是每个方块层中每个位置的方块在调色板中的索引。
+	// 索引为 BlockIndexNotExist 表示该位置在该层上没有方块
+	BlockIndices [LayerCount][]int32
+	// BlockPositionData 是方块索引到该方块附加数据的映射，
+	// 附加数据通常包含 block_entity_data 字段
+	BlockPositionData map[int32]map[string]any
+	// Entities 是该结构中的实体
+	Entities []map[string]any
+}
+
+// NewStructure 创建并返回一个尺寸为 size 的空结构
+func NewStructure(size protocol.BlockPos) *Structure {
+	volume := size[0] * size[1] * size[2]
+	s := &Structure{
+		FormatVersion:     DefaultFormatVersion,
+		Size:              size,
+		Palette:           make([]PaletteBlock, 0),
+		BlockPositionData: make(map[int32]map[string]any),
+		Entities:          make([]map[string]any, 0),
+	}
+	for layer := range LayerCount {
+		s.BlockIndices[layer] = make([]int32, volume)
+		for i := range s.BlockIndices[layer] {
+			s.BlockIndices[layer][i] = BlockIndexNotExist
+		}
+	}
+	return s
+}
+
+// Volume 返回该结构所包含的方块位置的数量
+func (s Structure) Volume() int32 {
+	return s.Size[0] * s.Size[1] * s.Size[2]
+}
+
+// Index 返回相对坐标 pos 处的方块在 BlockIndices 中的索引
+func (s Structure) Index(pos protocol.BlockPos) int32 {
+	return pos[0]*s.Size[1]*s.Size[2] + pos[1]*s.Size[2] + pos[2]
+}
+
+// Position 返回 BlockIndices 中索引为 index 的方块的相对坐标
+func (s Structure) Position(index int32) protocol.BlockPos {
+	return protocol.BlockPos{
+		index / (s.Size[1] * s.Size[2]),
+		index / s.Size[2] % s.Size[1],
+		index % s.Size[2],
+	}
+}
+
+// Block 返回 layer 层中索引为 index 处的方块。
+// 如果该位置没有方块，则 exist 为假
+func (s Structure) Block(layer int, index int32) (block PaletteBlock, exist bool) {
+	paletteIndex := s.BlockIndices[layer][index]
+	if paletteIndex < 0 || int(paletteIndex) >= len(s.Palette) {
+		return PaletteBlock{}, false
+	}
+	return s.Palette[paletteIndex], true
+}
+
+// BlockEntityData 返回索引为 index 处的方块的方块实体数据。
+// 如果该方块没有方块实体数据，则 exist 为假
+func (s Structure) BlockEntityData(index int32) (blockNBT map[string]any, exist bool) {
+	data, ok := s.BlockPositionData[index]
+	if !ok {
+		return nil, false
+	}
+	blockNBT, exist = data["block_entity_data"].(map[string]any)
+	return
+}
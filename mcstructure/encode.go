@@ -0,0 +1,49 @@
+package mcstructure
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+)
+
+// Encode 将 structure 编码为 .mcstructure 文件并写入 w
+func Encode(w io.Writer, structure *Structure) error {
+	var file structureFile
+
+	file.FormatVersion = structure.FormatVersion
+	file.Size = []int32{structure.Size[0], structure.Size[1], structure.Size[2]}
+	file.StructureWorldOrigin = []int32{structure.Origin[0], structure.Origin[1], structure.Origin[2]}
+
+	file.Structure.BlockIndices = make([][]int32, LayerCount)
+	for layer := range LayerCount {
+		file.Structure.BlockIndices[layer] = structure.BlockIndices[layer]
+	}
+
+	file.Structure.Entities = structure.Entities
+	if file.Structure.Entities == nil {
+		file.Structure.Entities = make([]map[string]any, 0)
+	}
+
+	palette := paletteFile{
+		BlockPalette:      make([]PaletteBlock, len(structure.Palette)),
+		BlockPositionData: make(map[string]map[string]any),
+	}
+	for index, block := range structure.Palette {
+		if block.States == nil {
+			block.States = make(map[string]any)
+		}
+		palette.BlockPalette[index] = block
+	}
+	for index, data := range structure.BlockPositionData {
+		palette.BlockPositionData[strconv.Itoa(int(index))] = data
+	}
+	file.Structure.Palette = map[string]paletteFile{DefaultPaletteName: palette}
+
+	err := nbt.NewEncoderWithEncoding(w, nbt.LittleEndian).Encode(file)
+	if err != nil {
+		return fmt.Errorf("Encode: %v", err)
+	}
+	return nil
+}
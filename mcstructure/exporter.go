@@ -0,0 +1,300 @@
+package mcstructure
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+const (
+	// DefaultTimeoutSubChunkRequest 描述请求子区块的最长截止时间
+	DefaultTimeoutSubChunkRequest = time.Second * 5
+	// DefaultBlockVersion 是导出的调色板中每个方块的版本。
+	// 调色板中的方块状态总是来自当前的方块状态表，因此这
+	// 与 bedrock-world-operator 写入存档时使用的版本相同，
+	// 读取时这些方块状态不会被升级
+	DefaultBlockVersion int32 = chunk.CurrentBlockVersion
+)
+
+// ExportProgress 描述导出的进度
+type ExportProgress struct {
+	Done  int // 已导出的区块数量
+	Total int // 需要导出的区块总数
+}
+
+// ExportOptions 是导出 .mcstructure 结构时的可选项
+type ExportOptions struct {
+	// Dimension 是目标区域所在的维度
+	Dimension int32
+	// OnProgress 在每个区块导出后被调用，可以为空
+	OnProgress func(progress ExportProgress)
+}
+
+// Exporter 是基于子区块请求实现的 .mcstructure 结构导出器。
+//
+// 导出器逐个区块地向租赁服请求子区块，并从其中读取方块和方块
+// 实体数据。应当说明的是，目标区域必须处于机器人的视距之内，
+// 否则租赁服不会返回相应的子区块
+type Exporter struct {
+	api *game_interface.GameInterface
+}
+
+// NewExporter 基于 api 创建并返回一个新的结构导出器
+func NewExporter(api *game_interface.GameInterface) *Exporter {
+	return &Exporter{api: api}
+}
+
+// exportContext 是导出单个结构时的上下文
+type exportContext struct {
+	structure    *Structure
+	startPos     protocol.BlockPos
+	endPos       protocol.BlockPos
+	paletteIndex map[uint32]int32
+}
+
+// Export 导出 startPos 到 endPos 之间的区域，并返回对应的结构
+func (e *Exporter) Export(startPos protocol.BlockPos, endPos protocol.BlockPos, options ExportOptions) (result *Structure, err error) {
+	for axis := range 3 {
+		if startPos[axis] > endPos[axis] {
+			startPos[axis], endPos[axis] = endPos[axis], startPos[axis]
+		}
+	}
+
+	dimensionRange := define.Dimension(options.Dimension).Range()
+	if int(startPos[1]) < dimensionRange.Min() || int(endPos[1]) > dimensionRange.Max() {
+		return nil, fmt.Errorf(
+			"Export: The Y range [%d, %d] is out of the dimension range [%d, %d]",
+			startPos[1], endPos[1], dimensionRange.Min(), dimensionRange.Max(),
+		)
+	}
+
+	ctx := exportContext{
+		structure: NewStructure(protocol.BlockPos{
+			endPos[0] - startPos[0] + 1,
+			endPos[1] - startPos[1] + 1,
+			endPos[2] - startPos[2] + 1,
+		}),
+		startPos:     startPos,
+		endPos:       endPos,
+		paletteIndex: make(map[uint32]int32),
+	}
+	ctx.structure.Origin = startPos
+
+	progress := ExportProgress{
+		Total: int(endPos[0]>>4-startPos[0]>>4+1) * int(endPos[2]>>4-startPos[2]>>4+1),
+	}
+	for chunkX := startPos[0] >> 4; chunkX <= endPos[0]>>4; chunkX++ {
+		for chunkZ := startPos[2] >> 4; chunkZ <= endPos[2]>>4; chunkZ++ {
+			err = e.exportChunk(&ctx, options.Dimension, dimensionRange, chunkX, chunkZ)
+			if err != nil {
+				return nil, fmt.Errorf("Export: %v", err)
+			}
+			progress.Done++
+			if options.OnProgress != nil {
+				options.OnProgress(progress)
+			}
+		}
+	}
+
+	return ctx.structure, nil
+}
+
+// requestSubChunks 请求位于 (chunkX, chunkZ) 的区块中
+// 从 startSubY 到 endSubY 的全部子区块
+func (e *Exporter) requestSubChunks(
+	dimension int32,
+	chunkX int32,
+	chunkZ int32,
+	startSubY int32,
+	endSubY int32,
+) (resp *packet.SubChunk, err error) {
+	api := e.api
+	position := protocol.SubChunkPos{chunkX, startSubY, chunkZ}
+
+	doOnce := new(sync.Once)
+	channel := make(chan struct{})
+	uniqueID := api.PacketListener().ListenPacket(
+		[]uint32{packet.IDSubChunk},
+		func(p packet.Packet) {
+			pk := p.(*packet.SubChunk)
+			if pk.Dimension != dimension || pk.Position != position {
+				return
+			}
+			doOnce.Do(func() {
+				resp = pk
+				close(channel)
+			})
+		},
+	)
+	defer api.PacketListener().DestroyListener(uniqueID)
+
+	offsets := make([]protocol.SubChunkOffset, 0, endSubY-startSubY+1)
+	for subY := startSubY; subY <= endSubY; subY++ {
+		offsets = append(offsets, protocol.SubChunkOffset{0, int8(subY - startSubY), 0})
+	}
+	err = api.Resources().WritePacket(&packet.SubChunkRequest{
+		Dimension: dimension,
+		Position:  position,
+		Offsets:   offsets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("requestSubChunks: %v", err)
+	}
+
+	timer := time.NewTimer(DefaultTimeoutSubChunkRequest)
+	defer timer.Stop()
+	select {
+	case <-channel:
+	case <-timer.C:
+		return nil, fmt.Errorf("requestSubChunks: Request sub chunks of chunk (%d,%d) failed due to time out", chunkX, chunkZ)
+	}
+
+	return resp, nil
+}
+
+// exportChunk 导出位于 (chunkX, chunkZ) 的区块中处于目标区域的部分
+func (e *Exporter) exportChunk(
+	ctx *exportContext,
+	dimension int32,
+	dimensionRange define.Range,
+	chunkX int32,
+	chunkZ int32,
+) error {
+	startSubY, endSubY := ctx.startPos[1]>>4, ctx.endPos[1]>>4
+	resp, err := e.requestSubChunks(dimension, chunkX, chunkZ, startSubY, endSubY)
+	if err != nil {
+		return fmt.Errorf("exportChunk: %v", err)
+	}
+
+	for _, entry := range resp.SubChunkEntries {
+		subY := resp.Position[1] + int32(entry.Offset[1])
+		origin := protocol.BlockPos{chunkX << 4, subY << 4, chunkZ << 4}
+
+		switch entry.Result {
+		case protocol.SubChunkResultSuccessAllAir:
+			err = e.exportSubChunk(ctx, origin, chunk.NewSubChunk(block.AirRuntimeID))
+			if err != nil {
+				return fmt.Errorf("exportChunk: %v", err)
+			}
+			continue
+		case protocol.SubChunkResultSuccess:
+		default:
+			return fmt.Errorf(
+				"exportChunk: Sub chunk (%d,%d,%d) is not available (result = %d); make sure it is in the view of the bot",
+				chunkX, subY, chunkZ, entry.Result,
+			)
+		}
+
+		buf := bytes.NewBuffer(entry.RawPayload)
+		subChunk, _, err := chunk.DecodeSubChunk(buf, dimensionRange, chunk.NetworkEncoding)
+		if err != nil {
+			return fmt.Errorf("exportChunk: %v", err)
+		}
+		err = e.exportSubChunk(ctx, origin, subChunk)
+		if err != nil {
+			return fmt.Errorf("exportChunk: %v", err)
+		}
+
+		for buf.Len() > 0 {
+			var blockNBT map[string]any
+			err = nbt.NewDecoderWithEncoding(buf, nbt.NetworkLittleEndian).Decode(&blockNBT)
+			if err != nil {
+				return fmt.Errorf("exportChunk: %v", err)
+			}
+			e.exportBlockEntity(ctx, blockNBT)
+		}
+	}
+
+	return nil
+}
+
+// exportSubChunk 将原点为 origin 的子区块 subChunk
+// 中处于目标区域的方块写入结构
+func (e *Exporter) exportSubChunk(ctx *exportContext, origin protocol.BlockPos, subChunk *chunk.SubChunk) error {
+	for x := max(origin[0], ctx.startPos[0]); x <= min(origin[0]+15, ctx.endPos[0]); x++ {
+		for y := max(origin[1], ctx.startPos[1]); y <= min(origin[1]+15, ctx.endPos[1]); y++ {
+			for z := max(origin[2], ctx.startPos[2]); z <= min(origin[2]+15, ctx.endPos[2]); z++ {
+				index := ctx.structure.Index(protocol.BlockPos{
+					x - ctx.startPos[0],
+					y - ctx.startPos[1],
+					z - ctx.startPos[2],
+				})
+				for layer := range LayerCount {
+					runtimeID := subChunk.Block(byte(x-origin[0]), byte(y-origin[1]), byte(z-origin[2]), uint8(layer))
+					if layer != LayerPrimary && runtimeID == block.AirRuntimeID {
+						continue
+					}
+					paletteIndex, err := e.paletteIndex(ctx, runtimeID)
+					if err != nil {
+						return fmt.Errorf("exportSubChunk: Block at (%d,%d,%d) is unknown; err = %v", x, y, z, err)
+					}
+					ctx.structure.BlockIndices[layer][index] = paletteIndex
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// paletteIndex 返回运行时 ID 为 runtimeID 的方块在调色板中的索引。
+// 如果该方块尚不在调色板中，则将其添加到调色板。
+//
+// 运行时 ID 被视为方块的网络哈希。如果租赁服使用的运行时 ID
+// 与本地的方块状态表不一致，则找不到对应的方块，此时返回错误，
+// 而不是将其记录为未知方块
+func (e *Exporter) paletteIndex(ctx *exportContext, runtimeID uint32) (int32, error) {
+	if index, ok := ctx.paletteIndex[runtimeID]; ok {
+		return index, nil
+	}
+
+	name, states, found := block.RuntimeIDToState(runtimeID)
+	if !found {
+		return 0, fmt.Errorf("paletteIndex: Runtime ID %d is not in the block state table", runtimeID)
+	}
+
+	index := int32(len(ctx.structure.Palette))
+	ctx.structure.Palette = append(ctx.structure.Palette, PaletteBlock{
+		Name:    name,
+		States:  states,
+		Version: DefaultBlockVersion,
+	})
+	ctx.paletteIndex[runtimeID] = index
+	return index, nil
+}
+
+// exportBlockEntity 将方块实体数据 blockNBT 写入结构。
+// 如果该方块实体不在目标区域中，则不执行任何操作
+func (e *Exporter) exportBlockEntity(ctx *exportContext, blockNBT map[string]any) {
+	x, okX := blockNBT["x"].(int32)
+	y, okY := blockNBT["y"].(int32)
+	z, okZ := blockNBT["z"].(int32)
+	if !okX || !okY || !okZ {
+		return
+	}
+
+	pos := protocol.BlockPos{x, y, z}
+	for axis := range 3 {
+		if pos[axis] < ctx.startPos[axis] || pos[axis] > ctx.endPos[axis] {
+			return
+		}
+	}
+
+	index := ctx.structure.Index(protocol.BlockPos{
+		x - ctx.startPos[0],
+		y - ctx.startPos[1],
+		z - ctx.startPos[2],
+	})
+	ctx.structure.BlockPositionData[index] = map[string]any{
+		"block_entity_data": blockNBT,
+	}
+}
@@ -0,0 +1,193 @@
+package mcstructure
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// DefaultSyncInterval 是导入时默认的同步间隔。
+// 每放置这么多个位置的方块后，导入器都会等待
+// 租赁服处理完此前的全部命令，然后再报告进度
+const DefaultSyncInterval = 256
+
+// ImportProgress 描述导入的进度
+type ImportProgress struct {
+	// NextIndex 是下一个待导入的方块索引。
+	// 在此之前的所有方块都已被租赁服处理，
+	// 因此可以将其作为 ImportOptions 的
+	// StartIndex 以从此处继续导入
+	NextIndex int32
+	// Total 是该结构的方块索引总数
+	Total int32
+}
+
+// SkippedBlock 是导入时因无法被还原而没有被放置的方块
+type SkippedBlock struct {
+	// Index 是该方块的方块索引
+	Index int32
+	// Layer 是该方块所在的方块层
+	Layer int
+	// Block 是该方块本身
+	Block PaletteBlock
+}
+
+// ImportOptions 是导入 .mcstructure 结构时的可选项
+type ImportOptions struct {
+	// StartIndex 指示从哪个方块索引开始导入，
+	// 这被用于从中断处继续导入
+	StartIndex int32
+	// SyncInterval 是同步间隔，为 0 时使用 DefaultSyncInterval
+	SyncInterval int32
+	// PlaceAir 指示是否需要放置结构中的空气方块
+	PlaceAir bool
+	// OnProgress 在每次同步后被调用，可以为空
+	OnProgress func(progress ImportProgress)
+	// OnSkip 在方块因无法被还原而被跳过时被调用，可以为空
+	OnSkip func(block SkippedBlock)
+}
+
+// Importer 是基于 NBTAssigner 实现的 .mcstructure 结构导入器。
+//
+// 普通方块将通过 setblock 命令放置，而 NBT 方块则先在操作台
+// 制作，然后再通过结构加载到其最终位置。
+//
+// 应当说明的是，目标区域必须处于已加载的区块中
+type Importer struct {
+	api      *game_interface.GameInterface
+	assigner *nbt_assigner.NBTAssigner
+}
+
+// NewImporter 基于 api 和 assigner 创建并返回一个新的结构导入器
+func NewImporter(api *game_interface.GameInterface, assigner *nbt_assigner.NBTAssigner) *Importer {
+	return &Importer{api: api, assigner: assigner}
+}
+
+// Import 将 structure 导入到以 origin 为原点的区域中。
+//
+// 只有主方块层会被放置。setblock 和结构加载都会替换该位置上
+// 的全部方块层，因此副方块层 (通常是含水方块中的水) 无法被还原。
+// 它们将被跳过，并通过 ImportOptions 的 OnSkip 报告。
+//
+// 如果导入中途失败，返回的 nextIndex 指示下一个应当导入
+// 的方块索引，调用者可以将其作为 ImportOptions 的 StartIndex
+// 以继续导入
+func (i *Importer) Import(structure *Structure, origin protocol.BlockPos, options ImportOptions) (nextIndex int32, err error) {
+	total := structure.Volume()
+	syncInterval := options.SyncInterval
+	if syncInterval <= 0 {
+		syncInterval = DefaultSyncInterval
+	}
+
+	nextIndex = max(options.StartIndex, 0)
+	for index := nextIndex; index < total; index++ {
+		if (index-nextIndex) > 0 && (index-nextIndex)%syncInterval == 0 {
+			err = i.sync(index, total, options)
+			if err != nil {
+				return nextIndex, fmt.Errorf("Import: %v", err)
+			}
+			nextIndex = index
+		}
+
+		relative := structure.Position(index)
+		pos := protocol.BlockPos{
+			origin[0] + relative[0],
+			origin[1] + relative[1],
+			origin[2] + relative[2],
+		}
+
+		err = i.placeBlock(structure, index, pos, options.PlaceAir)
+		if err != nil {
+			return nextIndex, fmt.Errorf("Import: %v", err)
+		}
+		if block, exist := structure.Block(LayerSecondary, index); exist && options.OnSkip != nil {
+			options.OnSkip(SkippedBlock{Index: index, Layer: LayerSecondary, Block: block})
+		}
+	}
+
+	err = i.sync(total, total, options)
+	if err != nil {
+		return nextIndex, fmt.Errorf("Import: %v", err)
+	}
+	return total, nil
+}
+
+// sync 等待租赁服处理完此前的全部命令，然后报告进度
+func (i *Importer) sync(nextIndex int32, total int32, options ImportOptions) error {
+	err := i.api.Commands().AwaitChangesGeneral()
+	if err != nil {
+		return fmt.Errorf("sync: %v", err)
+	}
+	if options.OnProgress != nil {
+		options.OnProgress(ImportProgress{NextIndex: nextIndex, Total: total})
+	}
+	return nil
+}
+
+// placeBlock 将 structure 中主方块层索引为 index 的方块放置在 pos 处
+func (i *Importer) placeBlock(structure *Structure, index int32, pos protocol.BlockPos, placeAir bool) error {
+	block, exist := structure.Block(LayerPrimary, index)
+	if !exist {
+		return nil
+	}
+	switch block.Name {
+	case "minecraft:structure_void":
+		return nil
+	case "minecraft:air":
+		if !placeAir {
+			return nil
+		}
+	}
+
+	states := block.States
+	if states == nil {
+		states = make(map[string]any)
+	}
+
+	blockNBT, ok := structure.BlockEntityData(index)
+	if !ok {
+		err := i.api.SetBlock().SetBlockAsync(pos, block.Name, utils.MarshalBlockStates(states))
+		if err != nil {
+			return fmt.Errorf("placeBlock: %v", err)
+		}
+		return nil
+	}
+
+	result := i.assigner.PlaceNBTBlocks([]nbt_assigner.NBTBlock{{
+		BlockName:   block.Name,
+		BlockStates: states,
+		BlockNBT:    blockNBT,
+	}})[0]
+	if result.Err != nil {
+		return fmt.Errorf("placeBlock: Failed to place NBT block %#v at index %d; err = %v", block.Name, index, result.Err)
+	}
+	// 床尾等方块随其相邻的方块一同被放置。
+	// 如果相邻的方块已被放置，那么再次放置
+	// 这个方块将覆盖它，因此总是跳过它
+	if result.PlacedByNeighbor {
+		return nil
+	}
+	if result.CanFast {
+		err := i.api.SetBlock().SetBlockAsync(pos, block.Name, utils.MarshalBlockStates(states))
+		if err != nil {
+			return fmt.Errorf("placeBlock: %v", err)
+		}
+		return nil
+	}
+
+	// 由 assigner 保存的结构以 NBT 方块及其偏移处方块
+	// 二者中坐标较小的一方为起点，因此需要从那里加载
+	loadPos := pos
+	for axis := range 3 {
+		loadPos[axis] += min(result.Offset[axis], 0)
+	}
+	err := i.api.StructureBackup().RevertStructure(result.UniqueID, loadPos)
+	if err != nil {
+		return fmt.Errorf("placeBlock: %v", err)
+	}
+
+	return nil
+}
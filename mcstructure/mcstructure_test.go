@@ -0,0 +1,110 @@
+package mcstructure
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+)
+
+// newTestStructure 返回一个包含普通方块、含水方块和 NBT 方块的结构
+func newTestStructure() *Structure {
+	structure := NewStructure(protocol.BlockPos{2, 1, 2})
+	structure.Origin = protocol.BlockPos{-16, 64, 32}
+	structure.Palette = []PaletteBlock{
+		{Name: "minecraft:stone", States: map[string]any{}, Version: 18163713},
+		{Name: "minecraft:oak_fence", States: map[string]any{}, Version: 18163713},
+		{Name: "minecraft:water", States: map[string]any{"liquid_depth": int32(0)}, Version: 18163713},
+		{
+			Name:    "minecraft:chest",
+			States:  map[string]any{"minecraft:cardinal_direction": "north"},
+			Version: 18163713,
+		},
+	}
+
+	structure.BlockIndices[LayerPrimary][0] = 0
+	structure.BlockIndices[LayerPrimary][1] = 1
+	structure.BlockIndices[LayerSecondary][1] = 2
+	structure.BlockIndices[LayerPrimary][3] = 3
+	structure.BlockPositionData[3] = map[string]any{
+		"block_entity_data": map[string]any{
+			"id":         "Chest",
+			"CustomName": "test",
+			"Items": []any{
+				map[string]any{
+					"Name":   "minecraft:apple",
+					"Count":  byte(3),
+					"Damage": int16(0),
+					"Slot":   byte(5),
+				},
+			},
+		},
+	}
+	structure.Entities = []map[string]any{}
+
+	return structure
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	structure := newTestStructure()
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, structure); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, structure) {
+		t.Fatalf("decoded structure differs from the original one\ngot:  %#v\nwant: %#v", decoded, structure)
+	}
+
+	// 复合标签的编码顺序是不确定的，
+	// 因此只比较再次解码后的结构
+	buf.Reset()
+	if err = Encode(&buf, decoded); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decodedAgain, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(decodedAgain, structure) {
+		t.Fatalf("structure differs from the original one after two round trips")
+	}
+}
+
+func TestDecodeKeepsWaterlogging(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, newTestStructure()); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	primary, exist := decoded.Block(LayerPrimary, 1)
+	if !exist || primary.Name != "minecraft:oak_fence" {
+		t.Fatalf("primary layer at index 1 = %#v (exist = %v), want minecraft:oak_fence", primary, exist)
+	}
+	secondary, exist := decoded.Block(LayerSecondary, 1)
+	if !exist || secondary.Name != "minecraft:water" {
+		t.Fatalf("secondary layer at index 1 = %#v (exist = %v), want minecraft:water", secondary, exist)
+	}
+	if _, exist = decoded.Block(LayerSecondary, 0); exist {
+		t.Fatalf("secondary layer at index 0 should be empty")
+	}
+}
+
+func TestPositionIndexRoundTrip(t *testing.T) {
+	structure := NewStructure(protocol.BlockPos{3, 4, 5})
+	for index := range structure.Volume() {
+		if got := structure.Index(structure.Position(index)); got != index {
+			t.Fatalf("Index(Position(%d)) = %d", index, got)
+		}
+	}
+}
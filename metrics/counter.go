@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter 是只增不减的计数器
+type Counter struct {
+	bits atomic.Uint64
+}
+
+// Inc 将计数器加一
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add 将计数器加上 delta。delta 不得为负数
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		panic("Add: Counter can not decrease")
+	}
+	for {
+		old := c.bits.Load()
+		if c.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+// Value 返回计数器当前的值
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(c.bits.Load())
+}
+
+// CounterVec 是按标签区分的一组计数器
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu       *sync.Mutex
+	counters map[string]*Counter
+	values   map[string][]string
+}
+
+// NewCounterVec 创建一组标签名称为 labelNames 的计数器，
+// 并将其注册到 DefaultRegistry。name 不需要包含 Namespace
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		name:       Namespace + name,
+		help:       help,
+		labelNames: labelNames,
+		mu:         new(sync.Mutex),
+		counters:   make(map[string]*Counter),
+		values:     make(map[string][]string),
+	}
+	DefaultRegistry.MustRegister(c)
+	return c
+}
+
+// WithLabelValues 返回标签值为 labelValues 的计数器。
+// labelValues 的数量必须与标签名称的数量相同
+func (c *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	if len(labelValues) != len(c.labelNames) {
+		panic(fmt.Sprintf("WithLabelValues: Metric %#v expects %d label values, but got %d", c.name, len(c.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter, ok := c.counters[key]
+	if !ok {
+		counter = new(Counter)
+		c.counters[key] = counter
+		c.values[key] = slices.Clone(labelValues)
+	}
+	return counter
+}
+
+// Name ..
+func (c *CounterVec) Name() string {
+	return c.name
+}
+
+// Help ..
+func (c *CounterVec) Help() string {
+	return c.help
+}
+
+// Type ..
+func (c *CounterVec) Type() string {
+	return "counter"
+}
+
+// samples ..
+func (c *CounterVec) samples() []sample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.counters))
+	for key := range c.counters {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	result := make([]sample, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, sample{
+			labelNames:  c.labelNames,
+			labelValues: c.values[key],
+			value:       c.counters[key].Value(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package metrics
+
+// GaugeFunc 是在每次导出时通过函数取值的仪表
+type GaugeFunc struct {
+	name     string
+	help     string
+	function func() float64
+}
+
+// NewGaugeFunc 创建一个在导出时调用 function 取值的仪表，
+// 并将其注册到 DefaultRegistry。name 不需要包含 Namespace
+func NewGaugeFunc(name string, help string, function func() float64) *GaugeFunc {
+	g := &GaugeFunc{
+		name:     Namespace + name,
+		help:     help,
+		function: function,
+	}
+	DefaultRegistry.MustRegister(g)
+	return g
+}
+
+// Name ..
+func (g *GaugeFunc) Name() string {
+	return g.name
+}
+
+// Help ..
+func (g *GaugeFunc) Help() string {
+	return g.help
+}
+
+// Type ..
+func (g *GaugeFunc) Type() string {
+	return "gauge"
+}
+
+// samples ..
+func (g *GaugeFunc) samples() []sample {
+	return []sample{{value: g.function()}}
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets 是以秒为单位的默认桶上界，
+// 它覆盖了从单条命令到整个 NBT 方块制作的耗时
+var DefaultBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5,
+	1, 2.5, 5, 10, 30, 60,
+}
+
+// Histogram 是对观测值进行分桶统计的直方图
+type Histogram struct {
+	mu      *sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe 记录一个观测值 value
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for index, upperBound := range h.buckets {
+		if value <= upperBound {
+			h.counts[index]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// ObserveSince 记录从 start 至今经过的秒数
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// HistogramVec 是按标签区分的一组直方图
+type HistogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+
+	mu         *sync.Mutex
+	histograms map[string]*Histogram
+	values     map[string][]string
+}
+
+// NewHistogramVec 创建一组标签名称为 labelNames 的直方图，
+// 并将其注册到 DefaultRegistry。name 不需要包含 Namespace。
+// 如果 buckets 为空，则使用 DefaultBuckets
+func NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	h := &HistogramVec{
+		name:       Namespace + name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    buckets,
+		mu:         new(sync.Mutex),
+		histograms: make(map[string]*Histogram),
+		values:     make(map[string][]string),
+	}
+	DefaultRegistry.MustRegister(h)
+	return h
+}
+
+// WithLabelValues 返回标签值为 labelValues 的直方图。
+// labelValues 的数量必须与标签名称的数量相同
+func (h *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	if len(labelValues) != len(h.labelNames) {
+		panic(fmt.Sprintf("WithLabelValues: Metric %#v expects %d label values, but got %d", h.name, len(h.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	histogram, ok := h.histograms[key]
+	if !ok {
+		histogram = &Histogram{
+			mu:      new(sync.Mutex),
+			buckets: h.buckets,
+			counts:  make([]uint64, len(h.buckets)),
+		}
+		h.histograms[key] = histogram
+		h.values[key] = slices.Clone(labelValues)
+	}
+	return histogram
+}
+
+// Name ..
+func (h *HistogramVec) Name() string {
+	return h.name
+}
+
+// Help ..
+func (h *HistogramVec) Help() string {
+	return h.help
+}
+
+// Type ..
+func (h *HistogramVec) Type() string {
+	return "histogram"
+}
+
+// samples ..
+func (h *HistogramVec) samples() []sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.histograms))
+	for key := range h.histograms {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	bucketLabelNames := append(slices.Clone(h.labelNames), "le")
+	result := make([]sample, 0)
+
+	for _, key := range keys {
+		histogram := h.histograms[key]
+		labelValues := h.values[key]
+
+		histogram.mu.Lock()
+		for index, upperBound := range histogram.buckets {
+			result = append(result, sample{
+				suffix:      "_bucket",
+				labelNames:  bucketLabelNames,
+				labelValues: append(slices.Clone(labelValues), formatFloat(upperBound)),
+				value:       float64(histogram.counts[index]),
+			})
+		}
+		result = append(result,
+			sample{
+				suffix:      "_bucket",
+				labelNames:  bucketLabelNames,
+				labelValues: append(slices.Clone(labelValues), formatFloat(math.Inf(1))),
+				value:       float64(histogram.count),
+			},
+			sample{
+				suffix:      "_sum",
+				labelNames:  h.labelNames,
+				labelValues: labelValues,
+				value:       histogram.sum,
+			},
+			sample{
+				suffix:      "_count",
+				labelNames:  h.labelNames,
+				labelValues: labelValues,
+				value:       float64(histogram.count),
+			},
+		)
+		histogram.mu.Unlock()
+	}
+
+	return result
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType 是 Prometheus 文本格式的 MIME 类型
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Namespace 是本模块所有指标名称的前缀
+const Namespace = "flowers_for_machines_"
+
+// sample 是指标的一个样本
+type sample struct {
+	suffix      string   // 样本名称相对于指标名称的后缀
+	labelNames  []string // 样本的标签名称
+	labelValues []string // 样本的标签值
+	value       float64  // 样本的值
+}
+
+// Collector 是可以被导出为 Prometheus 文本格式的指标
+type Collector interface {
+	// Name 返回这个指标的名称
+	Name() string
+	// Help 返回这个指标的说明
+	Help() string
+	// Type 返回这个指标的类型，
+	// 例如 counter, gauge 或 histogram
+	Type() string
+	// samples 返回这个指标当前的全部样本
+	samples() []sample
+}
+
+// Registry 是指标的注册表
+type Registry struct {
+	mu         *sync.Mutex
+	collectors map[string]Collector
+}
+
+// DefaultRegistry 是默认的指标注册表，
+// 由 NewCounterVec 等函数创建的指标都会被注册到这里
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry 创建并返回一个新的指标注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		mu:         new(sync.Mutex),
+		collectors: make(map[string]Collector),
+	}
+}
+
+// MustRegister 将 collector 注册到注册表中。
+// 如果已经存在同名的指标，则 MustRegister 将会惊慌
+func (r *Registry) MustRegister(collector Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.collectors[collector.Name()]; ok {
+		panic(fmt.Sprintf("MustRegister: Metric %#v is already registered", collector.Name()))
+	}
+	r.collectors[collector.Name()] = collector
+}
+
+// WriteText 将注册表中的全部指标以 Prometheus 文本格式写入 w。
+// 指标按名称升序排列，这使得输出是稳定的
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := make([]Collector, 0, len(r.collectors))
+	for _, collector := range r.collectors {
+		collectors = append(collectors, collector)
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(collectors, func(x, y Collector) int {
+		return strings.Compare(x.Name(), y.Name())
+	})
+
+	writer := bufio.NewWriter(w)
+	for _, collector := range collectors {
+		fmt.Fprintf(writer, "# HELP %s %s\n", collector.Name(), escapeHelp(collector.Help()))
+		fmt.Fprintf(writer, "# TYPE %s %s\n", collector.Name(), collector.Type())
+		for _, s := range collector.samples() {
+			writer.WriteString(collector.Name() + s.suffix)
+			writeLabels(writer, s.labelNames, s.labelValues)
+			writer.WriteString(" " + formatFloat(s.value) + "\n")
+		}
+	}
+
+	err := writer.Flush()
+	if err != nil {
+		return fmt.Errorf("WriteText: %v", err)
+	}
+	return nil
+}
+
+// writeLabels 将标签写入 w。如果没有标签，则不写入任何内容
+func writeLabels(w *bufio.Writer, labelNames []string, labelValues []string) {
+	if len(labelNames) == 0 {
+		return
+	}
+	w.WriteString("{")
+	for index, name := range labelNames {
+		if index > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString(name + `="` + escapeLabelValue(labelValues[index]) + `"`)
+	}
+	w.WriteString("}")
+}
+
+// escapeHelp 转义指标说明中的反斜杠和换行符
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue 转义标签值中的反斜杠、双引号和换行符
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatFloat 将 value 格式化为 Prometheus 文本格式中的数值
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// writeText 返回仅注册了 collectors 的注册表的文本格式输出
+func writeText(t *testing.T, collectors ...Collector) string {
+	t.Helper()
+
+	r := NewRegistry()
+	for _, collector := range collectors {
+		r.MustRegister(collector)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := r.WriteText(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestWriteTextCounter(t *testing.T) {
+	c := NewCounterVec("test_counter_total", "Counter\nwith a \\ backslash", "kind", "result")
+	c.WithLabelValues("chest", "ok").Add(2)
+	c.WithLabelValues(`a"b`, "c\\d\ne").Inc()
+
+	want := `# HELP flowers_for_machines_test_counter_total Counter\nwith a \\ backslash
+# TYPE flowers_for_machines_test_counter_total counter
+flowers_for_machines_test_counter_total{kind="a\"b",result="c\\d\ne"} 1
+flowers_for_machines_test_counter_total{kind="chest",result="ok"} 2
+`
+	if got := writeText(t, c); got != want {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestWriteTextGauge(t *testing.T) {
+	g := NewGaugeFunc("test_gauge", "Gauge", func() float64 { return 1.5 })
+	inf := NewGaugeFunc("test_gauge_inf", "Infinite gauge", func() float64 { return math.Inf(-1) })
+
+	// 指标按名称升序排列
+	want := `# HELP flowers_for_machines_test_gauge Gauge
+# TYPE flowers_for_machines_test_gauge gauge
+flowers_for_machines_test_gauge 1.5
+# HELP flowers_for_machines_test_gauge_inf Infinite gauge
+# TYPE flowers_for_machines_test_gauge_inf gauge
+flowers_for_machines_test_gauge_inf -Inf
+`
+	if got := writeText(t, inf, g); got != want {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestWriteTextHistogram(t *testing.T) {
+	h := NewHistogramVec("test_seconds", "Histogram", []float64{1, 0.5}, "kind")
+	histogram := h.WithLabelValues("bed")
+	histogram.Observe(0.25)
+	histogram.Observe(0.75)
+	histogram.Observe(3)
+
+	// 桶上界被排序，并且每个桶都是累积的
+	want := `# HELP flowers_for_machines_test_seconds Histogram
+# TYPE flowers_for_machines_test_seconds histogram
+flowers_for_machines_test_seconds_bucket{kind="bed",le="0.5"} 1
+flowers_for_machines_test_seconds_bucket{kind="bed",le="1"} 2
+flowers_for_machines_test_seconds_bucket{kind="bed",le="+Inf"} 3
+flowers_for_machines_test_seconds_sum{kind="bed"} 4
+flowers_for_machines_test_seconds_count{kind="bed"} 3
+`
+	if got := writeText(t, h); got != want {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestHistogramDefaultBuckets(t *testing.T) {
+	h := NewHistogramVec("test_default_seconds", "Histogram", nil)
+	h.WithLabelValues().Observe(100)
+
+	samples := h.samples()
+	if len(samples) != len(DefaultBuckets)+3 {
+		t.Fatalf("unexpected sample count %d", len(samples))
+	}
+	last := samples[len(DefaultBuckets)]
+	if last.labelValues[0] != "+Inf" || last.value != 1 || samples[0].value != 0 {
+		t.Fatalf("unexpected samples %#v", samples)
+	}
+}
+
+func TestMustRegisterDuplicate(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(&GaugeFunc{name: "duplicate"})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustRegister should panic on duplicate names")
+		}
+	}()
+	r.MustRegister(&GaugeFunc{name: "duplicate"})
+}
+
+func TestWithLabelValuesMismatch(t *testing.T) {
+	c := NewCounterVec("test_mismatch_total", "Counter", "kind")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("WithLabelValues should panic on label count mismatch")
+		}
+	}()
+	c.WithLabelValues("a", "b")
+}
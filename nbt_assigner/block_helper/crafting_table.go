@@ -0,0 +1,19 @@
+package block_helper
+
+type CraftingTableBlockHelper struct{}
+
+func (CraftingTableBlockHelper) KnownBlockStates() bool {
+	return true
+}
+
+func (CraftingTableBlockHelper) BlockName() string {
+	return "minecraft:crafting_table"
+}
+
+func (CraftingTableBlockHelper) BlockStates() map[string]any {
+	return map[string]any{}
+}
+
+func (CraftingTableBlockHelper) BlockStatesString() string {
+	return `[]`
+}
@@ -0,0 +1,19 @@
+package block_helper
+
+type SmithingTableBlockHelper struct{}
+
+func (SmithingTableBlockHelper) KnownBlockStates() bool {
+	return true
+}
+
+func (SmithingTableBlockHelper) BlockName() string {
+	return "minecraft:smithing_table"
+}
+
+func (SmithingTableBlockHelper) BlockStates() map[string]any {
+	return map[string]any{}
+}
+
+func (SmithingTableBlockHelper) BlockStatesString() string {
+	return `[]`
+}
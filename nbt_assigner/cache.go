@@ -0,0 +1,79 @@
+package nbt_assigner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+)
+
+// ListCache 列出缓存命中系统中的全部基容器和 NBT 方块缓存，
+// 返回的缓存按创建时间升序排列。
+// ListCache 是阻塞的，它会等待正在进行的制作完成
+func (n *NBTAssigner) ListCache() []nbt_cache.CacheEntry {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.cache.ListCache()
+}
+
+// EvictCache 驱逐种类为 kind 且哈希校验和为 hashNumber 的缓存，
+// 并删除保存该缓存的结构。evicted 指示该缓存是否存在。
+//
+// 应当说明的是，先前由 PlaceNBTBlock 返回的结构可能正是
+// 被驱逐的缓存，因此使用者不应再引用这些结构
+func (n *NBTAssigner) EvictCache(kind string, hashNumber uint64) (evicted bool, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	evicted, err = n.cache.EvictCache(kind, hashNumber)
+	n.cache.RequestSync()
+	if err != nil {
+		return false, fmt.Errorf("EvictCache: %v", err)
+	}
+	return evicted, nil
+}
+
+// EvictCacheOlderThan 驱逐创建时间早于 maxAge 之前的全部缓存。
+// evicted 指示被驱逐的缓存的数量，即便发生错误，它也是有效的
+func (n *NBTAssigner) EvictCacheOlderThan(maxAge time.Duration) (evicted int, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	evicted, err = n.cache.EvictCacheOlderThan(maxAge)
+	n.cache.RequestSync()
+	if err != nil {
+		return evicted, fmt.Errorf("EvictCacheOlderThan: %v", err)
+	}
+	return evicted, nil
+}
+
+// EvictCacheLRU 按最近最少使用的顺序驱逐缓存，直到剩余的缓存
+// 不多于 keep 条。evicted 指示被驱逐的缓存的数量，
+// 即便发生错误，它也是有效的
+func (n *NBTAssigner) EvictCacheLRU(keep int) (evicted int, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	evicted, err = n.cache.EvictCacheLRU(keep)
+	n.cache.RequestSync()
+	if err != nil {
+		return evicted, fmt.Errorf("EvictCacheLRU: %v", err)
+	}
+	return evicted, nil
+}
+
+// PurgeCache 驱逐全部基容器和 NBT 方块缓存，并删除保存它们的结构。
+// evicted 指示被驱逐的缓存的数量，即便发生错误，它也是有效的。
+//
+// 已登记的地图不会被清除
+func (n *NBTAssigner) PurgeCache() (evicted int, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	evicted, err = n.cache.PurgeCache()
+	n.cache.RequestSync()
+	if err != nil {
+		return evicted, fmt.Errorf("PurgeCache: %v", err)
+	}
+	return evicted, nil
+}
@@ -0,0 +1,133 @@
+package nbt_assigner
+
+import (
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// parseBlock 使用 nameChecker 解析 NBT 方块。
+//
+// 未通过 RegisterFilledMaps 登记的地图无法被复制，
+// 因此它们将从方块实体数据中移除，而方块的其余部分
+// 仍会被正常制作。每个被移除的地图都会以原因
+// DropReasonFilledMapNotRegistered 通过 onDrop
+// 报告。onDrop 可以为空
+func (n *NBTAssigner) parseBlock(
+	nameChecker func(name string) bool,
+	blockName string,
+	blockStates map[string]any,
+	blockNBT map[string]any,
+	onDrop func(item DroppedItem),
+) (nbtBlock nbt_parser_interface.Block, err error) {
+	nbtBlock, err = nbt_parser_interface.ParseBlock(nameChecker, blockName, blockStates, blockNBT)
+	if err != nil {
+		return nil, err
+	}
+
+	newBlockNBT := utils.DeepCopyNBT(blockNBT)
+	if !n.dropUnregisteredFilledMaps(nbtBlock.BlockName(), newBlockNBT, nil, onDrop) {
+		return nbtBlock, nil
+	}
+	return nbt_parser_interface.ParseBlock(nameChecker, blockName, blockStates, newBlockNBT)
+}
+
+// filledMapNotRegistered 检查 itemMap 是否是
+// 没有通过 RegisterFilledMaps 登记的地图
+func (n *NBTAssigner) filledMapNotRegistered(itemMap map[string]any) bool {
+	item, _, err := nbt_parser_interface.ParseItemNormal(nil, itemMap)
+	if err != nil {
+		return false
+	}
+	filledMap, ok := item.(*nbt_parser_item.FilledMap)
+	if !ok || !filledMap.IsComplex() {
+		return false
+	}
+	return !n.cache.FilledMapCache().CheckCache(filledMap.NBT.MapUUID)
+}
+
+// dropUnregisteredFilledMaps 就地移除名为 blockName 的方块在其方块实体数据
+// blockNBT 中所装有的，没有通过 RegisterFilledMaps 登记的地图。嵌套在其他
+// 容器中的地图也会被移除。如果 blockNBT 被修改，则返回真。
+//
+// path 是这个方块所在的槽位路径，每个被移除的地图都会通过 onDrop 报告
+func (n *NBTAssigner) dropUnregisteredFilledMaps(
+	blockName string,
+	blockNBT map[string]any,
+	path []uint8,
+	onDrop func(item DroppedItem),
+) (changed bool) {
+	// keep 检查槽位 slot 处的 value 所指示的物品是否
+	// 应当保留，并递归地处理该物品所装有的物品
+	keep := func(value any, slot uint8) bool {
+		itemMap, ok := value.(map[string]any)
+		if !ok {
+			return true
+		}
+		itemPath := append(append([]uint8(nil), path...), slot)
+		itemName, _ := itemMap["Name"].(string)
+
+		if n.filledMapNotRegistered(itemMap) {
+			changed = true
+			if onDrop != nil {
+				onDrop(DroppedItem{
+					Path:     itemPath,
+					ItemName: itemName,
+					Reason:   DropReasonFilledMapNotRegistered,
+				})
+			}
+			return false
+		}
+
+		subBlockName, ok := mapping.ItemNameToBlockName[itemName]
+		if !ok {
+			return true
+		}
+		tag, _ := itemMap["tag"].(map[string]any)
+		if !nbt_parser_item.HaveSubBlockData(tag) {
+			return true
+		}
+		if n.dropUnregisteredFilledMaps(subBlockName, tag, itemPath, onDrop) {
+			changed = true
+		}
+		return true
+	}
+
+	switch mapping.SupportBlocksPool[blockName] {
+	case mapping.SupportNBTBlockTypeFrame:
+		if !keep(blockNBT["Item"], 0) {
+			delete(blockNBT, "Item")
+		}
+	case mapping.SupportNBTBlockTypeDecoratedPot:
+		if !keep(blockNBT["item"], 0) {
+			delete(blockNBT, "item")
+		}
+	case mapping.SupportNBTBlockTypeContainer,
+		mapping.SupportNBTBlockTypeCrafter,
+		mapping.SupportNBTBlockTypeBrewingStand:
+		key, ok := mapping.ContainerStorageKey[blockName]
+		if !ok {
+			key = "Items"
+		}
+		if item, ok := blockNBT[key].(map[string]any); ok {
+			slot, _ := item["Slot"].(byte)
+			if !keep(item, slot) {
+				delete(blockNBT, key)
+			}
+		}
+		if list, ok := blockNBT[key].([]any); ok {
+			newList := make([]any, 0, len(list))
+			for _, value := range list {
+				item, _ := value.(map[string]any)
+				slot, _ := item["Slot"].(byte)
+				if keep(value, slot) {
+					newList = append(newList, value)
+				}
+			}
+			blockNBT[key] = newList
+		}
+	}
+
+	return
+}
@@ -29,11 +29,21 @@ var (
 	// 将它们进行集中性的物品附魔和物品改名处理。应当说明的是，这些物品应当置于非快捷栏的物品栏，
 	// 并且对于无需处理的物品，应当简单的置为 nil
 	EnchAndRenameMultiple func(console *nbt_console.Console, multipleItems [27]*nbt_parser_interface.Item) error
+	// MakeDecoratedPot 根据操作台 console，合成一个后、左、右和前四个面
+	// 依次使用 sherds 所指示的陶片的饰纹陶罐。resultSlot 指示其在背包中的位置
+	MakeDecoratedPot func(console *nbt_console.Console, sherds [4]string) (resultSlot resources_control.SlotID, err error)
 )
 
 var (
 	// NBTBlockIsSupported 检查 block 是否是受支持的 NBT 方块
 	NBTBlockIsSupported func(block nbt_parser_interface.Block) bool
+	// NBTBlockDroppedFields 返回 block 的方块实体数据
+	// 或方块状态中，在制作时无法被还原的字段的名称
+	NBTBlockDroppedFields func(block nbt_parser_interface.Block) []string
+	// NBTBlockPlacedByNeighbor 检查 block 是否总是随其相邻的方块
+	// 一同被制作，例如床尾总是随床头一同被制作。
+	// 这样的方块不应被单独放置，否则它将覆盖已放置的相邻方块
+	NBTBlockPlacedByNeighbor func(block nbt_parser_interface.Block) bool
 	// PlaceNBTBlock 根据传入的操作台和缓存命中系统，
 	// 在操作台的中心方块处制作一个 NBT 方块 nbtBlock。
 	//
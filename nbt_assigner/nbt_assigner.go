@@ -1,6 +1,8 @@
 package nbt_assigner
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -8,6 +10,7 @@ import (
 	nbt_assigner_interface "github.com/OmineDev/flowers-for-machines/nbt_assigner/interface"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
 	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
 
 	"github.com/google/uuid"
@@ -18,11 +21,48 @@ import (
 	_ "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
 )
 
+// ErrParseNBTBlock 指示 NBT 方块无法被解析，
+// 这通常是因为请求者给出的方块数据有误
+var ErrParseNBTBlock = errors.New("failed to parse the NBT block")
+
+// NBTBlock 描述了一个待放置的 NBT 方块
+type NBTBlock struct {
+	BlockName   string         // 这个 NBT 方块的方块名称
+	BlockStates map[string]any // 这个 NBT 方块的方块状态
+	BlockNBT    map[string]any // 这个 NBT 方块的方块实体数据
+}
+
+// PlaceNBTBlockResult 是放置单个 NBT 方块的结果，
+// 其各字段的含义与 PlaceNBTBlock 的返回值相同
+type PlaceNBTBlockResult struct {
+	CanFast  bool
+	UniqueID uuid.UUID
+	Offset   protocol.BlockPos
+	// PlacedByNeighbor 指示这个方块总是随其相邻的方块一同被制作，
+	// 例如床尾总是随床头一同被制作。此时 CanFast 为假，UniqueID
+	// 为空，调用者不应放置这个方块，否则它将覆盖已放置的相邻方块
+	PlacedByNeighbor bool
+	// DroppedFields 是这个方块的方块实体数据或方块状态中，
+	// 在制作时没有被还原的字段的名称，它与
+	// ValidateNBTBlock 所报告的相同
+	DroppedFields []string
+	// DroppedItems 和 LossyItems 分别是这个方块所装有的，
+	// 没有被还原的物品和只被近似还原的物品，它们与
+	// ValidateNBTBlock 所报告的相同
+	DroppedItems []DroppedItem
+	LossyItems   []DroppedItem
+	Err          error
+}
+
 // NBTAssigner 是封装好的 NBT 方块放置实现
 type NBTAssigner struct {
-	mu      *sync.Mutex
-	console *nbt_console.Console
-	cache   *nbt_cache.NBTCacheSystem
+	mu *sync.Mutex
+	// consoleMu 保护对 console 的更换，
+	// 从而使 ValidateNBTBlock 无需持有 mu
+	// 即可读取 console
+	consoleMu *sync.RWMutex
+	console   *nbt_console.Console
+	cache     *nbt_cache.NBTCacheSystem
 }
 
 // NewNBTAssigner 基于操作台和缓存命中系统创建并返回一个新的 NBT 方块放置实现。
@@ -33,18 +73,34 @@ func NewNBTAssigner(
 	cache *nbt_cache.NBTCacheSystem,
 ) *NBTAssigner {
 	return &NBTAssigner{
-		mu:      new(sync.Mutex),
-		console: console,
-		cache:   cache,
+		mu:        new(sync.Mutex),
+		consoleMu: new(sync.RWMutex),
+		console:   console,
+		cache:     cache,
 	}
 }
 
+// SetConsole 将 NBT 方块放置实现所使用的操作台更换为 console，
+// 并将已有的缓存命中系统重新附加到 console 上。
+//
+// 它通常在机器人重新连接到租赁服后被调用。SetConsole 会等待
+// 正在进行的制作完成，然后才更换操作台
+func (n *NBTAssigner) SetConsole(console *nbt_console.Console) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.consoleMu.Lock()
+	n.console = console
+	n.consoleMu.Unlock()
+	n.cache.SetConsole(console)
+}
+
 // PlaceNBTBlock 试图制作一个新的 NBT 方块，
 // 制作位置是在操作台的中心方块处。
 //
 // blockName 是要制作的 NBT 方块的方块名称；
 // blockStates 是要制作的 NBT 方块的方块状态；
 // blockNBT 是要制作的 NBT 方块的方块实体数据。
+// 其中没有通过 RegisterFilledMaps 登记的地图将被丢弃。
 //
 // canFast 指示目标方块是否可以直接通过 setblock 放置。
 //
@@ -52,6 +108,11 @@ func NewNBTAssigner(
 // 方块所在结构的唯一标识，并且 offset 指示其相邻的可能
 // 的方块，例如床的尾方块相对于头方块的偏移。
 //
+// 随相邻方块一同被制作的方块 (例如床尾) 不会被制作，
+// 此时 canFast 为假且 uniqueID 为空。如果需要识别
+// 这种情况，请使用 PlaceNBTBlocks 并检查其结果的
+// PlacedByNeighbor 字段。
+//
 // PlaceNBTBlock 是阻塞的，它保证同一时刻只会制作一个
 // NBT 方块
 func (n *NBTAssigner) PlaceNBTBlock(blockName string, blockStates map[string]any, blockNBT map[string]any) (
@@ -60,19 +121,184 @@ func (n *NBTAssigner) PlaceNBTBlock(blockName string, blockStates map[string]any
 	offset protocol.BlockPos,
 	err error,
 ) {
-	nbtBlock, err := nbt_parser_interface.ParseBlock(
-		n.console.API().Resources().ConstantPacket().ItemCanGetByCommand,
-		blockName,
-		blockStates,
-		blockNBT,
-	)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nameChecker := n.console.API().Resources().ConstantPacket().ItemCanGetByCommand
+	nbtBlock, err := n.parseBlock(nameChecker, blockName, blockStates, blockNBT, nil)
 	if err != nil {
-		return false, uuid.UUID{}, protocol.BlockPos{}, fmt.Errorf("PlaceNBTBlock: %v", err)
+		return false, uuid.UUID{}, protocol.BlockPos{}, fmt.Errorf("PlaceNBTBlock: %w; err = %v", ErrParseNBTBlock, err)
+	}
+	if nbt_assigner_interface.NBTBlockPlacedByNeighbor(nbtBlock) {
+		return false, uuid.UUID{}, protocol.BlockPos{}, nil
 	}
 
+	n.cache.BeginUse()
+	canFast, uniqueID, offset, err = nbt_assigner_interface.PlaceNBTBlock(n.console, n.cache, nbtBlock)
+	n.cache.EndUse()
+	n.cache.RequestSync()
+	return
+}
+
+// PlaceNBTBlocks 按顺序制作 blocks 中的每个 NBT 方块，
+// 并返回与 blocks 一一对应的放置结果。
+//
+// 在制作前，PlaceNBTBlocks 会按 NBT 方块的完整哈希校验和
+// 对 blocks 去重，这意味着相同的方块只会被制作一次，并且
+// 它们将共享同一个结构。
+//
+// 解析失败的方块不会影响其他方块的制作，其错误将被记录在
+// 相应结果的 Err 字段中，并且可以通过 ErrParseNBTBlock 识别。
+//
+// PlaceNBTBlocks 是阻塞的，它在整个批次的制作过程中
+// 独占 NBTAssigner
+func (n *NBTAssigner) PlaceNBTBlocks(blocks []NBTBlock) (results []PlaceNBTBlockResult) {
+	return n.PlaceNBTBlocksWithProgress(blocks, nil)
+}
+
+// PlaceNBTBlocksWithProgress 与 PlaceNBTBlocks 相同，
+// 但每得到一个放置结果 result 时，都会以该结果所对应的
+// 方块在 blocks 中的下标 indexes 调用 onProgress。
+//
+// 由于相同的方块只会被制作一次，indexes 可能包含多个下标。
+// onProgress 可以为空，它在持有 NBTAssigner 的锁时被调用，
+// 因此不应在其中调用 NBTAssigner 的其他方法
+func (n *NBTAssigner) PlaceNBTBlocksWithProgress(
+	blocks []NBTBlock,
+	onProgress func(indexes []int, result PlaceNBTBlockResult),
+) (results []PlaceNBTBlockResult) {
+	return n.PlaceNBTBlocksWithContext(context.Background(), blocks, onProgress)
+}
+
+// PlaceNBTBlocksWithContext 与 PlaceNBTBlocksWithProgress 相同，
+// 但它会在制作每个方块前检查 ctx 是否已被取消。
+//
+// 如果 ctx 已被取消，则剩余的方块不会被制作，它们的结果的
+// Err 字段将包装 ctx.Err()，并且 onProgress 不会为它们被调用
+func (n *NBTAssigner) PlaceNBTBlocksWithContext(
+	ctx context.Context,
+	blocks []NBTBlock,
+	onProgress func(indexes []int, result PlaceNBTBlockResult),
+) (results []PlaceNBTBlockResult) {
+	results = make([]PlaceNBTBlockResult, len(blocks))
+	uniqueHashes := make([]uint64, 0)
+	uniqueBlocks := make(map[uint64]nbt_parser_interface.Block)
+	hashToIndexes := make(map[uint64][]int)
+	parsedBlocks := make(map[int]nbt_parser_interface.Block)
+	droppedItems := make(map[int][]DroppedItem)
+	lossyItems := make(map[int][]DroppedItem)
+
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	canFast, uniqueID, offset, err = nbt_assigner_interface.PlaceNBTBlock(n.console, n.cache, nbtBlock)
+	nameChecker := n.console.API().Resources().ConstantPacket().ItemCanGetByCommand
+	for index, block := range blocks {
+		nbtBlock, err := n.parseBlock(nameChecker, block.BlockName, block.BlockStates, block.BlockNBT, func(item DroppedItem) {
+			droppedItems[index] = append(droppedItems[index], item)
+		})
+		if err != nil {
+			results[index].Err = fmt.Errorf("PlaceNBTBlocks: %w; err = %v", ErrParseNBTBlock, err)
+			if onProgress != nil {
+				onProgress([]int{index}, results[index])
+			}
+			continue
+		}
+
+		parsedBlocks[index] = nbtBlock
+		dropped, lossy := nbtBlock.DroppedItems()
+		droppedItems[index] = append(droppedItems[index], dropped...)
+		lossyItems[index] = lossy
+
+		hashNumber := nbt_hash.NBTBlockFullHash(nbtBlock)
+		if _, ok := uniqueBlocks[hashNumber]; !ok {
+			uniqueHashes = append(uniqueHashes, hashNumber)
+			uniqueBlocks[hashNumber] = nbtBlock
+		}
+		hashToIndexes[hashNumber] = append(hashToIndexes[hashNumber], index)
+	}
+
+	for _, hashNumber := range uniqueHashes {
+		var result PlaceNBTBlockResult
+		var err error
+
+		if ctx.Err() != nil {
+			for _, index := range hashToIndexes[hashNumber] {
+				results[index].Err = fmt.Errorf("PlaceNBTBlocks: %w", ctx.Err())
+			}
+			continue
+		}
+
+		// 相同的方块具有相同的方块状态，
+		// 因此它们要么都随相邻的方块被制作，要么都不是
+		if nbt_assigner_interface.NBTBlockPlacedByNeighbor(uniqueBlocks[hashNumber]) {
+			result.PlacedByNeighbor = true
+		} else {
+			n.cache.BeginUse()
+			result.CanFast, result.UniqueID, result.Offset, err = nbt_assigner_interface.PlaceNBTBlock(
+				n.console,
+				n.cache,
+				uniqueBlocks[hashNumber],
+			)
+			n.cache.EndUse()
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("PlaceNBTBlocks: %v", err)
+			for _, index := range hashToIndexes[hashNumber] {
+				results[index] = result
+			}
+			if onProgress != nil {
+				onProgress(hashToIndexes[hashNumber], result)
+			}
+			continue
+		}
+
+		// 没有被还原的字段和物品不参与哈希校验和的计算，
+		// 因此去重后的方块可能报告不同的字段和物品
+		groups := make([][]int, 0)
+		groupFields := make(map[string]int)
+		for _, index := range hashToIndexes[hashNumber] {
+			result.DroppedFields = nbt_assigner_interface.NBTBlockDroppedFields(parsedBlocks[index])
+			result.DroppedItems = droppedItems[index]
+			result.LossyItems = lossyItems[index]
+			results[index] = result
+
+			key := fmt.Sprintf("%q %#v %#v", result.DroppedFields, result.DroppedItems, result.LossyItems)
+			group, ok := groupFields[key]
+			if !ok {
+				group = len(groups)
+				groupFields[key] = group
+				groups = append(groups, nil)
+			}
+			groups[group] = append(groups[group], index)
+		}
+		if onProgress != nil {
+			for _, indexes := range groups {
+				onProgress(indexes, results[indexes[0]])
+			}
+		}
+	}
+
+	n.cache.RequestSync()
 	return
 }
+
+// RegisterFilledMaps 将存档中 pos 处的容器所装有的全部地图
+// 登记到缓存命中系统，使得此后制作的方块可以包含这些地图。
+//
+// 基岩版无法通过命令得到特定 UUID 的地图，因此只有事先
+// 登记过的地图才能被复制。
+//
+// mapUUIDs 指示新登记的地图的 UUID。
+// RegisterFilledMaps 是阻塞的，它会独占 NBTAssigner
+func (n *NBTAssigner) RegisterFilledMaps(pos protocol.BlockPos) (mapUUIDs []int64, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	mapUUIDs, err = n.cache.FilledMapCache().StoreFromWorld(pos)
+	if err != nil {
+		return nil, fmt.Errorf("RegisterFilledMaps: %v", err)
+	}
+
+	n.cache.RequestSync()
+	return mapUUIDs, nil
+}
@@ -0,0 +1,108 @@
+package nbt_block
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+)
+
+// bedFootOffset 是床尾相对于床头的偏移，
+// 其索引是床的朝向
+var bedFootOffset = []protocol.BlockPos{
+	{0, 0, -1},
+	{1, 0, 0},
+	{0, 0, 1},
+	{-1, 0, 0},
+}
+
+// 床
+type Bed struct {
+	console *nbt_console.Console
+	data    nbt_parser_block.Bed
+}
+
+func (b Bed) Offset() protocol.BlockPos {
+	return bedFootOffset[b.data.Direction()]
+}
+
+func (b *Bed) Make() error {
+	api := b.console.API()
+	offset := b.Offset()
+	footPos := b.console.NearBlockPosByIndex(nbt_console.ConsoleIndexCenterBlock, offset)
+
+	// 清空床头和床尾处的方块
+	err := api.SetBlock().SetBlock(b.console.Center(), "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	b.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.Air{})
+	err = api.SetBlock().SetBlock(footPos, "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	*b.console.NearBlockByIndex(nbt_console.ConsoleIndexCenterBlock, offset) = block_helper.Air{}
+
+	// 获取相应颜色的床
+	err = api.Replaceitem().ReplaceitemInInventory(
+		"@s",
+		game_interface.ReplacePathHotbarOnly,
+		game_interface.ReplaceitemInfo{
+			Name:     "minecraft:bed",
+			Count:    1,
+			MetaData: int16(b.data.NBT.Color),
+			Slot:     b.console.HotbarSlotID(),
+		},
+		"",
+		true,
+	)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	b.console.UseInventorySlot(nbt_console.RequesterUser, b.console.HotbarSlotID(), true)
+
+	// 前往操作台中心处
+	err = b.console.CanReachOrMove(b.console.Center())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 点击床尾下方的地板以放置床。
+	// 床头会位于机器人所朝向的方向，
+	// 而偏航角 0, 90, 180 和 270 依次
+	// 朝向南、西、北和东，这恰好与床的
+	// 朝向相对应
+	err = api.BotClick().PlaceBlock(
+		game_interface.UseItemOnBlocks{
+			HotbarSlotID: b.console.HotbarSlotID(),
+			BotPos:       b.console.Position(),
+			BlockPos:     protocol.BlockPos{footPos[0], footPos[1] - 1, footPos[2]},
+			BlockName:    nbt_console.BaseBackground,
+			BlockStates:  map[string]any{},
+			BotYaw:       float32(b.data.Direction()) * 90,
+		},
+		1,
+	)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	err = api.Commands().AwaitChangesGeneral()
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 更新操作台的方块记录
+	b.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        b.data.BlockName(),
+		States:      b.data.BlockStates(),
+	})
+	*b.console.NearBlockByIndex(nbt_console.ConsoleIndexCenterBlock, offset) = block_helper.NearBlock{
+		Name: b.data.BlockName(),
+	}
+
+	return nil
+}
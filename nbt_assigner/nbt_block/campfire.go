@@ -0,0 +1,87 @@
+package nbt_block
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// 营火
+type Campfire struct {
+	console *nbt_console.Console
+	cache   *nbt_cache.NBTCacheSystem
+	data    nbt_parser_block.Campfire
+}
+
+func (Campfire) Offset() protocol.BlockPos {
+	return protocol.BlockPos{0, 0, 0}
+}
+
+func (c *Campfire) Make() error {
+	api := c.console.API()
+
+	// 只有点燃的营火才能放置物品
+	litStates := maps.Clone(c.data.BlockStates())
+	litStates["extinguished"] = byte(0)
+
+	// 放置点燃的营火
+	err := api.SetBlock().SetBlock(c.console.Center(), "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	err = api.SetBlock().SetBlock(c.console.Center(), c.data.BlockName(), utils.MarshalBlockStates(litStates))
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	c.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        c.data.BlockName(),
+		States:      litStates,
+	})
+
+	// 将物品依次放置到营火上。
+	// 物品总是占用第一个空槽位，
+	// 因此按槽位顺序放置即可
+	for _, item := range c.data.NBT.Items {
+		err = holdItem(c.console, c.cache, item.Item, 1)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+
+		err = c.console.CanReachOrMove(c.console.Center())
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+
+		err = api.BotClick().ClickBlock(game_interface.UseItemOnBlocks{
+			HotbarSlotID: c.console.HotbarSlotID(),
+			BotPos:       c.console.Position(),
+			BlockPos:     c.console.Center(),
+			BlockName:    c.data.BlockName(),
+			BlockStates:  litStates,
+		})
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+	}
+
+	// 覆写营火的方块状态
+	err = api.SetBlock().SetBlock(c.console.Center(), c.data.BlockName(), c.data.BlockStatesString())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	c.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        c.data.BlockName(),
+		States:      c.data.BlockStates(),
+	})
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package nbt_block
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	nbt_assigner_interface "github.com/OmineDev/flowers-for-machines/nbt_assigner/interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// chiseledBookshelfFace 是雕纹书架正面所对应的方块面，
+// 其索引是雕纹书架的朝向
+var chiseledBookshelfFace = []int32{3, 4, 2, 5}
+
+// chiseledBookshelfClickPos 返回点击朝向为 direction 的
+// 雕纹书架的槽位 slot 时，点击位置相对于书架的坐标
+func chiseledBookshelfClickPos(direction int32, slot uint8) mgl32.Vec3 {
+	// 槽位在书架正面上的水平和竖直位置，
+	// 其中水平位置是面向书架正面时从左到右的
+	horizontal := (float32(slot%3) + 0.5) / 3
+	vertical := float32(0.75)
+	if slot >= 3 {
+		vertical = 0.25
+	}
+
+	switch direction {
+	case 0:
+		return mgl32.Vec3{horizontal, vertical, 1}
+	case 1:
+		return mgl32.Vec3{0, vertical, horizontal}
+	case 2:
+		return mgl32.Vec3{1 - horizontal, vertical, 0}
+	default:
+		return mgl32.Vec3{1, vertical, 1 - horizontal}
+	}
+}
+
+// 雕纹书架
+type ChiseledBookshelf struct {
+	console *nbt_console.Console
+	cache   *nbt_cache.NBTCacheSystem
+	data    nbt_parser_block.ChiseledBookshelf
+	states  map[string]any
+}
+
+func (ChiseledBookshelf) Offset() protocol.BlockPos {
+	return protocol.BlockPos{0, 0, 0}
+}
+
+// makeBooks 制作 books 中的每本书，并返回书架槽位到书所在快捷栏的映射。
+// 应当确保 books 中的每本书都具有互不相同的 NBT Hash Number
+func (c *ChiseledBookshelf) makeBooks(books []nbt_parser_block.ItemWithSlot) (result map[uint8]resources_control.SlotID, err error) {
+	api := c.console.API()
+	result = make(map[uint8]resources_control.SlotID)
+
+	// 制作复杂的书。
+	// 成书会被依次放置在快捷栏 0 及其之后
+	complexBooks := make([]nbt_parser_interface.Item, 0)
+	for _, book := range books {
+		if book.Item.IsComplex() {
+			complexBooks = append(complexBooks, book.Item)
+		}
+	}
+	resultSlot := make(map[uint64]resources_control.SlotID)
+	for _, method := range nbt_assigner_interface.MakeNBTItemMethod(c.console, c.cache, complexBooks...) {
+		slots, err := method.Make()
+		if err != nil {
+			return nil, fmt.Errorf("makeBooks: %v", err)
+		}
+		maps.Copy(resultSlot, slots)
+	}
+
+	// 可以直接使用命令获取的书
+	// 被放置在成书之后的快捷栏
+	hotbarSlotID := resources_control.SlotID(len(complexBooks))
+	for _, book := range books {
+		if book.Item.IsComplex() {
+			slotID, ok := resultSlot[nbt_hash.NBTItemNBTHash(book.Item)]
+			if !ok {
+				panic("makeBooks: Should nerver happened")
+			}
+			result[book.Slot] = slotID
+			continue
+		}
+
+		underlying := book.Item.UnderlyingItem()
+		defaultItem := underlying.(*nbt_parser_item.DefaultItem)
+		err = api.Replaceitem().ReplaceitemInInventory(
+			"@s",
+			game_interface.ReplacePathHotbarOnly,
+			game_interface.ReplaceitemInfo{
+				Name:     book.Item.ItemName(),
+				Count:    1,
+				MetaData: book.Item.ItemMetadata(),
+				Slot:     hotbarSlotID,
+			},
+			utils.MarshalItemComponent(defaultItem.Enhance.ItemComponent),
+			false,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("makeBooks: %v", err)
+		}
+		c.console.UseInventorySlot(nbt_console.RequesterUser, hotbarSlotID, true)
+		result[book.Slot] = hotbarSlotID
+		hotbarSlotID++
+	}
+	err = api.Commands().AwaitChangesGeneral()
+	if err != nil {
+		return nil, fmt.Errorf("makeBooks: %v", err)
+	}
+
+	// 如果书具有自定义物品名称
+	needRename := false
+	for _, book := range books {
+		underlying := book.Item.UnderlyingItem()
+		defaultItem := underlying.(*nbt_parser_item.DefaultItem)
+		if len(defaultItem.Enhance.DisplayName) > 0 {
+			needRename = true
+			break
+		}
+	}
+	if !needRename {
+		return result, nil
+	}
+
+	index, err := c.console.FindOrGenerateNewAnvil()
+	if err != nil {
+		return nil, fmt.Errorf("makeBooks: %v", err)
+	}
+	success, err := c.console.OpenContainerByIndex(index)
+	if err != nil {
+		return nil, fmt.Errorf("makeBooks: %v", err)
+	}
+	if !success {
+		return nil, fmt.Errorf("makeBooks: Failed to open the anvil")
+	}
+
+	transaction := api.ItemStackOperation().OpenTransaction()
+	for _, book := range books {
+		underlying := book.Item.UnderlyingItem()
+		defaultItem := underlying.(*nbt_parser_item.DefaultItem)
+		if len(defaultItem.Enhance.DisplayName) == 0 {
+			continue
+		}
+		transaction.RenameInventoryItem(result[book.Slot], defaultItem.Enhance.DisplayName)
+	}
+
+	success, _, _, err = transaction.Commit()
+	if err != nil {
+		_ = api.ContainerOpenAndClose().CloseContainer()
+		return nil, fmt.Errorf("makeBooks: %v", err)
+	}
+	if !success {
+		_ = api.ContainerOpenAndClose().CloseContainer()
+		return nil, fmt.Errorf("makeBooks: The server rejected the renaming operation")
+	}
+
+	err = api.ContainerOpenAndClose().CloseContainer()
+	if err != nil {
+		return nil, fmt.Errorf("makeBooks: %v", err)
+	}
+
+	return result, nil
+}
+
+// putBooks 将 books 中的每本书依次放入雕纹书架。
+// bookSlots 是书架槽位到书所在快捷栏的映射
+func (c *ChiseledBookshelf) putBooks(books []nbt_parser_block.ItemWithSlot, bookSlots map[uint8]resources_control.SlotID) error {
+	api := c.console.API()
+	direction := c.data.Direction()
+
+	err := c.console.CanReachOrMove(c.console.Center())
+	if err != nil {
+		return fmt.Errorf("putBooks: %v", err)
+	}
+
+	for _, book := range books {
+		hotbarSlotID := bookSlots[book.Slot]
+		if hotbarSlotID != c.console.HotbarSlotID() {
+			err = c.console.ChangeAndUpdateHotbarSlotID(hotbarSlotID)
+			if err != nil {
+				return fmt.Errorf("putBooks: %v", err)
+			}
+		}
+
+		err = api.BotClick().ClickBlockFace(
+			game_interface.UseItemOnBlocks{
+				HotbarSlotID: c.console.HotbarSlotID(),
+				BotPos:       c.console.Position(),
+				BlockPos:     c.console.Center(),
+				BlockName:    c.data.BlockName(),
+				BlockStates:  c.states,
+				ClickPos:     chiseledBookshelfClickPos(direction, book.Slot),
+			},
+			chiseledBookshelfFace[direction],
+		)
+		if err != nil {
+			return fmt.Errorf("putBooks: %v", err)
+		}
+
+		booksStored, _ := c.states["books_stored"].(int32)
+		c.states["books_stored"] = booksStored | 1<<book.Slot
+		c.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+			KnownStates: true,
+			Name:        c.data.BlockName(),
+			States:      maps.Clone(c.states),
+		})
+	}
+
+	return nil
+}
+
+func (c *ChiseledBookshelf) Make() error {
+	api := c.console.API()
+
+	// 放置空的雕纹书架
+	c.states = maps.Clone(c.data.BlockStates())
+	c.states["books_stored"] = int32(0)
+	err := api.SetBlock().SetBlock(c.console.Center(), "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	err = api.SetBlock().SetBlock(c.console.Center(), c.data.BlockName(), utils.MarshalBlockStates(c.states))
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	c.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        c.data.BlockName(),
+		States:      maps.Clone(c.states),
+	})
+
+	// 最后一次被交互的槽位总是最后放入，
+	// 这使得书架的比较器输出可以被还原
+	var lastBook *nbt_parser_block.ItemWithSlot
+	pending := make([]nbt_parser_block.ItemWithSlot, 0)
+	for _, book := range c.data.NBT.Items {
+		if int32(book.Slot)+1 == c.data.NBT.LastInteractedSlot {
+			lastBook = &book
+			continue
+		}
+		pending = append(pending, book)
+	}
+
+	// 每一轮只制作具有不同 NBT Hash Number 的书，
+	// 而相同的书将在之后的轮次中被再次制作
+	for len(pending) > 0 || lastBook != nil {
+		current := make([]nbt_parser_block.ItemWithSlot, 0)
+		next := make([]nbt_parser_block.ItemWithSlot, 0)
+		hashNumbers := make(map[uint64]bool)
+
+		for _, book := range pending {
+			hashNumber := nbt_hash.NBTItemNBTHash(book.Item)
+			if hashNumbers[hashNumber] {
+				next = append(next, book)
+				continue
+			}
+			hashNumbers[hashNumber] = true
+			current = append(current, book)
+		}
+		if len(current) == 0 {
+			current = append(current, *lastBook)
+			lastBook = nil
+		}
+
+		bookSlots, err := c.makeBooks(current)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		err = c.putBooks(current, bookSlots)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+
+		pending = next
+	}
+
+	return nil
+}
@@ -168,7 +168,7 @@ func (c *Container) makeNormal() error {
 			return fmt.Errorf("makeNormal: %v", err)
 		}
 
-		err = c.cache.NBTBlockCache().StoreCache(wantContainer, protocol.BlockPos{0, 0, 0})
+		_, err = c.cache.NBTBlockCache().StoreCache(wantContainer, protocol.BlockPos{0, 0, 0})
 		if err != nil {
 			return fmt.Errorf("makeNormal: %v", err)
 		}
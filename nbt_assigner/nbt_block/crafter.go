@@ -22,6 +22,15 @@ func (Crafter) Offset() protocol.BlockPos {
 	return protocol.BlockPos{0, 0, 0}
 }
 
+// DroppedFields 返回合成器中无法被还原的字段的名称。
+// 制作时不会提供红石信号，因此被触发的状态无法被还原
+func (c Crafter) DroppedFields() (result []string) {
+	if c.data.DroppedTriggered {
+		result = append(result, "triggered_bit")
+	}
+	return
+}
+
 func (c *Crafter) Make() error {
 	api := c.console.API()
 	center := c.console.Center()
@@ -0,0 +1,161 @@
+package nbt_block
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	nbt_assigner_interface "github.com/OmineDev/flowers-for-machines/nbt_assigner/interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+)
+
+// 饰纹陶罐
+type DecoratedPot struct {
+	console *nbt_console.Console
+	cache   *nbt_cache.NBTCacheSystem
+	data    nbt_parser_block.DecoratedPot
+}
+
+func (DecoratedPot) Offset() protocol.BlockPos {
+	return protocol.BlockPos{0, 0, 0}
+}
+
+func (d *DecoratedPot) Make() error {
+	var itemSlot resources_control.SlotID
+	var itemCount int
+	api := d.console.API()
+
+	// 首先制作罐中的物品。
+	// 制作物品时可能会使用操作台中心处的方块，
+	// 因此它需要在放置饰纹陶罐之前完成
+	if d.data.NBT.HaveItem {
+		// 数量多于 1 的复杂物品已在解析时被丢弃
+		itemCount = int(d.data.NBT.Item.ItemCount())
+		err := holdItem(d.console, d.cache, d.data.NBT.Item, uint8(itemCount))
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		itemSlot = d.console.HotbarSlotID()
+	}
+
+	// 清空操作台中心处的方块
+	err := api.SetBlock().SetBlock(d.console.Center(), "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	d.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.Air{})
+
+	// 饰纹陶罐不能与罐中的物品位于同一物品栏
+	potSlot := d.console.HotbarSlotID()
+	if d.data.NBT.HaveItem {
+		potSlot = (itemSlot + 1) % 9
+	}
+
+	// 取得饰纹陶罐。
+	// 带有陶片的饰纹陶罐只能通过合成得到
+	if d.data.HaveSherd() {
+		resultSlot, err := nbt_assigner_interface.MakeDecoratedPot(d.console, d.data.NBT.Sherds)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		if resultSlot > 8 {
+			err = moveToHotbar(d.console, resultSlot, potSlot)
+			if err != nil {
+				return fmt.Errorf("Make: %v", err)
+			}
+		} else {
+			potSlot = resultSlot
+		}
+	} else {
+		err = api.Replaceitem().ReplaceitemInInventory(
+			"@s",
+			game_interface.ReplacePathHotbarOnly,
+			game_interface.ReplaceitemInfo{
+				Name:     "minecraft:decorated_pot",
+				Count:    1,
+				MetaData: 0,
+				Slot:     potSlot,
+			},
+			"",
+			true,
+		)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		d.console.UseInventorySlot(nbt_console.RequesterUser, potSlot, true)
+	}
+
+	// 切换物品栏，如果需要的话
+	if potSlot != d.console.HotbarSlotID() {
+		err = d.console.ChangeAndUpdateHotbarSlotID(potSlot)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+	}
+
+	// 前往操作台中心处
+	err = d.console.CanReachOrMove(d.console.Center())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 放置饰纹陶罐
+	_, offsetPos, err := api.BotClick().PlaceBlockHighLevel(
+		d.console.Center(),
+		d.console.Position(),
+		d.console.HotbarSlotID(),
+		1,
+	)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	d.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: false,
+		Name:        d.data.BlockName(),
+	})
+	*d.console.NearBlockByIndex(nbt_console.ConsoleIndexCenterBlock, offsetPos) = block_helper.NearBlock{
+		Name: game_interface.BasePlaceBlock,
+	}
+
+	// 覆写饰纹陶罐的方块状态
+	err = api.SetBlock().SetBlock(d.console.Center(), d.data.BlockName(), d.data.BlockStatesString())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	d.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        d.data.BlockName(),
+		States:      d.data.BlockStates(),
+	})
+
+	// 如果罐中没有物品，则制作完成
+	if !d.data.NBT.HaveItem {
+		return nil
+	}
+
+	// 切换到罐中物品所在的物品栏
+	err = d.console.ChangeAndUpdateHotbarSlotID(itemSlot)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 将物品逐个放入饰纹陶罐
+	for range itemCount {
+		err = api.BotClick().ClickBlock(game_interface.UseItemOnBlocks{
+			HotbarSlotID: d.console.HotbarSlotID(),
+			BotPos:       d.console.Position(),
+			BlockPos:     d.console.Center(),
+			BlockName:    d.data.BlockName(),
+			BlockStates:  d.data.BlockStates(),
+		})
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package nbt_block
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+)
+
+// 花盆
+type FlowerPot struct {
+	console *nbt_console.Console
+	data    nbt_parser_block.FlowerPot
+}
+
+func (FlowerPot) Offset() protocol.BlockPos {
+	return protocol.BlockPos{0, 0, 0}
+}
+
+func (f *FlowerPot) Make() error {
+	api := f.console.API()
+
+	// 在操作台中心处放置花盆中的植物。
+	// 植物的物品名称与方块名称并不总是相同，
+	// 因此通过选取方块的方式来取得植物物品
+	err := api.SetBlock().SetBlock(f.console.Center(), f.data.NBT.PlantName, f.data.PlantStatesString())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	f.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        f.data.NBT.PlantName,
+		States:      f.data.NBT.PlantStates,
+	})
+
+	// 清空背包并取得植物物品
+	_, err = api.Commands().SendWSCommandWithResp("clear")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	f.console.CleanInventory()
+
+	success, currentSlot, err := api.BotClick().PickBlock(f.console.Center(), false)
+	if err != nil || !success {
+		_ = f.console.ChangeAndUpdateHotbarSlotID(nbt_console.DefaultHotbarSlot)
+	}
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	if !success {
+		return fmt.Errorf("Make: Failed to pick the plant %#v", f.data.NBT.PlantName)
+	}
+	f.console.UpdateHotbarSlotID(currentSlot)
+	f.console.UseInventorySlot(nbt_console.RequesterUser, currentSlot, true)
+
+	// 放置空的花盆
+	err = api.SetBlock().SetBlock(f.console.Center(), f.data.BlockName(), `["update_bit"=false]`)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	f.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: false,
+		Name:        f.data.BlockName(),
+	})
+
+	// 前往操作台中心处
+	err = f.console.CanReachOrMove(f.console.Center())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 将植物种入花盆
+	err = api.BotClick().ClickBlock(game_interface.UseItemOnBlocks{
+		HotbarSlotID: f.console.HotbarSlotID(),
+		BotPos:       f.console.Position(),
+		BlockPos:     f.console.Center(),
+		BlockName:    f.data.BlockName(),
+		BlockStates: map[string]any{
+			"update_bit": byte(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	f.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        f.data.BlockName(),
+		States:      f.data.BlockStates(),
+	})
+
+	return nil
+}
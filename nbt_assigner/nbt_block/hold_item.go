@@ -0,0 +1,262 @@
+package nbt_block
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	nbt_assigner_interface "github.com/OmineDev/flowers-for-machines/nbt_assigner/interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// moveToHotbar 将背包中 slotID 处的物品移动到快捷栏 hotbarSlotID 处。
+// 快捷栏 hotbarSlotID 处原有的物品将被丢弃
+func moveToHotbar(console *nbt_console.Console, slotID resources_control.SlotID, hotbarSlotID resources_control.SlotID) error {
+	api := console.API()
+
+	err := api.Replaceitem().ReplaceitemInInventory(
+		"@s",
+		game_interface.ReplacePathHotbarOnly,
+		game_interface.ReplaceitemInfo{
+			Name:     "minecraft:air",
+			Count:    1,
+			MetaData: 0,
+			Slot:     hotbarSlotID,
+		},
+		"",
+		true,
+	)
+	if err != nil {
+		return fmt.Errorf("moveToHotbar: %v", err)
+	}
+	console.UseInventorySlot(nbt_console.RequesterUser, hotbarSlotID, false)
+
+	success, err := api.ContainerOpenAndClose().OpenInventory()
+	if err != nil {
+		return fmt.Errorf("moveToHotbar: %v", err)
+	}
+	if !success {
+		return fmt.Errorf("moveToHotbar: Failed to open the inventory")
+	}
+
+	success, _, _, err = api.ItemStackOperation().OpenTransaction().
+		MoveBetweenInventory(slotID, hotbarSlotID, 1).
+		Commit()
+	if err != nil {
+		_ = api.ContainerOpenAndClose().CloseContainer()
+		return fmt.Errorf("moveToHotbar: %v", err)
+	}
+	if !success {
+		_ = api.ContainerOpenAndClose().CloseContainer()
+		return fmt.Errorf("moveToHotbar: The server rejected the stack request action")
+	}
+
+	err = api.ContainerOpenAndClose().CloseContainer()
+	if err != nil {
+		return fmt.Errorf("moveToHotbar: %v", err)
+	}
+
+	console.UseInventorySlot(nbt_console.RequesterUser, slotID, false)
+	console.UseInventorySlot(nbt_console.RequesterUser, hotbarSlotID, true)
+	return nil
+}
+
+// processComplexItem 处理复杂的物品 item
+func processComplexItem(
+	console *nbt_console.Console,
+	cache *nbt_cache.NBTCacheSystem,
+	item nbt_parser_interface.Item,
+) (canUseCommand bool, resultSlot resources_control.SlotID, err error) {
+	api := console.API()
+	underlying := item.UnderlyingItem()
+	defaultBlock := underlying.(*nbt_parser_item.DefaultItem)
+
+	// 子方块
+	if defaultBlock.Block.SubBlock != nil {
+		if !defaultBlock.Block.SubBlock.NeedSpecialHandle() {
+			return true, 0, nil
+		}
+		_, _, _, err = nbt_assigner_interface.PlaceNBTBlock(console, cache, defaultBlock.Block.SubBlock)
+		if err != nil {
+			return false, 0, fmt.Errorf("processComplexItem: %v", err)
+		}
+
+		_, hit, partHit, err := cache.NBTBlockCache().LoadCache(nbt_hash.CompletelyHashNumber{
+			HashNumber:    nbt_hash.NBTBlockFullHash(defaultBlock.Block.SubBlock),
+			SetHashNumber: nbt_hash.ContainerSetHash(defaultBlock.Block.SubBlock),
+		})
+		if err != nil {
+			return false, 0, fmt.Errorf("processComplexItem: %v", err)
+		}
+		if !hit || partHit {
+			panic("processComplexItem: Should nerver happened")
+		}
+
+		_, err = api.Commands().SendWSCommandWithResp("clear")
+		if err != nil {
+			return false, 0, fmt.Errorf("processComplexItem: %v", err)
+		}
+		console.CleanInventory()
+
+		success, currentSlot, err := api.BotClick().PickBlock(console.Center(), true)
+		if err != nil || !success {
+			_ = console.ChangeAndUpdateHotbarSlotID(nbt_console.DefaultHotbarSlot)
+		}
+		if err != nil {
+			return false, 0, fmt.Errorf("processComplexItem: %v", err)
+		}
+		if !success {
+			return false, 0, fmt.Errorf("processComplexItem: Failed to pick block due to unknown reason")
+		}
+		console.UpdateHotbarSlotID(currentSlot)
+		console.UseInventorySlot(nbt_console.RequesterUser, currentSlot, true)
+
+		return false, currentSlot, nil
+	}
+
+	// 复杂 NBT 物品制作
+	methods := nbt_assigner_interface.MakeNBTItemMethod(console, cache, item)
+	if len(methods) != 1 {
+		panic("processComplexItem: Should nerver happened")
+	}
+	resultSlotMapping, err := methods[0].Make()
+	if err != nil {
+		return false, 0, fmt.Errorf("processComplexItem: %v", err)
+	}
+	if len(resultSlotMapping) != 1 {
+		panic("processComplexItem: Should nerver happened")
+	}
+
+	// 将复杂 NBT 物品移动到快捷栏
+	for _, slotID := range resultSlotMapping {
+		resultSlot = slotID
+	}
+	if resultSlot > 8 {
+		err = moveToHotbar(console, resultSlot, console.HotbarSlotID())
+		if err != nil {
+			return false, 0, fmt.Errorf("processComplexItem: %v", err)
+		}
+		resultSlot = console.HotbarSlotID()
+	}
+
+	return false, resultSlot, nil
+}
+
+// holdItem 制作物品 item，然后使机器人手持它。
+// count 是可以通过命令直接获取的物品的数量，
+// 而复杂的物品总是只会被制作 1 个
+func holdItem(
+	console *nbt_console.Console,
+	cache *nbt_cache.NBTCacheSystem,
+	item nbt_parser_interface.Item,
+	count uint8,
+) error {
+	var canUseCommand bool
+	var resultSlot resources_control.SlotID
+	var err error
+	api := console.API()
+
+	// 如果这是一个复杂的物品
+	if item.IsComplex() {
+		canUseCommand, resultSlot, err = processComplexItem(console, cache, item)
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+	} else {
+		canUseCommand = true
+	}
+
+	// canUseCommand 指示可以先使用命令获取目标物品
+	if canUseCommand {
+		underlying := item.UnderlyingItem()
+		defaultItem := underlying.(*nbt_parser_item.DefaultItem)
+
+		err = api.Replaceitem().ReplaceitemInInventory(
+			"@s",
+			game_interface.ReplacePathHotbarOnly,
+			game_interface.ReplaceitemInfo{
+				Name:     item.ItemName(),
+				Count:    count,
+				MetaData: item.ItemMetadata(),
+				Slot:     console.HotbarSlotID(),
+			},
+			utils.MarshalItemComponent(defaultItem.Enhance.ItemComponent),
+			true,
+		)
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+
+		console.UseInventorySlot(nbt_console.RequesterUser, console.HotbarSlotID(), true)
+		resultSlot = console.HotbarSlotID()
+	}
+
+	// 切换物品栏，如果需要的话
+	if resultSlot != console.HotbarSlotID() {
+		err = console.ChangeAndUpdateHotbarSlotID(resultSlot)
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+	}
+
+	// 如果这个物品需要重命名或附魔
+	if !item.NeedEnchOrRename() {
+		return nil
+	}
+	underlying := item.UnderlyingItem()
+	defaultItem := underlying.(*nbt_parser_item.DefaultItem)
+
+	// 附魔处理
+	for _, ench := range defaultItem.Enhance.EnchList {
+		err = api.Commands().SendSettingsCommand(fmt.Sprintf("enchant @s %d %d", ench.ID, ench.Level), true)
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+	}
+	if len(defaultItem.Enhance.EnchList) > 0 {
+		err = api.Commands().AwaitChangesGeneral()
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+	}
+
+	// 物品改名处理
+	if len(defaultItem.Enhance.DisplayName) > 0 {
+		index, err := console.FindOrGenerateNewAnvil()
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+
+		success, err := console.OpenContainerByIndex(index)
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+		if !success {
+			return fmt.Errorf("holdItem: Failed to open the anvil who at %#v", console.BlockPosByIndex(index))
+		}
+
+		success, _, _, err = api.ItemStackOperation().OpenTransaction().
+			RenameInventoryItem(resultSlot, defaultItem.Enhance.DisplayName).
+			Commit()
+		if err != nil {
+			_ = api.ContainerOpenAndClose().CloseContainer()
+			return fmt.Errorf("holdItem: %v", err)
+		}
+		if !success {
+			_ = api.ContainerOpenAndClose().CloseContainer()
+			return fmt.Errorf("holdItem: The server rejected the renaming operation")
+		}
+
+		err = api.ContainerOpenAndClose().CloseContainer()
+		if err != nil {
+			return fmt.Errorf("holdItem: %v", err)
+		}
+	}
+
+	return nil
+}
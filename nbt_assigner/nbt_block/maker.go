@@ -2,6 +2,7 @@ package nbt_block
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
 	nbt_assigner_interface "github.com/OmineDev/flowers-for-machines/nbt_assigner/interface"
@@ -19,11 +20,14 @@ import (
 // 方块会被重复制作。
 //
 // MaxRetryPlaceNBTBlock 便是指示重复
-// 制作这个 NBT 方块的最大次数
-const MaxRetryPlaceNBTBlock = 7
+// 制作这个 NBT 方块的最大次数。
+// 它可以在制作任何 NBT 方块前修改
+var MaxRetryPlaceNBTBlock = 7
 
 func init() {
 	nbt_assigner_interface.NBTBlockIsSupported = NBTBlockIsSupported
+	nbt_assigner_interface.NBTBlockDroppedFields = NBTBlockDroppedFields
+	nbt_assigner_interface.NBTBlockPlacedByNeighbor = NBTBlockPlacedByNeighbor
 	nbt_assigner_interface.PlaceNBTBlock = PlaceNBTBlock
 }
 
@@ -39,12 +43,41 @@ func NBTBlockIsSupported(block nbt_parser_interface.Block) bool {
 	case *nbt_parser_block.Lectern:
 	case *nbt_parser_block.JukeBox:
 	case *nbt_parser_block.BrewingStand:
+	case *nbt_parser_block.Crafter:
+	case *nbt_parser_block.Bed:
+	case *nbt_parser_block.Skull:
+	case *nbt_parser_block.FlowerPot:
+	case *nbt_parser_block.DecoratedPot:
+	case *nbt_parser_block.ChiseledBookshelf:
+	case *nbt_parser_block.MobSpawner:
+	case *nbt_parser_block.Campfire:
 	default:
 		return false
 	}
 	return true
 }
 
+// NBTBlockDroppedFields 返回 block 的方块实体数据
+// 或方块状态中，在制作时无法被还原的字段的名称
+func NBTBlockDroppedFields(block nbt_parser_interface.Block) []string {
+	switch block := block.(type) {
+	case *nbt_parser_block.MobSpawner:
+		return MobSpawner{data: *block}.DroppedFields()
+	case *nbt_parser_block.Crafter:
+		return Crafter{data: *block}.DroppedFields()
+	}
+	return nil
+}
+
+// NBTBlockPlacedByNeighbor 检查 block 是否总是随其相邻的方块
+// 一同被制作，例如床尾总是随床头一同被制作
+func NBTBlockPlacedByNeighbor(block nbt_parser_interface.Block) bool {
+	if bed, ok := block.(*nbt_parser_block.Bed); ok {
+		return !bed.IsHead()
+	}
+	return false
+}
+
 // PlaceNBTBlock 根据传入的操作台和缓存命中系统，
 // 在操作台的中心方块处制作一个 NBT 方块 nbtBlock。
 //
@@ -63,6 +96,16 @@ func PlaceNBTBlock(
 	offset protocol.BlockPos,
 	err error,
 ) {
+	startTime := time.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		} else if canFast {
+			result = "fast"
+		}
+		placeDuration.WithLabelValues(blockTypeLabel(nbtBlock), result).ObserveSince(startTime)
+	}()
 	return placeNBTBlock(console, cache, nbtBlock, 0)
 }
 
@@ -92,6 +135,9 @@ func placeNBTBlock(
 
 	// 检查 NBT 缓存命中系统
 	structure, hit, partHit := cache.NBTBlockCache().CheckCache(hashNumber)
+	if repeatCount == 0 {
+		recordCacheLookup(hit, partHit)
+	}
 	if hit && !partHit {
 		return false, structure.UniqueID, structure.Offset, nil
 	}
@@ -155,6 +201,44 @@ func placeNBTBlock(
 			cache:   cache,
 			data:    *block,
 		}
+	case *nbt_parser_block.Bed:
+		method = &Bed{
+			console: console,
+			data:    *block,
+		}
+	case *nbt_parser_block.Skull:
+		method = &Skull{
+			console: console,
+			data:    *block,
+		}
+	case *nbt_parser_block.FlowerPot:
+		method = &FlowerPot{
+			console: console,
+			data:    *block,
+		}
+	case *nbt_parser_block.DecoratedPot:
+		method = &DecoratedPot{
+			console: console,
+			cache:   cache,
+			data:    *block,
+		}
+	case *nbt_parser_block.ChiseledBookshelf:
+		method = &ChiseledBookshelf{
+			console: console,
+			cache:   cache,
+			data:    *block,
+		}
+	case *nbt_parser_block.MobSpawner:
+		method = &MobSpawner{
+			console: console,
+			data:    *block,
+		}
+	case *nbt_parser_block.Campfire:
+		method = &Campfire{
+			console: console,
+			cache:   cache,
+			data:    *block,
+		}
 	}
 
 	// 放置相应方块
@@ -177,7 +261,8 @@ func placeNBTBlock(
 
 		if hashNumber.HashNumber != nbt_hash.NBTBlockFullHash(newBlock) {
 			nextCount := repeatCount + 1
-			if nextCount > MaxRetryPlaceNBTBlock {
+			if int(nextCount) > MaxRetryPlaceNBTBlock {
+				placeRetriesExhausted.WithLabelValues(blockTypeLabel(nbtBlock)).Inc()
 				return false, uuid.UUID{}, protocol.BlockPos{}, fmt.Errorf(
 					""+
 						"PlaceNBTBlock: Self loop when place NBT block, "+
@@ -189,16 +274,15 @@ func placeNBTBlock(
 					nbtBlock.Format(""), newBlock.Format(""),
 				)
 			}
+			placeRetries.WithLabelValues(blockTypeLabel(nbtBlock)).Inc()
 			return placeNBTBlock(console, cache, nbtBlock, nextCount)
 		}
 	}
 
 	// 保存缓存
-	err = cache.NBTBlockCache().StoreCache(nbtBlock, method.Offset())
+	structure, err = cache.NBTBlockCache().StoreCache(nbtBlock, method.Offset())
 	if err != nil {
 		return false, uuid.UUID{}, protocol.BlockPos{}, fmt.Errorf("PlaceNBTBlock: %v", err)
 	}
-
-	// 下次调用时将直接返回缓存
-	return placeNBTBlock(console, cache, nbtBlock, repeatCount)
+	return false, structure.UniqueID, structure.Offset, nil
 }
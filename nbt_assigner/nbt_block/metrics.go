@@ -0,0 +1,94 @@
+package nbt_block
+
+import (
+	"github.com/OmineDev/flowers-for-machines/metrics"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+var (
+	// placeDuration 记录制作单个 NBT 方块的耗时。
+	// 其标签 result 可以是 fast (可直接通过 setblock 放置),
+	// success 或 failure
+	placeDuration = metrics.NewHistogramVec(
+		"place_nbt_block_duration_seconds",
+		"Latency of placing a single NBT block, by block type and result (fast, success or failure).",
+		nil,
+		"block_type", "result",
+	)
+	// placeRetries 记录因完整性检查失败而重新制作 NBT 方块的次数
+	placeRetries = metrics.NewCounterVec(
+		"place_nbt_block_retries_total",
+		"Number of NBT block placement retries consumed out of MaxRetryPlaceNBTBlock, by block type.",
+		"block_type",
+	)
+	// cacheLookups 记录制作 NBT 方块时对缓存命中系统的查询结果，
+	// 其标签 result 可以是 hit (完整哈希命中), partial_hit (集合
+	// 哈希命中) 或 miss (未命中)。制作过程中对子方块等的查询以及
+	// 重试时的查询不会被记录
+	cacheLookups = metrics.NewCounterVec(
+		"nbt_block_cache_lookups_total",
+		"Number of NBT block cache lookups made when placing NBT blocks, by result (hit, partial_hit or miss).",
+		"result",
+	)
+	// placeRetriesExhausted 记录重试次数耗尽的 NBT 方块的数量
+	placeRetriesExhausted = metrics.NewCounterVec(
+		"place_nbt_block_retries_exhausted_total",
+		"Number of NBT blocks that failed after exhausting MaxRetryPlaceNBTBlock retries, by block type.",
+		"block_type",
+	)
+)
+
+// recordCacheLookup 记录一次对缓存命中系统的查询。
+// hit 和 isSetHashHit 是 CheckCache 的返回值
+func recordCacheLookup(hit bool, isSetHashHit bool) {
+	switch {
+	case hit && isSetHashHit:
+		cacheLookups.WithLabelValues("partial_hit").Inc()
+	case hit:
+		cacheLookups.WithLabelValues("hit").Inc()
+	default:
+		cacheLookups.WithLabelValues("miss").Inc()
+	}
+}
+
+// blockTypeLabel 返回 block 的种类的标签值
+func blockTypeLabel(block nbt_parser_interface.Block) string {
+	switch block.(type) {
+	case *nbt_parser_block.CommandBlock:
+		return "command_block"
+	case *nbt_parser_block.Sign:
+		return "sign"
+	case *nbt_parser_block.StructureBlock:
+		return "structure_block"
+	case *nbt_parser_block.Container:
+		return "container"
+	case *nbt_parser_block.Banner:
+		return "banner"
+	case *nbt_parser_block.Frame:
+		return "frame"
+	case *nbt_parser_block.Lectern:
+		return "lectern"
+	case *nbt_parser_block.JukeBox:
+		return "jukebox"
+	case *nbt_parser_block.BrewingStand:
+		return "brewing_stand"
+	case *nbt_parser_block.Crafter:
+		return "crafter"
+	case *nbt_parser_block.Bed:
+		return "bed"
+	case *nbt_parser_block.Skull:
+		return "skull"
+	case *nbt_parser_block.FlowerPot:
+		return "flower_pot"
+	case *nbt_parser_block.DecoratedPot:
+		return "decorated_pot"
+	case *nbt_parser_block.ChiseledBookshelf:
+		return "chiseled_bookshelf"
+	case *nbt_parser_block.MobSpawner:
+		return "mob_spawner"
+	case *nbt_parser_block.Campfire:
+		return "campfire"
+	}
+	return "other"
+}
@@ -0,0 +1,75 @@
+package nbt_block
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/metrics"
+)
+
+// cacheLookupSeries 返回默认注册表导出的文本中，
+// cacheLookups 的各个样本的值
+func cacheLookupSeries(t *testing.T) map[string]float64 {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	if err := metrics.DefaultRegistry.WriteText(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	series := make(map[string]float64)
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "flowers_for_machines_nbt_block_cache_lookups_total{") {
+			continue
+		}
+		index := strings.LastIndex(line, " ")
+		value, err := strconv.ParseFloat(line[index+1:], 64)
+		if err != nil {
+			t.Fatalf("malformed line %q", line)
+		}
+		series[line[:index]] = value
+	}
+	return series
+}
+
+func TestCacheLookupsExposition(t *testing.T) {
+	const (
+		hit        = `flowers_for_machines_nbt_block_cache_lookups_total{result="hit"}`
+		partialHit = `flowers_for_machines_nbt_block_cache_lookups_total{result="partial_hit"}`
+		miss       = `flowers_for_machines_nbt_block_cache_lookups_total{result="miss"}`
+	)
+	before := cacheLookupSeries(t)
+
+	// 第一次制作时未命中，第二次制作时完整命中
+	recordCacheLookup(false, false)
+	recordCacheLookup(true, false)
+
+	after := cacheLookupSeries(t)
+	if after[miss]-before[miss] != 1 {
+		t.Fatalf("unexpected miss count %v (before %v)", after[miss], before[miss])
+	}
+	if after[hit]-before[hit] != 1 {
+		t.Fatalf("unexpected hit count %v (before %v)", after[hit], before[hit])
+	}
+	if after[partialHit] != before[partialHit] {
+		t.Fatalf("unexpected partial hit count %v (before %v)", after[partialHit], before[partialHit])
+	}
+
+	recordCacheLookup(true, true)
+	if after, final := after[partialHit], cacheLookupSeries(t)[partialHit]; final-after != 1 {
+		t.Fatalf("unexpected partial hit count %v (before %v)", final, after)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := metrics.DefaultRegistry.WriteText(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "# TYPE flowers_for_machines_nbt_block_cache_lookups_total counter\n") {
+		t.Fatalf("missing TYPE line in %q", buf.String())
+	}
+}
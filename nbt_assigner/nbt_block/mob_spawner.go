@@ -0,0 +1,111 @@
+package nbt_block
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+)
+
+// 刷怪笼
+type MobSpawner struct {
+	console *nbt_console.Console
+	data    nbt_parser_block.MobSpawner
+}
+
+func (MobSpawner) Offset() protocol.BlockPos {
+	return protocol.BlockPos{0, 0, 0}
+}
+
+// DroppedFields 返回刷怪笼中无法被还原的字段的名称。
+// 刷怪蛋只能设置刷怪笼所生成的实体，而其余字段总是
+// 使用默认值，因此与默认值不同的字段都无法被还原。
+// 如果刷怪蛋不存在，则实体类型也无法被还原
+func (m MobSpawner) DroppedFields() (result []string) {
+	if len(m.data.NBT.DroppedEntityIdentifier) > 0 {
+		result = append(result, "EntityIdentifier")
+	}
+	for _, field := range mapping.MobSpawnerFields {
+		value, ok := m.data.NBT.Fields[field.Name]
+		if ok && value != field.DefaultValue {
+			result = append(result, field.Name)
+		}
+	}
+	return
+}
+
+func (m *MobSpawner) Make() error {
+	api := m.console.API()
+
+	// 检查刷怪蛋是否存在
+	eggName := m.data.SpawnEggName()
+	if !api.Resources().ConstantPacket().ItemCanGetByCommand(eggName) {
+		return fmt.Errorf("Make: Spawn egg %#v of entity %#v is not exist", eggName, m.data.NBT.EntityIdentifier)
+	}
+
+	// 放置空的刷怪笼
+	err := api.SetBlock().SetBlock(m.console.Center(), "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	err = api.SetBlock().SetBlock(m.console.Center(), m.data.BlockName(), m.data.BlockStatesString())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	m.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        m.data.BlockName(),
+		States:      m.data.BlockStates(),
+	})
+
+	// 只有在创造模式下使用刷怪蛋才能改变刷怪笼所生成的实体。
+	// 操作台在初始化时已将机器人切换到创造模式，但租赁服的
+	// 管理员可能在此后更改了它，因此在使用刷怪蛋前再次切换。
+	// 如果切换仍然失败，那么完整性检查会发现刷怪笼没有被改变
+	err = api.Commands().SendSettingsCommand("gamemode 1", true)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 获取刷怪蛋
+	err = api.Replaceitem().ReplaceitemInInventory(
+		"@s",
+		game_interface.ReplacePathHotbarOnly,
+		game_interface.ReplaceitemInfo{
+			Name:     eggName,
+			Count:    1,
+			MetaData: 0,
+			Slot:     m.console.HotbarSlotID(),
+		},
+		"",
+		true,
+	)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	m.console.UseInventorySlot(nbt_console.RequesterUser, m.console.HotbarSlotID(), true)
+
+	// 前往操作台中心处
+	err = m.console.CanReachOrMove(m.console.Center())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 对刷怪笼使用刷怪蛋
+	err = api.BotClick().ClickBlock(game_interface.UseItemOnBlocks{
+		HotbarSlotID: m.console.HotbarSlotID(),
+		BotPos:       m.console.Position(),
+		BlockPos:     m.console.Center(),
+		BlockName:    m.data.BlockName(),
+		BlockStates:  m.data.BlockStates(),
+	})
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	return nil
+}
@@ -56,7 +56,12 @@ func simpleStructureGetter(console *nbt_console.Console) (nbtMap map[string]any,
 		return nil, fmt.Errorf("simpleStructureGetter: %v", err)
 	}
 
-	<-channel
+	select {
+	case <-channel:
+	case <-api.Resources().Closed():
+		api.PacketListener().DestroyListener(uniqueID)
+		return nil, fmt.Errorf("simpleStructureGetter: Connection closed; err = %v", api.Resources().CloseError())
+	}
 	api.PacketListener().DestroyListener(uniqueID)
 
 	m := resp.StructureTemplate
@@ -0,0 +1,121 @@
+package nbt_block
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+)
+
+// 头颅
+type Skull struct {
+	console *nbt_console.Console
+	data    nbt_parser_block.Skull
+}
+
+func (Skull) Offset() protocol.BlockPos {
+	return protocol.BlockPos{0, 0, 0}
+}
+
+func (s *Skull) Make() error {
+	api := s.console.API()
+
+	// 清空操作台中心处的方块
+	err := api.SetBlock().SetBlock(s.console.Center(), "minecraft:air", "[]")
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	s.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.Air{})
+
+	// 获取相应种类的头颅
+	err = api.Replaceitem().ReplaceitemInInventory(
+		"@s",
+		game_interface.ReplacePathHotbarOnly,
+		game_interface.ReplaceitemInfo{
+			Name:     "minecraft:skull",
+			Count:    1,
+			MetaData: int16(s.data.NBT.SkullType),
+			Slot:     s.console.HotbarSlotID(),
+		},
+		"",
+		true,
+	)
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	s.console.UseInventorySlot(nbt_console.RequesterUser, s.console.HotbarSlotID(), true)
+
+	// 前往操作台中心处
+	err = s.console.CanReachOrMove(s.console.Center())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+
+	// 放置头颅
+	if s.data.IsStanding() {
+		// 放置在地面上的头颅的旋转角度与机器人的
+		// 朝向相反，因此需要使机器人朝向相应的方向
+		offsetPos := protocol.BlockPos{0, -1, 0}
+		floorPos := s.console.NearBlockPosByIndex(nbt_console.ConsoleIndexCenterBlock, offsetPos)
+		err = api.SetBlock().SetBlock(floorPos, game_interface.BasePlaceBlock, "[]")
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		*s.console.NearBlockByIndex(nbt_console.ConsoleIndexCenterBlock, offsetPos) = block_helper.NearBlock{
+			Name: game_interface.BasePlaceBlock,
+		}
+
+		err = api.BotClick().PlaceBlock(
+			game_interface.UseItemOnBlocks{
+				HotbarSlotID: s.console.HotbarSlotID(),
+				BotPos:       s.console.Position(),
+				BlockPos:     floorPos,
+				BlockName:    game_interface.BasePlaceBlock,
+				BlockStates:  map[string]any{},
+				BotYaw:       s.data.NBT.Rotation - 180,
+			},
+			1,
+		)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		err = api.Commands().AwaitChangesGeneral()
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+	} else {
+		facingDirection, _ := s.data.BlockStates()["facing_direction"].(int32)
+		_, offsetPos, err := api.BotClick().PlaceBlockHighLevel(
+			s.console.Center(),
+			s.console.Position(),
+			s.console.HotbarSlotID(),
+			uint8(facingDirection),
+		)
+		if err != nil {
+			return fmt.Errorf("Make: %v", err)
+		}
+		*s.console.NearBlockByIndex(nbt_console.ConsoleIndexCenterBlock, offsetPos) = block_helper.NearBlock{
+			Name: game_interface.BasePlaceBlock,
+		}
+	}
+	s.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: false,
+		Name:        s.data.BlockName(),
+	})
+
+	// 覆写头颅的方块状态
+	err = api.SetBlock().SetBlock(s.console.Center(), s.data.BlockName(), s.data.BlockStatesString())
+	if err != nil {
+		return fmt.Errorf("Make: %v", err)
+	}
+	s.console.UseHelperBlock(nbt_console.RequesterUser, nbt_console.ConsoleIndexCenterBlock, block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        s.data.BlockName(),
+		States:      s.data.BlockStates(),
+	})
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package base_container_cache
 
 import (
+	"sync"
+
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
 
 	"github.com/google/uuid"
@@ -12,6 +14,9 @@ type BaseContainerCache struct {
 	uniqueID string
 	// console 是机器人使用的操作台
 	console *nbt_console.Console
+	// mu 保护 cachedBaseContainer 和对 console 的更换，
+	// 它由共享同一份缓存的全部缓存命中系统共用
+	mu *sync.Mutex
 	// cachedBaseContainer 记载了已缓存的所有基容器
 	cachedBaseContainer map[uint64]StructureBaseContainer
 }
@@ -21,6 +26,32 @@ func NewBaseContainerCache(console *nbt_console.Console) *BaseContainerCache {
 	return &BaseContainerCache{
 		uniqueID:            uuid.NewString(),
 		console:             console,
+		mu:                  new(sync.Mutex),
 		cachedBaseContainer: make(map[uint64]StructureBaseContainer),
 	}
 }
+
+// ShareWith 返回一个基于操作台 console 的新的基容器缓存命中系统，
+// 它与 b 共享全部缓存。对其中一个的修改对另一个同样可见。
+//
+// 由于结构保存在存档中，因此 console 应当与 b 的操作台位于同一存档
+func (b *BaseContainerCache) ShareWith(console *nbt_console.Console) *BaseContainerCache {
+	return &BaseContainerCache{
+		uniqueID:            uuid.NewString(),
+		console:             console,
+		mu:                  b.mu,
+		cachedBaseContainer: b.cachedBaseContainer,
+	}
+}
+
+// SetConsole 将基容器缓存命中系统所使用的操作台更换为 console。
+// 已有的缓存将被保留，这使得机器人重新连接到租赁服后
+// 仍然可以继续使用它们。
+//
+// SetConsole 会等待持有缓存锁的操作完成，
+// 这些操作包括缓存的删除和清理
+func (b *BaseContainerCache) SetConsole(console *nbt_console.Console) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.console = console
+}
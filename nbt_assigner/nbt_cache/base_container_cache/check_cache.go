@@ -12,6 +12,9 @@ func (b *BaseContainerCache) CheckCache(name string, states map[string]any) (hit
 		BlockStatesString: utils.MarshalBlockStates(states),
 	}
 	hashNumber := container.Hash()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	_, hit = b.cachedBaseContainer[hashNumber]
 	return
 }
@@ -3,6 +3,7 @@ package base_container_cache
 import (
 	"bytes"
 	"strings"
+	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
@@ -24,6 +25,9 @@ type BaseContainer struct {
 type StructureBaseContainer struct {
 	UniqueID  uuid.UUID                           // 该容器所在结构的唯一标识符
 	Container block_helper.ContainerBlockOpenInfo // 该容器应当如何打开
+	CreatedAt time.Time                           // 该缓存被创建的时间
+	LastHitAt time.Time                           // 该缓存最近一次被命中的时间
+	HitCount  int                                 // 该缓存被命中的次数
 }
 
 // Hash 给出这个基容器的唯一哈希校验和。
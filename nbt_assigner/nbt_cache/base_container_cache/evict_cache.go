@@ -0,0 +1,25 @@
+package base_container_cache
+
+import "fmt"
+
+// DeleteCache 从缓存命中系统中删除哈希校验和为 hashNumber
+// 的基容器，并删除保存该基容器的结构。deleted 指示该缓存是否存在。
+//
+// 如果结构未能被删除，则缓存将被保留
+func (b *BaseContainerCache) DeleteCache(hashNumber uint64) (deleted bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	structure, ok := b.cachedBaseContainer[hashNumber]
+	if !ok {
+		return false, nil
+	}
+
+	err = b.console.API().StructureBackup().DeleteStructure(structure.UniqueID)
+	if err != nil {
+		return false, fmt.Errorf("DeleteCache: %v", err)
+	}
+	delete(b.cachedBaseContainer, hashNumber)
+
+	return true, nil
+}
@@ -1,6 +1,8 @@
 package base_container_cache
 
 import (
+	"time"
+
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
 	"github.com/OmineDev/flowers-for-machines/utils"
@@ -30,7 +32,9 @@ func (b *BaseContainerCache) LoadCache(
 	hashNumber := container.Hash()
 
 	// Try to load from internal structure record mapping
+	b.mu.Lock()
 	structure, ok := b.cachedBaseContainer[hashNumber]
+	b.mu.Unlock()
 	if !ok {
 		return false, nil
 	}
@@ -44,6 +48,15 @@ func (b *BaseContainerCache) LoadCache(
 		return false, nil
 	}
 
+	// Record hit
+	b.mu.Lock()
+	if current, ok := b.cachedBaseContainer[hashNumber]; ok {
+		current.HitCount++
+		current.LastHitAt = time.Now()
+		b.cachedBaseContainer[hashNumber] = current
+	}
+	b.mu.Unlock()
+
 	// Update underlying container data
 	newContainer := block_helper.ContainerBlockHelper{
 		OpenInfo: structure.Container,
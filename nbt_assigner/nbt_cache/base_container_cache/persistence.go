@@ -0,0 +1,104 @@
+package base_container_cache
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/google/uuid"
+)
+
+// CacheRecord 是 StructureBaseContainer 的可持久化形式
+type CacheRecord struct {
+	HashNumber            uint64 `json:"hash_number"`
+	UniqueID              string `json:"unique_id"`
+	BlockName             string `json:"block_name"`
+	BlockStatesString     string `json:"block_states_string"`
+	ConsiderOpenDirection bool   `json:"consider_open_direction"`
+	ShulkerFacing         uint8  `json:"shulker_facing"`
+
+	CreatedAt time.Time `json:"created_at"`
+	LastHitAt time.Time `json:"last_hit_at"`
+	HitCount  int       `json:"hit_count"`
+}
+
+// DumpCache 导出当前缓存命中系统中所有缓存的可持久化形式，
+// 返回的记录按哈希校验和升序排列
+func (b *BaseContainerCache) DumpCache() []CacheRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := make([]CacheRecord, 0, len(b.cachedBaseContainer))
+	for hashNumber, value := range b.cachedBaseContainer {
+		result = append(result, CacheRecord{
+			HashNumber:            hashNumber,
+			UniqueID:              value.UniqueID.String(),
+			BlockName:             value.Container.Name,
+			BlockStatesString:     utils.MarshalBlockStates(value.Container.States),
+			ConsiderOpenDirection: value.Container.ConsiderOpenDirection,
+			ShulkerFacing:         value.Container.ShulkerFacing,
+			CreatedAt:             value.CreatedAt,
+			LastHitAt:             value.LastHitAt,
+			HitCount:              value.HitCount,
+		})
+	}
+	slices.SortFunc(result, func(x, y CacheRecord) int {
+		return cmp.Compare(x.HashNumber, y.HashNumber)
+	})
+	return result
+}
+
+// RestoreCache 将 records 所记载的缓存恢复到当前缓存命中系统。
+// 对于每条记录，RestoreCache 都会检查其对应的结构是否仍然存在，
+// 结构不存在、无法导出 (例如导出超时) 或标识符无法解析的记录将被丢弃。
+//
+// restored 指示成功恢复的记录的数量，
+// 而 discarded 指示被丢弃的记录的数量
+func (b *BaseContainerCache) RestoreCache(records []CacheRecord) (restored int, discarded int) {
+	api := b.console.API().StructureBackup()
+
+	for _, record := range records {
+		b.mu.Lock()
+		_, ok := b.cachedBaseContainer[record.HashNumber]
+		b.mu.Unlock()
+		if ok {
+			continue
+		}
+
+		uniqueID, err := uuid.Parse(record.UniqueID)
+		if err != nil {
+			discarded++
+			continue
+		}
+		exist, _, err := api.ExportStructure(uniqueID)
+		if err != nil || !exist {
+			discarded++
+			continue
+		}
+
+		createdAt := record.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		b.mu.Lock()
+		b.cachedBaseContainer[record.HashNumber] = StructureBaseContainer{
+			UniqueID: uniqueID,
+			Container: block_helper.ContainerBlockOpenInfo{
+				Name:                  record.BlockName,
+				States:                utils.ParseBlockStatesString(record.BlockStatesString),
+				ConsiderOpenDirection: record.ConsiderOpenDirection,
+				ShulkerFacing:         record.ShulkerFacing,
+			},
+			CreatedAt: createdAt,
+			LastHitAt: record.LastHitAt,
+			HitCount:  record.HitCount,
+		}
+		b.mu.Unlock()
+		restored++
+	}
+
+	return restored, discarded
+}
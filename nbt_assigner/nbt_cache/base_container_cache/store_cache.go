@@ -2,6 +2,7 @@ package base_container_cache
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
@@ -29,7 +30,10 @@ func (b *BaseContainerCache) StoreCache(customName string, shulkerFacing uint8)
 	}
 	hashNumber := c.Hash()
 
-	if _, ok := b.cachedBaseContainer[hashNumber]; ok {
+	b.mu.Lock()
+	_, ok = b.cachedBaseContainer[hashNumber]
+	b.mu.Unlock()
+	if ok {
 		return nil
 	}
 
@@ -40,20 +44,32 @@ func (b *BaseContainerCache) StoreCache(customName string, shulkerFacing uint8)
 		return fmt.Errorf("StoreCache: %v", err)
 	}
 
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 其他共享此缓存的缓存命中系统可能已经保存了相同的基容器
+	if _, ok := b.cachedBaseContainer[hashNumber]; ok {
+		_ = b.console.API().StructureBackup().DeleteStructure(uniqueID)
+		return nil
+	}
 	b.cachedBaseContainer[hashNumber] = StructureBaseContainer{
 		UniqueID:  uniqueID,
 		Container: container.OpenInfo,
+		CreatedAt: time.Now(),
 	}
 	return nil
 }
 
 // CleanCache 清除该缓存命中系统中已有的全部缓存
 func (b *BaseContainerCache) CleanCache() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	api := b.console.API().StructureBackup()
 
 	for _, value := range b.cachedBaseContainer {
 		_ = api.DeleteStructure(value.UniqueID)
 	}
 
-	b.cachedBaseContainer = make(map[uint64]StructureBaseContainer)
+	clear(b.cachedBaseContainer)
 }
@@ -0,0 +1,189 @@
+package nbt_cache
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CacheKindBaseContainer = "base_container" // 基容器缓存
+	CacheKindNBTBlock      = "nbt_block"      // NBT 方块缓存
+)
+
+// CacheEntry 描述了缓存命中系统中的一条缓存
+type CacheEntry struct {
+	// Kind 是这条缓存所属的缓存命中系统，
+	// 可以是 CacheKindBaseContainer 或 CacheKindNBTBlock
+	Kind string
+	// HashNumber 是这条缓存的哈希校验和
+	HashNumber uint64
+	// BlockName 是这条缓存所储存的方块的名称
+	BlockName string
+	// UniqueID 是保存这条缓存的结构的唯一标识符
+	UniqueID uuid.UUID
+	// CreatedAt 是这条缓存被创建的时间
+	CreatedAt time.Time
+	// LastHitAt 是这条缓存最近一次被命中的时间，
+	// 为零值时表示它从未被命中
+	LastHitAt time.Time
+	// HitCount 是这条缓存被命中的次数
+	HitCount int
+}
+
+// LastUsedAt 返回这条缓存最近一次被使用的时间。
+// 从未被命中的缓存以其创建时间作为使用时间
+func (c CacheEntry) LastUsedAt() time.Time {
+	if c.LastHitAt.IsZero() {
+		return c.CreatedAt
+	}
+	return c.LastHitAt
+}
+
+// ListCache 列出基容器和 NBT 方块缓存命中系统中的全部缓存，
+// 返回的缓存按创建时间升序排列。
+//
+// 地图缓存由使用者通过登记的方式显式创建，
+// 因此它们不会被列出，也不会被驱逐
+func (n *NBTCacheSystem) ListCache() []CacheEntry {
+	result := make([]CacheEntry, 0)
+
+	for _, record := range n.b.DumpCache() {
+		uniqueID, _ := uuid.Parse(record.UniqueID)
+		result = append(result, CacheEntry{
+			Kind:       CacheKindBaseContainer,
+			HashNumber: record.HashNumber,
+			BlockName:  record.BlockName,
+			UniqueID:   uniqueID,
+			CreatedAt:  record.CreatedAt,
+			LastHitAt:  record.LastHitAt,
+			HitCount:   record.HitCount,
+		})
+	}
+	for _, record := range n.n.DumpCache() {
+		uniqueID, _ := uuid.Parse(record.UniqueID)
+		result = append(result, CacheEntry{
+			Kind:       CacheKindNBTBlock,
+			HashNumber: record.HashNumber,
+			BlockName:  record.BlockName,
+			UniqueID:   uniqueID,
+			CreatedAt:  record.CreatedAt,
+			LastHitAt:  record.LastHitAt,
+			HitCount:   record.HitCount,
+		})
+	}
+
+	slices.SortStableFunc(result, func(x, y CacheEntry) int {
+		return x.CreatedAt.Compare(y.CreatedAt)
+	})
+	return result
+}
+
+// EvictCache 驱逐种类为 kind 且哈希校验和为 hashNumber 的缓存，
+// 并删除保存该缓存的结构。evicted 指示该缓存是否存在。
+//
+// 与下方的其他驱逐操作相同，EvictCache 会等待共享同一份缓存的
+// 全部缓存命中系统完成正在进行的制作
+func (n *NBTCacheSystem) EvictCache(kind string, hashNumber uint64) (evicted bool, err error) {
+	n.usage.Lock()
+	defer n.usage.Unlock()
+
+	evicted, err = n.evictCache(kind, hashNumber)
+	if err != nil {
+		return false, fmt.Errorf("EvictCache: %v", err)
+	}
+	return evicted, nil
+}
+
+// evictCache 驱逐种类为 kind 且哈希校验和为 hashNumber 的缓存。
+// 调用者应当持有 n.usage 的写锁
+func (n *NBTCacheSystem) evictCache(kind string, hashNumber uint64) (evicted bool, err error) {
+	switch kind {
+	case CacheKindBaseContainer:
+		evicted, err = n.b.DeleteCache(hashNumber)
+	case CacheKindNBTBlock:
+		evicted, err = n.n.DeleteCache(hashNumber)
+	default:
+		return false, fmt.Errorf("evictCache: Unknown cache kind %#v", kind)
+	}
+	if err != nil {
+		return false, fmt.Errorf("evictCache: %v", err)
+	}
+	return evicted, nil
+}
+
+// evictEntries 驱逐 entries 中的全部缓存。
+// 遇到错误时，剩余的缓存将不会被驱逐。
+// 调用者应当持有 n.usage 的写锁
+func (n *NBTCacheSystem) evictEntries(entries []CacheEntry) (evicted int, err error) {
+	for _, entry := range entries {
+		ok, err := n.evictCache(entry.Kind, entry.HashNumber)
+		if err != nil {
+			return evicted, fmt.Errorf("evictEntries: %v", err)
+		}
+		if ok {
+			evicted++
+		}
+	}
+	return evicted, nil
+}
+
+// EvictCacheOlderThan 驱逐创建时间早于 maxAge 之前的全部缓存。
+// evicted 指示被驱逐的缓存的数量
+func (n *NBTCacheSystem) EvictCacheOlderThan(maxAge time.Duration) (evicted int, err error) {
+	n.usage.Lock()
+	defer n.usage.Unlock()
+
+	deadline := time.Now().Add(-maxAge)
+	entries := make([]CacheEntry, 0)
+
+	for _, entry := range n.ListCache() {
+		if entry.CreatedAt.Before(deadline) {
+			entries = append(entries, entry)
+		}
+	}
+
+	evicted, err = n.evictEntries(entries)
+	if err != nil {
+		return evicted, fmt.Errorf("EvictCacheOlderThan: %v", err)
+	}
+	return evicted, nil
+}
+
+// EvictCacheLRU 按最近最少使用的顺序驱逐缓存，
+// 直到剩余的缓存不多于 keep 条。
+// evicted 指示被驱逐的缓存的数量
+func (n *NBTCacheSystem) EvictCacheLRU(keep int) (evicted int, err error) {
+	n.usage.Lock()
+	defer n.usage.Unlock()
+
+	entries := n.ListCache()
+	if len(entries) <= max(keep, 0) {
+		return 0, nil
+	}
+
+	slices.SortStableFunc(entries, func(x, y CacheEntry) int {
+		return x.LastUsedAt().Compare(y.LastUsedAt())
+	})
+
+	evicted, err = n.evictEntries(entries[:len(entries)-max(keep, 0)])
+	if err != nil {
+		return evicted, fmt.Errorf("EvictCacheLRU: %v", err)
+	}
+	return evicted, nil
+}
+
+// PurgeCache 驱逐基容器和 NBT 方块缓存命中系统中的全部缓存，
+// 并删除保存它们的结构。evicted 指示被驱逐的缓存的数量
+func (n *NBTCacheSystem) PurgeCache() (evicted int, err error) {
+	n.usage.Lock()
+	defer n.usage.Unlock()
+
+	evicted, err = n.evictEntries(n.ListCache())
+	if err != nil {
+		return evicted, fmt.Errorf("PurgeCache: %v", err)
+	}
+	return evicted, nil
+}
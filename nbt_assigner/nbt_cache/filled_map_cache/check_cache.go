@@ -0,0 +1,10 @@
+package filled_map_cache
+
+// CheckCache 检索整个缓存命中系统，
+// 查询 UUID 为 mapUUID 的地图是否存在
+func (f *FilledMapCache) CheckCache(mapUUID int64) (hit bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, hit = f.cachedFilledMap[mapUUID]
+	return
+}
@@ -0,0 +1,16 @@
+package filled_map_cache
+
+import (
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+
+	"github.com/google/uuid"
+)
+
+// StructureFilledMap 指示了一个保存在结构中的地图。
+// 该地图被放置在结构中唯一的容器中
+type StructureFilledMap struct {
+	UniqueID  uuid.UUID                           // 该容器所在结构的唯一标识符
+	Container block_helper.ContainerBlockOpenInfo // 该容器应当如何打开
+	SlotID    resources_control.SlotID            // 地图在容器中的槽位
+}
@@ -0,0 +1,60 @@
+package filled_map_cache
+
+import (
+	"sync"
+
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/google/uuid"
+)
+
+// FilledMapCache 是基于操作台实现的地图缓存命中系统。
+// 它记载了每个地图 UUID 所对应的，保存在结构中的容器，
+// 使得已经存在于存档中的地图可以通过复制结构的方式得到
+type FilledMapCache struct {
+	// uniqueID 是当前缓存命中系统的唯一标识
+	uniqueID string
+	// console 是机器人使用的操作台
+	console *nbt_console.Console
+	// mu 保护 cachedFilledMap 和对 console 的更换，
+	// 它由共享同一份缓存的全部缓存命中系统共用
+	mu *sync.Mutex
+	// cachedFilledMap 记载了地图 UUID 到
+	// 装有该地图的结构的映射
+	cachedFilledMap map[int64]StructureFilledMap
+}
+
+// NewFilledMapCache 基于操作台 console 创建并返回一个新的地图缓存命中系统
+func NewFilledMapCache(console *nbt_console.Console) *FilledMapCache {
+	return &FilledMapCache{
+		uniqueID:        uuid.NewString(),
+		console:         console,
+		mu:              new(sync.Mutex),
+		cachedFilledMap: make(map[int64]StructureFilledMap),
+	}
+}
+
+// ShareWith 返回一个基于操作台 console 的新的地图缓存命中系统，
+// 它与 f 共享全部缓存。对其中一个的修改对另一个同样可见。
+//
+// 由于结构保存在存档中，因此 console 应当与 f 的操作台位于同一存档
+func (f *FilledMapCache) ShareWith(console *nbt_console.Console) *FilledMapCache {
+	return &FilledMapCache{
+		uniqueID:        uuid.NewString(),
+		console:         console,
+		mu:              f.mu,
+		cachedFilledMap: f.cachedFilledMap,
+	}
+}
+
+// SetConsole 将地图缓存命中系统所使用的操作台更换为 console。
+// 已有的缓存将被保留，这使得机器人重新连接到租赁服后
+// 仍然可以继续使用它们。
+//
+// SetConsole 会等待持有缓存锁的操作完成，
+// 这些操作包括缓存的删除和清理
+func (f *FilledMapCache) SetConsole(console *nbt_console.Console) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.console = console
+}
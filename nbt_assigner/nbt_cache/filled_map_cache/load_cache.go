@@ -0,0 +1,39 @@
+package filled_map_cache
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+)
+
+// LoadCache 在操作台索引为 index 的帮助方块处
+// 加载装有 UUID 为 mapUUID 的地图的容器。
+//
+// slotID 指示地图在该容器中的槽位。
+// 如果目标地图没有找到，则 hit 为假
+func (f *FilledMapCache) LoadCache(mapUUID int64, index int) (
+	hit bool,
+	slotID resources_control.SlotID,
+	err error,
+) {
+	f.mu.Lock()
+	structure, ok := f.cachedFilledMap[mapUUID]
+	f.mu.Unlock()
+	if !ok {
+		return false, 0, nil
+	}
+
+	err = f.console.API().StructureBackup().RevertStructure(
+		structure.UniqueID,
+		f.console.BlockPosByIndex(index),
+	)
+	if err != nil {
+		return false, 0, fmt.Errorf("LoadCache: %v", err)
+	}
+
+	f.console.UseHelperBlock(f.uniqueID, index, block_helper.ContainerBlockHelper{
+		OpenInfo: structure.Container,
+	})
+	return true, structure.SlotID, nil
+}
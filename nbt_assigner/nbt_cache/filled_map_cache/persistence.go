@@ -0,0 +1,99 @@
+package filled_map_cache
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/google/uuid"
+)
+
+// CacheRecord 是 StructureFilledMap 的可持久化形式
+type CacheRecord struct {
+	MapUUID               int64  `json:"map_uuid"`
+	UniqueID              string `json:"unique_id"`
+	BlockName             string `json:"block_name"`
+	BlockStatesString     string `json:"block_states_string"`
+	ConsiderOpenDirection bool   `json:"consider_open_direction"`
+	ShulkerFacing         uint8  `json:"shulker_facing"`
+	SlotID                uint8  `json:"slot_id"`
+}
+
+// DumpCache 导出当前缓存命中系统中所有缓存的可持久化形式，
+// 返回的记录按地图 UUID 升序排列
+func (f *FilledMapCache) DumpCache() []CacheRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]CacheRecord, 0, len(f.cachedFilledMap))
+	for mapUUID, value := range f.cachedFilledMap {
+		result = append(result, CacheRecord{
+			MapUUID:               mapUUID,
+			UniqueID:              value.UniqueID.String(),
+			BlockName:             value.Container.Name,
+			BlockStatesString:     utils.MarshalBlockStates(value.Container.States),
+			ConsiderOpenDirection: value.Container.ConsiderOpenDirection,
+			ShulkerFacing:         value.Container.ShulkerFacing,
+			SlotID:                uint8(value.SlotID),
+		})
+	}
+	slices.SortFunc(result, func(x, y CacheRecord) int {
+		return cmp.Compare(x.MapUUID, y.MapUUID)
+	})
+	return result
+}
+
+// RestoreCache 将 records 所记载的缓存恢复到当前缓存命中系统。
+// 对于每条记录，RestoreCache 都会检查其对应的结构是否仍然存在，
+// 结构不存在、无法导出 (例如导出超时) 或标识符无法解析的记录将被丢弃。
+//
+// restored 指示成功恢复的记录的数量，
+// 而 discarded 指示被丢弃的记录的数量
+func (f *FilledMapCache) RestoreCache(records []CacheRecord) (restored int, discarded int) {
+	api := f.console.API().StructureBackup()
+	structureExist := make(map[uuid.UUID]bool)
+
+	for _, record := range records {
+		f.mu.Lock()
+		_, ok := f.cachedFilledMap[record.MapUUID]
+		f.mu.Unlock()
+		if ok {
+			continue
+		}
+
+		uniqueID, err := uuid.Parse(record.UniqueID)
+		if err != nil {
+			discarded++
+			continue
+		}
+		exist, checked := structureExist[uniqueID]
+		if !checked {
+			exist, _, err = api.ExportStructure(uniqueID)
+			exist = exist && err == nil
+			structureExist[uniqueID] = exist
+		}
+		if !exist {
+			discarded++
+			continue
+		}
+
+		f.mu.Lock()
+		f.cachedFilledMap[record.MapUUID] = StructureFilledMap{
+			UniqueID: uniqueID,
+			Container: block_helper.ContainerBlockOpenInfo{
+				Name:                  record.BlockName,
+				States:                utils.ParseBlockStatesString(record.BlockStatesString),
+				ConsiderOpenDirection: record.ConsiderOpenDirection,
+				ShulkerFacing:         record.ShulkerFacing,
+			},
+			SlotID: resources_control.SlotID(record.SlotID),
+		}
+		f.mu.Unlock()
+		restored++
+	}
+
+	return restored, discarded
+}
@@ -0,0 +1,116 @@
+package filled_map_cache
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/nbt_block_cache"
+	nbt_parser_block "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+)
+
+// StoreFromWorld 将存档中 pos 处的容器保存到当前缓存命中系统。
+// 该容器中所有已初始化的地图都将被记录，这使得它们在此后可以
+// 通过复制结构的方式得到。
+//
+// 机器人会在必要时被传送到 pos 附近。
+// mapUUIDs 指示新记录的地图的 UUID
+func (f *FilledMapCache) StoreFromWorld(pos protocol.BlockPos) (mapUUIDs []int64, err error) {
+	api := f.console.API()
+
+	// Step 1: Backup the container
+	err = f.console.CanReachOrMove(pos)
+	if err != nil {
+		return nil, fmt.Errorf("StoreFromWorld: %v", err)
+	}
+	uniqueID, err := api.StructureBackup().BackupStructure(pos)
+	if err != nil {
+		return nil, fmt.Errorf("StoreFromWorld: %v", err)
+	}
+	defer func() {
+		if len(mapUUIDs) == 0 {
+			_ = api.StructureBackup().DeleteStructure(uniqueID)
+		}
+	}()
+
+	// Step 2: Parse the container
+	exist, template, err := api.StructureBackup().ExportStructure(uniqueID)
+	if err != nil {
+		return nil, fmt.Errorf("StoreFromWorld: %v", err)
+	}
+	if !exist {
+		return nil, fmt.Errorf("StoreFromWorld: The structure of the block at %#v is not found", pos)
+	}
+	blockName, blockStates, blockNBT, err := nbt_block_cache.BlockFromTemplate(template, protocol.BlockPos{0, 0, 0})
+	if err != nil {
+		return nil, fmt.Errorf("StoreFromWorld: %v", err)
+	}
+	block, err := nbt_parser_interface.ParseBlock(
+		api.Resources().ConstantPacket().ItemCanGetByCommand,
+		blockName,
+		blockStates,
+		blockNBT,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("StoreFromWorld: %v", err)
+	}
+
+	container, ok := block.(*nbt_parser_block.Container)
+	if crafter, isCrafter := block.(*nbt_parser_block.Crafter); isCrafter {
+		container, ok = crafter.AsContainer(), true
+	}
+	if !ok {
+		return nil, fmt.Errorf("StoreFromWorld: The block at %#v is not a container; blockName = %#v", pos, blockName)
+	}
+
+	// Step 3: Record filled maps
+	openInfo := block_helper.ContainerBlockOpenInfo{
+		Name:                  container.BlockName(),
+		States:                container.BlockStates(),
+		ConsiderOpenDirection: container.ConsiderOpenDirection(),
+		ShulkerFacing:         container.NBT.ShulkerFacing,
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, value := range container.NBT.Items {
+		filledMap, ok := value.Item.(*nbt_parser_item.FilledMap)
+		if !ok || !filledMap.IsComplex() {
+			continue
+		}
+		if _, ok := f.cachedFilledMap[filledMap.NBT.MapUUID]; ok {
+			continue
+		}
+		f.cachedFilledMap[filledMap.NBT.MapUUID] = StructureFilledMap{
+			UniqueID:  uniqueID,
+			Container: openInfo,
+			SlotID:    resources_control.SlotID(value.Slot),
+		}
+		mapUUIDs = append(mapUUIDs, filledMap.NBT.MapUUID)
+	}
+
+	return mapUUIDs, nil
+}
+
+// CleanCache 清除该缓存命中系统中已有的全部缓存
+func (f *FilledMapCache) CleanCache() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	api := f.console.API().StructureBackup()
+
+	deleted := make(map[string]bool)
+	for _, value := range f.cachedFilledMap {
+		if deleted[value.UniqueID.String()] {
+			continue
+		}
+		_ = api.DeleteStructure(value.UniqueID)
+		deleted[value.UniqueID.String()] = true
+	}
+
+	clear(f.cachedFilledMap)
+}
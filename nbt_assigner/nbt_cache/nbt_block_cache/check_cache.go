@@ -1,19 +1,28 @@
 package nbt_block_cache
 
 import (
+	"time"
+
 	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
 )
 
 // CheckCache 检索整个缓存命中系统，查询 hashNumber 是否存在。
 // 返回的 structure 指示命中的结果；
-// 返回的 isSetHashHit 指示命中的缓存是否是集合哈希校验和
+// 返回的 isSetHashHit 指示命中的缓存是否是集合哈希校验和。
+//
+// 每次命中都会更新该缓存的命中次数和最近命中时间
 func (n *NBTBlockCache) CheckCache(hashNumber nbt_hash.CompletelyHashNumber) (
 	structure StructureNBTBlock,
 	hit bool,
 	isSetHashHit bool,
 ) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
 	cache, ok := n.completelyCache[hashNumber.HashNumber]
 	if ok {
+		cache.HitCount++
+		cache.LastHitAt = time.Now()
 		return *cache, true, false
 	}
 
@@ -21,9 +30,11 @@ func (n *NBTBlockCache) CheckCache(hashNumber nbt_hash.CompletelyHashNumber) (
 		return StructureNBTBlock{}, false, false
 	}
 
-	cache, ok = n.setHashCache[hashNumber.HashNumber]
+	cache, ok = n.setHashCache[hashNumber.SetHashNumber]
 	if ok {
-		return *cache, true, false
+		cache.HitCount++
+		cache.LastHitAt = time.Now()
+		return *cache, true, true
 	}
 
 	return StructureNBTBlock{}, false, false
@@ -1,6 +1,8 @@
 package nbt_block_cache
 
 import (
+	"time"
+
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
 	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
 	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
@@ -19,4 +21,11 @@ type StructureNBTBlock struct {
 	Offset protocol.BlockPos
 	// Block 是这个结构储存的方块实体
 	Block nbt_parser_interface.Block
+	// CreatedAt 是这个缓存被创建的时间
+	CreatedAt time.Time
+	// LastHitAt 是这个缓存最近一次被命中的时间，
+	// 为零值时表示它从未被命中
+	LastHitAt time.Time
+	// HitCount 是这个缓存被命中的次数
+	HitCount int
 }
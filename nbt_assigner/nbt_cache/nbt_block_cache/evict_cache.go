@@ -0,0 +1,44 @@
+package nbt_block_cache
+
+import (
+	"fmt"
+
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+)
+
+// DeleteCache 从缓存命中系统中删除完整哈希校验和为 hashNumber
+// 的缓存，并删除保存该缓存的结构。deleted 指示该缓存是否存在。
+//
+// 如果结构未能被删除，则缓存将被保留
+func (n *NBTBlockCache) DeleteCache(hashNumber uint64) (deleted bool, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	structure, ok := n.completelyCache[hashNumber]
+	if !ok {
+		return false, nil
+	}
+
+	err = n.console.API().StructureBackup().DeleteStructure(structure.UniqueID)
+	if err != nil {
+		return false, fmt.Errorf("DeleteCache: %v", err)
+	}
+	delete(n.completelyCache, hashNumber)
+
+	setHashNumber := structure.HashNumber.SetHashNumber
+	if setHashNumber == nbt_hash.SetHashNumberNotExist || n.setHashCache[setHashNumber] != structure {
+		return true, nil
+	}
+
+	// 集合哈希校验和可能被多个缓存共享，
+	// 因此尝试让它指向剩余的另一个缓存
+	delete(n.setHashCache, setHashNumber)
+	for _, value := range n.completelyCache {
+		if value.HashNumber.SetHashNumber == setHashNumber {
+			n.setHashCache[setHashNumber] = value
+			break
+		}
+	}
+
+	return true, nil
+}
@@ -41,6 +41,9 @@ func (n *NBTBlockCache) LoadCache(hashNumber nbt_hash.CompletelyHashNumber) (
 	}
 
 	container, ok := structure.Block.(*nbt_parser_block.Container)
+	if crafter, isCrafter := structure.Block.(*nbt_parser_block.Crafter); isCrafter {
+		container, ok = crafter.AsContainer(), true
+	}
 	if ok {
 		n.console.UseHelperBlock(
 			n.uniqueID,
@@ -1,6 +1,8 @@
 package nbt_block_cache
 
 import (
+	"sync"
+
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
 
 	"github.com/google/uuid"
@@ -12,6 +14,9 @@ type NBTBlockCache struct {
 	uniqueID string
 	// console 是机器人使用的操作台
 	console *nbt_console.Console
+	// mu 保护 completelyCache、setHashCache 和对 console 的更换，
+	// 它由共享同一份缓存的全部缓存命中系统共用
+	mu *sync.Mutex
 	// completelyCache 记载了已缓存的所有 NBT 方块，
 	// 它指示 NBT 方块的完整哈希校验和到缓存数据结构
 	// 的映射
@@ -27,7 +32,34 @@ func NewNBTBlockCache(console *nbt_console.Console) *NBTBlockCache {
 	return &NBTBlockCache{
 		uniqueID:        uuid.NewString(),
 		console:         console,
+		mu:              new(sync.Mutex),
 		completelyCache: make(map[uint64]*StructureNBTBlock),
 		setHashCache:    make(map[uint64]*StructureNBTBlock),
 	}
 }
+
+// ShareWith 返回一个基于操作台 console 的新的 NBT 方块缓存命中系统，
+// 它与 n 共享全部缓存。对其中一个的修改对另一个同样可见。
+//
+// 由于结构保存在存档中，因此 console 应当与 n 的操作台位于同一存档
+func (n *NBTBlockCache) ShareWith(console *nbt_console.Console) *NBTBlockCache {
+	return &NBTBlockCache{
+		uniqueID:        uuid.NewString(),
+		console:         console,
+		mu:              n.mu,
+		completelyCache: n.completelyCache,
+		setHashCache:    n.setHashCache,
+	}
+}
+
+// SetConsole 将 NBT 方块缓存命中系统所使用的操作台更换为 console。
+// 已有的缓存将被保留，这使得机器人重新连接到租赁服后
+// 仍然可以继续使用它们。
+//
+// SetConsole 会等待持有缓存锁的操作完成，
+// 这些操作包括缓存的删除和清理
+func (n *NBTBlockCache) SetConsole(console *nbt_console.Console) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.console = console
+}
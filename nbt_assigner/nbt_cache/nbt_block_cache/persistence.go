@@ -0,0 +1,204 @@
+package nbt_block_cache
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+
+	"github.com/google/uuid"
+)
+
+// CacheRecord 是 StructureNBTBlock 的可持久化形式。
+// 它不包含方块实体数据本身，这些数据将在加载时
+// 重新从游戏中的结构读取。
+//
+// BlockName 仅供查阅，恢复缓存时不会使用它
+type CacheRecord struct {
+	UniqueID      string            `json:"unique_id"`
+	HashNumber    uint64            `json:"hash_number"`
+	SetHashNumber uint64            `json:"set_hash_number"`
+	Offset        protocol.BlockPos `json:"offset"`
+	BlockName     string            `json:"block_name,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	LastHitAt     time.Time         `json:"last_hit_at"`
+	HitCount      int               `json:"hit_count"`
+}
+
+// DumpCache 导出当前缓存命中系统中所有缓存的可持久化形式，
+// 返回的记录按哈希校验和升序排列
+func (n *NBTBlockCache) DumpCache() []CacheRecord {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	result := make([]CacheRecord, 0, len(n.completelyCache))
+	for _, value := range n.completelyCache {
+		result = append(result, CacheRecord{
+			UniqueID:      value.UniqueID.String(),
+			HashNumber:    value.HashNumber.HashNumber,
+			SetHashNumber: value.HashNumber.SetHashNumber,
+			Offset:        value.Offset,
+			BlockName:     value.Block.BlockName(),
+			CreatedAt:     value.CreatedAt,
+			LastHitAt:     value.LastHitAt,
+			HitCount:      value.HitCount,
+		})
+	}
+	slices.SortFunc(result, func(x, y CacheRecord) int {
+		return cmp.Compare(x.HashNumber, y.HashNumber)
+	})
+	return result
+}
+
+// RestoreCache 将 records 所记载的缓存恢复到当前缓存命中系统。
+//
+// 对于每条记录，RestoreCache 都会检查其对应的结构是否
+// 仍然存在，并从该结构重新解析出 NBT 方块。结构不存在、
+// 无法导出 (例如导出超时) 或无法解析的记录将被丢弃。
+//
+// restored 指示成功恢复的记录的数量，
+// 而 discarded 指示被丢弃的记录的数量
+func (n *NBTBlockCache) RestoreCache(records []CacheRecord) (restored int, discarded int) {
+	api := n.console.API()
+
+	for _, record := range records {
+		n.mu.Lock()
+		_, ok := n.completelyCache[record.HashNumber]
+		n.mu.Unlock()
+		if ok {
+			continue
+		}
+
+		uniqueID, err := uuid.Parse(record.UniqueID)
+		if err != nil {
+			discarded++
+			continue
+		}
+
+		exist, template, err := api.StructureBackup().ExportStructure(uniqueID)
+		if err != nil || !exist {
+			discarded++
+			continue
+		}
+
+		blockName, blockStates, blockNBT, err := BlockFromTemplate(template, record.Offset)
+		if err != nil {
+			discarded++
+			continue
+		}
+		block, err := nbt_parser_interface.ParseBlock(
+			api.Resources().ConstantPacket().ItemCanGetByCommand,
+			blockName,
+			blockStates,
+			blockNBT,
+		)
+		if err != nil {
+			discarded++
+			continue
+		}
+
+		structure := StructureNBTBlock{
+			UniqueID: uniqueID,
+			HashNumber: nbt_hash.CompletelyHashNumber{
+				HashNumber:    record.HashNumber,
+				SetHashNumber: record.SetHashNumber,
+			},
+			Offset:    record.Offset,
+			Block:     block,
+			CreatedAt: record.CreatedAt,
+			LastHitAt: record.LastHitAt,
+			HitCount:  record.HitCount,
+		}
+		// 旧版本的缓存文件没有记载创建时间
+		if structure.CreatedAt.IsZero() {
+			structure.CreatedAt = time.Now()
+		}
+
+		n.mu.Lock()
+		n.completelyCache[record.HashNumber] = &structure
+		if record.SetHashNumber != nbt_hash.SetHashNumberNotExist {
+			if _, ok := n.setHashCache[record.SetHashNumber]; !ok {
+				n.setHashCache[record.SetHashNumber] = &structure
+			}
+		}
+		n.mu.Unlock()
+		restored++
+	}
+
+	return restored, discarded
+}
+
+// BlockFromTemplate 从结构模板 template 中取出 NBT 方块的数据。
+// offset 是保存该结构时使用的偏移，它被用于定位 NBT 方块本身
+// 在结构中的位置
+func BlockFromTemplate(template map[string]any, offset protocol.BlockPos) (
+	blockName string,
+	blockStates map[string]any,
+	blockNBT map[string]any,
+	err error,
+) {
+	var pos, size [3]int32
+	for i := range 3 {
+		if offset[i] < 0 {
+			pos[i] = -offset[i]
+			size[i] = -offset[i] + 1
+		} else {
+			size[i] = offset[i] + 1
+		}
+	}
+	index := pos[0]*size[1]*size[2] + pos[1]*size[2] + pos[2]
+
+	structure, ok := template["structure"].(map[string]any)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Broken structure template (structure not found)")
+	}
+	palettes, _ := structure["palette"].(map[string]any)
+	palette, ok := palettes["default"].(map[string]any)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Broken structure template (default palette not found)")
+	}
+
+	layers, _ := structure["block_indices"].([]any)
+	if len(layers) == 0 {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Broken structure template (block indices not found)")
+	}
+	// 整数列表被解码为 []int32，而非 []any
+	primaryLayer, ok := layers[0].([]int32)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Broken structure template (block indices is not int32 list)")
+	}
+	if int(index) >= len(primaryLayer) {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Block index %d is out of range (length = %d)", index, len(primaryLayer))
+	}
+	blockIndex := primaryLayer[index]
+
+	blockPalette, _ := palette["block_palette"].([]any)
+	if blockIndex < 0 || int(blockIndex) >= len(blockPalette) {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Palette index %d is out of range (length = %d)", blockIndex, len(blockPalette))
+	}
+	paletteBlock, _ := blockPalette[blockIndex].(map[string]any)
+	blockName, ok = paletteBlock["name"].(string)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("BlockFromTemplate: Broken structure template (block name not found)")
+	}
+	blockStates, _ = paletteBlock["states"].(map[string]any)
+	if blockStates == nil {
+		blockStates = make(map[string]any)
+	}
+
+	blockNBT = make(map[string]any)
+	positionData, _ := palette["block_position_data"].(map[string]any)
+	data, ok := positionData[strconv.Itoa(int(index))].(map[string]any)
+	if ok {
+		if entityData, ok := data["block_entity_data"].(map[string]any); ok {
+			blockNBT = entityData
+		}
+	}
+
+	return blockName, blockStates, blockNBT, nil
+}
@@ -2,6 +2,7 @@ package nbt_block_cache
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
 	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
@@ -10,51 +11,70 @@ import (
 
 // StoreCache 将操作台中心处的 NBT 方块保存到当前的缓存命中系统。
 // block 是操作台中心处的 NBT 方块数据；
-// offset 是相对于这个 NBT 方块的偏移，例如床尾相对于床头的偏移
-func (n *NBTBlockCache) StoreCache(block nbt_parser_interface.Block, offset protocol.BlockPos) error {
-	var err error
-
+// offset 是相对于这个 NBT 方块的偏移，例如床尾相对于床头的偏移。
+//
+// stored 是缓存命中系统中保存的缓存。如果相同的方块
+// 已经被保存，则 stored 是已有的缓存
+func (n *NBTBlockCache) StoreCache(block nbt_parser_interface.Block, offset protocol.BlockPos) (
+	stored StructureNBTBlock,
+	err error,
+) {
 	structure := StructureNBTBlock{
 		HashNumber: nbt_hash.CompletelyHashNumber{
 			HashNumber:    nbt_hash.NBTBlockFullHash(block),
 			SetHashNumber: nbt_hash.ContainerSetHash(block),
 		},
-		Offset: offset,
-		Block:  block,
+		Offset:    offset,
+		Block:     block,
+		CreatedAt: time.Now(),
 	}
 
-	_, ok := n.completelyCache[structure.HashNumber.HashNumber]
-	if ok {
-		return nil
+	n.mu.Lock()
+	if existing, ok := n.completelyCache[structure.HashNumber.HashNumber]; ok {
+		stored = *existing
+		n.mu.Unlock()
+		return stored, nil
 	}
+	n.mu.Unlock()
 
 	structure.UniqueID, err = n.console.API().StructureBackup().BackupOffset(
 		n.console.Center(),
 		structure.Offset,
 	)
 	if err != nil {
-		return fmt.Errorf("StoreCache: %v", err)
+		return StructureNBTBlock{}, fmt.Errorf("StoreCache: %v", err)
 	}
 
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// 其他共享此缓存的缓存命中系统可能已经保存了相同的方块
+	if existing, ok := n.completelyCache[structure.HashNumber.HashNumber]; ok {
+		_ = n.console.API().StructureBackup().DeleteStructure(structure.UniqueID)
+		return *existing, nil
+	}
 	n.completelyCache[structure.HashNumber.HashNumber] = &structure
 	if structure.HashNumber.SetHashNumber == nbt_hash.SetHashNumberNotExist {
-		return nil
+		return structure, nil
 	}
 
 	if _, ok := n.setHashCache[structure.HashNumber.SetHashNumber]; !ok {
 		n.setHashCache[structure.HashNumber.SetHashNumber] = &structure
 	}
-	return nil
+	return structure, nil
 }
 
 // CleanCache 清除该缓存命中系统中已有的全部缓存
 func (n *NBTBlockCache) CleanCache() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
 	api := n.console.API().StructureBackup()
 
 	for _, value := range n.completelyCache {
 		_ = api.DeleteStructure(value.UniqueID)
 	}
 
-	n.completelyCache = make(map[uint64]*StructureNBTBlock)
-	n.setHashCache = make(map[uint64]*StructureNBTBlock)
+	clear(n.completelyCache)
+	clear(n.setHashCache)
 }
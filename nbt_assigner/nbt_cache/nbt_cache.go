@@ -1,7 +1,11 @@
 package nbt_cache
 
 import (
+	"sync"
+	"time"
+
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/base_container_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/filled_map_cache"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/nbt_block_cache"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
 )
@@ -10,6 +14,33 @@ import (
 type NBTCacheSystem struct {
 	b *base_container_cache.BaseContainerCache
 	n *nbt_block_cache.NBTBlockCache
+	f *filled_map_cache.FilledMapCache
+	// persistence 是缓存的持久化状态，
+	// 它由共享同一份缓存的全部缓存命中系统共用
+	persistence *persistence
+	// usage 在制作 NBT 方块期间被读锁定，而在驱逐缓存期间
+	// 被写锁定，从而避免缓存在被某个机器人使用时被驱逐。
+	// 它由共享同一份缓存的全部缓存命中系统共用
+	usage *sync.RWMutex
+}
+
+// persistence 是缓存的持久化状态
+type persistence struct {
+	mu *sync.Mutex
+	// file 是缓存的持久化文件路径，
+	// 为空时表示不对缓存进行持久化
+	file string
+	// lastSaved 是最近一次写入持久化文件的内容
+	lastSaved []byte
+
+	// syncTimer 是尚未触发的延迟写入，
+	// 为空时表示没有待进行的写入。
+	// 它由 timerMu 保护，而不是 mu
+	syncTimer *time.Timer
+	// onSyncError 在延迟写入失败时被调用，可以为空。
+	// 它由 timerMu 保护，而不是 mu
+	onSyncError func(err error)
+	timerMu     *sync.Mutex
 }
 
 // NewNBTCacheSystem 基于操作台 console 创建并返回一个新的 NBT 缓存命中系统
@@ -17,9 +48,43 @@ func NewNBTCacheSystem(console *nbt_console.Console) *NBTCacheSystem {
 	return &NBTCacheSystem{
 		b: base_container_cache.NewBaseContainerCache(console),
 		n: nbt_block_cache.NewNBTBlockCache(console),
+		f: filled_map_cache.NewFilledMapCache(console),
+		persistence: &persistence{
+			mu:      new(sync.Mutex),
+			timerMu: new(sync.Mutex),
+		},
+		usage: new(sync.RWMutex),
+	}
+}
+
+// ShareWith 返回一个基于操作台 console 的新的 NBT 缓存命中系统，
+// 它与 n 共享全部缓存及其持久化文件。这使得位于不同操作台的多个
+// 机器人可以使用彼此制作的缓存。
+//
+// 由于缓存所引用的结构保存在存档中，因此 console 应当
+// 与 n 的操作台位于同一存档
+func (n *NBTCacheSystem) ShareWith(console *nbt_console.Console) *NBTCacheSystem {
+	return &NBTCacheSystem{
+		b:           n.b.ShareWith(console),
+		n:           n.n.ShareWith(console),
+		f:           n.f.ShareWith(console),
+		persistence: n.persistence,
+		usage:       n.usage,
 	}
 }
 
+// BeginUse 标记缓存正在被用于制作 NBT 方块。
+// 在对应的 EndUse 被调用前，共享同一份缓存的
+// 全部缓存命中系统都无法驱逐缓存
+func (n *NBTCacheSystem) BeginUse() {
+	n.usage.RLock()
+}
+
+// EndUse 结束由 BeginUse 开始的使用
+func (n *NBTCacheSystem) EndUse() {
+	n.usage.RUnlock()
+}
+
 // BaseContainerCache 返回基容器缓存命中系统
 func (n *NBTCacheSystem) BaseContainerCache() *base_container_cache.BaseContainerCache {
 	return n.b
@@ -29,3 +94,20 @@ func (n *NBTCacheSystem) BaseContainerCache() *base_container_cache.BaseContaine
 func (n *NBTCacheSystem) NBTBlockCache() *nbt_block_cache.NBTBlockCache {
 	return n.n
 }
+
+// FilledMapCache 返回地图缓存命中系统
+func (n *NBTCacheSystem) FilledMapCache() *filled_map_cache.FilledMapCache {
+	return n.f
+}
+
+// SetConsole 将缓存命中系统所使用的操作台更换为 console。
+// 已有的缓存将被保留，这使得机器人重新连接到租赁服后仍然
+// 可以继续使用它们。
+//
+// 由于缓存所引用的结构保存在存档中，因此新的操作台应当
+// 与原有的操作台位于同一存档
+func (n *NBTCacheSystem) SetConsole(console *nbt_console.Console) {
+	n.b.SetConsole(console)
+	n.n.SetConsole(console)
+	n.f.SetConsole(console)
+}
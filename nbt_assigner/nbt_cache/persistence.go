@@ -0,0 +1,148 @@
+package nbt_cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/base_container_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/filled_map_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache/nbt_block_cache"
+)
+
+// CacheFileVersion 是缓存文件的格式版本
+const CacheFileVersion = 1
+
+// SyncDelay 是 RequestSync 在写入持久化文件前等待的时长。
+// 在此期间发生的全部变化只会导致一次写入
+var SyncDelay = 5 * time.Second
+
+// cacheFile 是缓存文件在磁盘上的格式
+type cacheFile struct {
+	Version       int                                `json:"version"`
+	BaseContainer []base_container_cache.CacheRecord `json:"base_container"`
+	NBTBlock      []nbt_block_cache.CacheRecord      `json:"nbt_block"`
+	FilledMap     []filled_map_cache.CacheRecord     `json:"filled_map,omitempty"`
+}
+
+// SetPersistentFile 将 path 设置为缓存命中系统的持久化文件，
+// 并从该文件加载先前保存的缓存。如果该文件不存在，则视为没
+// 有缓存可供加载。
+//
+// 加载时，每条缓存所引用的结构都会被检查是否仍然存在，
+// 只有仍然存在的结构才会被信任并恢复到缓存命中系统中。
+//
+// restored 指示成功恢复的缓存的数量，而 discarded 指示因结构
+// 不存在、无法导出或记录无法解析而被丢弃的缓存的数量。
+// 单条记录的失败不会导致 SetPersistentFile 返回错误
+func (n *NBTCacheSystem) SetPersistentFile(path string) (restored int, discarded int, err error) {
+	n.persistence.mu.Lock()
+	n.persistence.file = path
+	n.persistence.lastSaved = nil
+	n.persistence.mu.Unlock()
+
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("SetPersistentFile: %v", err)
+	}
+
+	var file cacheFile
+	err = json.Unmarshal(fileBytes, &file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("SetPersistentFile: %v", err)
+	}
+	if file.Version != CacheFileVersion {
+		return 0, 0, fmt.Errorf("SetPersistentFile: Unsupported cache file version %d (expected %d)", file.Version, CacheFileVersion)
+	}
+
+	count, dropped := n.b.RestoreCache(file.BaseContainer)
+	restored, discarded = restored+count, discarded+dropped
+	count, dropped = n.n.RestoreCache(file.NBTBlock)
+	restored, discarded = restored+count, discarded+dropped
+	count, dropped = n.f.RestoreCache(file.FilledMap)
+	restored, discarded = restored+count, discarded+dropped
+
+	err = n.Sync()
+	if err != nil {
+		return restored, discarded, fmt.Errorf("SetPersistentFile: %v", err)
+	}
+	return restored, discarded, nil
+}
+
+// SetSyncErrorHandler 设置在 RequestSync 所安排的写入失败时
+// 被调用的函数 handler。handler 可以为空，此时写入错误将被忽略
+func (n *NBTCacheSystem) SetSyncErrorHandler(handler func(err error)) {
+	n.persistence.timerMu.Lock()
+	defer n.persistence.timerMu.Unlock()
+	n.persistence.onSyncError = handler
+}
+
+// RequestSync 安排在 SyncDelay 后将缓存写入持久化文件。
+// 如果已有尚未进行的写入，则不会安排新的写入，这使得
+// 连续的变化只会导致一次写入。
+//
+// 写入失败时，由 SetSyncErrorHandler 设置的函数将被调用。
+// 如果需要立即写入并得到错误，则应使用 Sync
+func (n *NBTCacheSystem) RequestSync() {
+	n.persistence.timerMu.Lock()
+	defer n.persistence.timerMu.Unlock()
+
+	if n.persistence.syncTimer != nil {
+		return
+	}
+	n.persistence.syncTimer = time.AfterFunc(SyncDelay, func() {
+		n.persistence.timerMu.Lock()
+		n.persistence.syncTimer = nil
+		handler := n.persistence.onSyncError
+		n.persistence.timerMu.Unlock()
+
+		err := n.Sync()
+		if err != nil && handler != nil {
+			handler(err)
+		}
+	})
+}
+
+// Sync 立即将当前缓存命中系统中的全部缓存写入持久化文件。
+// 如果没有设置持久化文件，或缓存自上次写入以来没有变化，
+// 则不执行任何操作
+func (n *NBTCacheSystem) Sync() error {
+	n.persistence.mu.Lock()
+	defer n.persistence.mu.Unlock()
+
+	if len(n.persistence.file) == 0 {
+		return nil
+	}
+
+	fileBytes, err := json.Marshal(cacheFile{
+		Version:       CacheFileVersion,
+		BaseContainer: n.b.DumpCache(),
+		NBTBlock:      n.n.DumpCache(),
+		FilledMap:     n.f.DumpCache(),
+	})
+	if err != nil {
+		return fmt.Errorf("Sync: %v", err)
+	}
+	if n.persistence.lastSaved != nil && bytes.Equal(fileBytes, n.persistence.lastSaved) {
+		return nil
+	}
+
+	tempFile := n.persistence.file + ".tmp"
+	err = os.WriteFile(tempFile, fileBytes, 0644)
+	if err != nil {
+		return fmt.Errorf("Sync: %v", err)
+	}
+	err = os.Rename(tempFile, n.persistence.file)
+	if err != nil {
+		return fmt.Errorf("Sync: %v", err)
+	}
+
+	n.persistence.lastSaved = fileBytes
+	return nil
+}
@@ -0,0 +1,155 @@
+package nbt_cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCacheSystem 返回一个以 path 为持久化文件的，
+// 不含任何缓存的缓存命中系统
+func newTestCacheSystem(t *testing.T, path string) *NBTCacheSystem {
+	t.Helper()
+
+	n := NewNBTCacheSystem(nil)
+	restored, discarded, err := n.SetPersistentFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != 0 || discarded != 0 {
+		t.Fatalf("unexpected restored %d and discarded %d", restored, discarded)
+	}
+	return n
+}
+
+func TestSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	n := newTestCacheSystem(t, path)
+
+	if err := n.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file cacheFile
+	if err = json.Unmarshal(fileBytes, &file); err != nil {
+		t.Fatal(err)
+	}
+	if file.Version != CacheFileVersion {
+		t.Fatalf("unexpected version %d", file.Version)
+	}
+	if _, err = os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("temporary file should be renamed (err = %v)", err)
+	}
+
+	// 缓存没有变化时不会再次写入
+	if err = os.WriteFile(path, []byte("unchanged"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = n.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if fileBytes, _ = os.ReadFile(path); string(fileBytes) != "unchanged" {
+		t.Fatalf("unexpected rewrite %q", fileBytes)
+	}
+}
+
+func TestSyncKeepsFileOnFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	n := newTestCacheSystem(t, path)
+
+	if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path+".tmp", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.Sync(); err == nil {
+		t.Fatal("Sync should fail when the temporary file can not be written")
+	}
+	if fileBytes, _ := os.ReadFile(path); string(fileBytes) != "previous" {
+		t.Fatalf("persistent file should be kept, got %q", fileBytes)
+	}
+}
+
+func TestSetPersistentFileErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"broken json":         "{",
+		"unsupported version": `{"version":2}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := NewNBTCacheSystem(nil).SetPersistentFile(path); err == nil {
+				t.Fatal("SetPersistentFile should fail")
+			}
+		})
+	}
+}
+
+func TestRequestSync(t *testing.T) {
+	defer func(delay time.Duration) { SyncDelay = delay }(SyncDelay)
+	SyncDelay = 50 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	n := newTestCacheSystem(t, path)
+
+	// 连续的请求只会安排一次写入
+	n.RequestSync()
+	n.persistence.timerMu.Lock()
+	timer := n.persistence.syncTimer
+	n.persistence.timerMu.Unlock()
+	n.RequestSync()
+	n.RequestSync()
+	n.persistence.timerMu.Lock()
+	if n.persistence.syncTimer != timer {
+		t.Fatal("RequestSync should not schedule another write while one is pending")
+	}
+	n.persistence.timerMu.Unlock()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduled write did not happen")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	n.persistence.timerMu.Lock()
+	defer n.persistence.timerMu.Unlock()
+	if n.persistence.syncTimer != nil {
+		t.Fatal("timer should be cleared after the write")
+	}
+}
+
+func TestRequestSyncError(t *testing.T) {
+	defer func(delay time.Duration) { SyncDelay = delay }(SyncDelay)
+	SyncDelay = 10 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "missing", "cache.json")
+	n := newTestCacheSystem(t, path)
+
+	errs := make(chan error, 1)
+	n.SetSyncErrorHandler(func(err error) { errs <- err })
+	n.RequestSync()
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("handler should receive the write error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not called")
+	}
+}
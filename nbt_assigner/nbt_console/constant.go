@@ -4,13 +4,15 @@ import (
 	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
 )
 
-const (
+// 以下设置可以在创建操作台前修改
+var (
 	// BaseBackground 是操作台地板的构成方块
 	BaseBackground = "minecraft:sea_lantern"
 	// DefaultHotbarSlot 是机器人默认的手持物品栏
-	DefaultHotbarSlot = 5
+	DefaultHotbarSlot resources_control.SlotID = 5
 	// DefaultTimeoutInitConsole 是抵达操作台目标区域的最长等待期限
 	DefaultTimeoutInitConsole = time.Second * 30
 )
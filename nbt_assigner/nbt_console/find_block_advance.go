@@ -75,8 +75,52 @@ func (c Console) FindLoom(includeCenter bool) (index int, offset protocol.BlockP
 	return 0, protocol.BlockPos{}, nil
 }
 
-// FindNonAnvilAndNonLoom 从操作台的帮助方块
-// 中寻找一个既不是铁砧，也不是织布机的方块。
+// FindCraftingTable 从操作台的帮助方块中寻找一个工作台方块。
+// includeCenter 指示要查找的方块是否也包括操作台
+// 中心处的方块。
+//
+// 返回的 index 可用于 BlockByIndex，
+// 而返回的 offset 可用于 BlockByOffset。
+//
+// 如果返回的 block 不为空，则说明找到，
+// 否则没有找到。找到的方块可以通过修改
+// 其指向的值从而将它变成其他方块
+func (c Console) FindCraftingTable(includeCenter bool) (index int, offset protocol.BlockPos, block *block_helper.BlockHelper) {
+	for index, value := range c.helperBlocks {
+		if !includeCenter && index == 0 {
+			continue
+		}
+		if _, ok := (*value).(block_helper.CraftingTableBlockHelper); ok {
+			return index, helperBlockMapping[index], value
+		}
+	}
+	return 0, protocol.BlockPos{}, nil
+}
+
+// FindSmithingTable 从操作台的帮助方块中寻找一个锻造台方块。
+// includeCenter 指示要查找的方块是否也包括操作台
+// 中心处的方块。
+//
+// 返回的 index 可用于 BlockByIndex，
+// 而返回的 offset 可用于 BlockByOffset。
+//
+// 如果返回的 block 不为空，则说明找到，
+// 否则没有找到。找到的方块可以通过修改
+// 其指向的值从而将它变成其他方块
+func (c Console) FindSmithingTable(includeCenter bool) (index int, offset protocol.BlockPos, block *block_helper.BlockHelper) {
+	for index, value := range c.helperBlocks {
+		if !includeCenter && index == 0 {
+			continue
+		}
+		if _, ok := (*value).(block_helper.SmithingTableBlockHelper); ok {
+			return index, helperBlockMapping[index], value
+		}
+	}
+	return 0, protocol.BlockPos{}, nil
+}
+
+// FindNonWorkstation 从操作台的帮助方块中寻找一个
+// 不是铁砧、织布机、工作台或锻造台的方块。
 //
 // 这意味目标方块将可以是空气、容器或其他方块。
 //
@@ -86,11 +130,11 @@ func (c Console) FindLoom(includeCenter bool) (index int, offset protocol.BlockP
 // 返回的 index 可用于 BlockByIndex，
 // 而返回的 offset 可用于 BlockByOffset。
 //
-// FindNonAnvilAndNonLoom 在设计上认为一定
+// FindNonWorkstation 在设计上认为一定
 // 可以找到目标的方块。
 //
 // 找到的方块可以通过修改其指向的值从而将它变成其他方块
-func (c Console) FindNonAnvilAndNonLoom(includeCenter bool) (index int, offset protocol.BlockPos, block *block_helper.BlockHelper) {
+func (c Console) FindNonWorkstation(includeCenter bool) (index int, offset protocol.BlockPos, block *block_helper.BlockHelper) {
 	idxs := make([]int, 0)
 
 	for index, value := range c.helperBlocks {
@@ -98,14 +142,14 @@ func (c Console) FindNonAnvilAndNonLoom(includeCenter bool) (index int, offset p
 			continue
 		}
 		switch (*value).(type) {
-		case block_helper.AnvilBlockHelper, block_helper.LoomBlockHelper:
+		case block_helper.AnvilBlockHelper, block_helper.LoomBlockHelper, block_helper.CraftingTableBlockHelper, block_helper.SmithingTableBlockHelper:
 		default:
 			idxs = append(idxs, index)
 		}
 	}
 
 	if len(idxs) == 0 {
-		panic("FindNonAnvilAndNonLoom: Should nerver happened")
+		panic("FindNonWorkstation: Should nerver happened")
 	}
 
 	randIndex := rand.Intn(len(idxs))
@@ -140,7 +184,7 @@ func (c Console) FindSpaceToPlaceNewBlock(includeCenter bool) (
 		return
 	}
 
-	index, offset, block = c.FindNonAnvilAndNonLoom(includeCenter)
+	index, offset, block = c.FindNonWorkstation(includeCenter)
 	if block == nil {
 		panic("FindSpaceToPlaceNewBlock: Should nerver happened")
 	}
@@ -164,7 +208,7 @@ func (c Console) FindMutipleSpaceToPlaceNewBlock(includeCenter bool) (blockIndex
 			continue
 		}
 		switch (*value).(type) {
-		case block_helper.AnvilBlockHelper, block_helper.LoomBlockHelper:
+		case block_helper.AnvilBlockHelper, block_helper.LoomBlockHelper, block_helper.CraftingTableBlockHelper, block_helper.SmithingTableBlockHelper:
 		default:
 			blockIndexs = append(blockIndexs, index)
 		}
@@ -241,3 +285,63 @@ func (c *Console) FindOrGenerateNewLoom() (index int, err error) {
 
 	return index, nil
 }
+
+// FindOrGenerateNewCraftingTable 寻找操作台的 8 个帮助方块中
+// 是否有一个是工作台。如果没有，则生成一个新的工作台。
+// index 指示找到或生成的工作台在操作台上的索引
+func (c *Console) FindOrGenerateNewCraftingTable() (index int, err error) {
+	var block *block_helper.BlockHelper
+
+	index, _, block = c.FindCraftingTable(false)
+	if block != nil {
+		return
+	}
+
+	index, _, block = c.FindSpaceToPlaceNewBlock(false)
+	if block == nil {
+		panic("FindOrGenerateNewCraftingTable: Should nerver happened")
+	}
+
+	craftingTable := block_helper.CraftingTableBlockHelper{}
+	err = c.api.SetBlock().SetBlock(
+		c.BlockPosByIndex(index),
+		craftingTable.BlockName(),
+		craftingTable.BlockStatesString(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("FindOrGenerateNewCraftingTable: %v", err)
+	}
+	c.UseHelperBlock(RequesterSystemCall, index, craftingTable)
+
+	return index, nil
+}
+
+// FindOrGenerateNewSmithingTable 寻找操作台的 8 个帮助方块中
+// 是否有一个是锻造台。如果没有，则生成一个新的锻造台。
+// index 指示找到或生成的锻造台在操作台上的索引
+func (c *Console) FindOrGenerateNewSmithingTable() (index int, err error) {
+	var block *block_helper.BlockHelper
+
+	index, _, block = c.FindSmithingTable(false)
+	if block != nil {
+		return
+	}
+
+	index, _, block = c.FindSpaceToPlaceNewBlock(false)
+	if block == nil {
+		panic("FindOrGenerateNewSmithingTable: Should nerver happened")
+	}
+
+	smithingTable := block_helper.SmithingTableBlockHelper{}
+	err = c.api.SetBlock().SetBlock(
+		c.BlockPosByIndex(index),
+		smithingTable.BlockName(),
+		smithingTable.BlockStatesString(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("FindOrGenerateNewSmithingTable: %v", err)
+	}
+	c.UseHelperBlock(RequesterSystemCall, index, smithingTable)
+
+	return index, nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // OpenContainerByIndex 打开 index 所指示的操作台方块。
-// 被打开的目标方块必须是容器、铁砧或织布机。
+// 被打开的目标方块必须是容器、铁砧、织布机或工作台。
 // index 可用于 BlockByIndex 或 BlockPosByIndex
 func (c *Console) OpenContainerByIndex(index int) (success bool, err error) {
 	var container block_helper.ContainerBlockHelper
@@ -18,7 +18,7 @@ func (c *Console) OpenContainerByIndex(index int) (success bool, err error) {
 
 	block := c.BlockByIndex(index)
 	switch b := (*block).(type) {
-	case block_helper.AnvilBlockHelper, block_helper.LoomBlockHelper:
+	case block_helper.AnvilBlockHelper, block_helper.LoomBlockHelper, block_helper.CraftingTableBlockHelper, block_helper.SmithingTableBlockHelper:
 	case block_helper.ContainerBlockHelper:
 		container, isContainer = b, true
 	default:
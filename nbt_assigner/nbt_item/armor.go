@@ -0,0 +1,297 @@
+package nbt_item
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/block_helper"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// 盔甲 (皮革盔甲的颜色和盔甲纹饰)
+type Armor struct {
+	api   *nbt_console.Console
+	items []nbt_parser_item.Armor
+}
+
+func (a *Armor) Append(item ...nbt_parser_interface.Item) {
+	for _, value := range item {
+		val, ok := value.(*nbt_parser_item.Armor)
+		if !ok {
+			continue
+		}
+		a.items = append(a.items, *val)
+	}
+}
+
+// replaceitem 通过 replaceitem 将物品 itemName 放到背包的 slotID 处，
+// 并等待背包数据同步完成
+func (a *Armor) replaceitem(
+	path game_interface.ReplaceitemPath,
+	slotID resources_control.SlotID,
+	itemName string,
+	metadata int16,
+	component string,
+) error {
+	err := a.api.API().Replaceitem().ReplaceitemInInventory(
+		"@s",
+		path,
+		game_interface.ReplaceitemInfo{
+			Name:     itemName,
+			Count:    1,
+			MetaData: metadata,
+			Slot:     slotID,
+		},
+		component,
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("replaceitem: %v", err)
+	}
+	a.api.UseInventorySlot(nbt_console.RequesterUser, slotID, true)
+
+	err = a.api.API().Commands().AwaitChangesGeneral()
+	if err != nil {
+		return fmt.Errorf("replaceitem: %v", err)
+	}
+	return nil
+}
+
+// dye 在操作台上放置一个装满水的炼药锅，
+// 然后将皮革盔甲 armor 放在手持物品栏并用
+// 染过色的炼药锅为其染色。
+// resultSlot 指示染色后的皮革盔甲在背包中的位置
+func (a *Armor) dye(armor nbt_parser_item.Armor) (resultSlot resources_control.SlotID, err error) {
+	api := a.api.API()
+
+	dyeName, ok := mapping.LeatherArmorColorToDyeName[armor.NBT.Color]
+	if !ok {
+		panic("dye: Should nerver happened")
+	}
+
+	// Step 1: Place a new water cauldron.
+	// Note that we place air first to clean the color of the old cauldron.
+	cauldron := block_helper.ComplexBlock{
+		KnownStates: true,
+		Name:        "minecraft:cauldron",
+		States: map[string]any{
+			"cauldron_liquid": "water",
+			"fill_level":      int32(6),
+		},
+	}
+	index, _, _ := a.api.FindSpaceToPlaceNewBlock(false)
+	blockPos := a.api.BlockPosByIndex(index)
+
+	err = api.SetBlock().SetBlock(blockPos, "minecraft:air", "[]")
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+	a.api.UseHelperBlock(nbt_console.RequesterUser, index, block_helper.Air{})
+	err = api.SetBlock().SetBlock(blockPos, cauldron.BlockName(), cauldron.BlockStatesString())
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+	a.api.UseHelperBlock(nbt_console.RequesterUser, index, cauldron)
+
+	err = a.api.CanReachOrMove(blockPos)
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+	blockAction := game_interface.UseItemOnBlocks{
+		HotbarSlotID: a.api.HotbarSlotID(),
+		BotPos:       a.api.Position(),
+		BlockPos:     blockPos,
+		BlockName:    cauldron.BlockName(),
+		BlockStates:  cauldron.BlockStates(),
+	}
+
+	// Step 2: Dye the water in the cauldron
+	err = a.replaceitem(game_interface.ReplacePathHotbarOnly, a.api.HotbarSlotID(), dyeName, 0, "")
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+	err = api.BotClick().ClickBlock(blockAction)
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+
+	// Step 3: Dye the leather armor
+	err = a.replaceitem(
+		game_interface.ReplacePathHotbarOnly,
+		a.api.HotbarSlotID(),
+		armor.ItemName(),
+		armor.ItemMetadata(),
+		utils.MarshalItemComponent(armor.Enhance.ItemComponent),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+	err = api.BotClick().ClickBlock(blockAction)
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+
+	// Step 4: Wait changes
+	err = api.Commands().AwaitChangesGeneral()
+	if err != nil {
+		return 0, fmt.Errorf("dye: %v", err)
+	}
+
+	return a.api.HotbarSlotID(), nil
+}
+
+// trim 在操作台的锻造台上为背包 armorSlot 处的盔甲 armor 添加纹饰
+func (a *Armor) trim(armor nbt_parser_item.Armor, armorSlot resources_control.SlotID) (err error) {
+	api := a.api.API()
+
+	// Step 1: Get recipe network ID
+	recipeNetworkID, found := api.Resources().ConstantPacket().SmithingTrimRecipeNetworkID()
+	if !found {
+		return fmt.Errorf("trim: The server did not send the smithing trim recipe")
+	}
+
+	// Step 2: Compute expected item
+	armorItem, inventoryExisted := api.Resources().Inventories().GetItemStack(0, armorSlot)
+	if !inventoryExisted {
+		panic("trim: Should nerver happened")
+	}
+	nbtData := make(map[string]any)
+	maps.Copy(nbtData, armorItem.Stack.NBTData)
+	nbtData["Trim"] = map[string]any{
+		"Material": armor.NBT.Trim.Material,
+		"Pattern":  armor.NBT.Trim.Pattern,
+	}
+	expectedItem := resources_control.ExpectedNewItem{
+		ItemType: resources_control.ItemNewType{
+			UseNetworkID: true,
+			NetworkID:    armorItem.Stack.NetworkID,
+			UseMetadata:  true,
+			Metadata:     armorItem.Stack.MetadataValue,
+		},
+		BlockRuntimeID: resources_control.ItemNewBlockRuntimeID{
+			UseBlockRuntimeID: true,
+			BlockRuntimeID:    0,
+		},
+		NBT: resources_control.ItemNewNBTData{
+			UseNBTData:       true,
+			UseOriginDamage:  true,
+			NBTData:          nbtData,
+			ChangeRepairCost: false,
+			ChangeDamage:     false,
+		},
+	}
+
+	// Step 3: Replaceitem template and material
+	occupySlots := []resources_control.SlotID{armorSlot}
+	templateSlot := a.api.FindInventorySlot(occupySlots)
+	occupySlots = append(occupySlots, templateSlot)
+	err = a.replaceitem(
+		game_interface.ReplacePathInventory, templateSlot,
+		mapping.ArmorTrimPatternToTemplateName[armor.NBT.Trim.Pattern], 0, "",
+	)
+	if err != nil {
+		return fmt.Errorf("trim: %v", err)
+	}
+	defer a.api.UseInventorySlot(nbt_console.RequesterUser, templateSlot, false)
+
+	materialSlot := a.api.FindInventorySlot(occupySlots)
+	err = a.replaceitem(
+		game_interface.ReplacePathInventory, materialSlot,
+		mapping.ArmorTrimMaterialToItemName[armor.NBT.Trim.Material], 0, "",
+	)
+	if err != nil {
+		return fmt.Errorf("trim: %v", err)
+	}
+	defer a.api.UseInventorySlot(nbt_console.RequesterUser, materialSlot, false)
+
+	// Step 4: Open smithing table
+	index, err := a.api.FindOrGenerateNewSmithingTable()
+	if err != nil {
+		return fmt.Errorf("trim: %v", err)
+	}
+	success, err := a.api.OpenContainerByIndex(index)
+	if err != nil {
+		return fmt.Errorf("trim: %v", err)
+	}
+	if !success {
+		return fmt.Errorf("trim: Failed to open the smithing table")
+	}
+	defer api.ContainerOpenAndClose().CloseContainer()
+
+	// Step 5: Smithing
+	success, _, _, err = api.ItemStackOperation().OpenTransaction().
+		SmithingFromInventory(recipeNetworkID, templateSlot, armorSlot, materialSlot, expectedItem).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("trim: %v", err)
+	}
+	if !success {
+		return fmt.Errorf("trim: The server rejected the smithing stack request actions")
+	}
+
+	return nil
+}
+
+func (a *Armor) Make() (resultSlot map[uint64]resources_control.SlotID, err error) {
+	if len(a.items) == 0 {
+		return nil, nil
+	}
+	armor := a.items[0]
+
+	// Step 1: Get the base armor
+	var slotID resources_control.SlotID
+	if armor.NBT.HaveColor {
+		slotID, err = a.dye(armor)
+		if err != nil {
+			return nil, fmt.Errorf("Make: %v", err)
+		}
+	} else {
+		slotID = a.api.FindInventorySlot(nil)
+		err = a.replaceitem(
+			game_interface.ReplacePathInventory,
+			slotID,
+			armor.ItemName(),
+			armor.ItemMetadata(),
+			utils.MarshalItemComponent(armor.Enhance.ItemComponent),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Make: %v", err)
+		}
+	}
+
+	// Step 2: Add armor trim
+	if armor.NBT.HaveTrim {
+		err = a.trim(armor, slotID)
+		if err != nil {
+			a.api.UseInventorySlot(nbt_console.RequesterUser, slotID, false)
+			return nil, fmt.Errorf("Make: %v", err)
+		}
+	}
+
+	// Step 3: Check result
+	itemWeGet, inventoryExisted := a.api.API().Resources().Inventories().GetItemStack(0, slotID)
+	if !inventoryExisted {
+		panic("Make: Should nerver happened")
+	}
+	newItem, err := nbt_parser_interface.ParseItemNetwork(itemWeGet.Stack, armor.ItemName())
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if nbt_hash.NBTItemNBTHash(newItem) != nbt_hash.NBTItemNBTHash(&armor) {
+		a.api.UseInventorySlot(nbt_console.RequesterUser, slotID, false)
+		return nil, fmt.Errorf("Make: The armor we get is not the expected one; newItem = %#v, armor = %#v", newItem, armor)
+	}
+
+	// Step 4: Return
+	a.items = a.items[1:]
+	return map[uint64]resources_control.SlotID{
+		nbt_hash.NBTItemNBTHash(&armor): slotID,
+	}, nil
+}
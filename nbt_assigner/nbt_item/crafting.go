@@ -0,0 +1,169 @@
+package nbt_item
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface/item_stack_transaction"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+)
+
+const (
+	// CraftingTableGridSize 是工作台合成栏的槽位数量
+	CraftingTableGridSize = 9
+	// CraftingTableGridStart 是工作台合成栏在
+	// 合成窗口中的起始槽位 (32 至 40)
+	CraftingTableGridStart resources_control.SlotID = 32
+)
+
+// craftingIngredient 是合成中占用单个背包槽位的材料
+type craftingIngredient struct {
+	name     string
+	metadata int16
+	count    uint8
+	slot     resources_control.SlotID
+}
+
+// crafter 用于在操作台的工作台上合成物品
+type crafter struct {
+	api         *nbt_console.Console
+	ingredients []*craftingIngredient
+	shared      map[string]*craftingIngredient
+}
+
+// newCrafter ..
+func newCrafter(api *nbt_console.Console) *crafter {
+	return &crafter{
+		api:    api,
+		shared: make(map[string]*craftingIngredient),
+	}
+}
+
+// sharedIngredient 申请一种可以被多次合成共用的材料。
+// 同名同数据值的材料将放在同一个槽位
+func (c *crafter) sharedIngredient(name string, metadata int16) *craftingIngredient {
+	key := fmt.Sprintf("%s:%d", name, metadata)
+	if ingredient, ok := c.shared[key]; ok {
+		ingredient.count++
+		return ingredient
+	}
+	ingredient := &craftingIngredient{name: name, metadata: metadata, count: 1}
+	c.shared[key] = ingredient
+	c.ingredients = append(c.ingredients, ingredient)
+	return ingredient
+}
+
+// uniqueIngredient 申请一种独占一个槽位的材料。
+// 该槽位在材料被放入合成栏后将变为空气，
+// 因此可以用于放置合成的结果
+func (c *crafter) uniqueIngredient(name string, metadata int16, count uint8) *craftingIngredient {
+	ingredient := &craftingIngredient{name: name, metadata: metadata, count: count}
+	c.ingredients = append(c.ingredients, ingredient)
+	return ingredient
+}
+
+// replaceitem 将所有材料通过 replaceitem 放入背包
+func (c *crafter) replaceitem() error {
+	api := c.api.API()
+	occupySlots := make([]resources_control.SlotID, 0)
+
+	for _, ingredient := range c.ingredients {
+		if ingredient.count > 64 {
+			return fmt.Errorf("replaceitem: Too many %s (need %d) are required", ingredient.name, ingredient.count)
+		}
+
+		ingredient.slot = c.api.FindInventorySlot(occupySlots)
+		err := api.Replaceitem().ReplaceitemInInventory(
+			"@s",
+			game_interface.ReplacePathInventory,
+			game_interface.ReplaceitemInfo{
+				Name:     ingredient.name,
+				Count:    ingredient.count,
+				MetaData: ingredient.metadata,
+				Slot:     ingredient.slot,
+			},
+			"",
+			false,
+		)
+		if err != nil {
+			return fmt.Errorf("replaceitem: %v", err)
+		}
+
+		c.api.UseInventorySlot(nbt_console.RequesterUser, ingredient.slot, true)
+		occupySlots = append(occupySlots, ingredient.slot)
+	}
+
+	err := api.Commands().AwaitChangesGeneral()
+	if err != nil {
+		return fmt.Errorf("replaceitem: %v", err)
+	}
+	return nil
+}
+
+// release 将所有材料所在的槽位 (除 except 外) 标记为空气
+func (c *crafter) release(except ...resources_control.SlotID) {
+	for _, ingredient := range c.ingredients {
+		if slices.Contains(except, ingredient.slot) {
+			continue
+		}
+		c.api.UseInventorySlot(nbt_console.RequesterUser, ingredient.slot, false)
+	}
+}
+
+// craft 将 sources 处的物品各取 1 个放入合成栏，
+// 然后合成 resultCount 个 resultItem 到 resultSlot
+func (c *crafter) craft(
+	transaction *item_stack_transaction.ItemStackTransaction,
+	recipeNetworkID uint32,
+	sources []resources_control.SlotID,
+	resultSlot resources_control.SlotID,
+	resultCount uint8,
+	resultItem resources_control.ExpectedNewItem,
+) {
+	for index, source := range sources {
+		transaction.MoveToCraftingTable(source, CraftingTableGridStart+resources_control.SlotID(index), 1)
+	}
+	transaction.Crafting(recipeNetworkID, resultSlot, resultCount, resultItem)
+}
+
+// ingredientSlots 返回 ingredients 所在的槽位
+func ingredientSlots(ingredients ...*craftingIngredient) (result []resources_control.SlotID) {
+	for _, ingredient := range ingredients {
+		result = append(result, ingredient.slot)
+	}
+	return
+}
+
+// craftingExpectedItem 构造合成后得到的物品 itemName 的预期数据
+func craftingExpectedItem(
+	api *nbt_console.Console,
+	itemName string,
+	metadata int16,
+	nbtData map[string]any,
+) resources_control.ExpectedNewItem {
+	return resources_control.ExpectedNewItem{
+		ItemType: resources_control.ItemNewType{
+			UseNetworkID: true,
+			NetworkID:    int32(api.API().Resources().ConstantPacket().ItemByName(itemName).RuntimeID),
+			UseMetadata:  true,
+			Metadata:     uint32(metadata),
+		},
+		BlockRuntimeID: resources_control.ItemNewBlockRuntimeID{
+			UseBlockRuntimeID: true,
+			BlockRuntimeID:    0,
+		},
+		NBT: resources_control.ItemNewNBTData{
+			UseNBTData:       true,
+			UseOriginDamage:  false,
+			NBTData:          nbtData,
+			ChangeRepairCost: false,
+			ChangeDamage:     false,
+		},
+		Component: resources_control.ItemNewComponent{
+			UseCanPlaceOn: true,
+			UseCanDestroy: true,
+		},
+	}
+}
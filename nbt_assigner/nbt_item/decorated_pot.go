@@ -0,0 +1,96 @@
+package nbt_item
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/google/uuid"
+)
+
+// MakeDecoratedPot 根据操作台 api，合成一个后、左、右和前四个面
+// 依次使用 sherds 所指示的陶片的饰纹陶罐。
+// resultSlot 指示饰纹陶罐在背包中的位置
+func MakeDecoratedPot(api *nbt_console.Console, sherds [4]string) (resultSlot resources_control.SlotID, err error) {
+	// Step 1: Get recipe network ID
+	recipeNetworkID, found := api.API().Resources().ConstantPacket().MultiRecipeNetworkID(
+		uuid.MustParse(mapping.MultiRecipeDecoratedPot),
+	)
+	if !found {
+		return 0, fmt.Errorf("MakeDecoratedPot: The server did not send the decorated pot multi recipe")
+	}
+
+	// Step 2: Plan ingredients.
+	// The first sherd is unique, so the decorated pot
+	// could be crafted into its slot.
+	crafter := newCrafter(api)
+	ingredients := make([]*craftingIngredient, len(sherds))
+	for index, sherd := range sherds {
+		if index == 0 {
+			ingredients[index] = crafter.uniqueIngredient(sherd, 0, 1)
+			continue
+		}
+		ingredients[index] = crafter.sharedIngredient(sherd, 0)
+	}
+
+	// Step 3: Replaceitem ingredients
+	err = crafter.replaceitem()
+	if err != nil {
+		return 0, fmt.Errorf("MakeDecoratedPot: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			crafter.release()
+		}
+	}()
+
+	// Step 4: Open crafting table
+	index, err := api.FindOrGenerateNewCraftingTable()
+	if err != nil {
+		return 0, fmt.Errorf("MakeDecoratedPot: %v", err)
+	}
+	success, err := api.OpenContainerByIndex(index)
+	if err != nil {
+		return 0, fmt.Errorf("MakeDecoratedPot: %v", err)
+	}
+	if !success {
+		err = fmt.Errorf("MakeDecoratedPot: Failed to open the crafting table")
+		return 0, err
+	}
+	defer api.API().ContainerOpenAndClose().CloseContainer()
+
+	// Step 5: Craft decorated pot.
+	// Sherds must be placed in a diamond shape,
+	// so we could not use crafter.craft here.
+	sherdsNBT := make([]any, len(sherds))
+	transaction := api.API().ItemStackOperation().OpenTransaction()
+	for index, ingredient := range ingredients {
+		transaction.MoveToCraftingTable(
+			ingredient.slot,
+			CraftingTableGridStart+resources_control.SlotID(mapping.DecoratedPotSherdGrid[index]),
+			1,
+		)
+		sherdsNBT[index] = sherds[index]
+	}
+	resultSlot = ingredients[0].slot
+	transaction.Crafting(
+		recipeNetworkID, resultSlot, 1,
+		craftingExpectedItem(api, "minecraft:decorated_pot", 0, map[string]any{"sherds": sherdsNBT}),
+	)
+
+	// Step 6: Commit changes
+	success, _, _, err = transaction.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("MakeDecoratedPot: %v", err)
+	}
+	if !success {
+		err = fmt.Errorf("MakeDecoratedPot: The server rejected the crafting stack request actions")
+		return 0, err
+	}
+
+	// Step 7: Return
+	crafter.release(resultSlot)
+	return resultSlot, nil
+}
@@ -0,0 +1,102 @@
+package nbt_item
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+)
+
+// 地图。
+//
+// 基岩版的地图仅记载了其 UUID，且无法通过命令得到特定 UUID 的地图，
+// 因此只能复制已经存在于存档中的地图。这些地图所在的容器应当事先通过
+// FilledMapCache 的 StoreFromWorld 进行登记
+type FilledMap struct {
+	api   *nbt_console.Console
+	cache *nbt_cache.NBTCacheSystem
+	items []nbt_parser_item.FilledMap
+}
+
+func (f *FilledMap) Append(item ...nbt_parser_interface.Item) {
+	for _, value := range item {
+		val, ok := value.(*nbt_parser_item.FilledMap)
+		if !ok {
+			continue
+		}
+		f.items = append(f.items, *val)
+	}
+}
+
+func (f *FilledMap) Make() (resultSlot map[uint64]resources_control.SlotID, err error) {
+	if len(f.items) == 0 {
+		return nil, nil
+	}
+	api := f.api.API()
+	filledMap := f.items[0]
+
+	// Step 1: Load the container that holds the map
+	index, _, _ := f.api.FindSpaceToPlaceNewBlock(false)
+	hit, srcSlotID, err := f.cache.FilledMapCache().LoadCache(filledMap.NBT.MapUUID, index)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if !hit {
+		return nil, fmt.Errorf(
+			"Make: The map (map_uuid = %d) is not found in the cache; Use StoreFromWorld to register the container that holds it first",
+			filledMap.NBT.MapUUID,
+		)
+	}
+
+	// Step 2: Open the container
+	success, err := f.api.OpenContainerByIndex(index)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if !success {
+		return nil, fmt.Errorf("Make: Failed to open the container that holds the map (map_uuid = %d)", filledMap.NBT.MapUUID)
+	}
+
+	// Step 3: Move the map to the inventory
+	dstSlotID := f.api.FindInventorySlot(nil)
+	success, _, _, err = api.ItemStackOperation().OpenTransaction().
+		MoveToInventory(srcSlotID, dstSlotID, 1).
+		Commit()
+	if err != nil {
+		_ = api.ContainerOpenAndClose().CloseContainer()
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if !success {
+		_ = api.ContainerOpenAndClose().CloseContainer()
+		return nil, fmt.Errorf("Make: The server rejected the item stack operation when copy the map (map_uuid = %d)", filledMap.NBT.MapUUID)
+	}
+	err = api.ContainerOpenAndClose().CloseContainer()
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	f.api.UseInventorySlot(nbt_console.RequesterUser, dstSlotID, true)
+
+	// Step 4: Check result
+	itemWeGet, inventoryExisted := api.Resources().Inventories().GetItemStack(0, dstSlotID)
+	if !inventoryExisted {
+		panic("Make: Should nerver happened")
+	}
+	newItem, err := nbt_parser_interface.ParseItemNetwork(itemWeGet.Stack, filledMap.ItemName())
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if nbt_hash.NBTItemNBTHash(newItem) != nbt_hash.NBTItemNBTHash(&filledMap) {
+		f.api.UseInventorySlot(nbt_console.RequesterUser, dstSlotID, false)
+		return nil, fmt.Errorf("Make: The map we get is not the expected one; newItem = %#v, filledMap = %#v", newItem, filledMap)
+	}
+
+	// Step 5: Return
+	f.items = f.items[1:]
+	return map[uint64]resources_control.SlotID{
+		nbt_hash.NBTItemNBTHash(&filledMap): dstSlotID,
+	}, nil
+}
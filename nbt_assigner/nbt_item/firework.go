@@ -0,0 +1,318 @@
+package nbt_item
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_parser_general "github.com/OmineDev/flowers-for-machines/nbt_parser/general"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+
+	"github.com/google/uuid"
+)
+
+// FireworkRocketResultCount 是单次合成得到的烟花火箭数量
+const FireworkRocketResultCount uint8 = 3
+
+// fireworkExplosionIngredients 返回合成爆炸效果为 explosion 的烟花之星
+// (不含淡出颜色) 所需的共用材料。火药由调用者单独申请
+func fireworkExplosionIngredients(c *crafter, explosion nbt_parser_general.FireworkExplosion) (result []*craftingIngredient) {
+	for _, color := range explosion.Colors {
+		result = append(result, c.sharedIngredient(mapping.BannerColorToDyeName[int32(color)], 0))
+	}
+	if shapeItem, ok := mapping.FireworkTypeToShapeItem[explosion.Type]; ok {
+		result = append(result, c.sharedIngredient(shapeItem.Name, shapeItem.Metadata))
+	}
+	if explosion.Trail != 0 {
+		result = append(result, c.sharedIngredient("minecraft:diamond", 0))
+	}
+	if explosion.Flicker != 0 {
+		result = append(result, c.sharedIngredient("minecraft:glowstone_dust", 0))
+	}
+	return
+}
+
+// fireworkFadeIngredients 返回为烟花之星添加淡出颜色所需的共用材料
+func fireworkFadeIngredients(c *crafter, explosion nbt_parser_general.FireworkExplosion) (result []*craftingIngredient) {
+	for _, color := range explosion.FadeColors {
+		result = append(result, c.sharedIngredient(mapping.BannerColorToDyeName[int32(color)], 0))
+	}
+	return
+}
+
+// makeFirework 在操作台的工作台上合成爆炸效果为 explosions 的烟花之星。
+// 如果 isRocket 为真，则进一步将它们与 flight 个火药合成为烟花火箭。
+//
+// resultItem 是最终得到的物品的预期数据，
+// 而 resultSlot 指示最终物品在背包中的位置
+func makeFirework(
+	api *nbt_console.Console,
+	explosions []nbt_parser_general.FireworkExplosion,
+	isRocket bool,
+	flight byte,
+	resultItem resources_control.ExpectedNewItem,
+) (resultSlot resources_control.SlotID, err error) {
+	// Step 1: Get recipe network ID
+	recipeNetworkID, found := api.API().Resources().ConstantPacket().MultiRecipeNetworkID(
+		uuid.MustParse(mapping.MultiRecipeFireworks),
+	)
+	if !found {
+		return 0, fmt.Errorf("makeFirework: The server did not send the firework multi recipe")
+	}
+
+	// Step 2: Check ingredients count of each crafting
+	if isRocket && (flight < mapping.FireworkMinFlight || flight > mapping.FireworkMaxFlight) {
+		return 0, fmt.Errorf(
+			"makeFirework: Firework rocket with flight duration %d can not be crafted (expected %d to %d)",
+			flight, mapping.FireworkMinFlight, mapping.FireworkMaxFlight,
+		)
+	}
+	if isRocket && 1+int(flight)+len(explosions) > CraftingTableGridSize {
+		return 0, fmt.Errorf("makeFirework: Too many ingredients for firework rocket (flight = %d, explosions = %d)", flight, len(explosions))
+	}
+	for _, explosion := range explosions {
+		starCount := 1 + len(explosion.Colors) + int(explosion.Trail) + int(explosion.Flicker)
+		if _, ok := mapping.FireworkTypeToShapeItem[explosion.Type]; ok {
+			starCount++
+		}
+		if starCount > CraftingTableGridSize || 1+len(explosion.FadeColors) > CraftingTableGridSize {
+			return 0, fmt.Errorf("makeFirework: Too many ingredients for firework star %#v", explosion)
+		}
+	}
+
+	// Step 3: Plan ingredients
+	crafter := newCrafter(api)
+	gunpowders := make([]*craftingIngredient, len(explosions))
+	starIngredients := make([][]*craftingIngredient, len(explosions))
+	fadeIngredients := make([][]*craftingIngredient, len(explosions))
+	for index, explosion := range explosions {
+		gunpowders[index] = crafter.uniqueIngredient("minecraft:gunpowder", 0, 1)
+		starIngredients[index] = fireworkExplosionIngredients(crafter, explosion)
+		fadeIngredients[index] = fireworkFadeIngredients(crafter, explosion)
+	}
+
+	var paper, flightGunpowder *craftingIngredient
+	if isRocket {
+		paper = crafter.uniqueIngredient("minecraft:paper", 0, 1)
+		flightGunpowder = crafter.uniqueIngredient("minecraft:gunpowder", 0, flight)
+	}
+
+	// Step 4: Replaceitem ingredients
+	err = crafter.replaceitem()
+	if err != nil {
+		return 0, fmt.Errorf("makeFirework: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			crafter.release()
+		}
+	}()
+
+	// Step 5: Open crafting table
+	index, err := api.FindOrGenerateNewCraftingTable()
+	if err != nil {
+		return 0, fmt.Errorf("makeFirework: %v", err)
+	}
+	success, err := api.OpenContainerByIndex(index)
+	if err != nil {
+		return 0, fmt.Errorf("makeFirework: %v", err)
+	}
+	if !success {
+		err = fmt.Errorf("makeFirework: Failed to open the crafting table")
+		return 0, err
+	}
+	defer api.API().ContainerOpenAndClose().CloseContainer()
+
+	// Step 6: Craft firework stars.
+	// Note that each star will be crafted into the slot of its gunpowder,
+	// due to the gunpowder slot will become air after we move it into
+	// the crafting table.
+	transaction := api.API().ItemStackOperation().OpenTransaction()
+	for index, explosion := range explosions {
+		starSlot := gunpowders[index].slot
+		isFinal := !isRocket && len(explosion.FadeColors) == 0
+
+		baseExplosion := explosion
+		baseExplosion.FadeColors = nil
+		expectedStar := craftingExpectedItem(
+			api, "minecraft:firework_star", 0,
+			map[string]any{"FireworksItem": baseExplosion.ToNBT()},
+		)
+		if isFinal {
+			expectedStar = resultItem
+		}
+
+		crafter.craft(
+			transaction, recipeNetworkID,
+			append([]resources_control.SlotID{starSlot}, ingredientSlots(starIngredients[index]...)...),
+			starSlot, 1, expectedStar,
+		)
+		if len(explosion.FadeColors) == 0 {
+			continue
+		}
+
+		expectedStar = craftingExpectedItem(
+			api, "minecraft:firework_star", 0,
+			map[string]any{"FireworksItem": explosion.ToNBT()},
+		)
+		if !isRocket {
+			expectedStar = resultItem
+		}
+		crafter.craft(
+			transaction, recipeNetworkID,
+			append([]resources_control.SlotID{starSlot}, ingredientSlots(fadeIngredients[index]...)...),
+			starSlot, 1, expectedStar,
+		)
+	}
+	resultSlot = gunpowders[0].slot
+
+	// Step 7: Craft firework rocket
+	if isRocket {
+		sources := []resources_control.SlotID{paper.slot}
+		for range flight {
+			sources = append(sources, flightGunpowder.slot)
+		}
+		sources = append(sources, ingredientSlots(gunpowders...)...)
+		crafter.craft(transaction, recipeNetworkID, sources, paper.slot, FireworkRocketResultCount, resultItem)
+		resultSlot = paper.slot
+	}
+
+	// Step 8: Commit changes
+	success, _, _, err = transaction.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("makeFirework: %v", err)
+	}
+	if !success {
+		err = fmt.Errorf("makeFirework: The server rejected the crafting stack request actions")
+		return 0, err
+	}
+
+	// Step 9: Return
+	crafter.release(resultSlot)
+	return resultSlot, nil
+}
+
+// 烟花火箭
+type Firework struct {
+	api   *nbt_console.Console
+	items []nbt_parser_item.Firework
+}
+
+func (f *Firework) Append(item ...nbt_parser_interface.Item) {
+	for _, value := range item {
+		val, ok := value.(*nbt_parser_item.Firework)
+		if !ok {
+			continue
+		}
+		f.items = append(f.items, *val)
+	}
+}
+
+func (f *Firework) Make() (resultSlot map[uint64]resources_control.SlotID, err error) {
+	if len(f.items) == 0 {
+		return nil, nil
+	}
+	firework := f.items[0]
+
+	explosions := make([]any, 0)
+	for _, explosion := range firework.NBT.Explosions {
+		explosions = append(explosions, explosion.ToNBT())
+	}
+	expectedItem := craftingExpectedItem(
+		f.api, "minecraft:firework_rocket", firework.ItemMetadata(),
+		map[string]any{
+			"Fireworks": map[string]any{
+				"Explosions": explosions,
+				"Flight":     firework.NBT.Flight,
+			},
+		},
+	)
+
+	slotID, err := makeFirework(f.api, firework.NBT.Explosions, true, firework.NBT.Flight, expectedItem)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+
+	err = checkFireworkResult(f.api, slotID, "minecraft:firework_rocket", &firework)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+
+	f.items = f.items[1:]
+	return map[uint64]resources_control.SlotID{
+		nbt_hash.NBTItemNBTHash(&firework): slotID,
+	}, nil
+}
+
+// 烟花之星
+type FireworkStar struct {
+	api   *nbt_console.Console
+	items []nbt_parser_item.FireworkStar
+}
+
+func (f *FireworkStar) Append(item ...nbt_parser_interface.Item) {
+	for _, value := range item {
+		val, ok := value.(*nbt_parser_item.FireworkStar)
+		if !ok {
+			continue
+		}
+		f.items = append(f.items, *val)
+	}
+}
+
+func (f *FireworkStar) Make() (resultSlot map[uint64]resources_control.SlotID, err error) {
+	if len(f.items) == 0 {
+		return nil, nil
+	}
+	star := f.items[0]
+
+	expectedItem := craftingExpectedItem(
+		f.api, "minecraft:firework_star", star.ItemMetadata(),
+		map[string]any{"FireworksItem": star.NBT.Explosion.ToNBT()},
+	)
+
+	slotID, err := makeFirework(
+		f.api,
+		[]nbt_parser_general.FireworkExplosion{star.NBT.Explosion},
+		false, 0, expectedItem,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+
+	err = checkFireworkResult(f.api, slotID, "minecraft:firework_star", &star)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+
+	f.items = f.items[1:]
+	return map[uint64]resources_control.SlotID{
+		nbt_hash.NBTItemNBTHash(&star): slotID,
+	}, nil
+}
+
+// checkFireworkResult 检查背包 slotID 处的物品是否与 expected 具有相同的 NBT 哈希
+func checkFireworkResult(
+	api *nbt_console.Console,
+	slotID resources_control.SlotID,
+	itemName string,
+	expected nbt_parser_interface.Item,
+) error {
+	itemWeGet, inventoryExisted := api.API().Resources().Inventories().GetItemStack(0, slotID)
+	if !inventoryExisted {
+		panic("checkFireworkResult: Should nerver happened")
+	}
+
+	newItem, err := nbt_parser_interface.ParseItemNetwork(itemWeGet.Stack, itemName)
+	if err != nil {
+		return fmt.Errorf("checkFireworkResult: %v", err)
+	}
+	if nbt_hash.NBTItemNBTHash(newItem) != nbt_hash.NBTItemNBTHash(expected) {
+		panic("checkFireworkResult: Should nerver happened")
+	}
+
+	return nil
+}
@@ -16,6 +16,7 @@ func init() {
 	nbt_assigner_interface.EnchMultiple = EnchMultiple
 	nbt_assigner_interface.RenameMultiple = RenameMultiple
 	nbt_assigner_interface.EnchAndRenameMultiple = EnchAndRenameMultiple
+	nbt_assigner_interface.MakeDecoratedPot = MakeDecoratedPot
 }
 
 // NBTItemIsSupported 检查 item 是否是受支持的复杂物品
@@ -24,6 +25,11 @@ func NBTItemIsSupported(item nbt_parser_interface.Item) bool {
 	case *nbt_parser_item.Book:
 	case *nbt_parser_item.Banner:
 	case *nbt_parser_item.Shield:
+	case *nbt_parser_item.Firework:
+	case *nbt_parser_item.FireworkStar:
+	case *nbt_parser_item.SuspiciousStew:
+	case *nbt_parser_item.Armor:
+	case *nbt_parser_item.FilledMap:
 	default:
 		return false
 	}
@@ -45,6 +51,11 @@ func MakeNBTItemMethod(
 	books := make([]nbt_parser_interface.Item, 0)
 	banners := make([]nbt_parser_interface.Item, 0)
 	shields := make([]nbt_parser_interface.Item, 0)
+	fireworks := make([]nbt_parser_interface.Item, 0)
+	fireworkStars := make([]nbt_parser_interface.Item, 0)
+	suspiciousStews := make([]nbt_parser_interface.Item, 0)
+	armors := make([]nbt_parser_interface.Item, 0)
+	filledMaps := make([]nbt_parser_interface.Item, 0)
 
 	for _, item := range multipleItems {
 		switch item.(type) {
@@ -54,6 +65,16 @@ func MakeNBTItemMethod(
 			banners = append(banners, item)
 		case *nbt_parser_item.Shield:
 			shields = append(shields, item)
+		case *nbt_parser_item.Firework:
+			fireworks = append(fireworks, item)
+		case *nbt_parser_item.FireworkStar:
+			fireworkStars = append(fireworkStars, item)
+		case *nbt_parser_item.SuspiciousStew:
+			suspiciousStews = append(suspiciousStews, item)
+		case *nbt_parser_item.Armor:
+			armors = append(armors, item)
+		case *nbt_parser_item.FilledMap:
+			filledMaps = append(filledMaps, item)
 		}
 	}
 
@@ -76,6 +97,31 @@ func MakeNBTItemMethod(
 		element.Append(shields...)
 		result = append(result, element)
 	}
+	if len(fireworks) > 0 {
+		element := &Firework{api: console}
+		element.Append(fireworks...)
+		result = append(result, element)
+	}
+	if len(fireworkStars) > 0 {
+		element := &FireworkStar{api: console}
+		element.Append(fireworkStars...)
+		result = append(result, element)
+	}
+	if len(suspiciousStews) > 0 {
+		element := &SuspiciousStew{api: console}
+		element.Append(suspiciousStews...)
+		result = append(result, element)
+	}
+	if len(armors) > 0 {
+		element := &Armor{api: console}
+		element.Append(armors...)
+		result = append(result, element)
+	}
+	if len(filledMaps) > 0 {
+		element := &FilledMap{api: console, cache: cache}
+		element.Append(filledMaps...)
+		result = append(result, element)
+	}
 
 	return result
 }
@@ -0,0 +1,155 @@
+package nbt_item
+
+import (
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	nbt_parser_item "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+)
+
+// SuspiciousStewMaxToMake 是单次能制作的最多的谜之炖菜数量。
+// 每个谜之炖菜独占一个碗所在的槽位，而蘑菇和花是共用的，
+// 因此该值需要为这些共用材料留出足够的物品栏
+const SuspiciousStewMaxToMake = 16
+
+// 谜之炖菜
+type SuspiciousStew struct {
+	api   *nbt_console.Console
+	items []nbt_parser_item.SuspiciousStew
+}
+
+func (s *SuspiciousStew) Append(item ...nbt_parser_interface.Item) {
+	for _, value := range item {
+		val, ok := value.(*nbt_parser_item.SuspiciousStew)
+		if !ok {
+			continue
+		}
+		s.items = append(s.items, *val)
+	}
+}
+
+// recipeIngredients 根据合成谜之炖菜 stew 的配方申请其所需的材料。
+// 配方的第一种材料 (通常是碗) 是独占的，它所在的槽位将用于放置合成结果
+func (s *SuspiciousStew) recipeIngredients(
+	c *crafter,
+	stew nbt_parser_item.SuspiciousStew,
+) (recipeNetworkID uint32, ingredients []*craftingIngredient, err error) {
+	constantPacket := s.api.API().Resources().ConstantPacket()
+
+	recipes := constantPacket.CraftingRecipesByOutput(protocol.ItemType{
+		NetworkID:     int32(constantPacket.ItemByName("minecraft:suspicious_stew").RuntimeID),
+		MetadataValue: uint32(stew.ItemMetadata()),
+	})
+	if len(recipes) == 0 {
+		return 0, nil, fmt.Errorf("recipeIngredients: No recipe found for suspicious stew (metadata = %d)", stew.ItemMetadata())
+	}
+	recipe := recipes[0]
+
+	for _, input := range recipe.Input {
+		descriptor, ok := input.Descriptor.(*protocol.DefaultItemDescriptor)
+		if !ok {
+			return 0, nil, fmt.Errorf("recipeIngredients: Unsupported item descriptor %T in recipe %d", input.Descriptor, recipe.RecipeNetworkID)
+		}
+
+		name := constantPacket.ItemNameByNetworkID(int32(descriptor.NetworkID))
+		metadata := descriptor.MetadataValue
+		if metadata < 0 || metadata == 32767 {
+			metadata = 0
+		}
+
+		for range input.Count {
+			if len(ingredients) == 0 {
+				ingredients = append(ingredients, c.uniqueIngredient(name, metadata, 1))
+				continue
+			}
+			ingredients = append(ingredients, c.sharedIngredient(name, metadata))
+		}
+	}
+
+	if len(ingredients) == 0 || len(ingredients) > CraftingTableGridSize {
+		return 0, nil, fmt.Errorf("recipeIngredients: Recipe %d has %d ingredients which is not supported", recipe.RecipeNetworkID, len(ingredients))
+	}
+	return recipe.RecipeNetworkID, ingredients, nil
+}
+
+func (s *SuspiciousStew) Make() (resultSlot map[uint64]resources_control.SlotID, err error) {
+	api := s.api.API()
+	if len(s.items) == 0 {
+		return nil, nil
+	}
+
+	// Step 1: Plan ingredients
+	c := newCrafter(s.api)
+	stewsToMake := s.items[:min(len(s.items), SuspiciousStewMaxToMake)]
+	recipeNetworkIDs := make([]uint32, len(stewsToMake))
+	stewIngredients := make([][]*craftingIngredient, len(stewsToMake))
+	for index, stew := range stewsToMake {
+		recipeNetworkIDs[index], stewIngredients[index], err = s.recipeIngredients(c, stew)
+		if err != nil {
+			return nil, fmt.Errorf("Make: %v", err)
+		}
+	}
+
+	// Step 2: Replaceitem ingredients
+	err = c.replaceitem()
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			c.release()
+		}
+	}()
+
+	// Step 3: Open crafting table
+	index, err := s.api.FindOrGenerateNewCraftingTable()
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	success, err := s.api.OpenContainerByIndex(index)
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if !success {
+		err = fmt.Errorf("Make: Failed to open the crafting table")
+		return nil, err
+	}
+	defer api.ContainerOpenAndClose().CloseContainer()
+
+	// Step 4: Craft suspicious stews
+	resultSlot = make(map[uint64]resources_control.SlotID)
+	resultSlots := make([]resources_control.SlotID, 0)
+	transaction := api.ItemStackOperation().OpenTransaction()
+	for index, stew := range stewsToMake {
+		stewSlot := stewIngredients[index][0].slot
+		c.craft(
+			transaction,
+			recipeNetworkIDs[index],
+			ingredientSlots(stewIngredients[index]...),
+			stewSlot,
+			1,
+			craftingExpectedItem(s.api, "minecraft:suspicious_stew", stew.ItemMetadata(), make(map[string]any)),
+		)
+		resultSlot[nbt_hash.NBTItemNBTHash(&stew)] = stewSlot
+		resultSlots = append(resultSlots, stewSlot)
+	}
+
+	// Step 5: Commit changes
+	success, _, _, err = transaction.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("Make: %v", err)
+	}
+	if !success {
+		err = fmt.Errorf("Make: The server rejected the crafting stack request actions")
+		return nil, err
+	}
+
+	// Step 6: Return
+	c.release(resultSlots...)
+	s.items = s.items[len(stewsToMake):]
+	return resultSlot, nil
+}
@@ -0,0 +1,110 @@
+package nbt_assigner
+
+import (
+	"fmt"
+
+	nbt_assigner_interface "github.com/OmineDev/flowers-for-machines/nbt_assigner/interface"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+// 物品在制作时不会被还原的原因。
+// 除 DropReasonFilledMapNotRegistered 外，
+// 它们都由方块的解析器报告
+const (
+	DropReasonCanNotGetByCommand          = nbt_parser_interface.DropReasonCanNotGetByCommand
+	DropReasonCanNotPlaceByInteraction    = nbt_parser_interface.DropReasonCanNotPlaceByInteraction
+	DropReasonUnsupportedEffects          = nbt_parser_interface.DropReasonUnsupportedEffects
+	DropReasonEnchantmentsNotReproducible = nbt_parser_interface.DropReasonEnchantmentsNotReproducible
+	// DropReasonFilledMapNotRegistered 指示地图没有通过
+	// RegisterFilledMaps 登记，因此无法被复制
+	DropReasonFilledMapNotRegistered = "filled_map_not_registered"
+)
+
+// 物品只能被近似还原的原因，它们都由方块的解析器报告
+const (
+	LossReasonColorApproximated = nbt_parser_interface.LossReasonColorApproximated
+	LossReasonSlotNotPreserved  = nbt_parser_interface.LossReasonSlotNotPreserved
+	LossReasonExplosionsDropped = nbt_parser_interface.LossReasonExplosionsDropped
+)
+
+// DroppedItem 是在制作 NBT 方块时不会被还原，
+// 或只能被近似还原的物品
+type DroppedItem = nbt_parser_interface.DroppedItem
+
+// ValidateNBTBlockResult 是 ValidateNBTBlock 的校验结果
+type ValidateNBTBlockResult struct {
+	// BlockName 是升级后的方块名称
+	BlockName string
+	// BlockStates 是升级和修正后的方块状态
+	BlockStates map[string]any
+	// BlockStatesString 是 BlockStates 的字符串表示
+	BlockStatesString string
+
+	// NeedSpecialHandle 指示这个方块是否需要特殊处理，
+	// 为假时方块将直接通过命令放置
+	NeedSpecialHandle bool
+	// PlacedByNeighbor 指示这个方块总是随其相邻的方块一同被制作，
+	// 例如床尾总是随床头一同被制作，因此它不应被单独放置
+	PlacedByNeighbor bool
+	// NeedCheckCompletely 指示制作完成后是否会检查其完整性。
+	// 如果 NeedSpecialHandle 为假，则它总是假
+	NeedCheckCompletely bool
+	// IsSupported 指示这个方块是否是受支持的 NBT 方块。
+	// 不受支持的方块只会还原其名称和方块状态
+	IsSupported bool
+	// Format 是这个方块的人类可读的中文表示
+	Format string
+
+	// DroppedItems 是这个方块所装有的，
+	// 但在制作时不会被还原的物品
+	DroppedItems []DroppedItem
+	// LossyItems 是这个方块所装有的，
+	// 会被制作但只能被近似还原的物品
+	LossyItems []DroppedItem
+	// DroppedFields 是这个方块的方块实体数据或方块状态中，
+	// 在制作时不会被还原的字段的名称，例如刷怪笼的生成
+	// 间隔和生成范围，以及合成器被红石触发的状态
+	DroppedFields []string
+}
+
+// ValidateNBTBlock 解析 NBT 方块，并报告它将如何被制作。
+// 其参数与 PlaceNBTBlock 相同。
+//
+// ValidateNBTBlock 不会执行任何操作台动作，也不会改变世界，
+// 因此它不会等待正在进行的制作完成，可以与之并发地调用
+func (n *NBTAssigner) ValidateNBTBlock(blockName string, blockStates map[string]any, blockNBT map[string]any) (
+	result ValidateNBTBlockResult,
+	err error,
+) {
+	n.consoleMu.RLock()
+	console := n.console
+	n.consoleMu.RUnlock()
+
+	// 未登记的地图在制作前即被移除，因此方块的解析器不会报告它们
+	nameChecker := console.API().Resources().ConstantPacket().ItemCanGetByCommand
+	unregisteredMaps := make([]DroppedItem, 0)
+	nbtBlock, err := n.parseBlock(nameChecker, blockName, blockStates, blockNBT, func(item DroppedItem) {
+		unregisteredMaps = append(unregisteredMaps, item)
+	})
+	if err != nil {
+		return ValidateNBTBlockResult{}, fmt.Errorf("ValidateNBTBlock: %v", err)
+	}
+	dropped, lossy := nbtBlock.DroppedItems()
+
+	result = ValidateNBTBlockResult{
+		BlockName:         nbtBlock.BlockName(),
+		BlockStates:       nbtBlock.BlockStates(),
+		BlockStatesString: nbtBlock.BlockStatesString(),
+		NeedSpecialHandle: nbtBlock.NeedSpecialHandle(),
+		PlacedByNeighbor:  nbt_assigner_interface.NBTBlockPlacedByNeighbor(nbtBlock),
+		IsSupported:       nbt_assigner_interface.NBTBlockIsSupported(nbtBlock),
+		Format:            nbtBlock.Format(""),
+		DroppedItems:      append(unregisteredMaps, dropped...),
+		LossyItems:        append(make([]DroppedItem, 0), lossy...),
+		DroppedFields:     nbt_assigner_interface.NBTBlockDroppedFields(nbtBlock),
+	}
+	if result.NeedSpecialHandle {
+		result.NeedCheckCompletely = nbtBlock.NeedCheckCompletely()
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+)
+
+// BedNBT ..
+type BedNBT struct {
+	// Color 是床的颜色。
+	// 它与羊毛的颜色编号相同，
+	// 例如 0 是白色，而 14 是红色
+	Color byte
+}
+
+// 床
+type Bed struct {
+	DefaultBlock
+	NBT BedNBT
+}
+
+// IsHead 检查这个方块是否是床头
+func (b Bed) IsHead() bool {
+	headPieceBit, _ := b.BlockStates()["head_piece_bit"].(byte)
+	return headPieceBit != 0
+}
+
+// Direction 返回床的朝向。
+// 0 到 3 依次为南、西、北和东，
+// 它指示了床头相对于床尾的方向
+func (b Bed) Direction() int32 {
+	direction, _ := b.BlockStates()["direction"].(int32)
+	return direction & 3
+}
+
+// 床尾总是随床头一同被制作，
+// 因此只有床头需要特殊处理。
+// 床尾也不应被单独放置，否则
+// 它将覆盖已随床头放置的床尾
+func (b Bed) NeedSpecialHandle() bool {
+	return b.IsHead()
+}
+
+func (Bed) NeedCheckCompletely() bool {
+	return true
+}
+
+func (b Bed) formatNBT(prefix string) string {
+	// 床的颜色编号与染料的颜色编号是互补的
+	return prefix + fmt.Sprintf("床的颜色: %s\n", mapping.ColorFormat[15-int32(b.NBT.Color&15)])
+}
+
+func (b *Bed) Format(prefix string) string {
+	result := b.DefaultBlock.Format(prefix)
+	if b.NeedSpecialHandle() {
+		result += prefix + "附加数据: \n"
+		result += b.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (b *Bed) Parse(nbtMap map[string]any) error {
+	b.NBT.Color, _ = nbtMap["color"].(byte)
+	return nil
+}
+
+func (b Bed) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+	w.Uint8(&b.NBT.Color)
+	return buf.Bytes()
+}
+
+func (b *Bed) FullStableBytes() []byte {
+	return append(b.DefaultBlock.FullStableBytes(), b.NBTStableBytes()...)
+}
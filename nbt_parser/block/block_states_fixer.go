@@ -40,8 +40,13 @@ func DeepCopyAndFixStates(blockType uint8, blockName string, blockStates map[str
 	case mapping.SupportNBTBlockTypeLectern:
 		result["powered_bit"] = byte(0)
 	case mapping.SupportNBTBlockTypeCrafter:
+		// 被修正的触发状态由 Crafter.DroppedTriggered 报告
 		result["crafting"] = byte(0)
 		result["triggered_bit"] = byte(0)
+	case mapping.SupportNBTBlockTypeBed:
+		result["occupied_bit"] = byte(0)
+	case mapping.SupportNBTBlockTypeFlowerPot:
+		result["update_bit"] = byte(0)
 	}
 
 	return result
@@ -83,11 +83,12 @@ func (b *BrewingStand) Parse(nbtMap map[string]any) error {
 		if err != nil {
 			return fmt.Errorf("Parse: %v", err)
 		}
-		if !canGetByCommand {
+		slot, _ := itemMap["Slot"].(byte)
+		if !b.checkItem(slot, item, canGetByCommand) {
 			continue
 		}
+		b.keepItem(slot, item)
 
-		slot, _ := itemMap["Slot"].(byte)
 		switch slot {
 		case 1:
 			blockStates["brewing_stand_slot_a_bit"] = byte(1)
@@ -0,0 +1,87 @@
+package nbt_parser_block
+
+import (
+	"fmt"
+
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+// BrushableBlockNBT ..
+type BrushableBlockNBT struct {
+	HaveItem  bool
+	Item      nbt_parser_interface.Item
+	LootTable string
+}
+
+// 可疑的沙子或可疑的沙砾。
+//
+// 无法通过任何交互向其中放入物品，
+// 因此其中的物品和战利品表都不会被
+// 还原，而只会还原方块本身
+type BrushableBlock struct {
+	DefaultBlock
+	NBT BrushableBlockNBT
+}
+
+func (BrushableBlock) NeedSpecialHandle() bool {
+	return false
+}
+
+func (BrushableBlock) NeedCheckCompletely() bool {
+	return false
+}
+
+func (b BrushableBlock) formatNBT(prefix string) string {
+	result := ""
+
+	if len(b.NBT.LootTable) > 0 {
+		result += prefix + fmt.Sprintf("战利品表: %s\n", b.NBT.LootTable)
+	}
+	if b.NBT.HaveItem {
+		result += prefix + "埋藏的物品: \n"
+		result += b.NBT.Item.Format(prefix + "\t")
+	}
+
+	return result
+}
+
+func (b *BrushableBlock) Format(prefix string) string {
+	result := b.DefaultBlock.Format(prefix)
+	if b.NBT.HaveItem || len(b.NBT.LootTable) > 0 {
+		result += prefix + "附加数据 (不会被还原): \n"
+		result += b.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (b *BrushableBlock) Parse(nbtMap map[string]any) error {
+	b.NBT.LootTable, _ = nbtMap["LootTable"].(string)
+
+	itemMap, ok := nbtMap["item"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	item, canGetByCommand, err := nbt_parser_interface.ParseItemNormal(b.NameChecker, itemMap)
+	if err != nil {
+		return fmt.Errorf("Parse: %v", err)
+	}
+	if item.ItemCount() > 0 && item.ItemName() != "minecraft:air" {
+		b.NBT.HaveItem = true
+		b.NBT.Item = item
+		if b.checkItem(0, item, canGetByCommand) {
+			b.dropItem(0, item, nbt_parser_interface.DropReasonCanNotPlaceByInteraction)
+		}
+	}
+
+	return nil
+}
+
+// 埋藏的物品不会被还原，
+// 因此它不参与完整性检查
+func (BrushableBlock) NBTStableBytes() []byte {
+	return nil
+}
+
+func (b *BrushableBlock) FullStableBytes() []byte {
+	return b.DefaultBlock.FullStableBytes()
+}
@@ -0,0 +1,105 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+// CampfireNBT ..
+type CampfireNBT struct {
+	// Items 是营火上正在烹饪的物品。
+	// 放置到营火上的物品总是占用第一个空槽位，
+	// 因此这些物品的槽位总是连续的，并从 0 开始。
+	//
+	// 另外，物品的烹饪进度 (ItemTime) 会随时间
+	// 变化，因此它不会被还原
+	Items []ItemWithSlot
+}
+
+// 营火
+type Campfire struct {
+	DefaultBlock
+	NBT CampfireNBT
+}
+
+func (c Campfire) NeedSpecialHandle() bool {
+	return len(c.NBT.Items) > 0
+}
+
+func (Campfire) NeedCheckCompletely() bool {
+	return true
+}
+
+func (c Campfire) formatNBT(prefix string) string {
+	result := prefix + fmt.Sprintf("共放有 %d 个物品: \n", len(c.NBT.Items))
+	for _, item := range c.NBT.Items {
+		result += item.Format(prefix + "\t")
+	}
+	return result
+}
+
+func (c *Campfire) Format(prefix string) string {
+	result := c.DefaultBlock.Format(prefix)
+	if c.NeedSpecialHandle() {
+		result += prefix + "附加数据: \n"
+		result += c.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (c *Campfire) Parse(nbtMap map[string]any) error {
+	for index := range mapping.CampfireSlotCount {
+		itemMap, ok := nbtMap[fmt.Sprintf("Item%d", index+1)].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		item, canGetByCommand, err := nbt_parser_interface.ParseItemNormal(c.NameChecker, itemMap)
+		if err != nil {
+			return fmt.Errorf("Parse: %v", err)
+		}
+		// 营火的物品列表可能包含名称为空或数量为 0 的空槽位
+		if item.ItemCount() == 0 || item.ItemName() == "minecraft:" || item.ItemName() == "minecraft:air" {
+			continue
+		}
+		if !c.checkItem(uint8(index), item, canGetByCommand) {
+			continue
+		}
+		if !mapping.CampfireCookableItems[item.ItemName()] {
+			c.dropItem(uint8(index), item, nbt_parser_interface.DropReasonCanNotPlaceByInteraction)
+			continue
+		}
+		// 放置到营火上的物品总是占用第一个空槽位
+		if uint8(index) != uint8(len(c.NBT.Items)) {
+			c.loseItem(uint8(index), item, nbt_parser_interface.LossReasonSlotNotPreserved)
+		}
+		c.keepItem(uint8(index), item)
+
+		nbt_parser_interface.SetItemCount(item, 1)
+		c.NBT.Items = append(c.NBT.Items, ItemWithSlot{
+			Item: item,
+			Slot: uint8(len(c.NBT.Items)),
+		})
+	}
+	return nil
+}
+
+func (c Campfire) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	for _, item := range c.NBT.Items {
+		stableItemBytes := append(item.Item.FullStableBytes(), item.Slot)
+		w.ByteSlice(&stableItemBytes)
+	}
+
+	return buf.Bytes()
+}
+
+func (c *Campfire) FullStableBytes() []byte {
+	return append(c.DefaultBlock.FullStableBytes(), c.NBTStableBytes()...)
+}
@@ -0,0 +1,129 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+// ChiseledBookshelfNBT ..
+type ChiseledBookshelfNBT struct {
+	Items []ItemWithSlot
+	// LastInteractedSlot 是最后一次被交互的槽位 (从 1 开始)，
+	// 而 0 表示没有。它只会影响比较器的输出，并且制作时只能
+	// 尽力还原，因此它不参与完整性检查
+	LastInteractedSlot int32
+}
+
+// 雕纹书架
+type ChiseledBookshelf struct {
+	DefaultBlock
+	NBT ChiseledBookshelfNBT
+}
+
+// Direction 返回雕纹书架的朝向。
+// 0 到 3 依次为南、西、北和东
+func (c ChiseledBookshelf) Direction() int32 {
+	direction, _ := c.BlockStates()["direction"].(int32)
+	return direction & 3
+}
+
+func (c ChiseledBookshelf) NeedSpecialHandle() bool {
+	return len(c.NBT.Items) > 0
+}
+
+func (ChiseledBookshelf) NeedCheckCompletely() bool {
+	return true
+}
+
+func (c ChiseledBookshelf) formatNBT(prefix string) string {
+	result := prefix + fmt.Sprintf("共装有 %d 本书: \n", len(c.NBT.Items))
+	for _, item := range c.NBT.Items {
+		result += item.Format(prefix + "\t")
+	}
+	return result
+}
+
+func (c *ChiseledBookshelf) Format(prefix string) string {
+	result := c.DefaultBlock.Format(prefix)
+	if c.NeedSpecialHandle() {
+		result += prefix + "附加数据: \n"
+		result += c.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (c *ChiseledBookshelf) Parse(nbtMap map[string]any) error {
+	var booksStored int32
+	itemsMap, _ := nbtMap["Items"].([]any)
+
+	// 雕纹书架的物品列表总是包含所有槽位，
+	// 其中空槽位的物品名称为空或数量为 0
+	for index, value := range itemsMap {
+		if index >= mapping.ChiseledBookshelfSlotCount {
+			break
+		}
+
+		itemMap, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := itemMap["Name"].(string)
+		count, _ := itemMap["Count"].(byte)
+		if len(name) == 0 || count == 0 {
+			continue
+		}
+
+		item, canGetByCommand, err := nbt_parser_interface.ParseItemNormal(c.NameChecker, itemMap)
+		if err != nil {
+			return fmt.Errorf("Parse: %v", err)
+		}
+		if !c.checkItem(uint8(index), item, canGetByCommand) {
+			continue
+		}
+		// 书所具有的附魔 (例如附魔书所储存的附魔)
+		// 无法通过命令还原，因此这样的书将被丢弃
+		tag, _ := itemMap["tag"].(map[string]any)
+		if ench, _ := tag["ench"].([]any); len(ench) > 0 {
+			c.dropItem(uint8(index), item, nbt_parser_interface.DropReasonEnchantmentsNotReproducible)
+			continue
+		}
+		c.keepItem(uint8(index), item)
+
+		booksStored |= 1 << index
+		c.NBT.Items = append(c.NBT.Items, ItemWithSlot{
+			Item: item,
+			Slot: uint8(index),
+		})
+	}
+
+	c.States["books_stored"] = booksStored
+	c.NBT.LastInteractedSlot, _ = nbtMap["LastInteractedSlot"].(int32)
+	return nil
+}
+
+func (c ChiseledBookshelf) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	items := slices.Clone(c.NBT.Items)
+	slices.SortStableFunc(items, func(a ItemWithSlot, b ItemWithSlot) int {
+		return cmp.Compare(a.Slot, b.Slot)
+	})
+
+	for _, item := range items {
+		stableItemBytes := append(item.Item.FullStableBytes(), item.Slot)
+		w.ByteSlice(&stableItemBytes)
+	}
+
+	return buf.Bytes()
+}
+
+func (c *ChiseledBookshelf) FullStableBytes() []byte {
+	return append(c.DefaultBlock.FullStableBytes(), c.NBTStableBytes()...)
+}
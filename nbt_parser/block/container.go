@@ -139,9 +139,10 @@ func (c *Container) Parse(nbtMap map[string]any) error {
 		if err != nil {
 			return fmt.Errorf("Parse: %v", err)
 		}
-		if !canGetByCommand {
+		if !c.checkItem(slotID, item, canGetByCommand) {
 			continue
 		}
+		c.keepItem(slotID, item)
 
 		c.NBT.Items = append(
 			c.NBT.Items,
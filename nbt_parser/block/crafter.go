@@ -17,6 +17,10 @@ type CratferNBT struct {
 type Crafter struct {
 	DefaultBlock
 	NBT CratferNBT
+	// DroppedTriggered 指示原始的方块状态中，合成器是否处于
+	// 被红石触发的状态。制作时不会提供红石信号，因此该状态
+	// 总是被修正为未触发。它不参与完整性检查
+	DroppedTriggered bool
 }
 
 func (c *Crafter) AsContainer() *Container {
@@ -59,6 +63,9 @@ func (c Crafter) formatNBT(prefix string) string {
 
 func (c *Crafter) Format(prefix string) string {
 	result := c.DefaultBlock.Format(prefix)
+	if c.DroppedTriggered {
+		result += prefix + "被红石触发: 是 (无法还原)\n"
+	}
 	if c.NeedSpecialHandle() {
 		result += prefix + "附加数据: \n"
 		result += c.formatNBT(prefix + "\t")
@@ -75,6 +82,7 @@ func (c *Crafter) Parse(nbtMap map[string]any) error {
 
 	c.NBT.DisabledSlots, _ = nbtMap["disabled_slots"].(int16)
 	c.NBT.ContainerInfo = container.NBT
+	c.DefaultBlock = container.DefaultBlock
 
 	return nil
 }
@@ -0,0 +1,134 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+// DecoratedPotNBT ..
+type DecoratedPotNBT struct {
+	// Sherds 是饰纹陶罐后、左、右和前四个面
+	// 所使用的陶片。未使用陶片的面是红砖
+	Sherds   [4]string
+	HaveItem bool
+	Item     nbt_parser_interface.Item
+}
+
+// 饰纹陶罐
+type DecoratedPot struct {
+	DefaultBlock
+	NBT DecoratedPotNBT
+}
+
+// HaveSherd 检查饰纹陶罐是否使用了任何陶片
+func (d DecoratedPot) HaveSherd() bool {
+	for _, sherd := range d.NBT.Sherds {
+		if sherd != mapping.DecoratedPotDefaultSherd {
+			return true
+		}
+	}
+	return false
+}
+
+func (d DecoratedPot) NeedSpecialHandle() bool {
+	if d.HaveSherd() {
+		return true
+	}
+	if d.NBT.HaveItem {
+		return true
+	}
+	return false
+}
+
+func (DecoratedPot) NeedCheckCompletely() bool {
+	return true
+}
+
+func (d DecoratedPot) formatNBT(prefix string) string {
+	result := ""
+
+	if d.HaveSherd() {
+		result += prefix + fmt.Sprintf("陶片 (后, 左, 右, 前): %s\n", strings.Join(d.NBT.Sherds[:], ", "))
+	}
+	if d.NBT.HaveItem {
+		result += prefix + "罐中物品: \n"
+		result += d.NBT.Item.Format(prefix + "\t")
+	}
+
+	return result
+}
+
+func (d *DecoratedPot) Format(prefix string) string {
+	result := d.DefaultBlock.Format(prefix)
+	if d.NeedSpecialHandle() {
+		result += prefix + "附加数据: \n"
+		result += d.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (d *DecoratedPot) Parse(nbtMap map[string]any) error {
+	sherds, _ := nbtMap["sherds"].([]any)
+	for index := range d.NBT.Sherds {
+		d.NBT.Sherds[index] = mapping.DecoratedPotDefaultSherd
+		if index >= len(sherds) {
+			continue
+		}
+		sherd, _ := sherds[index].(string)
+		if len(sherd) == 0 {
+			continue
+		}
+		sherd = strings.ToLower(sherd)
+		if !strings.HasPrefix(sherd, "minecraft:") {
+			sherd = "minecraft:" + sherd
+		}
+		d.NBT.Sherds[index] = sherd
+	}
+
+	itemMap, ok := nbtMap["item"].(map[string]any)
+	if ok {
+		item, canGetByCommand, err := nbt_parser_interface.ParseItemNormal(d.NameChecker, itemMap)
+		if err != nil {
+			return fmt.Errorf("Parse: %v", err)
+		}
+		// 复杂物品只能被逐个制作并放入，
+		// 因此数量多于 1 的复杂物品将被丢弃
+		switch {
+		case item.ItemCount() == 0 || item.ItemName() == "minecraft:air":
+		case !d.checkItem(0, item, canGetByCommand):
+		case item.IsComplex() && item.ItemCount() > 1:
+			d.dropItem(0, item, nbt_parser_interface.DropReasonCanNotPlaceByInteraction)
+		default:
+			d.keepItem(0, item)
+			d.NBT.HaveItem = true
+			d.NBT.Item = item
+		}
+	}
+
+	return nil
+}
+
+func (d DecoratedPot) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	for index := range d.NBT.Sherds {
+		w.String(&d.NBT.Sherds[index])
+	}
+	w.Bool(&d.NBT.HaveItem)
+	if d.NBT.HaveItem {
+		itemStableBytes := d.NBT.Item.FullStableBytes()
+		w.ByteSlice(&itemStableBytes)
+	}
+
+	return buf.Bytes()
+}
+
+func (d *DecoratedPot) FullStableBytes() []byte {
+	return append(d.DefaultBlock.FullStableBytes(), d.NBTStableBytes()...)
+}
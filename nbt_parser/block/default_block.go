@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
 	"github.com/OmineDev/flowers-for-machines/utils"
 )
 
@@ -14,6 +15,11 @@ type DefaultBlock struct {
 	Name        string
 	States      map[string]any
 	NameChecker func(name string) bool
+
+	// droppedItems 和 lossyItems 分别是解析时
+	// 丢弃的物品和只能被近似还原的物品
+	droppedItems []nbt_parser_interface.DroppedItem
+	lossyItems   []nbt_parser_interface.DroppedItem
 }
 
 func (d *DefaultBlock) BlockName() string {
@@ -65,3 +71,50 @@ func (d *DefaultBlock) FullStableBytes() []byte {
 
 	return buf.Bytes()
 }
+
+func (d DefaultBlock) DroppedItems() (dropped []nbt_parser_interface.DroppedItem, lossy []nbt_parser_interface.DroppedItem) {
+	return d.droppedItems, d.lossyItems
+}
+
+// dropItem 记录槽位 slot 处的物品 item 因 reason 而被丢弃
+func (d *DefaultBlock) dropItem(slot uint8, item nbt_parser_interface.Item, reason string) {
+	d.droppedItems = append(d.droppedItems, nbt_parser_interface.DroppedItem{
+		Path:     []uint8{slot},
+		ItemName: item.ItemName(),
+		Reason:   reason,
+	})
+}
+
+// loseItem 记录槽位 slot 处的物品 item 因 reason 而只能被近似还原
+func (d *DefaultBlock) loseItem(slot uint8, item nbt_parser_interface.Item, reason string) {
+	d.lossyItems = append(d.lossyItems, nbt_parser_interface.DroppedItem{
+		Path:     []uint8{slot},
+		ItemName: item.ItemName(),
+		Reason:   reason,
+	})
+}
+
+// checkItem 检查槽位 slot 处的物品 item 能否被制作。
+// canGetByCommand 是解析 item 时得到的。如果不能，
+// 则记录 item 被丢弃的原因并返回假
+func (d *DefaultBlock) checkItem(slot uint8, item nbt_parser_interface.Item, canGetByCommand bool) bool {
+	if !canGetByCommand {
+		d.dropItem(slot, item, nbt_parser_interface.ItemDropReason(item))
+		return false
+	}
+	return true
+}
+
+// keepItem 记录将被制作的槽位 slot 处的物品 item
+// 自身或其所装有的物品中，被丢弃或只能被近似还原的物品
+func (d *DefaultBlock) keepItem(slot uint8, item nbt_parser_interface.Item) {
+	dropped, lossy := nbt_parser_interface.ItemDroppedItems(item)
+	for _, value := range dropped {
+		value.Path = append([]uint8{slot}, value.Path...)
+		d.droppedItems = append(d.droppedItems, value)
+	}
+	for _, value := range lossy {
+		value.Path = append([]uint8{slot}, value.Path...)
+		d.lossyItems = append(d.lossyItems, value)
+	}
+}
@@ -0,0 +1,116 @@
+package nbt_parser_block_test
+
+import (
+	"fmt"
+	"testing"
+
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	_ "github.com/OmineDev/flowers-for-machines/nbt_parser/block"
+	_ "github.com/OmineDev/flowers-for-machines/nbt_parser/item"
+)
+
+// testItem 返回位于槽位 slot 的物品在存档中的形式
+func testItem(name string, count byte, slot byte, tag map[string]any) map[string]any {
+	item := map[string]any{
+		"Name":   name,
+		"Count":  count,
+		"Damage": int16(0),
+		"Slot":   slot,
+	}
+	if tag != nil {
+		item["tag"] = tag
+	}
+	return item
+}
+
+// formatDroppedItems 将 items 格式化为便于比较的字符串
+func formatDroppedItems(items []nbt_parser_interface.DroppedItem) string {
+	result := ""
+	for _, item := range items {
+		result += fmt.Sprintf("%v %s %s; ", item.Path, item.ItemName, item.Reason)
+	}
+	return result
+}
+
+func TestDroppedItems(t *testing.T) {
+	nameChecker := func(name string) bool {
+		return name != "minecraft:bedrock"
+	}
+	approximated := map[string]any{"customColor": utils.EncodeVarRGBA(180, 40, 40, 0xff)}
+
+	testCases := []struct {
+		name        string
+		blockName   string
+		blockStates map[string]any
+		blockNBT    map[string]any
+		dropped     string
+		lossy       string
+	}{
+		{
+			name:        "container",
+			blockName:   "minecraft:chest",
+			blockStates: map[string]any{"minecraft:cardinal_direction": "north"},
+			blockNBT: map[string]any{"Items": []any{
+				testItem("minecraft:apple", 1, 0, nil),
+				testItem("minecraft:potion", 1, 1, map[string]any{"CustomPotionEffects": []any{
+					map[string]any{"Id": byte(1), "Amplifier": byte(0), "Duration": int32(1800)},
+					map[string]any{"Id": byte(3), "Amplifier": byte(0), "Duration": int32(1800)},
+				}}),
+				testItem("minecraft:leather_chestplate", 1, 2, approximated),
+				testItem("minecraft:undyed_shulker_box", 1, 5, map[string]any{"Items": []any{
+					testItem("minecraft:leather_boots", 1, 3, approximated),
+					testItem("minecraft:bedrock", 1, 4, nil),
+				}}),
+			}},
+			dropped: "[1] minecraft:potion unsupported_effects; [5 4] minecraft:bedrock can_not_get_by_command; ",
+			lossy:   "[2] minecraft:leather_chestplate color_approximated; [5 3] minecraft:leather_boots color_approximated; ",
+		},
+		{
+			name:        "campfire",
+			blockName:   "minecraft:campfire",
+			blockStates: map[string]any{"minecraft:cardinal_direction": "north"},
+			blockNBT: map[string]any{
+				"Item1": testItem("minecraft:apple", 1, 0, nil),
+				"Item2": testItem("", 0, 0, nil),
+				"Item3": testItem("minecraft:porkchop", 1, 0, nil),
+			},
+			dropped: "[0] minecraft:apple can_not_place_by_interaction; ",
+			lossy:   "[2] minecraft:porkchop slot_not_preserved; ",
+		},
+		{
+			name:      "chiseled bookshelf",
+			blockName: "minecraft:chiseled_bookshelf",
+			blockNBT: map[string]any{"Items": []any{
+				testItem("minecraft:book", 1, 0, nil),
+				testItem("minecraft:enchanted_book", 1, 0, map[string]any{"ench": []any{
+					map[string]any{"id": int16(0), "lvl": int16(1)},
+				}}),
+			}},
+			dropped: "[1] minecraft:enchanted_book enchantments_not_reproducible; ",
+		},
+		{
+			name:      "decorated pot",
+			blockName: "minecraft:decorated_pot",
+			blockNBT: map[string]any{"item": testItem("minecraft:firework_rocket", 3, 0, map[string]any{
+				"Fireworks": map[string]any{"Explosions": []any{}, "Flight": byte(2)},
+			})},
+			dropped: "[0] minecraft:firework_rocket can_not_place_by_interaction; ",
+		},
+	}
+
+	for _, testCase := range testCases {
+		block, err := nbt_parser_interface.ParseBlock(nameChecker, testCase.blockName, testCase.blockStates, testCase.blockNBT)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		dropped, lossy := block.DroppedItems()
+		if got := formatDroppedItems(dropped); got != testCase.dropped {
+			t.Fatalf("%s: unexpected dropped items %q", testCase.name, got)
+		}
+		if got := formatDroppedItems(lossy); got != testCase.lossy {
+			t.Fatalf("%s: unexpected lossy items %q", testCase.name, got)
+		}
+	}
+}
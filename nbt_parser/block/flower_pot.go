@@ -0,0 +1,98 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+)
+
+// FlowerPotNBT ..
+type FlowerPotNBT struct {
+	HavePlant   bool
+	PlantName   string
+	PlantStates map[string]any
+}
+
+// 花盆
+type FlowerPot struct {
+	DefaultBlock
+	NBT FlowerPotNBT
+}
+
+// PlantStatesString 返回花盆中植物的方块状态的字符串表示
+func (f FlowerPot) PlantStatesString() string {
+	return utils.MarshalBlockStates(f.NBT.PlantStates)
+}
+
+func (f FlowerPot) NeedSpecialHandle() bool {
+	return f.NBT.HavePlant
+}
+
+func (FlowerPot) NeedCheckCompletely() bool {
+	return true
+}
+
+func (f FlowerPot) formatNBT(prefix string) string {
+	result := prefix + fmt.Sprintf("植物名称: %s\n", f.NBT.PlantName)
+	result += prefix + fmt.Sprintf("植物状态: %s\n", f.PlantStatesString())
+	return result
+}
+
+func (f *FlowerPot) Format(prefix string) string {
+	result := f.DefaultBlock.Format(prefix)
+	if f.NeedSpecialHandle() {
+		result += prefix + "附加数据: \n"
+		result += f.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (f *FlowerPot) Parse(nbtMap map[string]any) error {
+	plantBlock, ok := nbtMap["PlantBlock"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	name, _ := plantBlock["name"].(string)
+	states, _ := plantBlock["states"].(map[string]any)
+	version, _ := plantBlock["version"].(int32)
+	if len(name) == 0 || name == "minecraft:air" {
+		return nil
+	}
+
+	newBlock := blockupgrader.Upgrade(blockupgrader.BlockState{
+		Name:       name,
+		Properties: states,
+		Version:    version,
+	})
+	f.NBT.HavePlant = true
+	f.NBT.PlantName = newBlock.Name
+	f.NBT.PlantStates = newBlock.Properties
+	if f.NBT.PlantStates == nil {
+		f.NBT.PlantStates = make(map[string]any)
+	}
+
+	return nil
+}
+
+func (f FlowerPot) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	w.Bool(&f.NBT.HavePlant)
+	if f.NBT.HavePlant {
+		plantStates := f.PlantStatesString()
+		w.String(&f.NBT.PlantName)
+		w.String(&plantStates)
+	}
+
+	return buf.Bytes()
+}
+
+func (f *FlowerPot) FullStableBytes() []byte {
+	return append(f.DefaultBlock.FullStableBytes(), f.NBTStableBytes()...)
+}
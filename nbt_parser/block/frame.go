@@ -54,7 +54,8 @@ func (f *Frame) Parse(nbtMap map[string]any) error {
 		if err != nil {
 			return fmt.Errorf("Parse: %v", err)
 		}
-		if canGetByCommand && item.ItemName() != "minecraft:filled_map" {
+		if f.checkItem(0, item, canGetByCommand) {
+			f.keepItem(0, item)
 			f.NBT.HaveItem = true
 			f.NBT.Item = item
 		}
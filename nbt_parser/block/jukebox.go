@@ -66,7 +66,8 @@ func (j *JukeBox) Parse(nbtMap map[string]any) error {
 		if err != nil {
 			return fmt.Errorf("Parse: %v", err)
 		}
-		if canGetByCommand {
+		if j.checkItem(0, disc, canGetByCommand) {
+			j.keepItem(0, disc)
 			j.NBT.HaveDisc = true
 			j.NBT.Disc = disc
 		}
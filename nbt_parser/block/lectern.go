@@ -66,7 +66,8 @@ func (l *Lectern) Parse(nbtMap map[string]any) error {
 		if err != nil {
 			return fmt.Errorf("Parse: %v", err)
 		}
-		if canGetByCommand {
+		if l.checkItem(0, book, canGetByCommand) {
+			l.keepItem(0, book)
 			l.NBT.HaveBook = true
 			l.NBT.Book = book
 		}
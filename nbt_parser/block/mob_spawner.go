@@ -0,0 +1,101 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+)
+
+// MobSpawnerNBT ..
+type MobSpawnerNBT struct {
+	EntityIdentifier string
+	// DroppedEntityIdentifier 是因为相应的刷怪蛋无法通过命令
+	// 获取而被丢弃的实体类型。此时 EntityIdentifier 为空，
+	// 即制作的刷怪笼不会生成任何实体。它不参与完整性检查
+	DroppedEntityIdentifier string
+	// Fields 是刷怪笼的各个数值字段，
+	// 其键是字段在方块实体数据中的名称。
+	// 它们不会被还原，因此不参与完整性检查
+	Fields map[string]int16
+}
+
+// 刷怪笼
+type MobSpawner struct {
+	DefaultBlock
+	NBT MobSpawnerNBT
+}
+
+// SpawnEggName 返回刷怪笼所生成的实体对应的刷怪蛋的物品名称
+func (m MobSpawner) SpawnEggName() string {
+	return m.NBT.EntityIdentifier + "_spawn_egg"
+}
+
+func (m MobSpawner) NeedSpecialHandle() bool {
+	return len(m.NBT.EntityIdentifier) > 0
+}
+
+func (MobSpawner) NeedCheckCompletely() bool {
+	return true
+}
+
+func (m MobSpawner) formatNBT(prefix string) string {
+	result := prefix + fmt.Sprintf("实体类型: %s\n", m.NBT.EntityIdentifier)
+	if len(m.NBT.DroppedEntityIdentifier) > 0 {
+		result = prefix + fmt.Sprintf("实体类型: %s (刷怪蛋不存在，无法还原)\n", m.NBT.DroppedEntityIdentifier)
+	}
+	for _, field := range mapping.MobSpawnerFields {
+		value, ok := m.NBT.Fields[field.Name]
+		if ok && value != field.DefaultValue {
+			result += prefix + fmt.Sprintf("%s: %d\n", field.Format, value)
+		}
+	}
+	return result
+}
+
+func (m *MobSpawner) Format(prefix string) string {
+	result := m.DefaultBlock.Format(prefix)
+	if m.NeedSpecialHandle() || len(m.NBT.DroppedEntityIdentifier) > 0 {
+		result += prefix + "附加数据: \n"
+		result += m.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (m *MobSpawner) Parse(nbtMap map[string]any) error {
+	entityIdentifier, _ := nbtMap["EntityIdentifier"].(string)
+	entityIdentifier = strings.ToLower(entityIdentifier)
+	if len(entityIdentifier) > 0 && !strings.HasPrefix(entityIdentifier, "minecraft:") {
+		entityIdentifier = "minecraft:" + entityIdentifier
+	}
+	m.NBT.EntityIdentifier = entityIdentifier
+
+	// 刷怪笼的实体只能通过刷怪蛋设置，
+	// 因此无法获取刷怪蛋时只放置空的刷怪笼
+	if len(entityIdentifier) > 0 && m.NameChecker != nil && !m.NameChecker(m.SpawnEggName()) {
+		m.NBT.DroppedEntityIdentifier = entityIdentifier
+		m.NBT.EntityIdentifier = ""
+	}
+
+	m.NBT.Fields = make(map[string]int16)
+	for _, field := range mapping.MobSpawnerFields {
+		if value, ok := nbtMap[field.Name].(int16); ok {
+			m.NBT.Fields[field.Name] = value
+		}
+	}
+
+	return nil
+}
+
+func (m MobSpawner) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+	w.String(&m.NBT.EntityIdentifier)
+	return buf.Bytes()
+}
+
+func (m *MobSpawner) FullStableBytes() []byte {
+	return append(m.DefaultBlock.FullStableBytes(), m.NBTStableBytes()...)
+}
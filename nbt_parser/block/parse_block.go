@@ -71,7 +71,25 @@ func ParseBlock(
 	case mapping.SupportNBTBlockTypeBrewingStand:
 		block = &BrewingStand{DefaultBlock: defaultBlock}
 	case mapping.SupportNBTBlockTypeCrafter:
-		block = &Crafter{DefaultBlock: defaultBlock}
+		// 触发状态已被修正，因此需要从原始的方块状态中读取
+		triggered, _ := newBlock.Properties["triggered_bit"].(byte)
+		block = &Crafter{DefaultBlock: defaultBlock, DroppedTriggered: triggered != 0}
+	case mapping.SupportNBTBlockTypeBed:
+		block = &Bed{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeSkull:
+		block = &Skull{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeFlowerPot:
+		block = &FlowerPot{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeDecoratedPot:
+		block = &DecoratedPot{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeChiseledBookshelf:
+		block = &ChiseledBookshelf{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeMobSpawner:
+		block = &MobSpawner{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeCampfire:
+		block = &Campfire{DefaultBlock: defaultBlock}
+	case mapping.SupportNBTBlockTypeBrushableBlock:
+		block = &BrushableBlock{DefaultBlock: defaultBlock}
 	default:
 		panic("ParseNBTBlock: Should nerver happened")
 	}
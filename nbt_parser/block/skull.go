@@ -0,0 +1,112 @@
+package nbt_parser_block
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+)
+
+// SkullNBT ..
+type SkullNBT struct {
+	SkullType byte
+	// Rotation 是放置在地面上的头颅的旋转角度。
+	// 它总是 22.5 的倍数，且位于 [0, 360) 之间
+	Rotation float32
+	// MouthMoving 指示龙首的嘴部是否正在活动。
+	// 这取决于红石信号，而导入时并不会提供
+	// 红石信号，因此它不参与完整性检查
+	MouthMoving bool
+}
+
+// 头颅
+type Skull struct {
+	DefaultBlock
+	NBT SkullNBT
+}
+
+// IsStanding 检查头颅是否放置在地面上
+func (s Skull) IsStanding() bool {
+	facingDirection, _ := s.BlockStates()["facing_direction"].(int32)
+	return facingDirection <= 1
+}
+
+func (s Skull) NeedSpecialHandle() bool {
+	if s.NBT.SkullType != mapping.SkullTypeSkeleton {
+		return true
+	}
+	if s.NBT.Rotation != 0 {
+		return true
+	}
+	return false
+}
+
+func (Skull) NeedCheckCompletely() bool {
+	return true
+}
+
+func (s Skull) formatNBT(prefix string) string {
+	result := ""
+
+	skullType, ok := mapping.SkullTypeFormat[s.NBT.SkullType]
+	if !ok {
+		skullType = fmt.Sprintf("未知 (%d)", s.NBT.SkullType)
+	}
+	result += prefix + fmt.Sprintf("头颅种类: %s\n", skullType)
+
+	if s.IsStanding() {
+		result += prefix + fmt.Sprintf("旋转角度: %.1f\n", s.NBT.Rotation)
+	}
+	if s.NBT.MouthMoving {
+		result += prefix + "嘴部活动: 是\n"
+	}
+
+	return result
+}
+
+func (s *Skull) Format(prefix string) string {
+	result := s.DefaultBlock.Format(prefix)
+	if s.NeedSpecialHandle() {
+		result += prefix + "附加数据: \n"
+		result += s.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+func (s *Skull) Parse(nbtMap map[string]any) error {
+	switch skullType := nbtMap["SkullType"].(type) {
+	case byte:
+		s.NBT.SkullType = skullType
+	case int32:
+		s.NBT.SkullType = byte(skullType)
+	}
+
+	// 挂在墙上的头颅的朝向完全由方块状态决定，
+	// 而放置在地面上的头颅只有 16 种旋转角度
+	if s.IsStanding() {
+		rotation, _ := nbtMap["Rotation"].(float32)
+		step := int(math.Round(float64(rotation)/22.5)) & 15
+		s.NBT.Rotation = float32(step) * 22.5
+	}
+
+	mouthMoving, _ := nbtMap["MouthMoving"].(byte)
+	s.NBT.MouthMoving = (mouthMoving != 0)
+
+	return nil
+}
+
+func (s Skull) NBTStableBytes() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	w.Uint8(&s.NBT.SkullType)
+	w.Float32(&s.NBT.Rotation)
+
+	return buf.Bytes()
+}
+
+func (s *Skull) FullStableBytes() []byte {
+	return append(s.DefaultBlock.FullStableBytes(), s.NBTStableBytes()...)
+}
@@ -0,0 +1,114 @@
+package nbt_parser_general
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+)
+
+// FireworkExplosion 是烟花火箭或烟花之星的单个爆炸效果
+type FireworkExplosion struct {
+	Colors     []byte // 颜色
+	FadeColors []byte // 淡出颜色
+	Flicker    byte   // 是否闪烁
+	Trail      byte   // 是否有踪迹
+	Type       byte   // 形状
+}
+
+// fireworkColors 将 NBT 中的字节数组 value 转换为颜色列表。
+// 由于 NBT 解码器将字节数组解码为定长数组，因此需要通过反射读取
+func fireworkColors(value any) (result []byte) {
+	val := reflect.ValueOf(value)
+	if val.Kind() != reflect.Array && val.Kind() != reflect.Slice {
+		return nil
+	}
+	if val.Type().Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	for index := range val.Len() {
+		color := byte(val.Index(index).Uint())
+		if _, ok := mapping.ColorFormat[int32(color)]; !ok {
+			continue
+		}
+		result = append(result, color)
+	}
+	return
+}
+
+// ParseFireworkExplosion 从 nbtMap 解析一个烟花爆炸效果
+func ParseFireworkExplosion(nbtMap map[string]any) FireworkExplosion {
+	var result FireworkExplosion
+
+	result.Colors = fireworkColors(nbtMap["FireworkColor"])
+	result.FadeColors = fireworkColors(nbtMap["FireworkFade"])
+	result.Flicker, _ = nbtMap["FireworkFlicker"].(byte)
+	result.Trail, _ = nbtMap["FireworkTrail"].(byte)
+	result.Type, _ = nbtMap["FireworkType"].(byte)
+
+	if result.Flicker != 0 {
+		result.Flicker = 1
+	}
+	if result.Trail != 0 {
+		result.Trail = 1
+	}
+	if _, ok := mapping.FireworkTypeFormat[result.Type]; !ok {
+		result.Type = mapping.FireworkTypeSmallBall
+	}
+
+	return result
+}
+
+// ToNBT 将 f 转换为 NBT 复合标签
+func (f FireworkExplosion) ToNBT() map[string]any {
+	colors := make([]byte, len(f.Colors))
+	fadeColors := make([]byte, len(f.FadeColors))
+	copy(colors, f.Colors)
+	copy(fadeColors, f.FadeColors)
+
+	return map[string]any{
+		"FireworkColor":   colors,
+		"FireworkFade":    fadeColors,
+		"FireworkFlicker": f.Flicker,
+		"FireworkTrail":   f.Trail,
+		"FireworkType":    f.Type,
+	}
+}
+
+// Format ..
+func (f FireworkExplosion) Format(prefix string) string {
+	colorNames := make([]string, 0)
+	for _, color := range f.Colors {
+		colorNames = append(colorNames, mapping.ColorFormat[int32(color)])
+	}
+
+	result := prefix + mapping.FireworkTypeFormat[f.Type]
+	result += fmt.Sprintf(" (颜色: %s", strings.Join(colorNames, "、"))
+
+	if len(f.FadeColors) > 0 {
+		fadeColorNames := make([]string, 0)
+		for _, color := range f.FadeColors {
+			fadeColorNames = append(fadeColorNames, mapping.ColorFormat[int32(color)])
+		}
+		result += fmt.Sprintf("; 淡出颜色: %s", strings.Join(fadeColorNames, "、"))
+	}
+	if f.Trail != 0 {
+		result += "; 踪迹"
+	}
+	if f.Flicker != 0 {
+		result += "; 闪烁"
+	}
+
+	return result + ")\n"
+}
+
+// Marshal ..
+func (f *FireworkExplosion) Marshal(io protocol.IO) {
+	io.ByteSlice(&f.Colors)
+	io.ByteSlice(&f.FadeColors)
+	io.Uint8(&f.Flicker)
+	io.Uint8(&f.Trail)
+	io.Uint8(&f.Type)
+}
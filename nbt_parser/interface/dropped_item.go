@@ -0,0 +1,54 @@
+package nbt_parser_interface
+
+const (
+	// DropReasonCanNotGetByCommand 指示物品无法通过命令获取
+	DropReasonCanNotGetByCommand = "can_not_get_by_command"
+	// DropReasonCanNotPlaceByInteraction 指示物品无法通过
+	// 交互放入方块，例如营火上无法烹饪的物品、可疑的沙子中
+	// 埋藏的物品或饰纹陶罐中数量多于 1 的复杂物品
+	DropReasonCanNotPlaceByInteraction = "can_not_place_by_interaction"
+	// DropReasonUnsupportedEffects 指示药水、药箭或谜之炖菜
+	// 具有无法在基岩版还原的效果，例如多个自定义效果
+	DropReasonUnsupportedEffects = "unsupported_effects"
+	// DropReasonEnchantmentsNotReproducible 指示雕纹书架中的书
+	// 具有无法通过命令还原的附魔，例如附魔书所储存的附魔
+	DropReasonEnchantmentsNotReproducible = "enchantments_not_reproducible"
+)
+
+const (
+	// LossReasonColorApproximated 指示皮革盔甲的颜色无法被精确还原，
+	// 它将被染成距离原始颜色最近的单一染料颜色
+	LossReasonColorApproximated = "color_approximated"
+	// LossReasonSlotNotPreserved 指示物品无法被放回原始的槽位。
+	// 放置到营火上的物品总是占用第一个空槽位，因此营火中
+	// 空槽位之后的物品将被前移
+	LossReasonSlotNotPreserved = "slot_not_preserved"
+	// LossReasonExplosionsDropped 指示烟花火箭或烟花之星的部分
+	// 爆炸效果没有颜色，因此无法被合成。该物品仍会被制作，
+	// 但这些爆炸效果将被丢弃
+	LossReasonExplosionsDropped = "explosions_dropped"
+)
+
+// DroppedItem 是方块在解析时丢弃的，
+// 或只能被近似还原的物品
+type DroppedItem struct {
+	// Path 是该物品所在的槽位。
+	// 如果该物品位于嵌套的容器中，
+	// 则 Path 依次记载了每一层的槽位
+	Path []uint8
+	// ItemName 是该物品的名称
+	ItemName string
+	// Reason 是该物品不会被还原 (或只能被近似还原) 的原因
+	Reason string
+}
+
+var (
+	// ItemDropReason 返回无法通过命令获取 (参见 ParseItemNormal)
+	// 的物品 item 被丢弃的原因
+	ItemDropReason func(item Item) string
+	// ItemDroppedItems 返回将被制作的物品 item 自身或其所装有
+	// 的物品中，不会被还原的物品 dropped 和只能被近似还原的物品
+	// lossy。它们的槽位路径是相对于 item 的，这意味着 item 自身
+	// 的槽位路径为空
+	ItemDroppedItems func(item Item) (dropped []DroppedItem, lossy []DroppedItem)
+)
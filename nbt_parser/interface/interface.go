@@ -67,7 +67,7 @@ var (
 	// DeepCopyAndFixStates 在实现上是深拷贝的，这意味着使用者可以安全的修改返回值
 	DeepCopyAndFixStates func(blockType uint8, blockName string, blockStates map[string]any) map[string]any
 	// SetItemCount 设置 item 的物品数量为 count。
-	// 它目前是对酿造台中烈焰粉所在槽位的特殊处理
+	// 它目前被用于酿造台中烈焰粉所在的槽位和营火上的物品
 	SetItemCount func(item Item, count uint8)
 )
 
@@ -101,6 +101,9 @@ type Block interface {
 	// 的稳定唯一表示。其与 NBTStableBytes 的
 	// 区别在于它还会考虑方块的名称和方块状态
 	FullStableBytes() []byte
+	// DroppedItems 返回这个方块在解析时丢弃的物品 dropped，
+	// 以及会被制作但只能被近似还原的物品 lossy
+	DroppedItems() (dropped []DroppedItem, lossy []DroppedItem)
 }
 
 // Item 是所有已实现的 NBT 物品的统称
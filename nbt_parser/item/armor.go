@@ -0,0 +1,165 @@
+package nbt_parser_item
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// ArmorTrim 是盔甲的纹饰
+type ArmorTrim struct {
+	Material string
+	Pattern  string
+}
+
+// ArmorNBT ..
+type ArmorNBT struct {
+	// HaveColor 指示皮革盔甲是否被染色。
+	// Color 是距离原始颜色最近的，可以
+	// 通过单一染料得到的颜色，它也是实际
+	// 制作出的颜色。
+	//
+	// OriginalColor 是皮革盔甲的原始颜色。
+	// 它不参与哈希计算，因为制作出的物品只
+	// 会具有 Color 所指示的颜色
+	HaveColor     bool
+	Color         [3]uint8
+	OriginalColor [3]uint8
+	// HaveTrim 指示盔甲是否具有纹饰
+	HaveTrim bool
+	Trim     ArmorTrim
+}
+
+// 盔甲 (皮革盔甲的颜色和盔甲纹饰)
+type Armor struct {
+	DefaultItem
+	NBT ArmorNBT
+}
+
+func (a Armor) formatNBT(prefix string) string {
+	result := ""
+	if a.NBT.HaveColor {
+		result += prefix + fmt.Sprintf("颜色: %s\n", mapping.LeatherArmorColorToDyeName[a.NBT.Color])
+		if a.NBT.OriginalColor != a.NBT.Color {
+			result += prefix + fmt.Sprintf(
+				"原始颜色: #%02X%02X%02X (无法精确还原)\n",
+				a.NBT.OriginalColor[0], a.NBT.OriginalColor[1], a.NBT.OriginalColor[2],
+			)
+		}
+	}
+	if a.NBT.HaveTrim {
+		result += prefix + fmt.Sprintf("纹饰: %s (材料: %s)\n", a.NBT.Trim.Pattern, a.NBT.Trim.Material)
+	}
+	return result
+}
+
+func (a *Armor) Format(prefix string) string {
+	result := a.DefaultItem.Format(prefix)
+	if a.IsComplex() {
+		result += prefix + "附加数据: \n"
+		result += a.formatNBT(prefix + "\t")
+	}
+	return result
+}
+
+// parse ..
+func (a *Armor) parse(tag map[string]any) {
+	a.NBT = ArmorNBT{}
+
+	if mapping.LeatherArmor[a.ItemName()] {
+		if customColor, ok := tag["customColor"].(int32); ok {
+			rgb, _ := utils.DecodeVarRGBA(customColor)
+			a.NBT.HaveColor = true
+			a.NBT.Color = utils.SearchForBestColor(rgb, mapping.LeatherArmorDyeColor)
+			a.NBT.OriginalColor = rgb
+		}
+	}
+
+	trim, ok := tag["Trim"].(map[string]any)
+	if !ok {
+		return
+	}
+	material, _ := trim["Material"].(string)
+	if len(material) == 0 {
+		material, _ = trim["material"].(string)
+	}
+	pattern, _ := trim["Pattern"].(string)
+	if len(pattern) == 0 {
+		pattern, _ = trim["pattern"].(string)
+	}
+	material = strings.TrimPrefix(strings.ToLower(material), "minecraft:")
+	pattern = strings.TrimPrefix(strings.ToLower(pattern), "minecraft:")
+
+	if _, ok := mapping.ArmorTrimMaterialToItemName[material]; !ok {
+		return
+	}
+	if _, ok := mapping.ArmorTrimPatternToTemplateName[pattern]; !ok {
+		return
+	}
+	a.NBT.HaveTrim = true
+	a.NBT.Trim = ArmorTrim{
+		Material: material,
+		Pattern:  pattern,
+	}
+}
+
+func (a *Armor) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	a.parse(tag)
+	return nil
+}
+
+func (a *Armor) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	a.parse(item.NBTData)
+	return nil
+}
+
+// ColorIsApproximated 检查 item 是否是颜色无法被精确还原的皮革盔甲。
+// 这样的盔甲仍会被制作，但只会被染成距离原始颜色最近的单一染料颜色
+func ColorIsApproximated(item nbt_parser_interface.Item) bool {
+	armor, ok := item.(*Armor)
+	if !ok || !armor.NBT.HaveColor {
+		return false
+	}
+	return armor.NBT.OriginalColor != armor.NBT.Color
+}
+
+func (a Armor) IsComplex() bool {
+	return a.NBT.HaveColor || a.NBT.HaveTrim
+}
+
+func (a Armor) complexFieldsOnly() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	w.Bool(&a.NBT.HaveColor)
+	if a.NBT.HaveColor {
+		w.Uint8(&a.NBT.Color[0])
+		w.Uint8(&a.NBT.Color[1])
+		w.Uint8(&a.NBT.Color[2])
+	}
+	w.Bool(&a.NBT.HaveTrim)
+	if a.NBT.HaveTrim {
+		w.String(&a.NBT.Trim.Material)
+		w.String(&a.NBT.Trim.Pattern)
+	}
+
+	return buf.Bytes()
+}
+
+func (a *Armor) NBTStableBytes() []byte {
+	return append(a.DefaultItem.NBTStableBytes(), a.complexFieldsOnly()...)
+}
+
+func (a *Armor) TypeStableBytes() []byte {
+	return append(a.DefaultItem.TypeStableBytes(), a.complexFieldsOnly()...)
+}
+
+func (a *Armor) FullStableBytes() []byte {
+	return append(a.TypeStableBytes(), a.Basic.Count)
+}
@@ -0,0 +1,117 @@
+package nbt_parser_item
+
+import (
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// testArmor 返回附加数据为 tag 的盔甲在存档中的形式
+func testArmor(name string, tag map[string]any) map[string]any {
+	return map[string]any{
+		"Name":   name,
+		"Count":  byte(1),
+		"Damage": int16(0),
+		"tag":    tag,
+	}
+}
+
+func TestParseArmor(t *testing.T) {
+	red := [3]uint8{176, 46, 38}
+	testCases := []struct {
+		name             string
+		item             map[string]any
+		wantColor        bool
+		color            [3]uint8
+		originalColor    [3]uint8
+		wantApproximated bool
+		wantTrim         bool
+		trim             ArmorTrim
+	}{
+		{
+			name: "exact dye color",
+			item: testArmor("minecraft:leather_chestplate", map[string]any{
+				"customColor": utils.EncodeVarRGBA(red[0], red[1], red[2], 0xff),
+			}),
+			wantColor:     true,
+			color:         red,
+			originalColor: red,
+		},
+		{
+			name: "approximated color",
+			item: testArmor("minecraft:leather_helmet", map[string]any{
+				"customColor": utils.EncodeVarRGBA(180, 40, 40, 0xff),
+			}),
+			wantColor:        true,
+			color:            red,
+			originalColor:    [3]uint8{180, 40, 40},
+			wantApproximated: true,
+		},
+		{
+			name: "color of non leather armor",
+			item: testArmor("minecraft:iron_chestplate", map[string]any{
+				"customColor": utils.EncodeVarRGBA(red[0], red[1], red[2], 0xff),
+			}),
+		},
+		{
+			name: "trim",
+			item: testArmor("minecraft:iron_chestplate", map[string]any{
+				"Trim": map[string]any{"Material": "gold", "Pattern": "coast"},
+			}),
+			wantTrim: true,
+			trim:     ArmorTrim{Material: "gold", Pattern: "coast"},
+		},
+		{
+			name: "trim with lowercase keys and namespace",
+			item: testArmor("minecraft:diamond_boots", map[string]any{
+				"Trim": map[string]any{"material": "minecraft:Gold", "pattern": "minecraft:COAST"},
+			}),
+			wantTrim: true,
+			trim:     ArmorTrim{Material: "gold", Pattern: "coast"},
+		},
+		{
+			name: "unknown trim material",
+			item: testArmor("minecraft:iron_chestplate", map[string]any{
+				"Trim": map[string]any{"Material": "wood", "Pattern": "coast"},
+			}),
+		},
+		{
+			name: "unknown trim pattern",
+			item: testArmor("minecraft:iron_chestplate", map[string]any{
+				"Trim": map[string]any{"Material": "gold", "Pattern": "stripes"},
+			}),
+		},
+		{
+			name: "dyed and trimmed",
+			item: testArmor("minecraft:leather_boots", map[string]any{
+				"customColor": utils.EncodeVarRGBA(red[0], red[1], red[2], 0xff),
+				"Trim":        map[string]any{"Material": "gold", "Pattern": "coast"},
+			}),
+			wantColor:     true,
+			color:         red,
+			originalColor: red,
+			wantTrim:      true,
+			trim:          ArmorTrim{Material: "gold", Pattern: "coast"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		item, _ := parseItem(t, nil, testCase.item)
+		armor, ok := item.(*Armor)
+		if !ok {
+			t.Fatalf("%s: unexpected item %T", testCase.name, item)
+		}
+		if armor.NBT.HaveColor != testCase.wantColor || armor.NBT.Color != testCase.color || armor.NBT.OriginalColor != testCase.originalColor {
+			t.Fatalf("%s: unexpected color %#v", testCase.name, armor.NBT)
+		}
+		if ColorIsApproximated(armor) != testCase.wantApproximated {
+			t.Fatalf("%s: unexpected approximated %v", testCase.name, ColorIsApproximated(armor))
+		}
+		if armor.NBT.HaveTrim != testCase.wantTrim || armor.NBT.Trim != testCase.trim {
+			t.Fatalf("%s: unexpected trim %#v", testCase.name, armor.NBT)
+		}
+		if armor.IsComplex() != (testCase.wantColor || testCase.wantTrim) {
+			t.Fatalf("%s: unexpected complex %v", testCase.name, armor.IsComplex())
+		}
+	}
+}
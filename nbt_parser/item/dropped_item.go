@@ -0,0 +1,46 @@
+package nbt_parser_item
+
+import (
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+func init() {
+	nbt_parser_interface.ItemDropReason = ItemDropReason
+	nbt_parser_interface.ItemDroppedItems = ItemDroppedItems
+}
+
+// ItemDropReason 返回无法通过命令获取 (参见 ParseItemNormal)
+// 的物品 item 被丢弃的原因
+func ItemDropReason(item nbt_parser_interface.Item) string {
+	if HasUnsupportedEffects(item) {
+		return nbt_parser_interface.DropReasonUnsupportedEffects
+	}
+	return nbt_parser_interface.DropReasonCanNotGetByCommand
+}
+
+// ItemDroppedItems 返回将被制作的物品 item 自身或其所装有
+// 的物品中，不会被还原的物品 dropped 和只能被近似还原的物品
+// lossy。它们的槽位路径是相对于 item 的，这意味着 item 自身
+// 的槽位路径为空
+func ItemDroppedItems(item nbt_parser_interface.Item) (
+	dropped []nbt_parser_interface.DroppedItem,
+	lossy []nbt_parser_interface.DroppedItem,
+) {
+	addLossy := func(reason string) {
+		lossy = append(lossy, nbt_parser_interface.DroppedItem{
+			ItemName: item.ItemName(),
+			Reason:   reason,
+		})
+	}
+	if ColorIsApproximated(item) {
+		addLossy(nbt_parser_interface.LossReasonColorApproximated)
+	}
+	if ExplosionsDropped(item) {
+		addLossy(nbt_parser_interface.LossReasonExplosionsDropped)
+	}
+
+	block := item.UnderlyingItem().(*DefaultItem).Block
+	dropped = append(dropped, block.droppedItems...)
+	lossy = append(lossy, block.lossyItems...)
+	return
+}
@@ -0,0 +1,78 @@
+package nbt_parser_item
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// FilledMapNBT ..
+type FilledMapNBT struct {
+	// MapUUID 是该地图的唯一标识符，
+	// 它指向储存在存档中的地图数据。
+	// 为 0 时表示地图尚未初始化
+	MapUUID int64
+}
+
+// 地图
+type FilledMap struct {
+	DefaultItem
+	NBT FilledMapNBT
+}
+
+func (f *FilledMap) Format(prefix string) string {
+	result := f.DefaultItem.Format(prefix)
+	if f.IsComplex() {
+		result += prefix + "附加数据: \n"
+		result += prefix + fmt.Sprintf("\t地图 UUID: %d\n", f.NBT.MapUUID)
+	}
+	return result
+}
+
+// parse ..
+func (f *FilledMap) parse(tag map[string]any) {
+	f.NBT = FilledMapNBT{}
+	f.NBT.MapUUID, _ = tag["map_uuid"].(int64)
+
+	// 已初始化的地图通过复制已有的地图得到，
+	// 因此无法保证物品组件与原物品一致
+	if f.IsComplex() {
+		f.DefaultItem.Enhance.ItemComponent = utils.ItemComponent{}
+	}
+}
+
+func (f *FilledMap) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	f.parse(tag)
+	return nil
+}
+
+func (f *FilledMap) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	f.parse(item.NBTData)
+	return nil
+}
+
+func (f FilledMap) IsComplex() bool {
+	return f.NBT.MapUUID != 0
+}
+
+func (f FilledMap) complexFieldsOnly() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+	w.Int64(&f.NBT.MapUUID)
+	return buf.Bytes()
+}
+
+func (f *FilledMap) NBTStableBytes() []byte {
+	return append(f.DefaultItem.NBTStableBytes(), f.complexFieldsOnly()...)
+}
+
+func (f *FilledMap) TypeStableBytes() []byte {
+	return append(f.DefaultItem.TypeStableBytes(), f.complexFieldsOnly()...)
+}
+
+func (f *FilledMap) FullStableBytes() []byte {
+	return append(f.TypeStableBytes(), f.Basic.Count)
+}
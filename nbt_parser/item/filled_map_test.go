@@ -0,0 +1,54 @@
+package nbt_parser_item
+
+import "testing"
+
+// testFilledMap 返回附加数据为 tag 的地图在存档中的形式
+func testFilledMap(tag map[string]any) map[string]any {
+	return map[string]any{
+		"Name":   "minecraft:filled_map",
+		"Count":  byte(1),
+		"Damage": int16(0),
+		"tag":    tag,
+	}
+}
+
+func TestParseFilledMap(t *testing.T) {
+	item, _ := parseItem(t, nil, testFilledMap(map[string]any{
+		"map_uuid":                int64(-4294967281),
+		"map_scale":               int32(0),
+		"map_is_scaled":           byte(0),
+		"minecraft:keep_on_death": byte(1),
+	}))
+	filledMap, ok := item.(*FilledMap)
+	if !ok {
+		t.Fatalf("unexpected item %T", item)
+	}
+	if filledMap.NBT.MapUUID != -4294967281 || !filledMap.IsComplex() {
+		t.Fatalf("unexpected filled map %#v", filledMap.NBT)
+	}
+
+	// 已初始化的地图通过复制得到，因此其物品组件被丢弃
+	if filledMap.Enhance.ItemComponent.KeepOnDeath {
+		t.Fatalf("unexpected item component %#v", filledMap.Enhance.ItemComponent)
+	}
+}
+
+func TestParseEmptyFilledMap(t *testing.T) {
+	for _, tag := range []map[string]any{
+		{"minecraft:keep_on_death": byte(1)},
+		{"map_uuid": int64(0), "minecraft:keep_on_death": byte(1)},
+		{"map_uuid": int32(7), "minecraft:keep_on_death": byte(1)},
+	} {
+		item, _ := parseItem(t, nil, testFilledMap(tag))
+		filledMap, ok := item.(*FilledMap)
+		if !ok {
+			t.Fatalf("unexpected item %T", item)
+		}
+		if filledMap.NBT.MapUUID != 0 || filledMap.IsComplex() {
+			t.Fatalf("unexpected filled map %#v for tag %#v", filledMap.NBT, tag)
+		}
+		if !filledMap.Enhance.ItemComponent.KeepOnDeath {
+			t.Fatalf("item component of empty map should be kept for tag %#v", tag)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package nbt_parser_item
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	nbt_parser_general "github.com/OmineDev/flowers-for-machines/nbt_parser/general"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// FireworkNBT ..
+type FireworkNBT struct {
+	Explosions []nbt_parser_general.FireworkExplosion
+	Flight     byte
+}
+
+// 烟花火箭
+type Firework struct {
+	DefaultItem
+	NBT FireworkNBT
+	// droppedExplosions 是因没有颜色而无法合成，
+	// 因此被丢弃的爆炸效果的数量
+	droppedExplosions int
+}
+
+func (f Firework) formatNBT(prefix string) string {
+	result := prefix + fmt.Sprintf("飞行时间: %d\n", f.NBT.Flight)
+	if explosionCount := len(f.NBT.Explosions); explosionCount > 0 {
+		result += prefix + fmt.Sprintf("爆炸效果 (合计 %d 个效果): \n", explosionCount)
+	}
+	for _, explosion := range f.NBT.Explosions {
+		result += explosion.Format(prefix + "\t- ")
+	}
+	return result
+}
+
+func (f *Firework) Format(prefix string) string {
+	result := f.DefaultItem.Format(prefix)
+	if f.IsComplex() {
+		result += prefix + "附加数据: \n"
+		result += f.formatNBT(prefix + "\t")
+	}
+	if f.droppedExplosions > 0 {
+		result += prefix + fmt.Sprintf("被丢弃的爆炸效果: %d 个 (没有颜色)\n", f.droppedExplosions)
+	}
+	return result
+}
+
+// parse ..
+func (f *Firework) parse(tag map[string]any) {
+	f.NBT = FireworkNBT{Flight: 1}
+	f.droppedExplosions = 0
+
+	fireworks, _ := tag["Fireworks"].(map[string]any)
+	if len(fireworks) == 0 {
+		return
+	}
+
+	if flight, ok := fireworks["Flight"].(byte); ok {
+		f.NBT.Flight = flight
+	}
+	explosions, _ := fireworks["Explosions"].([]any)
+	for _, value := range explosions {
+		val, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		// 没有颜色的烟花之星无法被合成
+		explosion := nbt_parser_general.ParseFireworkExplosion(val)
+		if len(explosion.Colors) == 0 {
+			f.droppedExplosions++
+			continue
+		}
+		f.NBT.Explosions = append(f.NBT.Explosions, explosion)
+	}
+
+	// 通过合成得到的烟花火箭无法保留物品组件
+	if f.IsComplex() {
+		f.DefaultItem.Enhance.ItemComponent = utils.ItemComponent{}
+	}
+}
+
+func (f *Firework) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	f.parse(tag)
+	return nil
+}
+
+func (f *Firework) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	f.parse(item.NBTData)
+	return nil
+}
+
+func (f Firework) IsComplex() bool {
+	if len(f.NBT.Explosions) > 0 {
+		return true
+	}
+	if f.NBT.Flight > 1 {
+		return true
+	}
+	return false
+}
+
+func (f Firework) complexFieldsOnly() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	protocol.SliceUint16Length(w, &f.NBT.Explosions)
+	w.Uint8(&f.NBT.Flight)
+
+	return buf.Bytes()
+}
+
+func (f *Firework) NBTStableBytes() []byte {
+	return append(f.DefaultItem.NBTStableBytes(), f.complexFieldsOnly()...)
+}
+
+func (f *Firework) TypeStableBytes() []byte {
+	return append(f.DefaultItem.TypeStableBytes(), f.complexFieldsOnly()...)
+}
+
+func (f *Firework) FullStableBytes() []byte {
+	return append(f.TypeStableBytes(), f.Basic.Count)
+}
+
+// FireworkStarNBT ..
+type FireworkStarNBT struct {
+	HaveExplosion bool
+	Explosion     nbt_parser_general.FireworkExplosion
+}
+
+// 烟花之星
+type FireworkStar struct {
+	DefaultItem
+	NBT FireworkStarNBT
+	// droppedExplosion 指示该烟花之星的爆炸效果
+	// 因没有颜色而无法合成，因此被丢弃
+	droppedExplosion bool
+}
+
+func (f FireworkStar) formatNBT(prefix string) string {
+	return prefix + "爆炸效果: \n" + f.NBT.Explosion.Format(prefix+"\t- ")
+}
+
+func (f *FireworkStar) Format(prefix string) string {
+	result := f.DefaultItem.Format(prefix)
+	if f.IsComplex() {
+		result += prefix + "附加数据: \n"
+		result += f.formatNBT(prefix + "\t")
+	}
+	if f.droppedExplosion {
+		result += prefix + "被丢弃的爆炸效果: 1 个 (没有颜色)\n"
+	}
+	return result
+}
+
+// parse ..
+func (f *FireworkStar) parse(tag map[string]any) {
+	f.NBT = FireworkStarNBT{}
+	f.droppedExplosion = false
+
+	// customColor 由爆炸效果的颜色计算得到，因此不需要解析
+	fireworksItem, _ := tag["FireworksItem"].(map[string]any)
+	if len(fireworksItem) == 0 {
+		return
+	}
+
+	explosion := nbt_parser_general.ParseFireworkExplosion(fireworksItem)
+	if len(explosion.Colors) == 0 {
+		f.droppedExplosion = true
+		return
+	}
+	f.NBT = FireworkStarNBT{
+		HaveExplosion: true,
+		Explosion:     explosion,
+	}
+
+	// 通过合成得到的烟花之星无法保留物品组件
+	f.DefaultItem.Enhance.ItemComponent = utils.ItemComponent{}
+}
+
+func (f *FireworkStar) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	f.parse(tag)
+	return nil
+}
+
+func (f *FireworkStar) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	f.parse(item.NBTData)
+	return nil
+}
+
+func (f FireworkStar) IsComplex() bool {
+	return f.NBT.HaveExplosion
+}
+
+func (f FireworkStar) complexFieldsOnly() []byte {
+	buf := bytes.NewBuffer(nil)
+	w := protocol.NewWriter(buf, 0)
+
+	w.Bool(&f.NBT.HaveExplosion)
+	if f.NBT.HaveExplosion {
+		protocol.Single(w, &f.NBT.Explosion)
+	}
+
+	return buf.Bytes()
+}
+
+func (f *FireworkStar) NBTStableBytes() []byte {
+	return append(f.DefaultItem.NBTStableBytes(), f.complexFieldsOnly()...)
+}
+
+func (f *FireworkStar) TypeStableBytes() []byte {
+	return append(f.DefaultItem.TypeStableBytes(), f.complexFieldsOnly()...)
+}
+
+func (f *FireworkStar) FullStableBytes() []byte {
+	return append(f.TypeStableBytes(), f.Basic.Count)
+}
+
+// ExplosionsDropped 检查 item 是否是具有因没有颜色而无法合成的
+// 爆炸效果的烟花火箭或烟花之星。这样的物品仍会被制作，
+// 但这些爆炸效果将被丢弃
+func ExplosionsDropped(item nbt_parser_interface.Item) bool {
+	switch value := item.(type) {
+	case *Firework:
+		return value.droppedExplosions > 0
+	case *FireworkStar:
+		return value.droppedExplosion
+	}
+	return false
+}
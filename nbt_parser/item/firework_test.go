@@ -0,0 +1,114 @@
+package nbt_parser_item
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_general "github.com/OmineDev/flowers-for-machines/nbt_parser/general"
+)
+
+// testExplosion 返回烟花之星的爆炸效果在存档中的形式
+func testExplosion(colors []byte, fadeColors []byte, explosionType byte) map[string]any {
+	return map[string]any{
+		"FireworkColor":   colors,
+		"FireworkFade":    fadeColors,
+		"FireworkFlicker": byte(1),
+		"FireworkTrail":   byte(0),
+		"FireworkType":    explosionType,
+	}
+}
+
+func TestParseFirework(t *testing.T) {
+	item, canGetByCommand := parseItem(t, nil, map[string]any{
+		"Name":   "minecraft:firework_rocket",
+		"Count":  byte(3),
+		"Damage": int16(0),
+		"tag": map[string]any{
+			"Fireworks": map[string]any{
+				"Explosions": []any{
+					testExplosion([]byte{1, 11}, []byte{15}, mapping.FireworkTypeStar),
+					testExplosion([]byte{}, []byte{4}, mapping.FireworkTypeBurst),
+				},
+				"Flight": byte(2),
+			},
+		},
+	})
+	firework, ok := item.(*Firework)
+	if !ok || !canGetByCommand {
+		t.Fatalf("unexpected item %T (canGetByCommand = %v)", item, canGetByCommand)
+	}
+
+	want := FireworkNBT{
+		Explosions: []nbt_parser_general.FireworkExplosion{
+			{Colors: []byte{1, 11}, FadeColors: []byte{15}, Flicker: 1, Type: mapping.FireworkTypeStar},
+		},
+		Flight: 2,
+	}
+	if !reflect.DeepEqual(firework.NBT, want) {
+		t.Fatalf("unexpected firework %#v", firework.NBT)
+	}
+	if !firework.IsComplex() || firework.ItemCount() != 3 {
+		t.Fatalf("unexpected complex %v and count %d", firework.IsComplex(), firework.ItemCount())
+	}
+
+	// 没有颜色的爆炸效果被丢弃并报告
+	if firework.droppedExplosions != 1 || !ExplosionsDropped(firework) {
+		t.Fatalf("unexpected dropped explosions %d", firework.droppedExplosions)
+	}
+}
+
+func TestParsePlainFirework(t *testing.T) {
+	item, _ := parseItem(t, nil, map[string]any{
+		"Name":   "minecraft:firework_rocket",
+		"Count":  byte(1),
+		"Damage": int16(0),
+		"tag": map[string]any{
+			"Fireworks": map[string]any{"Explosions": []any{}, "Flight": byte(1)},
+		},
+	})
+	firework := item.(*Firework)
+	if firework.IsComplex() || ExplosionsDropped(firework) {
+		t.Fatalf("unexpected firework %#v", firework.NBT)
+	}
+}
+
+func TestParseFireworkStar(t *testing.T) {
+	item, _ := parseItem(t, nil, map[string]any{
+		"Name":   "minecraft:firework_star",
+		"Count":  byte(1),
+		"Damage": int16(0),
+		"tag": map[string]any{
+			"FireworksItem": testExplosion([]byte{4, 99}, []byte{}, mapping.FireworkTypeCreeperHead),
+			"customColor":   int32(-12827478),
+		},
+	})
+	star, ok := item.(*FireworkStar)
+	if !ok {
+		t.Fatalf("unexpected item %T", item)
+	}
+
+	// 未知的颜色被忽略
+	want := FireworkStarNBT{
+		HaveExplosion: true,
+		Explosion:     nbt_parser_general.FireworkExplosion{Colors: []byte{4}, Flicker: 1, Type: mapping.FireworkTypeCreeperHead},
+	}
+	if !reflect.DeepEqual(star.NBT, want) || ExplosionsDropped(star) {
+		t.Fatalf("unexpected firework star %#v", star.NBT)
+	}
+}
+
+func TestParseColorlessFireworkStar(t *testing.T) {
+	item, _ := parseItem(t, nil, map[string]any{
+		"Name":   "minecraft:firework_star",
+		"Count":  byte(1),
+		"Damage": int16(0),
+		"tag": map[string]any{
+			"FireworksItem": testExplosion([]byte{}, []byte{1}, mapping.FireworkTypeLargeBall),
+		},
+	})
+	star := item.(*FireworkStar)
+	if star.IsComplex() || !ExplosionsDropped(star) {
+		t.Fatalf("unexpected firework star %#v", star.NBT)
+	}
+}
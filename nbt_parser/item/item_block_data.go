@@ -29,6 +29,12 @@ type ItemBlockData struct {
 	// 数据的容器，但如果被判定为不需要特殊
 	// 处理，则 SubBlock 仍然解析为空
 	SubBlock nbt_parser_interface.Block
+
+	// droppedItems 和 lossyItems 分别是子方块在
+	// 解析时丢弃的物品和只能被近似还原的物品。
+	// 即便 SubBlock 为空，它们也会被记录
+	droppedItems []nbt_parser_interface.DroppedItem
+	lossyItems   []nbt_parser_interface.DroppedItem
 }
 
 // HaveSubBlockData 验证 tag 是否指向有效的子方块数据荷载。
@@ -124,6 +130,7 @@ func ParseItemBlock(
 	if err != nil {
 		return ItemBlockData{}, fmt.Errorf("ParseItemBlock: %v", err)
 	}
+	result.droppedItems, result.lossyItems = subBlock.DroppedItems()
 	if subBlock.NeedSpecialHandle() {
 		result.SubBlock = subBlock
 	}
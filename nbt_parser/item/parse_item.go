@@ -13,7 +13,9 @@ import (
 //
 // nameChecker 是一个可选的函数，用于检查 name 所
 // 指示的物品名称是否可通过指令获取。如果不能，则返
-// 回的 canGetByCommand 为假。
+// 回的 canGetByCommand 为假。对于具有无法还原的效果
+// 的物品 (参见 HasUnsupportedEffects)，canGetByCommand
+// 也总是为假。
 //
 // 无论 canGetByCommand 的值是多少，如果解析没有发
 // 生错误，则 item 不会为空。
@@ -51,6 +53,20 @@ func ParseItemNormal(
 		item = &Banner{DefaultItem: defaultItem}
 	case mapping.SupportNBTItemTypeShield:
 		item = &Shield{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeFirework:
+		item = &Firework{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeFireworkStar:
+		item = &FireworkStar{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypePotion:
+		item = &Potion{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeTippedArrow:
+		item = &TippedArrow{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeSuspiciousStew:
+		item = &SuspiciousStew{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeArmor:
+		item = &Armor{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeFilledMap:
+		item = &FilledMap{DefaultItem: defaultItem}
 	default:
 		panic("ParseItemNormal: Should nerver happened")
 	}
@@ -59,6 +75,17 @@ func ParseItemNormal(
 	if err != nil {
 		return nil, false, fmt.Errorf("ParseItemNormal: %v", err)
 	}
+
+	// 具有无法还原的效果的物品无法被制作
+	if HasUnsupportedEffects(item) {
+		return item, false, nil
+	}
+	// 无法通过命令获取的谜之炖菜可以在工作台上合成
+	if stew, ok := item.(*SuspiciousStew); ok && !canGetByCommand {
+		stew.markNeedCraft()
+		canGetByCommand = true
+	}
+
 	return item, canGetByCommand, nil
 }
 
@@ -84,6 +111,20 @@ func ParseItemNetwork(itemStack protocol.ItemStack, itemName string) (item nbt_p
 		item = &Banner{DefaultItem: defaultItem}
 	case mapping.SupportNBTItemTypeShield:
 		item = &Shield{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeFirework:
+		item = &Firework{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeFireworkStar:
+		item = &FireworkStar{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypePotion:
+		item = &Potion{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeTippedArrow:
+		item = &TippedArrow{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeSuspiciousStew:
+		item = &SuspiciousStew{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeArmor:
+		item = &Armor{DefaultItem: defaultItem}
+	case mapping.SupportNBTItemTypeFilledMap:
+		item = &FilledMap{DefaultItem: defaultItem}
 	default:
 		panic("ParseItemNetwork: Should nerver happened")
 	}
@@ -0,0 +1,34 @@
+package nbt_parser_item
+
+import (
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+)
+
+// parseItem 将 itemMap 编码为小端序的 NBT 并重新解码，
+// 然后使用 nameChecker 解析其所指示的物品。这使得 itemMap
+// 中的字节数组等数据与从存档中读取到的具有相同的类型
+func parseItem(
+	t *testing.T,
+	nameChecker func(name string) bool,
+	itemMap map[string]any,
+) (item nbt_parser_interface.Item, canGetByCommand bool) {
+	t.Helper()
+
+	itemBytes, err := nbt.MarshalEncoding(itemMap, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err = nbt.UnmarshalEncoding(itemBytes, &decoded, nbt.LittleEndian); err != nil {
+		t.Fatal(err)
+	}
+
+	item, canGetByCommand, err = ParseItemNormal(nameChecker, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return item, canGetByCommand
+}
@@ -0,0 +1,219 @@
+package nbt_parser_item
+
+import (
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	"github.com/OmineDev/flowers-for-machines/utils"
+)
+
+// potionIDFromTag 从物品的 tag 标签解析药水 ID。
+// 它用于识别以 Java 版药水名称或自定义效果记载的药水。
+//
+// specified 指示 tag 是否记载了药水名称或自定义效果，
+// 而 found 指示它们能否被映射为单个基岩版药水 ID
+func potionIDFromTag(tag map[string]any) (potionID int16, specified bool, found bool) {
+	effects, ok := tag["CustomPotionEffects"].([]any)
+	if !ok {
+		effects, _ = tag["custom_potion_effects"].([]any)
+	}
+
+	name, haveName := tag["Potion"].(string)
+	if haveName {
+		potionID, found = mapping.PotionJavaNameToID[strings.TrimPrefix(strings.ToLower(name), "minecraft:")]
+		if len(effects) == 0 {
+			return potionID, true, found
+		}
+	}
+	if len(effects) == 0 {
+		return 0, false, false
+	}
+
+	// 基岩版的药水只能具有其药水 ID 所对应的效果，
+	// 因此多个自定义效果无法被还原
+	if len(effects) != 1 {
+		return 0, true, false
+	}
+	effect, ok := effects[0].(map[string]any)
+	if !ok {
+		return 0, true, false
+	}
+	effectID, _ := effect["Id"].(byte)
+	amplifier, _ := effect["Amplifier"].(byte)
+
+	effectPotionID, effectFound := mapping.PotionEffectToID[mapping.PotionEffect{
+		ID:        int32(effectID),
+		Amplifier: int32(amplifier),
+	}]
+	if !effectFound {
+		return 0, true, false
+	}
+	// 药水名称所对应的效果与自定义效果不同，
+	// 这意味着该药水同时具有两种效果
+	if haveName && potionID != 0 && potionID != effectPotionID {
+		return 0, true, false
+	}
+	return effectPotionID, true, true
+}
+
+// HasUnsupportedEffects 检查 item 是否是具有无法在基岩版
+// 还原的效果的药水、药箭或谜之炖菜，例如多个自定义效果或
+// 未知的效果。这样的物品无法被制作，因此它们总是被丢弃
+func HasUnsupportedEffects(item nbt_parser_interface.Item) bool {
+	switch value := item.(type) {
+	case *Potion:
+		return value.unsupportedEffects
+	case *TippedArrow:
+		return value.unsupportedEffects
+	case *SuspiciousStew:
+		return value.unsupportedEffects
+	}
+	return false
+}
+
+// 药水 (包括喷溅药水和滞留药水)
+type Potion struct {
+	DefaultItem
+	// unsupportedEffects 指示该药水的效果无法在基岩版还原
+	unsupportedEffects bool
+}
+
+// parse ..
+func (p *Potion) parse(tag map[string]any) {
+	if p.DefaultItem.Basic.Metadata != 0 {
+		return
+	}
+	potionID, specified, found := potionIDFromTag(tag)
+	if found {
+		p.DefaultItem.Basic.Metadata = potionID
+	}
+	p.unsupportedEffects = specified && !found
+}
+
+func (p *Potion) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	p.parse(tag)
+	return nil
+}
+
+func (p *Potion) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	p.parse(item.NBTData)
+	return nil
+}
+
+// 药箭 (包括普通的箭)
+type TippedArrow struct {
+	DefaultItem
+	// unsupportedEffects 指示该药箭的效果无法在基岩版还原
+	unsupportedEffects bool
+}
+
+// parse ..
+func (t *TippedArrow) parse(tag map[string]any) {
+	if t.DefaultItem.Basic.Metadata != 0 {
+		return
+	}
+	potionID, specified, found := potionIDFromTag(tag)
+	if found {
+		t.DefaultItem.Basic.Metadata = potionID + 1
+	}
+	t.unsupportedEffects = specified && !found
+}
+
+func (t *TippedArrow) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	t.parse(tag)
+	return nil
+}
+
+func (t *TippedArrow) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	t.parse(item.NBTData)
+	return nil
+}
+
+// SuspiciousStewNBT ..
+type SuspiciousStewNBT struct {
+	// NeedCraft 指示该谜之炖菜无法通过命令获取，
+	// 因此需要在工作台上合成
+	NeedCraft bool
+}
+
+// 谜之炖菜
+type SuspiciousStew struct {
+	DefaultItem
+	NBT SuspiciousStewNBT
+	// unsupportedEffects 指示该谜之炖菜的效果无法在基岩版还原
+	unsupportedEffects bool
+}
+
+func (s *SuspiciousStew) Format(prefix string) string {
+	result := s.DefaultItem.Format(prefix)
+	if s.IsComplex() {
+		result += prefix + "附加数据: \n"
+		result += prefix + "\t需要通过合成得到\n"
+	}
+	return result
+}
+
+// parse ..
+func (s *SuspiciousStew) parse(tag map[string]any) {
+	var effectID int32
+	var found bool
+
+	if s.DefaultItem.Basic.Metadata != 0 {
+		return
+	}
+
+	effects, _ := tag["Effects"].([]any)
+	if len(effects) > 0 {
+		if effect, ok := effects[0].(map[string]any); ok {
+			id, ok := effect["EffectId"].(byte)
+			effectID, found = int32(id), ok
+		}
+	}
+	if len(effects) == 0 {
+		effects, _ = tag["effects"].([]any)
+		if len(effects) > 0 {
+			if effect, ok := effects[0].(map[string]any); ok {
+				name, _ := effect["id"].(string)
+				effectID, found = mapping.EffectJavaNameToID[strings.TrimPrefix(strings.ToLower(name), "minecraft:")]
+			}
+		}
+	}
+	if len(effects) == 0 {
+		return
+	}
+
+	// 基岩版的谜之炖菜只能具有单个效果，
+	// 并且该效果必须对应某种花
+	metadata, ok := mapping.SuspiciousStewEffectToMetadata[effectID]
+	if !found || !ok || len(effects) != 1 {
+		s.unsupportedEffects = true
+		return
+	}
+	s.DefaultItem.Basic.Metadata = metadata
+}
+
+// markNeedCraft 将无法通过命令获取的谜之炖菜标记为需要合成。
+// 通过合成得到的谜之炖菜无法保留物品组件
+func (s *SuspiciousStew) markNeedCraft() {
+	s.NBT.NeedCraft = true
+	s.DefaultItem.Enhance.ItemComponent = utils.ItemComponent{}
+}
+
+func (s *SuspiciousStew) ParseNormal(nbtMap map[string]any) error {
+	tag, _ := nbtMap["tag"].(map[string]any)
+	s.parse(tag)
+	return nil
+}
+
+func (s *SuspiciousStew) ParseNetwork(item protocol.ItemStack, itemName string) error {
+	s.parse(item.NBTData)
+	return nil
+}
+
+func (s SuspiciousStew) IsComplex() bool {
+	return s.NBT.NeedCraft
+}
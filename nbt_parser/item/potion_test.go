@@ -0,0 +1,183 @@
+package nbt_parser_item
+
+import (
+	"testing"
+)
+
+// testPotion 返回药水家族的物品 name 在存档中的形式
+func testPotion(name string, tag map[string]any) map[string]any {
+	return map[string]any{
+		"Name":   name,
+		"Count":  byte(1),
+		"Damage": int16(0),
+		"tag":    tag,
+	}
+}
+
+// testEffect 返回 Java 版的自定义药水效果
+func testEffect(id byte, amplifier byte) map[string]any {
+	return map[string]any{
+		"Id":        id,
+		"Amplifier": amplifier,
+		"Duration":  int32(3600),
+	}
+}
+
+func TestParsePotion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		itemMap         map[string]any
+		metadata        int16
+		canGetByCommand bool
+	}{
+		{
+			name:            "java name",
+			itemMap:         testPotion("minecraft:potion", map[string]any{"Potion": "minecraft:strong_healing"}),
+			metadata:        22,
+			canGetByCommand: true,
+		},
+		{
+			name: "single custom effect",
+			itemMap: testPotion("minecraft:splash_potion", map[string]any{
+				"CustomPotionEffects": []any{testEffect(1, 1)},
+			}),
+			metadata:        16,
+			canGetByCommand: true,
+		},
+		{
+			name: "name agrees with effect",
+			itemMap: testPotion("minecraft:lingering_potion", map[string]any{
+				"Potion":                "minecraft:swiftness",
+				"custom_potion_effects": []any{testEffect(1, 0)},
+			}),
+			metadata:        14,
+			canGetByCommand: true,
+		},
+		{
+			name: "name conflicts with effect",
+			itemMap: testPotion("minecraft:potion", map[string]any{
+				"Potion":              "minecraft:swiftness",
+				"CustomPotionEffects": []any{testEffect(6, 0)},
+			}),
+			canGetByCommand: false,
+		},
+		{
+			name: "multiple custom effects",
+			itemMap: testPotion("minecraft:potion", map[string]any{
+				"CustomPotionEffects": []any{testEffect(1, 0), testEffect(5, 0)},
+			}),
+			canGetByCommand: false,
+		},
+		{
+			name:            "unknown name",
+			itemMap:         testPotion("minecraft:potion", map[string]any{"Potion": "minecraft:luck"}),
+			canGetByCommand: false,
+		},
+		{
+			name:            "tipped arrow",
+			itemMap:         testPotion("minecraft:arrow", map[string]any{"Potion": "minecraft:long_poison"}),
+			metadata:        27,
+			canGetByCommand: true,
+		},
+		{
+			name:            "plain arrow",
+			itemMap:         testPotion("minecraft:arrow", map[string]any{}),
+			canGetByCommand: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		item, canGetByCommand := parseItem(t, nil, testCase.itemMap)
+		if canGetByCommand != testCase.canGetByCommand || HasUnsupportedEffects(item) == testCase.canGetByCommand {
+			t.Fatalf("%s: unexpected canGetByCommand %v", testCase.name, canGetByCommand)
+		}
+		if item.ItemMetadata() != testCase.metadata {
+			t.Fatalf("%s: unexpected metadata %d", testCase.name, item.ItemMetadata())
+		}
+	}
+}
+
+func TestParsePotionKeepsMetadata(t *testing.T) {
+	itemMap := testPotion("minecraft:potion", map[string]any{"Potion": "minecraft:strong_healing"})
+	itemMap["Damage"] = int16(5)
+
+	// 数据值已经指示了药水，因此 tag 不会覆盖它
+	item, _ := parseItem(t, nil, itemMap)
+	if item.ItemMetadata() != 5 {
+		t.Fatalf("unexpected metadata %d", item.ItemMetadata())
+	}
+}
+
+func TestParseSuspiciousStew(t *testing.T) {
+	canGet := func(string) bool { return true }
+	cannotGet := func(string) bool { return false }
+
+	testCases := []struct {
+		name            string
+		nameChecker     func(string) bool
+		tag             map[string]any
+		metadata        int16
+		needCraft       bool
+		canGetByCommand bool
+	}{
+		{
+			name:        "bedrock effect",
+			nameChecker: canGet,
+			tag: map[string]any{
+				"Effects": []any{map[string]any{"EffectId": byte(8), "EffectDuration": int32(120)}},
+			},
+			metadata:        1,
+			canGetByCommand: true,
+		},
+		{
+			name:        "java effect",
+			nameChecker: canGet,
+			tag: map[string]any{
+				"effects": []any{map[string]any{"id": "minecraft:wither", "duration": int32(160)}},
+			},
+			metadata:        7,
+			canGetByCommand: true,
+		},
+		{
+			name:        "need craft",
+			nameChecker: cannotGet,
+			tag: map[string]any{
+				"Effects": []any{map[string]any{"EffectId": byte(12), "EffectDuration": int32(80)}},
+			},
+			metadata:        9,
+			needCraft:       true,
+			canGetByCommand: true,
+		},
+		{
+			name:        "multiple effects",
+			nameChecker: canGet,
+			tag: map[string]any{
+				"Effects": []any{
+					map[string]any{"EffectId": byte(8), "EffectDuration": int32(120)},
+					map[string]any{"EffectId": byte(12), "EffectDuration": int32(80)},
+				},
+			},
+		},
+		{
+			name:        "effect without flower",
+			nameChecker: canGet,
+			tag: map[string]any{
+				"Effects": []any{map[string]any{"EffectId": byte(1), "EffectDuration": int32(120)}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		item, canGetByCommand := parseItem(t, testCase.nameChecker, testPotion("minecraft:suspicious_stew", testCase.tag))
+		stew, ok := item.(*SuspiciousStew)
+		if !ok {
+			t.Fatalf("%s: unexpected item %T", testCase.name, item)
+		}
+		if canGetByCommand != testCase.canGetByCommand || HasUnsupportedEffects(stew) == testCase.canGetByCommand {
+			t.Fatalf("%s: unexpected canGetByCommand %v", testCase.name, canGetByCommand)
+		}
+		if stew.ItemMetadata() != testCase.metadata || stew.NBT.NeedCraft != testCase.needCraft {
+			t.Fatalf("%s: unexpected metadata %d and need craft %v", testCase.name, stew.ItemMetadata(), stew.NBT.NeedCraft)
+		}
+	}
+}
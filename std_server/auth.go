@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RolePublic = "public" // 无需身份验证
+	RoleUser   = "user"   // 需要任意有效的凭据
+	RoleAdmin  = "admin"  // 需要具有 admin 角色的凭据
+)
+
+// DefaultMaxClockSkew 是 HMAC 签名中的时间戳
+// 与服务器时间之间默认允许的最大偏差
+const DefaultMaxClockSkew = time.Minute * 5
+
+// MinTokenLength 是令牌和 HMAC 密钥的最小长度
+const MinTokenLength = 16
+
+// MaxSignedBodySize 是 HMAC 签名验证时所读取的请求体的最大字节数。
+// 请求体需要在签名验证前被完整读取，因此更大的请求体将被直接拒绝
+const MaxSignedBodySize = 32 << 20
+
+// HMACScheme 是 HMAC 签名验证所使用的 Authorization 方案。
+//
+// 其格式为 "HMAC-SHA256 <名称>:<Unix 时间戳>:<签名>"，
+// 其中签名是以凭据的 HMACSecret 为密钥，对以下内容计算的
+// HMAC-SHA256 的十六进制表示:
+//
+//	<请求方法>\n<请求 URI>\n<Unix 时间戳>\n<请求体的 SHA256 十六进制表示>
+//
+// 请求 URI 包含查询参数，例如 /jobs?limit=1。
+//
+// 每个签名只能使用一次，重放的请求将被拒绝。因此，客户端不应在
+// 同一秒内发送方法、URI 和请求体都完全相同的多个请求
+const HMACScheme = "HMAC-SHA256"
+
+// roleLevel 将角色映射到其权限等级
+var roleLevel = map[string]int{
+	RolePublic: 0,
+	RoleUser:   1,
+	RoleAdmin:  2,
+}
+
+// replayCache 记录在允许的时间偏差内已经使用过的 HMAC 签名，
+// 以拒绝重放的请求
+type replayCache struct {
+	mu   *sync.Mutex
+	seen map[string]time.Time // 签名到其过期时间的映射
+}
+
+// newReplayCache 创建并返回一个新的 replayCache
+func newReplayCache() *replayCache {
+	return &replayCache{
+		mu:   new(sync.Mutex),
+		seen: make(map[string]time.Time),
+	}
+}
+
+// use 将签名 signature 标记为已使用，它在 expireAt 之后被遗忘。
+// 如果该签名此前已经被使用过，则返回假
+func (r *replayCache) use(signature string, expireAt time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, value := range r.seen {
+		if now.After(value) {
+			delete(r.seen, key)
+		}
+	}
+
+	if _, ok := r.seen[signature]; ok {
+		return false
+	}
+	r.seen[signature] = expireAt
+	return true
+}
+
+// validate 检查身份验证配置，
+// 并返回所有不合法的配置项的错误信息
+func (a AuthConfig) validate() (errs []error) {
+	invalid := func(path string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	if len(a.AdminToken) > 0 && len(a.AdminToken) < MinTokenLength {
+		invalid(
+			"http.auth.admin_token (env "+envName("http.auth.admin_token")+")",
+			"must be at least %d characters", MinTokenLength,
+		)
+	}
+	if a.Enabled && len(a.AdminToken) == 0 && len(a.Credentials) == 0 {
+		invalid("http.auth.credentials", "at least one credential or admin_token is required when auth is enabled")
+	}
+	if a.MaxClockSkew <= 0 {
+		invalid("http.auth.max_clock_skew", "%v must be positive; e.g. \"5m\"", a.MaxClockSkew)
+	}
+
+	names := make(map[string]bool)
+	for index, credential := range a.Credentials {
+		path := fmt.Sprintf("http.auth.credentials[%d]", index)
+		if len(credential.Name) == 0 {
+			invalid(path+".name", "must not be empty")
+		} else if names[credential.Name] {
+			invalid(path+".name", "%#v is used by another credential", credential.Name)
+		} else if strings.Contains(credential.Name, ":") {
+			invalid(path+".name", "%#v must not contain ':'", credential.Name)
+		}
+		names[credential.Name] = true
+
+		if credential.Role != RoleUser && credential.Role != RoleAdmin {
+			invalid(path+".role", "%#v must be %#v or %#v", credential.Role, RoleUser, RoleAdmin)
+		}
+		if len(credential.Token) == 0 && len(credential.HMACSecret) == 0 {
+			invalid(path, "token or hmac_secret is required")
+		}
+		if len(credential.Token) > 0 && len(credential.Token) < MinTokenLength {
+			invalid(path+".token", "must be at least %d characters", MinTokenLength)
+		}
+		if len(credential.HMACSecret) > 0 && len(credential.HMACSecret) < MinTokenLength {
+			invalid(path+".hmac_secret", "must be at least %d characters", MinTokenLength)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(a.RouteRoles)) {
+		role := a.RouteRoles[key]
+		path := fmt.Sprintf("http.auth.route_roles[%#v]", key)
+		if _, ok := routeByKey(key); !ok {
+			invalid(path, "unknown route; the key must look like \"GET /process_exit\"")
+		}
+		if _, ok := roleLevel[role]; !ok {
+			invalid(path, "%#v must be %#v, %#v or %#v", role, RolePublic, RoleUser, RoleAdmin)
+		}
+	}
+
+	return errs
+}
+
+// credentials 返回全部可用的凭据，
+// 包括由 AdminToken 给出的凭据
+func (a AuthConfig) credentials() []Credential {
+	result := append([]Credential(nil), a.Credentials...)
+	if len(a.AdminToken) > 0 {
+		result = append(result, Credential{
+			Name:  "admin_token",
+			Role:  RoleAdmin,
+			Token: a.AdminToken,
+		})
+	}
+	return result
+}
+
+// requiredRole 返回调用 method 和 path 所指示的 API 所需的最低角色
+func (a AuthConfig) requiredRole(method string, path string) string {
+	key := routeKey(method, path)
+	if role, ok := a.RouteRoles[key]; ok {
+		return role
+	}
+	if r, ok := routeByKey(key); ok {
+		return r.role
+	}
+	return RoleUser
+}
+
+// AuthMiddleware 返回按 auth 对请求进行身份验证和鉴权的中间件。
+// 未通过身份验证的请求将得到 401，权限不足的请求将得到 403
+func AuthMiddleware(auth AuthConfig) gin.HandlerFunc {
+	credentials := auth.credentials()
+	replays := newReplayCache()
+
+	return func(c *gin.Context) {
+		// 未知的路由交由 NoRoute 处理
+		if !auth.Enabled || len(c.FullPath()) == 0 {
+			c.Next()
+			return
+		}
+
+		requiredRole := auth.requiredRole(c.Request.Method, c.FullPath())
+		if requiredRole == RolePublic {
+			c.Next()
+			return
+		}
+
+		credential, err := authenticate(c, credentials, auth.MaxClockSkew, replays)
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+				Success:   false,
+				ErrorType: ResponseErrorTypeParseError,
+				ErrorInfo: fmt.Sprintf("Request body is larger than %d bytes", maxBytesError.Limit),
+			})
+			return
+		}
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="std_server"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Success:   false,
+				ErrorType: ResponseErrorTypeUnauthorized,
+				ErrorInfo: fmt.Sprintf("Unauthorized: %v", err),
+			})
+			return
+		}
+
+		if roleLevel[credential.Role] < roleLevel[requiredRole] {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Success:   false,
+				ErrorType: ResponseErrorTypeForbidden,
+				ErrorInfo: fmt.Sprintf("Forbidden: Credential %#v (role = %s) can not call %s %s", credential.Name, credential.Role, c.Request.Method, c.FullPath()),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticate 根据请求 c 的 Authorization 头
+// 从 credentials 中找出请求所使用的凭据。
+// replays 记录已经使用过的 HMAC 签名
+func authenticate(
+	c *gin.Context,
+	credentials []Credential,
+	maxClockSkew time.Duration,
+	replays *replayCache,
+) (credential Credential, err error) {
+	header := c.GetHeader("Authorization")
+	scheme, value, _ := strings.Cut(header, " ")
+
+	switch {
+	case len(header) == 0:
+		return Credential{}, fmt.Errorf("authenticate: Authorization header is missing")
+	case strings.EqualFold(scheme, "Bearer"):
+		return authenticateBearer(strings.TrimSpace(value), credentials)
+	case strings.EqualFold(scheme, HMACScheme):
+		return authenticateHMAC(c, strings.TrimSpace(value), credentials, maxClockSkew, replays)
+	default:
+		return Credential{}, fmt.Errorf("authenticate: Unsupported authorization scheme %#v", scheme)
+	}
+}
+
+// authenticateBearer 找出令牌为 token 的凭据
+func authenticateBearer(token string, credentials []Credential) (credential Credential, err error) {
+	found := false
+	for _, value := range credentials {
+		if len(value.Token) == 0 {
+			continue
+		}
+		// 总是比较全部凭据，避免泄露匹配的位置
+		if subtle.ConstantTimeCompare([]byte(token), []byte(value.Token)) == 1 && !found {
+			credential, found = value, true
+		}
+	}
+	if !found {
+		return Credential{}, fmt.Errorf("authenticateBearer: Invalid token")
+	}
+	return credential, nil
+}
+
+// authenticateHMAC 验证请求 c 的 HMAC 签名 value，并返回签名所使用的凭据。
+// 已经记录在 replays 中的签名将被拒绝
+func authenticateHMAC(
+	c *gin.Context,
+	value string,
+	credentials []Credential,
+	maxClockSkew time.Duration,
+	replays *replayCache,
+) (credential Credential, err error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Malformed signature; expected <name>:<timestamp>:<signature>")
+	}
+	name, timestampString, signatureString := parts[0], parts[1], parts[2]
+
+	found := false
+	for _, value := range credentials {
+		if value.Name == name && len(value.HMACSecret) > 0 {
+			credential, found = value, true
+			break
+		}
+	}
+	if !found {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Unknown key %#v", name)
+	}
+
+	timestamp, err := strconv.ParseInt(timestampString, 10, 64)
+	if err != nil {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Malformed timestamp %#v", timestampString)
+	}
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Timestamp is out of the allowed clock skew (%v)", maxClockSkew)
+	}
+
+	signature, err := hex.DecodeString(signatureString)
+	if err != nil {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Malformed signature %#v", signatureString)
+	}
+
+	// 读取请求体后需要将其放回，以便后续的处理函数使用。
+	// 此时签名尚未被验证，因此需要限制所读取的字节数
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxSignedBodySize))
+	if err != nil {
+		return Credential{}, fmt.Errorf("authenticateHMAC: %w", err)
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !hmac.Equal(signature, SignRequest(
+		credential.HMACSecret,
+		c.Request.Method,
+		c.Request.URL.RequestURI(),
+		timestamp,
+		body,
+	)) {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Signature mismatch")
+	}
+
+	// 超出允许的时间偏差的签名会因时间戳而被拒绝，
+	// 因此只需在此之前记住该签名
+	if !replays.use(name+":"+strings.ToLower(signatureString), time.Unix(timestamp, 0).Add(maxClockSkew)) {
+		return Credential{}, fmt.Errorf("authenticateHMAC: Signature has already been used")
+	}
+	return credential, nil
+}
+
+// SignRequest 计算请求的 HMAC-SHA256 签名，
+// 其格式参见 HMACScheme
+func SignRequest(secret string, method string, requestURI string, timestamp int64, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + requestURI + "\n" + strconv.FormatInt(timestamp, 10) + "\n" + hex.EncodeToString(bodyHash[:])))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testUserToken  = "user-token-0123456789"
+	testAdminToken = "admin-token-0123456789"
+	testHMACSecret = "hmac-secret-0123456789"
+)
+
+// testAuthConfig 返回启用了身份验证的配置
+func testAuthConfig() AuthConfig {
+	return AuthConfig{
+		Enabled:    true,
+		AdminToken: testAdminToken,
+		Credentials: []Credential{
+			{Name: "builder", Role: RoleUser, Token: testUserToken},
+			{Name: "signer", Role: RoleUser, HMACSecret: testHMACSecret},
+		},
+		MaxClockSkew: DefaultMaxClockSkew,
+	}
+}
+
+// testRouter 返回一个使用 auth 进行身份验证的路由器。
+// 它的每个 API 都返回所收到的请求体
+func testRouter(auth AuthConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(auth))
+	for _, r := range routes {
+		router.Handle(r.method, r.path, func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.Data(http.StatusOK, "text/plain", body)
+		})
+	}
+	router.NoRoute(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusNotFound)
+	})
+	return router
+}
+
+// hmacHeader 返回使用 secret 对请求签名后得到的 Authorization 头
+func hmacHeader(name string, secret string, method string, requestURI string, timestamp int64, body []byte) string {
+	signature := SignRequest(secret, method, requestURI, timestamp, body)
+	return fmt.Sprintf("%s %s:%d:%s", HMACScheme, name, timestamp, hex.EncodeToString(signature))
+}
+
+// serve 使用 router 处理请求，并返回响应
+func serve(router *gin.Engine, method string, target string, authorization string, body []byte) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, bytes.NewReader(body))
+	if len(authorization) > 0 {
+		request.Header.Set("Authorization", authorization)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	now := time.Now().Unix()
+	body := []byte(`{"block_name":"minecraft:chest"}`)
+	signed := func(method string, requestURI string, timestamp int64) string {
+		return hmacHeader("signer", testHMACSecret, method, requestURI, timestamp, body)
+	}
+
+	testCases := []struct {
+		name          string
+		auth          func(auth *AuthConfig)
+		method        string
+		target        string
+		authorization string
+		want          int
+	}{
+		{"disabled", func(auth *AuthConfig) { auth.Enabled = false }, http.MethodGet, "/process_exit", "", http.StatusOK},
+		{"public route", nil, http.MethodGet, "/", "", http.StatusOK},
+		{"unknown route", nil, http.MethodGet, "/unknown", "", http.StatusNotFound},
+		{"missing header", nil, http.MethodGet, "/jobs", "", http.StatusUnauthorized},
+		{"unsupported scheme", nil, http.MethodGet, "/jobs", "Basic " + testUserToken, http.StatusUnauthorized},
+
+		{"bearer user", nil, http.MethodGet, "/jobs", "Bearer " + testUserToken, http.StatusOK},
+		{"bearer case insensitive", nil, http.MethodGet, "/jobs", "bearer  " + testUserToken, http.StatusOK},
+		{"bearer invalid", nil, http.MethodGet, "/jobs", "Bearer " + testUserToken + "x", http.StatusUnauthorized},
+		{"bearer hmac credential", nil, http.MethodGet, "/jobs", "Bearer " + testHMACSecret, http.StatusUnauthorized},
+		{"bearer user on admin route", nil, http.MethodDelete, "/cache", "Bearer " + testUserToken, http.StatusForbidden},
+		{"bearer admin on admin route", nil, http.MethodDelete, "/cache", "Bearer " + testAdminToken, http.StatusOK},
+		{"route with parameters", nil, http.MethodDelete, "/cache/nbt_block/1", "Bearer " + testUserToken, http.StatusForbidden},
+
+		{"route made public", func(auth *AuthConfig) {
+			auth.RouteRoles = map[string]string{"GET /metrics": RolePublic}
+		}, http.MethodGet, "/metrics", "", http.StatusOK},
+		{"route made admin", func(auth *AuthConfig) {
+			auth.RouteRoles = map[string]string{"POST /place_nbt_block": RoleAdmin}
+		}, http.MethodPost, "/place_nbt_block", "Bearer " + testUserToken, http.StatusForbidden},
+
+		{"hmac", nil, http.MethodPost, "/jobs", signed(http.MethodPost, "/jobs", now), http.StatusOK},
+		{"hmac with query", nil, http.MethodPost, "/jobs?wait=1", signed(http.MethodPost, "/jobs?wait=1", now+1), http.StatusOK},
+		{"hmac other uri", nil, http.MethodPost, "/jobs?wait=1", signed(http.MethodPost, "/jobs", now+2), http.StatusUnauthorized},
+		{"hmac other method", nil, http.MethodPost, "/jobs", signed(http.MethodGet, "/jobs", now+3), http.StatusUnauthorized},
+		{"hmac other body", nil, http.MethodPost, "/jobs",
+			hmacHeader("signer", testHMACSecret, http.MethodPost, "/jobs", now, []byte("{}")), http.StatusUnauthorized},
+		{"hmac wrong secret", nil, http.MethodPost, "/jobs",
+			hmacHeader("signer", testHMACSecret+"x", http.MethodPost, "/jobs", now, body), http.StatusUnauthorized},
+		{"hmac unknown key", nil, http.MethodPost, "/jobs",
+			hmacHeader("nobody", testHMACSecret, http.MethodPost, "/jobs", now, body), http.StatusUnauthorized},
+		{"hmac token credential", nil, http.MethodPost, "/jobs",
+			hmacHeader("builder", testUserToken, http.MethodPost, "/jobs", now, body), http.StatusUnauthorized},
+		{"hmac stale", nil, http.MethodPost, "/jobs",
+			signed(http.MethodPost, "/jobs", now-int64(DefaultMaxClockSkew/time.Second)-60), http.StatusUnauthorized},
+		{"hmac future", nil, http.MethodPost, "/jobs",
+			signed(http.MethodPost, "/jobs", now+int64(DefaultMaxClockSkew/time.Second)+60), http.StatusUnauthorized},
+		{"hmac malformed", nil, http.MethodPost, "/jobs", HMACScheme + " signer:" + fmt.Sprint(now), http.StatusUnauthorized},
+		{"hmac malformed timestamp", nil, http.MethodPost, "/jobs", HMACScheme + " signer:now:00", http.StatusUnauthorized},
+		{"hmac malformed signature", nil, http.MethodPost, "/jobs", fmt.Sprintf("%s signer:%d:zz", HMACScheme, now), http.StatusUnauthorized},
+		{"hmac user on admin route", nil, http.MethodPost, "/cache/evict_lru",
+			signed(http.MethodPost, "/cache/evict_lru", now), http.StatusForbidden},
+	}
+
+	for _, testCase := range testCases {
+		auth := testAuthConfig()
+		if testCase.auth != nil {
+			testCase.auth(&auth)
+		}
+		response := serve(testRouter(auth), testCase.method, testCase.target, testCase.authorization, body)
+		if response.Code != testCase.want {
+			t.Fatalf("%s: unexpected status %d; body = %s", testCase.name, response.Code, response.Body)
+		}
+		if response.Code == http.StatusUnauthorized && len(response.Header().Get("WWW-Authenticate")) == 0 {
+			t.Fatalf("%s: WWW-Authenticate header is missing", testCase.name)
+		}
+		// 通过身份验证后，处理函数仍然可以读取完整的请求体
+		if response.Code == http.StatusOK && testCase.method == http.MethodPost && response.Body.String() != string(body) {
+			t.Fatalf("%s: unexpected body %q", testCase.name, response.Body)
+		}
+	}
+}
+
+func TestAuthMiddlewareReplay(t *testing.T) {
+	router := testRouter(testAuthConfig())
+	body := []byte("{}")
+	authorization := hmacHeader("signer", testHMACSecret, http.MethodPost, "/jobs", time.Now().Unix(), body)
+
+	if response := serve(router, http.MethodPost, "/jobs", authorization, body); response.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", response.Code)
+	}
+	response := serve(router, http.MethodPost, "/jobs", authorization, body)
+	if response.Code != http.StatusUnauthorized || !strings.Contains(response.Body.String(), "already been used") {
+		t.Fatalf("replayed request should be rejected, got %d; body = %s", response.Code, response.Body)
+	}
+
+	// 每个路由器都有独立的重放缓存
+	if response = serve(testRouter(testAuthConfig()), http.MethodPost, "/jobs", authorization, body); response.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", response.Code)
+	}
+}
+
+func TestAuthMiddlewareBodyLimit(t *testing.T) {
+	router := testRouter(testAuthConfig())
+	body := bytes.Repeat([]byte{'a'}, MaxSignedBodySize+1)
+	authorization := hmacHeader("signer", testHMACSecret, http.MethodPost, "/jobs", time.Now().Unix(), body)
+
+	response := serve(router, http.MethodPost, "/jobs", authorization, body)
+	if response.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("unexpected status %d", response.Code)
+	}
+
+	// 请求体只有在签名者已知时才会被读取
+	authorization = hmacHeader("nobody", testHMACSecret, http.MethodPost, "/jobs", time.Now().Unix(), body)
+	if response = serve(router, http.MethodPost, "/jobs", authorization, body); response.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status %d", response.Code)
+	}
+}
+
+func TestReplayCacheExpire(t *testing.T) {
+	replays := newReplayCache()
+	if !replays.use("a", time.Now().Add(-time.Second)) {
+		t.Fatal("first use should succeed")
+	}
+	if !replays.use("b", time.Now().Add(time.Minute)) {
+		t.Fatal("first use should succeed")
+	}
+	// 过期的签名被遗忘，而未过期的签名仍然被拒绝
+	if !replays.use("a", time.Now().Add(time.Minute)) {
+		t.Fatal("expired signature should be forgotten")
+	}
+	if replays.use("b", time.Now().Add(time.Minute)) {
+		t.Fatal("signature should not be used twice")
+	}
+}
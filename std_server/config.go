@@ -0,0 +1,540 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_block"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix 是覆盖配置的环境变量的前缀。
+// 配置项 timeouts.command_request 对应的环境变量为 FFM_TIMEOUTS_COMMAND_REQUEST
+const EnvPrefix = "FFM_"
+
+// EnvConfigFile 是指定配置文件路径的环境变量
+const EnvConfigFile = EnvPrefix + "CONFIG"
+
+// Config 是标准服务器的配置。
+//
+// 配置的优先级从低到高依次为：默认值、配置文件、
+// 环境变量和命令行参数
+type Config struct {
+	Connection struct {
+		RentalServerCode     string `yaml:"rental_server_code"`
+		RentalServerPasscode string `yaml:"rental_server_passcode"`
+		AuthServerAddress    string `yaml:"auth_server_address"`
+		AuthServerToken      string `yaml:"auth_server_token"`
+	} `yaml:"connection"`
+
+	Console struct {
+		CenterX           int32  `yaml:"center_x"`
+		CenterY           int32  `yaml:"center_y"`
+		CenterZ           int32  `yaml:"center_z"`
+		BaseBackground    string `yaml:"base_background"`
+		DefaultHotbarSlot int    `yaml:"default_hotbar_slot"`
+	} `yaml:"console"`
+
+	// ExtraBots 是除 connection 所指示的机器人以外的其他机器人，
+	// 它们与该机器人位于同一租赁服，并共享同一个任务队列和缓存
+	ExtraBots []BotConfig `yaml:"extra_bots"`
+
+	Timeouts struct {
+		CommandRequest time.Duration `yaml:"command_request"`
+		ContainerOpen  time.Duration `yaml:"container_open"`
+		BlockPick      time.Duration `yaml:"block_pick"`
+		InitConsole    time.Duration `yaml:"init_console"`
+	} `yaml:"timeouts"`
+
+	Retries struct {
+		PlaceNBTBlock int `yaml:"place_nbt_block"`
+		ContainerOpen int `yaml:"container_open"`
+		BlockPick     int `yaml:"block_pick"`
+	} `yaml:"retries"`
+
+	Cache struct {
+		PersistentFile string `yaml:"persistent_file"`
+	} `yaml:"cache"`
+
+	HTTP struct {
+		ListenAddress string     `yaml:"listen_address"`
+		TLSCertFile   string     `yaml:"tls_cert_file"`
+		TLSKeyFile    string     `yaml:"tls_key_file"`
+		Auth          AuthConfig `yaml:"auth"`
+	} `yaml:"http"`
+
+	GRPC struct {
+		// ListenAddress 为空时不启动 gRPC 服务器
+		ListenAddress string `yaml:"listen_address"`
+	} `yaml:"grpc"`
+
+	JobQueue struct {
+		Size int `yaml:"size"`
+	} `yaml:"job_queue"`
+}
+
+// AuthConfig 是 HTTP API 的身份验证配置
+type AuthConfig struct {
+	// Enabled 指示是否启用身份验证。
+	// 为假时，任何人都可以调用全部 API
+	Enabled bool `yaml:"enabled"`
+	// AdminToken 是一个具有 admin 角色的 Bearer 令牌，
+	// 它便于通过环境变量或命令行参数配置
+	AdminToken string `yaml:"admin_token"`
+	// Credentials 是全部可用的凭据
+	Credentials []Credential `yaml:"credentials"`
+	// MaxClockSkew 是 HMAC 签名中的时间戳
+	// 与服务器时间之间所允许的最大偏差。
+	// 在此期间内，每个签名只能被使用一次
+	MaxClockSkew time.Duration `yaml:"max_clock_skew"`
+	// RouteRoles 覆盖各个 API 所需的最低角色。
+	// 其键的格式为 "GET /process_exit"，
+	// 其值可以是 public, user 或 admin
+	RouteRoles map[string]string `yaml:"route_roles"`
+}
+
+// Credential 是访问 HTTP API 的凭据。
+// Token 和 HMACSecret 至少需要提供一个
+type Credential struct {
+	// Name 是凭据的名称，它同时是 HMAC 签名中的密钥标识
+	Name string `yaml:"name"`
+	// Role 是凭据的角色，可以是 user 或 admin
+	Role string `yaml:"role"`
+	// Token 是用于 Bearer 验证的令牌
+	Token string `yaml:"token"`
+	// HMACSecret 是用于 HMAC 签名验证的密钥
+	HMACSecret string `yaml:"hmac_secret"`
+}
+
+// BotConfig 是工作池中单个机器人的配置
+type BotConfig struct {
+	// Name 是机器人的名称，它仅用于日志。
+	// 为空时使用 "bot-<序号>"
+	Name string `yaml:"name"`
+	// AuthServerAddress 为空时使用 connection.auth_server_address
+	AuthServerAddress string `yaml:"auth_server_address"`
+	// AuthServerToken 指示机器人所使用的账户，
+	// 每个机器人都应当使用不同的账户
+	AuthServerToken string `yaml:"auth_server_token"`
+	// CenterX, CenterY 和 CenterZ 是机器人的操作台中心，
+	// 不同机器人的操作台不能重叠
+	CenterX int32 `yaml:"center_x"`
+	CenterY int32 `yaml:"center_y"`
+	CenterZ int32 `yaml:"center_z"`
+}
+
+const (
+	// consoleRadius 是操作台在 X 轴和 Z 轴上自中心向外延伸的距离
+	consoleRadius = 5
+	// consoleHalfHeight 是操作台在 Y 轴上自中心向上和向下延伸的距离
+	consoleHalfHeight = 2
+)
+
+// Bots 返回全部机器人的配置，其中第一个是
+// connection 和 console 所指示的机器人
+func (cfg Config) Bots() []BotConfig {
+	result := []BotConfig{{
+		AuthServerAddress: cfg.Connection.AuthServerAddress,
+		AuthServerToken:   cfg.Connection.AuthServerToken,
+		CenterX:           cfg.Console.CenterX,
+		CenterY:           cfg.Console.CenterY,
+		CenterZ:           cfg.Console.CenterZ,
+	}}
+	result = append(result, cfg.ExtraBots...)
+
+	for index := range result {
+		if len(result[index].Name) == 0 {
+			result[index].Name = fmt.Sprintf("bot-%d", index)
+		}
+		if len(result[index].AuthServerAddress) == 0 {
+			result[index].AuthServerAddress = cfg.Connection.AuthServerAddress
+		}
+	}
+	return result
+}
+
+// DefaultConfig 返回默认的配置。
+// 各项设置的默认值取自其所在的包
+func DefaultConfig() Config {
+	var cfg Config
+
+	cfg.Console.BaseBackground = nbt_console.BaseBackground
+	cfg.Console.DefaultHotbarSlot = int(nbt_console.DefaultHotbarSlot)
+
+	cfg.Timeouts.CommandRequest = game_interface.DefaultTimeoutCommandRequest
+	cfg.Timeouts.ContainerOpen = game_interface.DefaultTimeoutContainerOpen
+	cfg.Timeouts.BlockPick = game_interface.DefaultTimeoutBlockPick
+	cfg.Timeouts.InitConsole = nbt_console.DefaultTimeoutInitConsole
+
+	cfg.Retries.PlaceNBTBlock = nbt_block.MaxRetryPlaceNBTBlock
+	cfg.Retries.ContainerOpen = game_interface.MaxRetryContainerOpen
+	cfg.Retries.BlockPick = game_interface.MaxRetryBlockPick
+
+	cfg.HTTP.Auth.MaxClockSkew = DefaultMaxClockSkew
+
+	cfg.JobQueue.Size = DefaultJobQueueSize
+	return cfg
+}
+
+// LoadConfigFile 将配置文件 path 的内容加载到 cfg。
+// 配置文件中未知的配置项将被视为错误
+func LoadConfigFile(cfg *Config, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("LoadConfigFile: %v", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	err = decoder.Decode(cfg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("LoadConfigFile: %s: %v", path, err)
+	}
+	return nil
+}
+
+// ApplyEnv 使用环境变量覆盖 cfg 中的配置项。
+// lookupEnv 通常是 os.LookupEnv
+func ApplyEnv(cfg *Config, lookupEnv func(key string) (string, bool)) error {
+	var errs []error
+
+	walkConfig(cfg, func(path string, field reflect.Value) {
+		key := envName(path)
+		value, ok := lookupEnv(key)
+		if !ok {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", key, err))
+		}
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("ApplyEnv: %v", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate 检查 cfg 中的全部配置项，
+// 并返回所有不合法的配置项的错误信息
+func (cfg Config) Validate() error {
+	var errs []error
+	invalid := func(path string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf(
+			"%s (env %s): %s",
+			path, envName(path), fmt.Sprintf(format, args...),
+		))
+	}
+
+	if len(cfg.Connection.RentalServerCode) == 0 {
+		invalid("connection.rental_server_code", "must not be empty; e.g. -rsn=\"123456\"")
+	}
+	if len(cfg.Connection.AuthServerAddress) == 0 {
+		invalid("connection.auth_server_address", "must not be empty; e.g. -asa=\"http://127.0.0.1\"")
+	} else if !strings.HasPrefix(cfg.Connection.AuthServerAddress, "http://") &&
+		!strings.HasPrefix(cfg.Connection.AuthServerAddress, "https://") {
+		invalid("connection.auth_server_address", "%#v must start with http:// or https://", cfg.Connection.AuthServerAddress)
+	}
+
+	if !strings.HasPrefix(cfg.Console.BaseBackground, "minecraft:") {
+		invalid("console.base_background", "%#v is not a namespaced block name like \"minecraft:sea_lantern\"", cfg.Console.BaseBackground)
+	}
+	if cfg.Console.DefaultHotbarSlot < 0 || cfg.Console.DefaultHotbarSlot > 8 {
+		invalid("console.default_hotbar_slot", "%d is out of range [0, 8]", cfg.Console.DefaultHotbarSlot)
+	}
+
+	errs = append(errs, cfg.validateBots()...)
+
+	for _, timeout := range []struct {
+		path  string
+		value time.Duration
+	}{
+		{"timeouts.command_request", cfg.Timeouts.CommandRequest},
+		{"timeouts.container_open", cfg.Timeouts.ContainerOpen},
+		{"timeouts.block_pick", cfg.Timeouts.BlockPick},
+		{"timeouts.init_console", cfg.Timeouts.InitConsole},
+	} {
+		if timeout.value <= 0 {
+			invalid(timeout.path, "%v must be positive; e.g. \"5s\"", timeout.value)
+		}
+	}
+
+	// 重试计数在内部以 uint8 记录
+	if cfg.Retries.PlaceNBTBlock < 0 || cfg.Retries.PlaceNBTBlock > 254 {
+		invalid("retries.place_nbt_block", "%d is out of range [0, 254]", cfg.Retries.PlaceNBTBlock)
+	}
+	if cfg.Retries.ContainerOpen < 1 {
+		invalid("retries.container_open", "%d must be at least 1", cfg.Retries.ContainerOpen)
+	}
+	if cfg.Retries.BlockPick < 1 {
+		invalid("retries.block_pick", "%d must be at least 1", cfg.Retries.BlockPick)
+	}
+
+	if len(cfg.HTTP.ListenAddress) == 0 {
+		invalid("http.listen_address", "must not be empty; e.g. \":8080\" or -ssp=8080")
+	} else if _, port, err := net.SplitHostPort(cfg.HTTP.ListenAddress); err != nil {
+		invalid("http.listen_address", "%#v is not a valid address: %v", cfg.HTTP.ListenAddress, err)
+	} else if number, err := strconv.ParseUint(port, 10, 16); err != nil || number == 0 {
+		invalid("http.listen_address", "%#v has an invalid port; the port must be in range [1, 65535]", cfg.HTTP.ListenAddress)
+	}
+
+	if (len(cfg.HTTP.TLSCertFile) == 0) != (len(cfg.HTTP.TLSKeyFile) == 0) {
+		invalid("http.tls_cert_file", "tls_cert_file and tls_key_file must be set together")
+	} else if len(cfg.HTTP.TLSCertFile) > 0 {
+		if _, err := tls.LoadX509KeyPair(cfg.HTTP.TLSCertFile, cfg.HTTP.TLSKeyFile); err != nil {
+			invalid("http.tls_cert_file", "failed to load the certificate and key: %v", err)
+		}
+	}
+
+	errs = append(errs, cfg.HTTP.Auth.validate()...)
+
+	if len(cfg.GRPC.ListenAddress) > 0 {
+		if _, port, err := net.SplitHostPort(cfg.GRPC.ListenAddress); err != nil {
+			invalid("grpc.listen_address", "%#v is not a valid address: %v", cfg.GRPC.ListenAddress, err)
+		} else if number, err := strconv.ParseUint(port, 10, 16); err != nil || number == 0 {
+			invalid("grpc.listen_address", "%#v has an invalid port; the port must be in range [1, 65535]", cfg.GRPC.ListenAddress)
+		} else if cfg.GRPC.ListenAddress == cfg.HTTP.ListenAddress {
+			invalid("grpc.listen_address", "%#v is already used by http.listen_address", cfg.GRPC.ListenAddress)
+		}
+	}
+
+	if cfg.JobQueue.Size < 1 {
+		invalid("job_queue.size", "%d must be at least 1", cfg.JobQueue.Size)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateBots 检查 extra_bots 中的每个机器人，
+// 并返回所有不合法的配置项的错误信息
+func (cfg Config) validateBots() (errs []error) {
+	invalid := func(path string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	bots := cfg.Bots()
+	for index, bot := range bots[1:] {
+		path := fmt.Sprintf("extra_bots[%d]", index)
+
+		if len(bot.AuthServerToken) == 0 {
+			invalid(path+".auth_server_token", "must not be empty; each bot needs its own account")
+		}
+		if !strings.HasPrefix(bot.AuthServerAddress, "http://") && !strings.HasPrefix(bot.AuthServerAddress, "https://") {
+			invalid(path+".auth_server_address", "%#v must start with http:// or https://", bot.AuthServerAddress)
+		}
+
+		// bots[index] 是 extra_bots[index] 之前的机器人
+		for other := range index + 1 {
+			otherPath := "connection"
+			if other > 0 {
+				otherPath = fmt.Sprintf("extra_bots[%d]", other-1)
+			}
+			if bot.Name == bots[other].Name {
+				invalid(path+".name", "%#v is used by %s", bot.Name, otherPath)
+			}
+			if len(bot.AuthServerToken) > 0 && bot.AuthServerToken == bots[other].AuthServerToken {
+				invalid(path+".auth_server_token", "is the same as the one of %s; each bot needs its own account", otherPath)
+			}
+			if consolesOverlap(bot, bots[other]) {
+				invalid(
+					path+".center_x",
+					"the console at (%d, %d, %d) overlaps the one of %s at (%d, %d, %d)",
+					bot.CenterX, bot.CenterY, bot.CenterZ, otherPath,
+					bots[other].CenterX, bots[other].CenterY, bots[other].CenterZ,
+				)
+			}
+		}
+	}
+
+	return errs
+}
+
+// consolesOverlap 检查机器人 a 和 b 的操作台是否重叠
+func consolesOverlap(a BotConfig, b BotConfig) bool {
+	distance := func(x int32, y int32) int64 {
+		if x > y {
+			return int64(x) - int64(y)
+		}
+		return int64(y) - int64(x)
+	}
+	return distance(a.CenterX, b.CenterX) <= 2*consoleRadius &&
+		distance(a.CenterY, b.CenterY) <= 2*consoleHalfHeight &&
+		distance(a.CenterZ, b.CenterZ) <= 2*consoleRadius
+}
+
+// Apply 将 cfg 中的调优设置应用到相应的包。
+// 它应当在与租赁服建立连接前被调用
+func (cfg Config) Apply() {
+	nbt_console.BaseBackground = cfg.Console.BaseBackground
+	nbt_console.DefaultHotbarSlot = resources_control.SlotID(cfg.Console.DefaultHotbarSlot)
+	nbt_console.DefaultTimeoutInitConsole = cfg.Timeouts.InitConsole
+
+	game_interface.DefaultTimeoutCommandRequest = cfg.Timeouts.CommandRequest
+	game_interface.DefaultTimeoutContainerOpen = cfg.Timeouts.ContainerOpen
+	game_interface.DefaultTimeoutBlockPick = cfg.Timeouts.BlockPick
+	game_interface.MaxRetryContainerOpen = cfg.Retries.ContainerOpen
+	game_interface.MaxRetryBlockPick = cfg.Retries.BlockPick
+
+	nbt_block.MaxRetryPlaceNBTBlock = cfg.Retries.PlaceNBTBlock
+}
+
+// walkConfig 对 cfg 中的每个配置项调用 f。
+// path 是配置项在配置文件中的路径，例如 timeouts.command_request
+func walkConfig(cfg *Config, f func(path string, field reflect.Value)) {
+	var walk func(prefix string, value reflect.Value)
+	walk = func(prefix string, value reflect.Value) {
+		for index := range value.NumField() {
+			name := strings.Split(value.Type().Field(index).Tag.Get("yaml"), ",")[0]
+			path := name
+			if len(prefix) > 0 {
+				path = prefix + "." + name
+			}
+
+			field := value.Field(index)
+			switch field.Kind() {
+			case reflect.Struct:
+				walk(path, field)
+			case reflect.Slice, reflect.Map:
+				// 列表和映射只能在配置文件中设置
+			default:
+				f(path, field)
+			}
+		}
+	}
+	walk("", reflect.ValueOf(cfg).Elem())
+}
+
+// envName 返回配置项 path 所对应的环境变量名称
+func envName(path string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// setField 将字符串 value 解析后写入配置项 field
+func setField(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%#v is not a valid duration; e.g. \"5s\"", value)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%#v is not a valid boolean; e.g. \"true\"", value)
+		}
+		field.SetBool(boolean)
+	case reflect.Int, reflect.Int32:
+		number, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%#v is not a valid %d-bit integer", value, field.Type().Bits())
+		}
+		field.SetInt(number)
+	default:
+		panic("setField: Should nerver happened")
+	}
+	return nil
+}
+
+// loadConfig 按优先级从默认值、配置文件、环境变量和命令行参数
+// 加载配置，并检查其合法性
+func loadConfig() (cfg Config, err error) {
+	cfg = DefaultConfig()
+
+	configFile := flag.String("c", "", "The path of the YAML config file. It can also be set by "+EnvConfigFile+".")
+	rentalServerCode := flag.String("rsn", "", "The rental server number.")
+	rentalServerPasscode := flag.String("rsp", "", "The pass code of the rental server.")
+	authServerAddress := flag.String("asa", "", "The auth server address.")
+	authServerToken := flag.String("ast", "", "The auth server token.")
+	standardServerPort := flag.Int("ssp", 0, "The server port to running.")
+	standardServerBindAddress := flag.String("sba", "", "The address the server binds to, e.g. 127.0.0.1. Leave it empty to bind all interfaces.")
+	grpcServerPort := flag.Int("gsp", 0, "The gRPC server port to running. It binds to the same address as the HTTP server. Leave it 0 to disable.")
+	consoleCenterX := flag.Int("ccx", 0, "The X position of the center of the console.")
+	consoleCenterY := flag.Int("ccy", 0, "The Y position of the center of the console.")
+	consoleCenterZ := flag.Int("ccz", 0, "The Z position of the center of the console.")
+	cacheFilePath := flag.String("cf", "", "The file to persist the NBT cache to. Leave it empty to disable persistence.")
+	jobQueueSize := flag.Int("jqs", DefaultJobQueueSize, "The maximum number of pending jobs.")
+	flag.Parse()
+
+	path := *configFile
+	if len(path) == 0 {
+		path = os.Getenv(EnvConfigFile)
+	}
+	if len(path) > 0 {
+		if err = LoadConfigFile(&cfg, path); err != nil {
+			return Config{}, fmt.Errorf("loadConfig: %v", err)
+		}
+	}
+
+	if err = ApplyEnv(&cfg, os.LookupEnv); err != nil {
+		return Config{}, fmt.Errorf("loadConfig: %v", err)
+	}
+
+	// 只有显式给出的命令行参数才会覆盖配置
+	grpcServerPortSet := false
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "rsn":
+			cfg.Connection.RentalServerCode = *rentalServerCode
+		case "rsp":
+			cfg.Connection.RentalServerPasscode = *rentalServerPasscode
+		case "asa":
+			cfg.Connection.AuthServerAddress = *authServerAddress
+		case "ast":
+			cfg.Connection.AuthServerToken = *authServerToken
+		case "ssp":
+			host, _, _ := net.SplitHostPort(cfg.HTTP.ListenAddress)
+			cfg.HTTP.ListenAddress = net.JoinHostPort(host, strconv.Itoa(*standardServerPort))
+		case "sba":
+			_, port, _ := net.SplitHostPort(cfg.HTTP.ListenAddress)
+			cfg.HTTP.ListenAddress = net.JoinHostPort(*standardServerBindAddress, port)
+		case "gsp":
+			grpcServerPortSet = true
+		case "ccx":
+			cfg.Console.CenterX = int32(*consoleCenterX)
+		case "ccy":
+			cfg.Console.CenterY = int32(*consoleCenterY)
+		case "ccz":
+			cfg.Console.CenterZ = int32(*consoleCenterZ)
+		case "cf":
+			cfg.Cache.PersistentFile = *cacheFilePath
+		case "jqs":
+			cfg.JobQueue.Size = *jobQueueSize
+		}
+	})
+	// -gsp 使用 HTTP 服务器的地址，因此需要在 -sba 之后处理
+	if grpcServerPortSet {
+		cfg.GRPC.ListenAddress = ""
+		if *grpcServerPort != 0 {
+			host, _, _ := net.SplitHostPort(cfg.HTTP.ListenAddress)
+			cfg.GRPC.ListenAddress = net.JoinHostPort(host, strconv.Itoa(*grpcServerPort))
+		}
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("loadConfig: Invalid configuration:\n%v", err)
+	}
+	return cfg, nil
+}
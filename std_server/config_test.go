@@ -0,0 +1,292 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testConfig 返回一个可以通过检查的配置
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Connection.RentalServerCode = "123456"
+	cfg.Connection.AuthServerAddress = "http://127.0.0.1"
+	cfg.HTTP.ListenAddress = ":8080"
+	return cfg
+}
+
+// testEnv 返回一个从 env 中查找环境变量的函数
+func testEnv(env map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg := testConfig()
+	err := ApplyEnv(&cfg, testEnv(map[string]string{
+		"FFM_CONNECTION_RENTAL_SERVER_CODE": "654321",
+		"FFM_CONSOLE_CENTER_X":              "-12",
+		"FFM_CONSOLE_DEFAULT_HOTBAR_SLOT":   "3",
+		"FFM_TIMEOUTS_COMMAND_REQUEST":      "1m30s",
+		"FFM_HTTP_AUTH_ENABLED":             "true",
+		"FFM_HTTP_AUTH_ADMIN_TOKEN":         "0123456789abcdef",
+		"FFM_HTTP_AUTH_MAX_CLOCK_SKEW":      "30s",
+		"FFM_JOB_QUEUE_SIZE":                "8",
+		// 列表和映射只能在配置文件中设置
+		"FFM_EXTRA_BOTS":             "ignored",
+		"FFM_HTTP_AUTH_CREDENTIALS":  "ignored",
+		"FFM_HTTP_AUTH_ROUTE_ROLES":  "ignored",
+		"FFM_CONNECTION_UNKNOWN_KEY": "ignored",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Connection.RentalServerCode != "654321" ||
+		cfg.Console.CenterX != -12 ||
+		cfg.Console.DefaultHotbarSlot != 3 ||
+		cfg.Timeouts.CommandRequest != 90*time.Second ||
+		!cfg.HTTP.Auth.Enabled ||
+		cfg.HTTP.Auth.AdminToken != "0123456789abcdef" ||
+		cfg.HTTP.Auth.MaxClockSkew != 30*time.Second ||
+		cfg.JobQueue.Size != 8 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.ExtraBots != nil || cfg.HTTP.Auth.Credentials != nil || cfg.HTTP.Auth.RouteRoles != nil {
+		t.Fatal("lists and maps should not be set by environment variables")
+	}
+	// 未被覆盖的配置项保持不变
+	if cfg.Timeouts.ContainerOpen != DefaultConfig().Timeouts.ContainerOpen {
+		t.Fatalf("unexpected timeout %v", cfg.Timeouts.ContainerOpen)
+	}
+	if err = cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyEnvErrors(t *testing.T) {
+	cfg := testConfig()
+	err := ApplyEnv(&cfg, testEnv(map[string]string{
+		"FFM_TIMEOUTS_BLOCK_PICK":     "5",
+		"FFM_RETRIES_BLOCK_PICK":      "three",
+		"FFM_CONSOLE_CENTER_Y":        "2147483648",
+		"FFM_HTTP_AUTH_ENABLED":       "yes please",
+		"FFM_CONSOLE_BASE_BACKGROUND": "minecraft:stone",
+	}))
+	if err == nil {
+		t.Fatal("ApplyEnv should fail")
+	}
+
+	// 所有不合法的环境变量都被一并报告
+	for _, want := range []string{
+		`FFM_TIMEOUTS_BLOCK_PICK: "5" is not a valid duration`,
+		`FFM_RETRIES_BLOCK_PICK: "three" is not a valid 64-bit integer`,
+		`FFM_CONSOLE_CENTER_Y: "2147483648" is not a valid 32-bit integer`,
+		`FFM_HTTP_AUTH_ENABLED: "yes please" is not a valid boolean`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not contain %q", err, want)
+		}
+	}
+	if cfg.Console.BaseBackground != "minecraft:stone" {
+		t.Fatal("valid environment variables should still be applied")
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg := testConfig()
+	err := LoadConfigFile(&cfg, write("nested.yaml", `
+console:
+  center_z: 64
+timeouts:
+  init_console: 1m
+http:
+  auth:
+    credentials:
+      - name: builder
+        role: user
+        token: change-me-to-a-long-token
+    route_roles:
+      "GET /metrics": public
+extra_bots:
+  - auth_server_token: another-account-token
+    center_x: 32
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Console.CenterZ != 64 || cfg.Timeouts.InitConsole != time.Minute {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	// 配置文件中未出现的配置项保持不变
+	if cfg.Connection.RentalServerCode != "123456" || cfg.Timeouts.CommandRequest != DefaultConfig().Timeouts.CommandRequest {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if len(cfg.HTTP.Auth.Credentials) != 1 || cfg.HTTP.Auth.RouteRoles["GET /metrics"] != RolePublic || len(cfg.ExtraBots) != 1 {
+		t.Fatalf("unexpected auth %+v and bots %+v", cfg.HTTP.Auth, cfg.ExtraBots)
+	}
+	if err = cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = LoadConfigFile(&cfg, write("empty.yaml", "")); err != nil {
+		t.Fatalf("empty file should be accepted: %v", err)
+	}
+	if err = LoadConfigFile(&cfg, write("unknown.yaml", "console:\n  center_w: 1\n")); err == nil {
+		t.Fatal("unknown fields should be rejected")
+	}
+	if err = LoadConfigFile(&cfg, write("duration.yaml", "timeouts:\n  block_pick: soon\n")); err == nil {
+		t.Fatal("invalid durations should be rejected")
+	}
+	if err = LoadConfigFile(&cfg, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Fatal("missing file should be rejected")
+	}
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := LoadConfigFile(&cfg, "config.example.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	badFile := filepath.Join(dir, "bad.pem")
+	if err := os.WriteFile(badFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	credential := func(name string) Credential {
+		return Credential{Name: name, Role: RoleUser, Token: "0123456789abcdef"}
+	}
+	extraBot := BotConfig{AuthServerToken: "another-account-token", CenterX: 32}
+
+	testCases := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   string
+	}{
+		{"rental server code", func(cfg *Config) { cfg.Connection.RentalServerCode = "" },
+			"connection.rental_server_code (env FFM_CONNECTION_RENTAL_SERVER_CODE): must not be empty"},
+		{"auth server address", func(cfg *Config) { cfg.Connection.AuthServerAddress = "" },
+			"connection.auth_server_address (env FFM_CONNECTION_AUTH_SERVER_ADDRESS): must not be empty"},
+		{"auth server scheme", func(cfg *Config) { cfg.Connection.AuthServerAddress = "127.0.0.1" },
+			"must start with http:// or https://"},
+		{"base background", func(cfg *Config) { cfg.Console.BaseBackground = "sea_lantern" },
+			"console.base_background (env FFM_CONSOLE_BASE_BACKGROUND)"},
+		{"hotbar slot", func(cfg *Config) { cfg.Console.DefaultHotbarSlot = 9 },
+			"console.default_hotbar_slot (env FFM_CONSOLE_DEFAULT_HOTBAR_SLOT): 9 is out of range [0, 8]"},
+		{"timeout", func(cfg *Config) { cfg.Timeouts.ContainerOpen = 0 },
+			"timeouts.container_open (env FFM_TIMEOUTS_CONTAINER_OPEN): 0s must be positive"},
+		{"place retries", func(cfg *Config) { cfg.Retries.PlaceNBTBlock = 255 },
+			"retries.place_nbt_block (env FFM_RETRIES_PLACE_NBT_BLOCK): 255 is out of range [0, 254]"},
+		{"container open retries", func(cfg *Config) { cfg.Retries.ContainerOpen = 0 },
+			"retries.container_open (env FFM_RETRIES_CONTAINER_OPEN): 0 must be at least 1"},
+		{"block pick retries", func(cfg *Config) { cfg.Retries.BlockPick = 0 },
+			"retries.block_pick (env FFM_RETRIES_BLOCK_PICK): 0 must be at least 1"},
+		{"listen address", func(cfg *Config) { cfg.HTTP.ListenAddress = "" },
+			"http.listen_address (env FFM_HTTP_LISTEN_ADDRESS): must not be empty"},
+		{"listen address format", func(cfg *Config) { cfg.HTTP.ListenAddress = "8080" },
+			"is not a valid address"},
+		{"listen port", func(cfg *Config) { cfg.HTTP.ListenAddress = ":0" },
+			"has an invalid port"},
+		{"tls pair", func(cfg *Config) { cfg.HTTP.TLSCertFile = badFile },
+			"tls_cert_file and tls_key_file must be set together"},
+		{"tls files", func(cfg *Config) { cfg.HTTP.TLSCertFile, cfg.HTTP.TLSKeyFile = badFile, badFile },
+			"failed to load the certificate and key"},
+		{"grpc address", func(cfg *Config) { cfg.GRPC.ListenAddress = "9090" },
+			"grpc.listen_address (env FFM_GRPC_LISTEN_ADDRESS)"},
+		{"grpc port", func(cfg *Config) { cfg.GRPC.ListenAddress = ":65536" },
+			"has an invalid port"},
+		{"grpc same address", func(cfg *Config) { cfg.GRPC.ListenAddress = ":8080" },
+			"is already used by http.listen_address"},
+		{"job queue", func(cfg *Config) { cfg.JobQueue.Size = 0 },
+			"job_queue.size (env FFM_JOB_QUEUE_SIZE): 0 must be at least 1"},
+		{"bot token", func(cfg *Config) { cfg.ExtraBots = []BotConfig{{CenterX: 32}} },
+			"extra_bots[0].auth_server_token: must not be empty"},
+		{"bot address", func(cfg *Config) {
+			bot := extraBot
+			bot.AuthServerAddress = "127.0.0.1"
+			cfg.ExtraBots = []BotConfig{bot}
+		}, "extra_bots[0].auth_server_address"},
+		{"bot name", func(cfg *Config) {
+			bot := extraBot
+			bot.Name = "bot-0"
+			cfg.ExtraBots = []BotConfig{bot}
+		}, `extra_bots[0].name: "bot-0" is used by connection`},
+		{"bot account", func(cfg *Config) {
+			cfg.ExtraBots = []BotConfig{extraBot, {AuthServerToken: extraBot.AuthServerToken, CenterX: 64}}
+		}, "extra_bots[1].auth_server_token: is the same as the one of extra_bots[0]"},
+		{"bot console", func(cfg *Config) {
+			bot := extraBot
+			bot.CenterX = 10
+			cfg.ExtraBots = []BotConfig{bot}
+		}, "extra_bots[0].center_x: the console at (10, 0, 0) overlaps the one of connection"},
+		{"admin token", func(cfg *Config) { cfg.HTTP.Auth.AdminToken = "short" },
+			"http.auth.admin_token (env FFM_HTTP_AUTH_ADMIN_TOKEN): must be at least 16 characters"},
+		{"auth without credentials", func(cfg *Config) { cfg.HTTP.Auth.Enabled = true },
+			"http.auth.credentials: at least one credential or admin_token is required"},
+		{"clock skew", func(cfg *Config) { cfg.HTTP.Auth.MaxClockSkew = 0 },
+			"http.auth.max_clock_skew: 0s must be positive"},
+		{"credential name", func(cfg *Config) { cfg.HTTP.Auth.Credentials = []Credential{credential("")} },
+			"http.auth.credentials[0].name: must not be empty"},
+		{"credential duplicate", func(cfg *Config) {
+			cfg.HTTP.Auth.Credentials = []Credential{credential("a"), credential("a")}
+		}, `http.auth.credentials[1].name: "a" is used by another credential`},
+		{"credential colon", func(cfg *Config) { cfg.HTTP.Auth.Credentials = []Credential{credential("a:b")} },
+			`http.auth.credentials[0].name: "a:b" must not contain ':'`},
+		{"credential role", func(cfg *Config) {
+			c := credential("a")
+			c.Role = RolePublic
+			cfg.HTTP.Auth.Credentials = []Credential{c}
+		}, `http.auth.credentials[0].role: "public" must be "user" or "admin"`},
+		{"credential secret", func(cfg *Config) {
+			cfg.HTTP.Auth.Credentials = []Credential{{Name: "a", Role: RoleUser}}
+		}, "http.auth.credentials[0]: token or hmac_secret is required"},
+		{"credential token", func(cfg *Config) {
+			c := credential("a")
+			c.Token = "short"
+			cfg.HTTP.Auth.Credentials = []Credential{c}
+		}, "http.auth.credentials[0].token: must be at least 16 characters"},
+		{"credential hmac secret", func(cfg *Config) {
+			c := credential("a")
+			c.HMACSecret = "short"
+			cfg.HTTP.Auth.Credentials = []Credential{c}
+		}, "http.auth.credentials[0].hmac_secret: must be at least 16 characters"},
+		{"route key", func(cfg *Config) { cfg.HTTP.Auth.RouteRoles = map[string]string{"GET /unknown": RoleUser} },
+			`http.auth.route_roles["GET /unknown"]: unknown route`},
+		{"route role", func(cfg *Config) { cfg.HTTP.Auth.RouteRoles = map[string]string{"GET /process_exit": "root"} },
+			`http.auth.route_roles["GET /process_exit"]: "root" must be "public", "user" or "admin"`},
+	}
+
+	if err := testConfig().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, testCase := range testCases {
+		cfg := testConfig()
+		testCase.modify(&cfg)
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatalf("%s: Validate should fail", testCase.name)
+		}
+		if !strings.Contains(err.Error(), testCase.want) {
+			t.Fatalf("%s: error %q does not contain %q", testCase.name, err, testCase.want)
+		}
+	}
+}
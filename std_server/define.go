@@ -3,8 +3,18 @@ package main
 const (
 	ResponseErrorTypeParseError = iota
 	ResponseErrorTypeRuntimeError
+	ResponseErrorTypeReconnecting
+	ResponseErrorTypeUnauthorized
+	ResponseErrorTypeForbidden
+	ResponseErrorTypeCancelled
 )
 
+type ErrorResponse struct {
+	Success   bool   `json:"success"`
+	ErrorType int    `json:"error_type"`
+	ErrorInfo string `json:"error_info"`
+}
+
 type PlaceNBTBlockRequest struct {
 	BlockName            string `json:"block_name"`
 	BlockStatesString    string `json:"block_states_string"`
@@ -20,7 +30,149 @@ type PlaceNBTBlockResponse struct {
 	StructureUniqueID string `json:"structure_unique_id"`
 	StructureName     string `json:"structure_name"`
 
+	// PlacedByNeighbor 指示此方块总是随其相邻的方块一同被制作，
+	// 例如床尾总是随床头一同被制作。此时请求者不应放置此方块，
+	// 否则它将覆盖已放置的相邻方块
+	PlacedByNeighbor bool `json:"placed_by_neighbor"`
+
+	// OffsetX, OffsetY 和 OffsetZ 是相邻的可能的方块相对于此方块的偏移，
+	// 例如床的尾方块相对于头方块的偏移。
+	//
+	// 结构的原点是此方块与相邻方块在各轴上的较小者，例如床的
+	// 头方块与尾方块中坐标较小的一方。因此，若要使此方块位于
+	// pos 处，则应当在 pos + min(offset, 0) (逐轴计算) 处加载结构
 	OffsetX int32 `json:"offset_x"`
 	OffsetY int32 `json:"offset_y"`
 	OffsetZ int32 `json:"offset_z"`
+
+	// DroppedFields 是方块实体数据或方块状态中没有被还原的字段的名称，
+	// 例如刷怪蛋不存在时刷怪笼的 EntityIdentifier 和合成器的 triggered_bit
+	DroppedFields []string `json:"dropped_fields"`
+	// DroppedItems 和 LossyItems 分别是方块所装有的，
+	// 没有被还原的物品和只被近似还原的物品
+	DroppedItems []DroppedItem `json:"dropped_items"`
+	LossyItems   []DroppedItem `json:"lossy_items"`
+}
+
+type SubmitJobRequest struct {
+	Blocks []PlaceNBTBlockRequest `json:"blocks"`
+}
+
+type SubmitJobResponse struct {
+	Success   bool   `json:"success"`
+	ErrorType int    `json:"error_type"`
+	ErrorInfo string `json:"error_info"`
+
+	JobID         string `json:"job_id"`
+	QueueDepth    int    `json:"queue_depth"`
+	QueueCapacity int    `json:"queue_capacity"`
+}
+
+type JobStatusResponse struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+
+	Done  int `json:"done"`
+	Total int `json:"total"`
+
+	QueueDepth    int `json:"queue_depth"`
+	QueueCapacity int `json:"queue_capacity"`
+
+	// Error 描述任务为何失败或被取消，
+	// 它在任务成功时为空
+	Error   string                  `json:"error,omitempty"`
+	Results []PlaceNBTBlockResponse `json:"results"`
+}
+
+type RegisterFilledMapsRequest struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+	Z int32 `json:"z"`
+}
+
+type RegisterFilledMapsResponse struct {
+	Success   bool   `json:"success"`
+	ErrorType int    `json:"error_type"`
+	ErrorInfo string `json:"error_info"`
+
+	MapUUIDs []int64 `json:"map_uuids"`
+}
+
+// ExportStructureRequest 描述需要导出的区域。
+// 该区域必须处于机器人的视距之内
+type ExportStructureRequest struct {
+	StartX    int32 `json:"start_x"`
+	StartY    int32 `json:"start_y"`
+	StartZ    int32 `json:"start_z"`
+	EndX      int32 `json:"end_x"`
+	EndY      int32 `json:"end_y"`
+	EndZ      int32 `json:"end_z"`
+	Dimension int32 `json:"dimension"`
+}
+
+type ExportStructureResponse struct {
+	Success   bool   `json:"success"`
+	ErrorType int    `json:"error_type"`
+	ErrorInfo string `json:"error_info"`
+
+	// StructureBase64String 是以 Base64 编码的 .mcstructure 文件
+	StructureBase64String string `json:"structure_base64_string"`
+}
+
+type DroppedItem struct {
+	Path     []int  `json:"path"`
+	ItemName string `json:"item_name"`
+	Reason   string `json:"reason"`
+}
+
+type ValidateNBTBlockResponse struct {
+	Success   bool   `json:"success"`
+	ErrorType int    `json:"error_type"`
+	ErrorInfo string `json:"error_info"`
+
+	BlockName         string `json:"block_name"`
+	BlockStatesString string `json:"block_states_string"`
+
+	NeedSpecialHandle   bool   `json:"need_special_handle"`
+	PlacedByNeighbor    bool   `json:"placed_by_neighbor"`
+	NeedCheckCompletely bool   `json:"need_check_completely"`
+	IsSupported         bool   `json:"is_supported"`
+	Format              string `json:"format"`
+
+	DroppedItems  []DroppedItem `json:"dropped_items"`
+	LossyItems    []DroppedItem `json:"lossy_items"`
+	DroppedFields []string      `json:"dropped_fields"`
+}
+
+type CacheEntryResponse struct {
+	Kind       string `json:"kind"`
+	HashNumber uint64 `json:"hash_number,string"`
+	BlockName  string `json:"block_name"`
+
+	StructureUniqueID string `json:"structure_unique_id"`
+	StructureName     string `json:"structure_name"`
+
+	CreatedAt int64 `json:"created_at"`
+	LastHitAt int64 `json:"last_hit_at"`
+	HitCount  int   `json:"hit_count"`
+}
+
+type ListCacheResponse struct {
+	Entries []CacheEntryResponse `json:"entries"`
+}
+
+type EvictCacheOlderThanRequest struct {
+	MaxAgeSeconds int64 `json:"max_age_seconds"`
+}
+
+type EvictCacheLRURequest struct {
+	Keep int `json:"keep"`
+}
+
+type EvictCacheResponse struct {
+	Success   bool   `json:"success"`
+	ErrorType int    `json:"error_type"`
+	ErrorInfo string `json:"error_info"`
+
+	Evicted int `json:"evicted"`
 }
@@ -1,36 +1,141 @@
 package main
 
 import (
+	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mcstructure"
+	"github.com/OmineDev/flowers-for-machines/metrics"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
 	"github.com/OmineDev/flowers-for-machines/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
 )
 
 func CheckAlive(c *gin.Context) {
 	c.Writer.WriteString("Still Alive")
 }
 
+func Metrics(c *gin.Context) {
+	c.Header("Content-Type", metrics.ContentType)
+	_ = metrics.DefaultRegistry.WriteText(c.Writer)
+}
+
 func ProcessExist(c *gin.Context) {
-	_, _ = gameInterface.Commands().SendWSCommandWithResp("deop @s")
-	_ = mcClient.Conn().Close()
+	for _, bot := range pool.Bots() {
+		if session, err := bot.supervisor.Session(); err == nil {
+			_, _ = session.GameInterface.Commands().SendWSCommandWithResp("deop @s")
+			_ = session.Client.Conn().Close()
+		}
+	}
 	go func() {
 		time.Sleep(time.Second)
+		// 在退出前写入尚未持久化的缓存
+		if err := cache.Sync(); err != nil {
+			pterm.Warning.Printfln("无法将缓存写入持久化文件; err = %v", err)
+		}
 		os.Exit(0)
 	}()
 }
 
+// decodeBlockNBT 将 request 中以 Base64 编码的小端序
+// NBT 解码为方块实体数据。如果解码失败，则 success 为假，
+// 且 failedResponse 是应当返回给请求者的响应体
+func decodeBlockNBT(request PlaceNBTBlockRequest) (
+	blockNBT map[string]any,
+	success bool,
+	failedResponse PlaceNBTBlockResponse,
+) {
+	blockNBTBytes, err := base64.StdEncoding.DecodeString(request.BlockNBTBase64String)
+	if err != nil {
+		return nil, false, PlaceNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse block NBT base64 string; err = %v", err),
+		}
+	}
+	err = nbt.UnmarshalEncoding(blockNBTBytes, &blockNBT, nbt.LittleEndian)
+	if err != nil {
+		return nil, false, PlaceNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Block NBT bytes is broken; err = %v", err),
+		}
+	}
+	return blockNBT, true, PlaceNBTBlockResponse{}
+}
+
+// makeDroppedItems 将没有被还原或只被近似还原的物品 items 包装为响应体。
+// 槽位路径被转换为 []int，以避免其在 JSON 中被编码为 Base64 字符串
+func makeDroppedItems(items []nbt_assigner.DroppedItem) []DroppedItem {
+	result := make([]DroppedItem, 0, len(items))
+	for _, item := range items {
+		path := make([]int, 0, len(item.Path))
+		for _, slot := range item.Path {
+			path = append(path, int(slot))
+		}
+		result = append(result, DroppedItem{
+			Path:     path,
+			ItemName: item.ItemName,
+			Reason:   item.Reason,
+		})
+	}
+	return result
+}
+
+// makePlaceNBTBlockResponse 将 NBT 方块的放置结果 result 包装为响应体
+func makePlaceNBTBlockResponse(result nbt_assigner.PlaceNBTBlockResult) PlaceNBTBlockResponse {
+	if errors.Is(result.Err, ErrReconnecting) {
+		return PlaceNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to place NBT block; err = %v", result.Err),
+		}
+	}
+	if errors.Is(result.Err, nbt_assigner.ErrParseNBTBlock) {
+		return PlaceNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse NBT block; err = %v", result.Err),
+		}
+	}
+	if result.Err != nil {
+		return PlaceNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeRuntimeError,
+			ErrorInfo: fmt.Sprintf("Runtime error: Failed to place NBT block; err = %v", result.Err),
+		}
+	}
+	return PlaceNBTBlockResponse{
+		Success:           true,
+		CanFast:           result.CanFast,
+		StructureUniqueID: result.UniqueID.String(),
+		StructureName:     utils.MakeUUIDSafeString(result.UniqueID),
+		PlacedByNeighbor:  result.PlacedByNeighbor,
+		OffsetX:           result.Offset.X(),
+		OffsetY:           result.Offset.Y(),
+		OffsetZ:           result.Offset.Z(),
+		DroppedFields:     append(make([]string, 0), result.DroppedFields...),
+		DroppedItems:      makeDroppedItems(result.DroppedItems),
+		LossyItems:        makeDroppedItems(result.LossyItems),
+	}
+}
+
 func PlaceNBTBlock(c *gin.Context) {
 	var request PlaceNBTBlockRequest
-	var blockNBT map[string]any
 
-	err := c.BindJSON(&request)
+	err := c.ShouldBindJSON(&request)
 	if err != nil {
 		c.JSON(http.StatusOK, PlaceNBTBlockResponse{
 			Success:   false,
@@ -40,46 +145,466 @@ func PlaceNBTBlock(c *gin.Context) {
 		return
 	}
 
-	blockNBTBytes, err := base64.StdEncoding.DecodeString(request.BlockNBTBase64String)
+	blockNBT, success, failedResponse := decodeBlockNBT(request)
+	if !success {
+		c.JSON(http.StatusOK, failedResponse)
+		return
+	}
+
+	// 客户端断开连接时，任务会在放置前被取消
+	response := cancelledResponse()
+	jobQueue.Run(
+		c.Request.Context(),
+		[]nbt_assigner.NBTBlock{{
+			BlockName:   request.BlockName,
+			BlockStates: utils.ParseBlockStatesString(request.BlockStatesString),
+			BlockNBT:    blockNBT,
+		}},
+		func(_ []int, result nbt_assigner.PlaceNBTBlockResult) {
+			response = makePlaceNBTBlockResponse(result)
+		},
+	)
+	c.JSON(http.StatusOK, response)
+}
+
+func PlaceNBTBlocks(c *gin.Context) {
+	var requests []PlaceNBTBlockRequest
+
+	// 请求体无法解析时，仍然返回数组，
+	// 以便请求者总是以相同的方式处理响应体
+	err := c.ShouldBindJSON(&requests)
 	if err != nil {
-		c.JSON(http.StatusOK, PlaceNBTBlockResponse{
+		c.JSON(http.StatusOK, []PlaceNBTBlockResponse{{
 			Success:   false,
 			ErrorType: ResponseErrorTypeParseError,
-			ErrorInfo: fmt.Sprintf("Failed to parse block NBT base64 string; err = %v", err),
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
+		}})
+		return
+	}
+
+	responses := make([]PlaceNBTBlockResponse, len(requests))
+	blocks := make([]nbt_assigner.NBTBlock, 0)
+	blockIndexes := make([]int, 0)
+
+	for index, request := range requests {
+		blockNBT, success, failedResponse := decodeBlockNBT(request)
+		if !success {
+			responses[index] = failedResponse
+			continue
+		}
+		blocks = append(blocks, nbt_assigner.NBTBlock{
+			BlockName:   request.BlockName,
+			BlockStates: utils.ParseBlockStatesString(request.BlockStatesString),
+			BlockNBT:    blockNBT,
+		})
+		blockIndexes = append(blockIndexes, index)
+	}
+
+	// 整个批次由同一个机器人制作，以便对相同的方块去重
+	for _, index := range blockIndexes {
+		responses[index] = cancelledResponse()
+	}
+	jobQueue.Run(c.Request.Context(), blocks, func(indexes []int, result nbt_assigner.PlaceNBTBlockResult) {
+		for _, index := range indexes {
+			responses[blockIndexes[index]] = makePlaceNBTBlockResponse(result)
+		}
+	})
+	c.JSON(http.StatusOK, responses)
+}
+
+func RegisterFilledMaps(c *gin.Context) {
+	var request RegisterFilledMapsRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusOK, RegisterFilledMapsResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
 		})
 		return
 	}
-	err = nbt.UnmarshalEncoding(blockNBTBytes, &blockNBT, nbt.LittleEndian)
+
+	bot, session, err := pool.Acquire()
 	if err != nil {
-		c.JSON(http.StatusOK, PlaceNBTBlockResponse{
+		c.JSON(http.StatusOK, RegisterFilledMapsResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to register filled maps; err = %v", err),
+		})
+		return
+	}
+	defer pool.Release(bot)
+
+	mapUUIDs, err := bot.assigner.RegisterFilledMaps(protocol.BlockPos{request.X, request.Y, request.Z})
+	if err = bot.supervisor.WrapError(session, err); errors.Is(err, ErrReconnecting) {
+		c.JSON(http.StatusOK, RegisterFilledMapsResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to register filled maps; err = %v", err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusOK, RegisterFilledMapsResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeRuntimeError,
+			ErrorInfo: fmt.Sprintf("Runtime error: Failed to register filled maps; err = %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RegisterFilledMapsResponse{
+		Success:  true,
+		MapUUIDs: mapUUIDs,
+	})
+}
+
+func ExportStructure(c *gin.Context) {
+	var request ExportStructureRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusOK, ExportStructureResponse{
 			Success:   false,
 			ErrorType: ResponseErrorTypeParseError,
-			ErrorInfo: fmt.Sprintf("Block NBT bytes is broken; err = %v", err),
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
+		})
+		return
+	}
+
+	bot, session, err := pool.Acquire()
+	if err != nil {
+		c.JSON(http.StatusOK, ExportStructureResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to export structure; err = %v", err),
+		})
+		return
+	}
+	defer pool.Release(bot)
+
+	structure, err := mcstructure.NewExporter(session.GameInterface).Export(
+		protocol.BlockPos{request.StartX, request.StartY, request.StartZ},
+		protocol.BlockPos{request.EndX, request.EndY, request.EndZ},
+		mcstructure.ExportOptions{Dimension: request.Dimension},
+	)
+	if err = bot.supervisor.WrapError(session, err); errors.Is(err, ErrReconnecting) {
+		c.JSON(http.StatusOK, ExportStructureResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to export structure; err = %v", err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusOK, ExportStructureResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeRuntimeError,
+			ErrorInfo: fmt.Sprintf("Runtime error: Failed to export structure; err = %v", err),
+		})
+		return
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = mcstructure.Encode(buf, structure)
+	if err != nil {
+		c.JSON(http.StatusOK, ExportStructureResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeRuntimeError,
+			ErrorInfo: fmt.Sprintf("Runtime error: Failed to encode structure; err = %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExportStructureResponse{
+		Success:               true,
+		StructureBase64String: base64.StdEncoding.EncodeToString(buf.Bytes()),
+	})
+}
+
+func ValidateNBTBlock(c *gin.Context) {
+	var request PlaceNBTBlockRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusOK, ValidateNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
+		})
+		return
+	}
+
+	blockNBT, success, failedResponse := decodeBlockNBT(request)
+	if !success {
+		c.JSON(http.StatusOK, ValidateNBTBlockResponse{
+			Success:   false,
+			ErrorType: failedResponse.ErrorType,
+			ErrorInfo: failedResponse.ErrorInfo,
+		})
+		return
+	}
+
+	// 物品名称检查依赖于服务器下发的常量数据包，
+	// 因此校验同样需要一个可用的会话
+	bot, _, err := pool.Acquire()
+	if err != nil {
+		c.JSON(http.StatusOK, ValidateNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to validate NBT block; err = %v", err),
 		})
 		return
 	}
+	defer pool.Release(bot)
 
-	canFast, uniqueID, offset, err := wrapper.PlaceNBTBlock(
+	result, err := bot.assigner.ValidateNBTBlock(
 		request.BlockName,
 		utils.ParseBlockStatesString(request.BlockStatesString),
 		blockNBT,
 	)
 	if err != nil {
-		c.JSON(http.StatusOK, PlaceNBTBlockResponse{
+		c.JSON(http.StatusOK, ValidateNBTBlockResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to validate NBT block; err = %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ValidateNBTBlockResponse{
+		Success:             true,
+		BlockName:           result.BlockName,
+		BlockStatesString:   result.BlockStatesString,
+		NeedSpecialHandle:   result.NeedSpecialHandle,
+		PlacedByNeighbor:    result.PlacedByNeighbor,
+		NeedCheckCompletely: result.NeedCheckCompletely,
+		IsSupported:         result.IsSupported,
+		Format:              result.Format,
+		DroppedItems:        makeDroppedItems(result.DroppedItems),
+		LossyItems:          makeDroppedItems(result.LossyItems),
+		DroppedFields:       append(make([]string, 0), result.DroppedFields...),
+	})
+}
+
+func SubmitJob(c *gin.Context) {
+	var request SubmitJobRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, SubmitJobResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
+		})
+		return
+	}
+
+	job, err := jobQueue.Submit(request.Blocks)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, SubmitJobResponse{
+			Success:       false,
+			ErrorType:     ResponseErrorTypeRuntimeError,
+			ErrorInfo:     fmt.Sprintf("Runtime error: Failed to submit job; err = %v", err),
+			QueueDepth:    jobQueue.Depth(),
+			QueueCapacity: jobQueue.Capacity(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SubmitJobResponse{
+		Success:       true,
+		JobID:         job.id.String(),
+		QueueDepth:    jobQueue.Depth(),
+		QueueCapacity: jobQueue.Capacity(),
+	})
+}
+
+func GetJobQueue(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"queue_depth":    jobQueue.Depth(),
+		"queue_capacity": jobQueue.Capacity(),
+	})
+}
+
+func GetJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	response, found := jobQueue.Status(id)
+	if !found {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func CancelJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	found, cancelled := jobQueue.Cancel(id)
+	if !found {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if !cancelled {
+		c.AbortWithStatus(http.StatusConflict)
+		return
+	}
+
+	response, _ := jobQueue.Status(id)
+	c.JSON(http.StatusOK, response)
+}
+
+// makeEvictCacheResponse 将驱逐缓存的结果包装为响应体。
+// 即便 err 不为空，已被驱逐的缓存的数量 evicted 也会被返回
+func makeEvictCacheResponse(evicted int, err error) EvictCacheResponse {
+	if errors.Is(err, ErrReconnecting) {
+		return EvictCacheResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeReconnecting,
+			ErrorInfo: fmt.Sprintf("Reconnecting: Failed to evict cache; err = %v", err),
+			Evicted:   evicted,
+		}
+	}
+	if err != nil {
+		return EvictCacheResponse{
 			Success:   false,
 			ErrorType: ResponseErrorTypeRuntimeError,
-			ErrorInfo: fmt.Sprintf("Runtime error: Failed to place NBT block; err = %v", err),
+			ErrorInfo: fmt.Sprintf("Runtime error: Failed to evict cache; err = %v", err),
+			Evicted:   evicted,
+		}
+	}
+	return EvictCacheResponse{
+		Success: true,
+		Evicted: evicted,
+	}
+}
+
+// evictCache 占用一个空闲的机器人以执行驱逐操作 evict，
+// 并将其结果写入 c。缓存由全部机器人共享，因此驱逐对
+// 全部机器人生效，并且它会等待其他机器人完成正在进行的制作
+func evictCache(c *gin.Context, evict func(assigner *nbt_assigner.NBTAssigner) (evicted int, err error)) {
+	bot, session, err := pool.Acquire()
+	if err != nil {
+		c.JSON(http.StatusOK, makeEvictCacheResponse(0, err))
+		return
+	}
+	defer pool.Release(bot)
+
+	evicted, err := evict(bot.assigner)
+	c.JSON(http.StatusOK, makeEvictCacheResponse(evicted, bot.supervisor.WrapError(session, err)))
+}
+
+func ListCache(c *gin.Context) {
+	entries := cache.ListCache()
+	response := ListCacheResponse{
+		Entries: make([]CacheEntryResponse, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		var lastHitAt int64
+		if !entry.LastHitAt.IsZero() {
+			lastHitAt = entry.LastHitAt.Unix()
+		}
+		response.Entries = append(response.Entries, CacheEntryResponse{
+			Kind:              entry.Kind,
+			HashNumber:        entry.HashNumber,
+			BlockName:         entry.BlockName,
+			StructureUniqueID: entry.UniqueID.String(),
+			StructureName:     utils.MakeUUIDSafeString(entry.UniqueID),
+			CreatedAt:         entry.CreatedAt.Unix(),
+			LastHitAt:         lastHitAt,
+			HitCount:          entry.HitCount,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func EvictCache(c *gin.Context) {
+	hashNumber, err := strconv.ParseUint(c.Param("hash"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusOK, EvictCacheResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse hash number; err = %v", err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, PlaceNBTBlockResponse{
-		Success:           true,
-		CanFast:           canFast,
-		StructureUniqueID: uniqueID.String(),
-		StructureName:     utils.MakeUUIDSafeString(uniqueID),
-		OffsetX:           offset.X(),
-		OffsetY:           offset.Y(),
-		OffsetZ:           offset.Z(),
+	kind := c.Param("kind")
+	if kind != nbt_cache.CacheKindBaseContainer && kind != nbt_cache.CacheKindNBTBlock {
+		c.JSON(http.StatusOK, EvictCacheResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Unknown cache kind %#v", kind),
+		})
+		return
+	}
+
+	evictCache(c, func(assigner *nbt_assigner.NBTAssigner) (int, error) {
+		evicted, err := assigner.EvictCache(kind, hashNumber)
+		if evicted {
+			return 1, err
+		}
+		return 0, err
 	})
 }
+
+func EvictCacheOlderThan(c *gin.Context) {
+	var request EvictCacheOlderThanRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusOK, EvictCacheResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
+		})
+		return
+	}
+
+	if request.MaxAgeSeconds < 0 {
+		c.JSON(http.StatusOK, EvictCacheResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Max age must not be negative; request.MaxAgeSeconds = %d", request.MaxAgeSeconds),
+		})
+		return
+	}
+
+	evictCache(c, func(assigner *nbt_assigner.NBTAssigner) (int, error) {
+		return assigner.EvictCacheOlderThan(time.Duration(request.MaxAgeSeconds) * time.Second)
+	})
+}
+
+func EvictCacheLRU(c *gin.Context) {
+	var request EvictCacheLRURequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusOK, EvictCacheResponse{
+			Success:   false,
+			ErrorType: ResponseErrorTypeParseError,
+			ErrorInfo: fmt.Sprintf("Failed to parse request; err = %v", err),
+		})
+		return
+	}
+
+	evictCache(c, func(assigner *nbt_assigner.NBTAssigner) (int, error) {
+		return assigner.EvictCacheLRU(request.Keep)
+	})
+}
+
+func PurgeCache(c *gin.Context) {
+	evictCache(c, (*nbt_assigner.NBTAssigner).PurgeCache)
+}
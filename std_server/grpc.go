@@ -0,0 +1,314 @@
+package main
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pb/std_server.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/std_server/pb"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/pterm/pterm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HealthCheckInterval 是检查机器人在线状态以更新健康检查结果的间隔
+const HealthCheckInterval = time.Second
+
+// grpcServer 实现了 pb.NBTBlockServiceServer
+type grpcServer struct {
+	pb.UnimplementedNBTBlockServiceServer
+}
+
+// decodeGRPCBlock 将 block 解码为 NBT 方块。如果解码失败，
+// 则 success 为假，且 failedResult 是应当返回给请求者的结果
+func decodeGRPCBlock(block *pb.NBTBlock) (
+	result nbt_assigner.NBTBlock,
+	success bool,
+	failedResult *pb.PlaceNBTBlockResult,
+) {
+	var blockNBT map[string]any
+
+	err := nbt.UnmarshalEncoding(block.GetBlockNbt(), &blockNBT, nbt.LittleEndian)
+	if err != nil {
+		return nbt_assigner.NBTBlock{}, false, &pb.PlaceNBTBlockResult{
+			Error: &pb.Error{
+				Code:    pb.ErrorCode_ERROR_CODE_INVALID_NBT,
+				Message: fmt.Sprintf("Block NBT bytes is broken; err = %v", err),
+			},
+		}
+	}
+
+	return nbt_assigner.NBTBlock{
+		BlockName:   block.GetBlockName(),
+		BlockStates: utils.ParseBlockStatesString(block.GetBlockStatesString()),
+		BlockNBT:    blockNBT,
+	}, true, nil
+}
+
+// makeGRPCDroppedItems 将没有被还原或只被近似还原的物品 items 包装为 gRPC 的物品
+func makeGRPCDroppedItems(items []nbt_assigner.DroppedItem) []*pb.DroppedItem {
+	result := make([]*pb.DroppedItem, 0, len(items))
+	for _, item := range items {
+		path := make([]uint32, 0, len(item.Path))
+		for _, slot := range item.Path {
+			path = append(path, uint32(slot))
+		}
+		result = append(result, &pb.DroppedItem{
+			Path:     path,
+			ItemName: item.ItemName,
+			Reason:   item.Reason,
+		})
+	}
+	return result
+}
+
+// makeGRPCResult 将 NBT 方块的放置结果 result 包装为 gRPC 的结果
+func makeGRPCResult(result nbt_assigner.PlaceNBTBlockResult) *pb.PlaceNBTBlockResult {
+	if errors.Is(result.Err, ErrReconnecting) {
+		return &pb.PlaceNBTBlockResult{
+			Error: &pb.Error{
+				Code:    pb.ErrorCode_ERROR_CODE_RECONNECTING,
+				Message: fmt.Sprintf("Reconnecting: Failed to place NBT block; err = %v", result.Err),
+			},
+		}
+	}
+	if errors.Is(result.Err, nbt_assigner.ErrParseNBTBlock) {
+		return &pb.PlaceNBTBlockResult{
+			Error: &pb.Error{
+				Code:    pb.ErrorCode_ERROR_CODE_INVALID_NBT,
+				Message: fmt.Sprintf("Failed to parse NBT block; err = %v", result.Err),
+			},
+		}
+	}
+	if result.Err != nil {
+		return &pb.PlaceNBTBlockResult{
+			Error: &pb.Error{
+				Code:    pb.ErrorCode_ERROR_CODE_RUNTIME_ERROR,
+				Message: fmt.Sprintf("Runtime error: Failed to place NBT block; err = %v", result.Err),
+			},
+		}
+	}
+	return &pb.PlaceNBTBlockResult{
+		CanFast:           result.CanFast,
+		StructureUniqueId: result.UniqueID.String(),
+		StructureName:     utils.MakeUUIDSafeString(result.UniqueID),
+		PlacedByNeighbor:  result.PlacedByNeighbor,
+		OffsetX:           result.Offset.X(),
+		OffsetY:           result.Offset.Y(),
+		OffsetZ:           result.Offset.Z(),
+		DroppedFields:     result.DroppedFields,
+		DroppedItems:      makeGRPCDroppedItems(result.DroppedItems),
+		LossyItems:        makeGRPCDroppedItems(result.LossyItems),
+	}
+}
+
+// PlaceNBTBlock ..
+func (grpcServer) PlaceNBTBlock(ctx context.Context, request *pb.PlaceNBTBlockRequest) (*pb.PlaceNBTBlockResponse, error) {
+	var result nbt_assigner.PlaceNBTBlockResult
+
+	if request.GetBlock() == nil {
+		return nil, status.Error(codes.InvalidArgument, "PlaceNBTBlock: block is required")
+	}
+
+	block, success, failedResult := decodeGRPCBlock(request.GetBlock())
+	if !success {
+		return &pb.PlaceNBTBlockResponse{Result: failedResult}, nil
+	}
+
+	// 客户端取消请求时，任务会在放置前被取消
+	placed := false
+	jobQueue.Run(ctx, []nbt_assigner.NBTBlock{block}, func(_ []int, value nbt_assigner.PlaceNBTBlockResult) {
+		result, placed = value, true
+	})
+	if !placed {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return &pb.PlaceNBTBlockResponse{Result: makeGRPCResult(result)}, nil
+}
+
+// PlaceNBTBlocks ..
+func (grpcServer) PlaceNBTBlocks(request *pb.PlaceNBTBlocksRequest, stream grpc.ServerStreamingServer[pb.PlaceNBTBlocksProgress]) error {
+	total := uint32(len(request.GetBlocks()))
+	blocks := make([]nbt_assigner.NBTBlock, 0)
+	blockIndexes := make([]uint32, 0)
+
+	// 每个方块至多产生一条进度，因此向 progress 发送永远不会阻塞。
+	// 这避免了在持有 NBTAssigner 的锁时等待缓慢的客户端
+	progress := make(chan *pb.PlaceNBTBlocksProgress, len(request.GetBlocks()))
+	done := uint32(0)
+	report := func(indexes []uint32, result *pb.PlaceNBTBlockResult) {
+		done += uint32(len(indexes))
+		progress <- &pb.PlaceNBTBlocksProgress{
+			Indexes: indexes,
+			Result:  result,
+			Done:    done,
+			Total:   total,
+		}
+	}
+
+	sendErr := make(chan error, 1)
+	go func() {
+		var err error
+		for value := range progress {
+			if err == nil {
+				err = stream.Send(value)
+			}
+		}
+		sendErr <- err
+	}()
+
+	for index, value := range request.GetBlocks() {
+		block, success, failedResult := decodeGRPCBlock(value)
+		if !success {
+			report([]uint32{uint32(index)}, failedResult)
+			continue
+		}
+		blocks = append(blocks, block)
+		blockIndexes = append(blockIndexes, uint32(index))
+	}
+
+	if len(blocks) > 0 {
+		// 整个批次由同一个机器人制作，以便对相同的方块去重。
+		// 客户端断开连接时，制作将在当前的 NBT 方块完成后停止
+		jobQueue.Run(stream.Context(), blocks, func(indexes []int, result nbt_assigner.PlaceNBTBlockResult) {
+			requestIndexes := make([]uint32, len(indexes))
+			for i, index := range indexes {
+				requestIndexes[i] = blockIndexes[index]
+			}
+			report(requestIndexes, makeGRPCResult(result))
+		})
+	}
+
+	close(progress)
+	if err := <-sendErr; err != nil {
+		return fmt.Errorf("PlaceNBTBlocks: %v", err)
+	}
+	return nil
+}
+
+// grpcAuthenticate 按 HTTP API 的身份验证配置验证 gRPC 请求的凭据。
+//
+// gRPC 只支持 Bearer 令牌，它通过 authorization 元数据给出。
+// 健康检查总是无需身份验证，而其他方法需要任意有效的凭据
+func grpcAuthenticate(ctx context.Context, fullMethod string) error {
+	auth := config.HTTP.Auth
+	if !auth.Enabled || strings.HasPrefix(fullMethod, "/"+grpc_health_v1.Health_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "grpcAuthenticate: authorization metadata is missing")
+	}
+
+	scheme, token, _ := strings.Cut(values[0], " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return status.Errorf(codes.Unauthenticated, "grpcAuthenticate: Unsupported authorization scheme %#v; only Bearer is supported", scheme)
+	}
+	if _, err := authenticateBearer(strings.TrimSpace(token), auth.credentials()); err != nil {
+		return status.Errorf(codes.Unauthenticated, "grpcAuthenticate: %v", err)
+	}
+
+	return nil
+}
+
+// watchHealth 按机器人的在线状态持续更新 server 中的服务状态。
+// 只要有任意一个机器人在线，服务就是可用的
+func watchHealth(server *health.Server) {
+	ticker := time.NewTicker(HealthCheckInterval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		if pool.Online() > 0 {
+			status = grpc_health_v1.HealthCheckResponse_SERVING
+		}
+		server.SetServingStatus("", status)
+		server.SetServingStatus(pb.NBTBlockService_ServiceDesc.ServiceName, status)
+	}
+}
+
+// NewGRPCServer 创建提供 NBTBlockService 和健康检查的 gRPC 服务器
+func NewGRPCServer() (*grpc.Server, error) {
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(
+			ctx context.Context,
+			req any,
+			info *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler,
+		) (any, error) {
+			if err := grpcAuthenticate(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(
+			srv any,
+			ss grpc.ServerStream,
+			info *grpc.StreamServerInfo,
+			handler grpc.StreamHandler,
+		) error {
+			if err := grpcAuthenticate(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+
+	if len(config.HTTP.TLSCertFile) > 0 {
+		creds, err := credentials.NewServerTLSFromFile(config.HTTP.TLSCertFile, config.HTTP.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("NewGRPCServer: %v", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(options...)
+	pb.RegisterNBTBlockServiceServer(server, grpcServer{})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(pb.NBTBlockService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go watchHealth(healthServer)
+
+	return server, nil
+}
+
+// RunGRPCServer 在 config.GRPC.ListenAddress 上运行 gRPC 服务器。
+// 如果该地址为空，则 RunGRPCServer 立即返回
+func RunGRPCServer() {
+	if len(config.GRPC.ListenAddress) == 0 {
+		return
+	}
+
+	server, err := NewGRPCServer()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	listener, err := net.Listen("tcp", config.GRPC.ListenAddress)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	pterm.Info.Printfln("gRPC 服务器正在监听 %s", config.GRPC.ListenAddress)
+	if err = server.Serve(listener); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/std_server/pb"
+)
+
+func TestMakeGRPCResult(t *testing.T) {
+	result := makeGRPCResult(nbt_assigner.PlaceNBTBlockResult{
+		Offset:        protocol.BlockPos{1, 0, -1},
+		DroppedFields: []string{"EntityIdentifier", "Delay"},
+		DroppedItems: []nbt_assigner.DroppedItem{
+			{Path: []uint8{5, 4}, ItemName: "minecraft:bedrock", Reason: nbt_assigner.DropReasonCanNotGetByCommand},
+		},
+		LossyItems: []nbt_assigner.DroppedItem{
+			{Path: []uint8{2}, ItemName: "minecraft:porkchop", Reason: nbt_assigner.LossReasonSlotNotPreserved},
+		},
+	})
+
+	if result.GetError() != nil || result.GetOffsetX() != 1 || result.GetOffsetZ() != -1 {
+		t.Fatalf("unexpected result %v", result)
+	}
+	if fmt.Sprint(result.GetDroppedFields()) != "[EntityIdentifier Delay]" {
+		t.Fatalf("unexpected dropped fields %v", result.GetDroppedFields())
+	}
+
+	dropped, lossy := result.GetDroppedItems(), result.GetLossyItems()
+	if len(dropped) != 1 ||
+		fmt.Sprint(dropped[0].GetPath()) != "[5 4]" ||
+		dropped[0].GetItemName() != "minecraft:bedrock" ||
+		dropped[0].GetReason() != nbt_assigner.DropReasonCanNotGetByCommand {
+		t.Fatalf("unexpected dropped items %v", dropped)
+	}
+	if len(lossy) != 1 ||
+		fmt.Sprint(lossy[0].GetPath()) != "[2]" ||
+		lossy[0].GetReason() != nbt_assigner.LossReasonSlotNotPreserved {
+		t.Fatalf("unexpected lossy items %v", lossy)
+	}
+}
+
+func TestMakeGRPCResultError(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want pb.ErrorCode
+	}{
+		{fmt.Errorf("place: %w", ErrReconnecting), pb.ErrorCode_ERROR_CODE_RECONNECTING},
+		{fmt.Errorf("place: %w", nbt_assigner.ErrParseNBTBlock), pb.ErrorCode_ERROR_CODE_INVALID_NBT},
+		{errors.New("place: failed"), pb.ErrorCode_ERROR_CODE_RUNTIME_ERROR},
+	}
+
+	for _, testCase := range testCases {
+		// 放置失败时，其余字段均为零值
+		result := makeGRPCResult(nbt_assigner.PlaceNBTBlockResult{
+			DroppedFields: []string{"Delay"},
+			Err:           testCase.err,
+		})
+		if result.GetError().GetCode() != testCase.want || len(result.GetDroppedFields()) != 0 {
+			t.Fatalf("%v: unexpected result %v", testCase.err, result)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultJobQueueSize 是任务队列的默认容量
+	DefaultJobQueueSize = 64
+	// DefaultJobRetention 是已结束的任务被保留的时长，
+	// 超过此时长的任务将被移除且不再能被查询
+	DefaultJobRetention = time.Minute * 10
+)
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// jobBlock 是任务中的单个 NBT 方块
+type jobBlock struct {
+	index int
+	block nbt_assigner.NBTBlock
+}
+
+// Job 是一个放置 NBT 方块的任务
+type Job struct {
+	id         uuid.UUID
+	status     string
+	cancelled  bool
+	err        string
+	finishTime time.Time
+	blocks     []jobBlock
+	done       int
+	responses  []PlaceNBTBlockResponse
+	// resolved 指示 responses 中的每个结果是否已经得到
+	resolved []bool
+
+	// ctx 在任务被取消时被取消，
+	// 正在执行的任务会在当前方块制作完成后停止
+	ctx    context.Context
+	cancel context.CancelFunc
+	// finished 在任务结束时被关闭
+	finished chan struct{}
+
+	// synchronous 指示该任务是否由同步的 API 提交。
+	// 同步任务不会等待正在重新连接的机器人
+	synchronous bool
+	// onProgress 在每得到一个放置结果时被调用，可以为空。
+	// indexes 是该结果所对应的方块在 responses 中的下标
+	onProgress func(indexes []int, result nbt_assigner.PlaceNBTBlockResult)
+}
+
+// newJob 创建并返回一个尚未提交的新任务，
+// 它共有 total 个方块，其中可以放置的方块是 blocks
+func newJob(ctx context.Context, total int, blocks []jobBlock) *Job {
+	job := &Job{
+		id:        uuid.New(),
+		status:    JobStatusQueued,
+		blocks:    blocks,
+		responses: make([]PlaceNBTBlockResponse, total),
+		resolved:  make([]bool, total),
+		finished:  make(chan struct{}),
+	}
+	job.ctx, job.cancel = context.WithCancel(ctx)
+	return job
+}
+
+// cancelledResponse 返回因任务被取消而未被放置的方块的响应体
+func cancelledResponse() PlaceNBTBlockResponse {
+	return PlaceNBTBlockResponse{
+		Success:   false,
+		ErrorType: ResponseErrorTypeCancelled,
+		ErrorInfo: "Cancelled: The job was cancelled before the block was placed",
+	}
+}
+
+// JobQueue 是有界的任务队列。
+//
+// 队列中的任务由多个工作者并发地执行，每个工作者
+// 在执行任务时独占机器人工作池中的一个空闲机器人，
+// 并使用该机器人的 NBTAssigner 放置任务中的全部方块。
+// 同步的放置 API 也通过 Run 使用该队列的工作者，
+// 但同步任务不受队列容量的限制，并且先于异步任务被执行
+type JobQueue struct {
+	mu      *sync.Mutex
+	jobs    map[uuid.UUID]*Job
+	queued  int
+	pending chan *Job
+	// urgent 用于将同步任务直接交给空闲的工作者
+	urgent chan *Job
+}
+
+// NewJobQueue 创建并返回一个容量为 size 的任务队列，
+// 并启动 workers 个执行这些任务的工作者。workers 通常
+// 与机器人的数量相同
+func NewJobQueue(size int, workers int) *JobQueue {
+	q := newJobQueue(size)
+	for range max(workers, 1) {
+		go q.worker()
+	}
+	return q
+}
+
+// newJobQueue 创建并返回一个容量为 size 的，
+// 尚未启动任何工作者的任务队列
+func newJobQueue(size int) *JobQueue {
+	if size <= 0 {
+		size = DefaultJobQueueSize
+	}
+	return &JobQueue{
+		mu:      new(sync.Mutex),
+		jobs:    make(map[uuid.UUID]*Job),
+		pending: make(chan *Job, size),
+		urgent:  make(chan *Job),
+	}
+}
+
+// Submit 将 requests 作为一个新的异步任务提交到队列中。
+// 如果队列已满，则返回错误
+func (q *JobQueue) Submit(requests []PlaceNBTBlockRequest) (*Job, error) {
+	blocks := make([]jobBlock, 0)
+	failedResponses := make(map[int]PlaceNBTBlockResponse)
+
+	for index, request := range requests {
+		blockNBT, success, failedResponse := decodeBlockNBT(request)
+		if !success {
+			failedResponses[index] = failedResponse
+			continue
+		}
+		blocks = append(blocks, jobBlock{
+			index: index,
+			block: nbt_assigner.NBTBlock{
+				BlockName:   request.BlockName,
+				BlockStates: utils.ParseBlockStatesString(request.BlockStatesString),
+				BlockNBT:    blockNBT,
+			},
+		})
+	}
+
+	job := newJob(context.Background(), len(requests), blocks)
+	for index, response := range failedResponses {
+		job.responses[index] = response
+		job.resolved[index] = true
+		job.done++
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.cleanExpiredJobs()
+	err := q.enqueue(job)
+	if err != nil {
+		return nil, fmt.Errorf("Submit: %v", err)
+	}
+	q.jobs[job.id] = job
+
+	return job, nil
+}
+
+// Run 将 blocks 作为一个同步任务交给某个空闲的工作者，并等待它结束。
+//
+// 同步任务不受队列容量的限制，也不会排在已提交的异步任务之后，
+// 它只等待某个工作者完成其当前的任务。同步任务不会等待正在
+// 重新连接的机器人，也不能通过 Cancel 取消。如果 ctx 被取消，
+// 则尚未开始的任务将不会被执行，而正在执行的任务将在当前的
+// NBT 方块制作完成后停止。
+//
+// 每得到一个放置结果，都会以该结果所对应的方块在 blocks 中的
+// 下标调用 onProgress。onProgress 可以为空，并且在 Run 返回后
+// 不会再被调用
+func (q *JobQueue) Run(
+	ctx context.Context,
+	blocks []nbt_assigner.NBTBlock,
+	onProgress func(indexes []int, result nbt_assigner.PlaceNBTBlockResult),
+) {
+	jobBlocks := make([]jobBlock, len(blocks))
+	for index, block := range blocks {
+		jobBlocks[index] = jobBlock{index: index, block: block}
+	}
+	job := newJob(ctx, len(blocks), jobBlocks)
+	job.synchronous = true
+	job.onProgress = onProgress
+
+	q.mu.Lock()
+	q.queued++
+	q.mu.Unlock()
+
+	select {
+	case q.urgent <- job:
+	case <-ctx.Done():
+		q.cancelJob(job)
+		return
+	}
+
+	select {
+	case <-job.finished:
+	case <-ctx.Done():
+		q.cancelJob(job)
+		<-job.finished
+	}
+}
+
+// enqueue 将 job 放入队列。
+// 调用者应当持有 q.mu
+func (q *JobQueue) enqueue(job *Job) error {
+	select {
+	case q.pending <- job:
+	default:
+		return fmt.Errorf("enqueue: The job queue is full (capacity = %d)", cap(q.pending))
+	}
+	q.queued++
+	return nil
+}
+
+// Cancel 取消 ID 为 id 的任务。
+// 排队中的任务将不会被执行，而正在执行的任务将在
+// 当前的 NBT 方块放置完成后停止，已得到的结果将被保留。
+//
+// found 指示目标任务是否存在；
+// cancelled 指示目标任务是否已被取消，
+// 已结束的任务无法被取消
+func (q *JobQueue) Cancel(id uuid.UUID) (found bool, cancelled bool) {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	q.mu.Unlock()
+	if !ok {
+		return false, false
+	}
+	return true, q.cancelJob(job)
+}
+
+// cancelJob 取消任务 job，并返回它是否已被取消
+func (q *JobQueue) cancelJob(job *Job) (cancelled bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job.status != JobStatusQueued && job.status != JobStatusRunning {
+		return job.status == JobStatusCancelled
+	}
+
+	job.cancelled = true
+	job.cancel()
+	if job.status == JobStatusQueued {
+		q.queued--
+		q.finish(job)
+	}
+	return true
+}
+
+// Depth 返回队列中尚未开始执行且未被取消的任务的数量
+func (q *JobQueue) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queued
+}
+
+// Capacity 返回队列的容量
+func (q *JobQueue) Capacity() int {
+	return cap(q.pending)
+}
+
+// Status 返回 ID 为 id 的任务的当前状态
+func (q *JobQueue) Status(id uuid.UUID) (response JobStatusResponse, found bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return JobStatusResponse{}, false
+	}
+	return q.makeStatusResponse(job), true
+}
+
+// makeStatusResponse 将任务 job 的状态包装为响应体。
+// 调用者应当持有 q.mu
+func (q *JobQueue) makeStatusResponse(job *Job) JobStatusResponse {
+	response := JobStatusResponse{
+		JobID:         job.id.String(),
+		Status:        job.status,
+		Done:          job.done,
+		Total:         len(job.responses),
+		QueueDepth:    q.queued,
+		QueueCapacity: q.Capacity(),
+		Error:         job.err,
+	}
+	if !job.finishTime.IsZero() {
+		response.Results = job.responses
+	}
+	return response
+}
+
+// cleanExpiredJobs 移除已结束且超过保留时长的任务。
+// 调用者应当持有 q.mu
+func (q *JobQueue) cleanExpiredJobs() {
+	for id, job := range q.jobs {
+		if !job.finishTime.IsZero() && time.Since(job.finishTime) > DefaultJobRetention {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+// worker 按顺序执行其从队列中取出的每个任务。
+// 同步任务总是先于排队中的异步任务被执行
+func (q *JobQueue) worker() {
+	for {
+		var job *Job
+		select {
+		case job = <-q.urgent:
+		default:
+			select {
+			case job = <-q.urgent:
+			case job = <-q.pending:
+			}
+		}
+
+		q.mu.Lock()
+		if job.cancelled {
+			// 已被取消的任务在取消时就已经结束
+			q.mu.Unlock()
+			continue
+		}
+		if job.ctx.Err() != nil {
+			job.cancelled = true
+			q.queued--
+			q.finish(job)
+			q.mu.Unlock()
+			continue
+		}
+		job.status = JobStatusRunning
+		q.queued--
+		q.mu.Unlock()
+
+		q.runJob(job)
+	}
+}
+
+// acquireBot 为任务 job 占用一个空闲的机器人。
+// 对于异步任务，如果没有任何机器人在线，则等待某个机器人上线
+func (q *JobQueue) acquireBot(job *Job) (bot *Bot, session *Session, err error) {
+	if job.synchronous {
+		return pool.Acquire()
+	}
+	bot, session = pool.AcquireWait()
+	return bot, session, nil
+}
+
+// runJob 使用一个机器人放置任务 job 中的全部方块，并记录其结果。
+//
+// 对于异步任务，如果放置期间连接断开，则未能放置的方块
+// 将在某个机器人重新上线后由该机器人 (重新) 放置
+func (q *JobQueue) runJob(job *Job) {
+	pending := job.blocks
+
+	for len(pending) > 0 && job.ctx.Err() == nil {
+		bot, session, err := q.acquireBot(job)
+		if err != nil {
+			for index := range pending {
+				q.record(job, pending, []int{index}, nbt_assigner.PlaceNBTBlockResult{Err: err})
+			}
+			break
+		}
+
+		blocks := make([]nbt_assigner.NBTBlock, len(pending))
+		for index, value := range pending {
+			blocks[index] = value.block
+		}
+		retry := make([]jobBlock, 0)
+		bot.assigner.PlaceNBTBlocksWithContext(job.ctx, blocks, func(indexes []int, result nbt_assigner.PlaceNBTBlockResult) {
+			if result.Err != nil && session.Closed() && !job.synchronous {
+				for _, index := range indexes {
+					retry = append(retry, pending[index])
+				}
+				return
+			}
+			result.Err = bot.supervisor.WrapError(session, result.Err)
+			q.record(job, pending, indexes, result)
+		})
+		pool.Release(bot)
+
+		pending = retry
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.finish(job)
+}
+
+// record 将方块 blocks[indexes] 的放置结果 result 记录到任务 job 中
+func (q *JobQueue) record(job *Job, blocks []jobBlock, indexes []int, result nbt_assigner.PlaceNBTBlockResult) {
+	responseIndexes := make([]int, len(indexes))
+	for i, index := range indexes {
+		responseIndexes[i] = blocks[index].index
+	}
+
+	q.mu.Lock()
+	for _, index := range responseIndexes {
+		job.responses[index] = makePlaceNBTBlockResponse(result)
+		job.resolved[index] = true
+		job.done++
+	}
+	q.mu.Unlock()
+
+	if job.onProgress != nil {
+		job.onProgress(responseIndexes, result)
+	}
+}
+
+// finish 结束任务 job，并根据已得到的结果决定其最终状态。
+// 被取消的任务总是以 JobStatusCancelled 结束，并且
+// 它尚未放置的方块将被标记为已取消。
+// 调用者应当持有 q.mu
+func (q *JobQueue) finish(job *Job) {
+	failed := 0
+	for index, response := range job.responses {
+		if !job.resolved[index] {
+			continue
+		}
+		if !response.Success {
+			failed++
+		}
+	}
+	for _, value := range job.blocks {
+		if job.cancelled && !job.resolved[value.index] {
+			job.responses[value.index] = cancelledResponse()
+		}
+	}
+
+	switch {
+	case job.cancelled:
+		job.status = JobStatusCancelled
+		job.err = fmt.Sprintf("The job was cancelled after %d of %d blocks were done", job.done, len(job.responses))
+	case failed > 0:
+		job.status = JobStatusFailed
+		job.err = fmt.Sprintf("%d of %d blocks failed", failed, len(job.responses))
+	default:
+		job.status = JobStatusSucceeded
+	}
+	job.finishTime = time.Now()
+
+	job.cancel()
+	close(job.finished)
+}
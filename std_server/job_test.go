@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+)
+
+// testRequest 返回一个可以被解码的放置请求
+func testRequest(t *testing.T) PlaceNBTBlockRequest {
+	t.Helper()
+
+	blockNBT, err := nbt.MarshalEncoding(map[string]any{"id": "Chest"}, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return PlaceNBTBlockRequest{
+		BlockName:            "minecraft:chest",
+		BlockStatesString:    `["minecraft:cardinal_direction"="north"]`,
+		BlockNBTBase64String: base64.StdEncoding.EncodeToString(blockNBT),
+	}
+}
+
+// startJob 模拟工作者开始执行任务 job
+func startJob(q *JobQueue, job *Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job.status = JobStatusRunning
+	q.queued--
+}
+
+func TestSubmitFullQueue(t *testing.T) {
+	q := newJobQueue(1)
+
+	if _, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t)}); err == nil {
+		t.Fatal("Submit should fail when the queue is full")
+	}
+	if depth := q.Depth(); depth != 1 {
+		t.Fatalf("unexpected depth %d", depth)
+	}
+}
+
+func TestSubmitParseError(t *testing.T) {
+	q := newJobQueue(1)
+
+	broken := testRequest(t)
+	broken.BlockNBTBase64String = "!"
+	job, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t), broken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(job.blocks) != 1 || job.done != 1 || !job.resolved[1] {
+		t.Fatalf("unexpected job blocks %d, done %d and resolved %v", len(job.blocks), job.done, job.resolved)
+	}
+	if job.responses[1].ErrorType != ResponseErrorTypeParseError {
+		t.Fatalf("unexpected error type %d", job.responses[1].ErrorType)
+	}
+}
+
+func TestRunIgnoresCapacity(t *testing.T) {
+	q := newJobQueue(1)
+	if _, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t)}); err != nil {
+		t.Fatal(err)
+	}
+
+	returned := make(chan struct{})
+	go func() {
+		q.Run(context.Background(), []nbt_assigner.NBTBlock{{BlockName: "minecraft:chest"}}, nil)
+		close(returned)
+	}()
+
+	// 队列已满时，同步任务仍然被直接交给工作者
+	var job *Job
+	select {
+	case job = <-q.urgent:
+	case <-time.After(2 * time.Second):
+		t.Fatal("synchronous job was not handed to a worker")
+	}
+	if !job.synchronous {
+		t.Fatal("job should be synchronous")
+	}
+
+	startJob(q, job)
+	q.record(job, job.blocks, []int{0}, nbt_assigner.PlaceNBTBlockResult{CanFast: true})
+	q.mu.Lock()
+	q.finish(job)
+	q.mu.Unlock()
+
+	select {
+	case <-returned:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after the job finished")
+	}
+	if job.status != JobStatusSucceeded {
+		t.Fatalf("unexpected status %s", job.status)
+	}
+}
+
+func TestRunCancelledBeforeStart(t *testing.T) {
+	q := newJobQueue(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	q.Run(ctx, []nbt_assigner.NBTBlock{{BlockName: "minecraft:chest"}}, func([]int, nbt_assigner.PlaceNBTBlockResult) {
+		called = true
+	})
+
+	if called {
+		t.Fatal("onProgress should not be called")
+	}
+	if depth := q.Depth(); depth != 0 {
+		t.Fatalf("unexpected depth %d", depth)
+	}
+}
+
+func TestCancelQueuedJob(t *testing.T) {
+	q := newJobQueue(1)
+	job, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, cancelled := q.Cancel(job.id)
+	if !found || !cancelled {
+		t.Fatalf("unexpected found %v and cancelled %v", found, cancelled)
+	}
+	response, _ := q.Status(job.id)
+	if response.Status != JobStatusCancelled || response.QueueDepth != 0 {
+		t.Fatalf("unexpected status %s and depth %d", response.Status, response.QueueDepth)
+	}
+	if response.Results[0].ErrorType != ResponseErrorTypeCancelled {
+		t.Fatalf("unexpected error type %d", response.Results[0].ErrorType)
+	}
+
+	// 已结束的任务仍然被报告为已取消
+	if found, cancelled = q.Cancel(job.id); !found || !cancelled {
+		t.Fatalf("unexpected found %v and cancelled %v", found, cancelled)
+	}
+	if found, _ = q.Cancel([16]byte{}); found {
+		t.Fatal("unknown job should not be found")
+	}
+}
+
+func TestCancelKeepsPartialResults(t *testing.T) {
+	q := newJobQueue(1)
+	job, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t), testRequest(t), testRequest(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-q.pending
+	startJob(q, job)
+
+	q.record(job, job.blocks, []int{0}, nbt_assigner.PlaceNBTBlockResult{CanFast: true})
+	if found, cancelled := q.Cancel(job.id); !found || !cancelled {
+		t.Fatalf("unexpected found %v and cancelled %v", found, cancelled)
+	}
+	if job.ctx.Err() == nil {
+		t.Fatal("context of the running job should be cancelled")
+	}
+	if response, _ := q.Status(job.id); response.Status != JobStatusRunning || response.Results != nil {
+		t.Fatalf("running job should not finish before the current block is done, got %s", response.Status)
+	}
+
+	// 工作者在当前的方块完成后结束任务
+	q.record(job, job.blocks, []int{1}, nbt_assigner.PlaceNBTBlockResult{CanFast: true})
+	q.mu.Lock()
+	q.finish(job)
+	q.mu.Unlock()
+
+	response, _ := q.Status(job.id)
+	if response.Status != JobStatusCancelled || response.Done != 2 || response.Total != 3 {
+		t.Fatalf("unexpected status %s, done %d and total %d", response.Status, response.Done, response.Total)
+	}
+	for index, want := range []bool{true, true, false} {
+		if response.Results[index].Success != want {
+			t.Fatalf("unexpected result %d: %+v", index, response.Results[index])
+		}
+	}
+	if response.Results[2].ErrorType != ResponseErrorTypeCancelled {
+		t.Fatalf("unexpected error type %d", response.Results[2].ErrorType)
+	}
+}
+
+func TestFinishFailed(t *testing.T) {
+	q := newJobQueue(1)
+	job, err := q.Submit([]PlaceNBTBlockRequest{testRequest(t), testRequest(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-q.pending
+	startJob(q, job)
+
+	q.record(job, job.blocks, []int{0, 1}, nbt_assigner.PlaceNBTBlockResult{Err: ErrReconnecting})
+	q.mu.Lock()
+	q.finish(job)
+	q.mu.Unlock()
+
+	response, _ := q.Status(job.id)
+	if response.Status != JobStatusFailed || response.Error != "2 of 2 blocks failed" {
+		t.Fatalf("unexpected status %s and error %q", response.Status, response.Error)
+	}
+	if response.Results[0].ErrorType != ResponseErrorTypeReconnecting {
+		t.Fatalf("unexpected error type %d", response.Results[0].ErrorType)
+	}
+	select {
+	case <-job.finished:
+	default:
+		t.Fatal("finished should be closed")
+	}
+}
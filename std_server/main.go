@@ -1,121 +1,106 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/OmineDev/flowers-for-machines/client"
 	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
 	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
-	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
 	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
-	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
 
 	"github.com/pterm/pterm"
 )
 
 var (
-	mcClient      *client.Client
-	resources     *resources_control.Resources
-	gameInterface *game_interface.GameInterface
-	console       *nbt_console.Console
-	cache         *nbt_cache.NBTCacheSystem
-	wrapper       *nbt_assigner.NBTAssigner
+	pool     *BotPool
+	cache    *nbt_cache.NBTCacheSystem
+	jobQueue *JobQueue
 )
 
-var (
-	rentalServerCode     *string
-	rentalServerPasscode *string
-	authServerAddress    *string
-	authServerToken      *string
-	standardServerPort   *int
-	consoleCenterX       *int
-	consoleCenterY       *int
-	consoleCenterZ       *int
-)
-
-func init() {
-	rentalServerCode = flag.String("rsn", "", "The rental server number.")
-	rentalServerPasscode = flag.String("rsp", "", "The pass code of the rental server.")
-	authServerAddress = flag.String("asa", "", "The auth server address.")
-	authServerToken = flag.String("ast", "", "The auth server token.")
-	standardServerPort = flag.Int("ssp", 0, "The server port to running.")
-	consoleCenterX = flag.Int("ccx", 0, "The X position of the center of the console.")
-	consoleCenterY = flag.Int("ccy", 0, "The Y position of the center of the console.")
-	consoleCenterZ = flag.Int("ccz", 0, "The Z position of the center of the console.")
-
-	flag.Parse()
-	if len(*rentalServerCode) == 0 {
-		log.Fatalln("Please provide your rental server number.\n\te.g. -rsn=\"123456\"")
-	}
-	if len(*authServerAddress) == 0 {
-		log.Fatalln("Please provide your auth server address.\n\te.g. -asa=\"http://127.0.0.1\"")
-	}
-	if *standardServerPort == 0 {
-		log.Fatalln("Please provide the server port to running.\n\te.g. -ssp=0")
-	}
-}
+// config 是标准服务器的配置
+var config Config
 
 func main() {
 	var err error
-	cfg := client.Config{
-		AuthServerAddress:    *authServerAddress,
-		AuthServerToken:      *authServerToken,
-		RentalServerCode:     *rentalServerCode,
-		RentalServerPasscode: *rentalServerPasscode,
-	}
 
-	for {
-		c, err := client.LoginRentalServer(cfg)
+	config, err = loadConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	config.Apply()
+
+	bots := make([]*Bot, 0)
+	for index, botConfig := range config.Bots() {
+		supervisor := NewSupervisor(
+			botConfig.Name,
+			client.Config{
+				AuthServerAddress:    botConfig.AuthServerAddress,
+				AuthServerToken:      botConfig.AuthServerToken,
+				RentalServerCode:     config.Connection.RentalServerCode,
+				RentalServerPasscode: config.Connection.RentalServerPasscode,
+			},
+			protocol.BlockPos{botConfig.CenterX, botConfig.CenterY, botConfig.CenterZ},
+		)
+		session, err := supervisor.Start()
 		if err != nil {
-			if strings.Contains(fmt.Sprintf("%v", err), "netease.report.kick.hint") {
-				continue
-			}
 			panic(err)
 		}
-		mcClient = c
-		break
-	}
 
-	resources = resources_control.NewResourcesControl(mcClient)
-	gameInterface = game_interface.NewGameInterface(resources)
-	requestPermission()
-
-	console, err = nbt_console.NewConsole(
-		gameInterface,
-		protocol.BlockPos{
-			int32(*consoleCenterX),
-			int32(*consoleCenterY),
-			int32(*consoleCenterZ),
-		},
-	)
-	if err != nil {
-		panic(err)
+		// 结构保存在存档中，因此全部机器人共享同一份缓存
+		var botCache *nbt_cache.NBTCacheSystem
+		if index == 0 {
+			cache = nbt_cache.NewNBTCacheSystem(session.Console)
+			if len(config.Cache.PersistentFile) > 0 {
+				restored, discarded, err := cache.SetPersistentFile(config.Cache.PersistentFile)
+				if err != nil {
+					panic(err)
+				}
+				pterm.Info.Printfln("已从 %s 恢复 %d 个缓存", config.Cache.PersistentFile, restored)
+				if discarded > 0 {
+					pterm.Warning.Printfln("已丢弃 %d 个结构不存在或无法解析的缓存", discarded)
+				}
+				cache.SetSyncErrorHandler(func(err error) {
+					pterm.Warning.Printfln("无法将缓存写入 %s; err = %v", config.Cache.PersistentFile, err)
+				})
+			}
+			botCache = cache
+		} else {
+			botCache = cache.ShareWith(session.Console)
+		}
+
+		bot := NewBot(botConfig.Name, supervisor, nbt_assigner.NewNBTAssigner(session.Console, botCache))
+		go supervisor.Run(func(session *Session) {
+			bot.assigner.SetConsole(session.Console)
+		})
+		bots = append(bots, bot)
+		pterm.Success.Printfln("[%s] 已连接到租赁服", botConfig.Name)
 	}
-	cache = nbt_cache.NewNBTCacheSystem(console)
-	wrapper = nbt_assigner.NewNBTAssigner(console, cache)
 
+	pool = NewBotPool(bots)
+	jobQueue = NewJobQueue(config.JobQueue.Size, len(bots))
+
+	go RunGRPCServer()
 	RunServer()
 }
 
-func requestPermission() {
+// requestPermission 等待机器人被给予管理员权限
+func requestPermission(gameInterface *game_interface.GameInterface) error {
 	api := gameInterface.Commands()
 
 	_, err := api.SendWSCommandWithResp("deop @s")
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("requestPermission: %v", err)
 	}
 
 	ticker := time.NewTicker(time.Second * 3)
 	defer ticker.Stop()
 	for {
-		resp, err := gameInterface.Commands().SendWSCommandWithResp("querytarget @s")
+		resp, err := api.SendWSCommandWithResp("querytarget @s")
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("requestPermission: %v", err)
 		}
 
 		if resp.SuccessCount == 0 {
@@ -124,6 +109,6 @@ func requestPermission() {
 			continue
 		}
 
-		break
+		return nil
 	}
 }
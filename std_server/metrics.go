@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/OmineDev/flowers-for-machines/metrics"
+)
+
+func init() {
+	metrics.NewGaugeFunc(
+		"job_queue_depth",
+		"Number of jobs waiting in the job queue.",
+		func() float64 {
+			if jobQueue == nil {
+				return 0
+			}
+			return float64(jobQueue.Depth())
+		},
+	)
+	metrics.NewGaugeFunc(
+		"job_queue_capacity",
+		"Maximum number of jobs that can wait in the job queue.",
+		func() float64 {
+			if jobQueue == nil {
+				return 0
+			}
+			return float64(jobQueue.Capacity())
+		},
+	)
+	metrics.NewGaugeFunc(
+		"bots_online",
+		"Number of bots that are connected to the rental server.",
+		func() float64 {
+			if pool == nil {
+				return 0
+			}
+			return float64(pool.Online())
+		},
+	)
+	metrics.NewGaugeFunc(
+		"bots_idle",
+		"Number of bots that are not placing blocks.",
+		func() float64 {
+			if pool == nil {
+				return 0
+			}
+			return float64(pool.Idle())
+		},
+	)
+}
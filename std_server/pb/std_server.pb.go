@@ -0,0 +1,913 @@
+// 标准服务器的 gRPC 接口。
+//
+// 它与 JSON HTTP API 提供相同的 NBT 方块放置能力，
+// 但直接以字节传输小端序 NBT，并提供结构化的错误码。
+//
+// 修改本文件后，在 std_server 目录下执行 go generate 以重新生成
+// pb 包中的代码 (需要 protoc, protoc-gen-go 和 protoc-gen-go-grpc)
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        v4.25.1
+// source: pb/std_server.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ErrorCode 指示单个 NBT 方块放置失败的原因
+type ErrorCode int32
+
+const (
+	// 没有错误
+	ErrorCode_ERROR_CODE_OK ErrorCode = 0
+	// block_nbt 不是合法的小端序 NBT
+	ErrorCode_ERROR_CODE_INVALID_NBT ErrorCode = 1
+	// 放置过程中发生了运行时错误
+	ErrorCode_ERROR_CODE_RUNTIME_ERROR ErrorCode = 2
+	// 机器人正在重新连接到租赁服，稍后可以重试
+	ErrorCode_ERROR_CODE_RECONNECTING ErrorCode = 3
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "ERROR_CODE_OK",
+		1: "ERROR_CODE_INVALID_NBT",
+		2: "ERROR_CODE_RUNTIME_ERROR",
+		3: "ERROR_CODE_RECONNECTING",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_OK":            0,
+		"ERROR_CODE_INVALID_NBT":   1,
+		"ERROR_CODE_RUNTIME_ERROR": 2,
+		"ERROR_CODE_RECONNECTING":  3,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_std_server_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_pb_std_server_proto_enumTypes[0]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{0}
+}
+
+// NBTBlock 是要放置的 NBT 方块
+type NBTBlock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 方块名称，例如 minecraft:chest
+	BlockName string `protobuf:"bytes,1,opt,name=block_name,json=blockName,proto3" json:"block_name,omitempty"`
+	// 方块状态字符串，例如 ["facing_direction"=2]
+	BlockStatesString string `protobuf:"bytes,2,opt,name=block_states_string,json=blockStatesString,proto3" json:"block_states_string,omitempty"`
+	// 以小端序编码的方块实体数据
+	BlockNbt []byte `protobuf:"bytes,3,opt,name=block_nbt,json=blockNbt,proto3" json:"block_nbt,omitempty"`
+}
+
+func (x *NBTBlock) Reset() {
+	*x = NBTBlock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NBTBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NBTBlock) ProtoMessage() {}
+
+func (x *NBTBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NBTBlock.ProtoReflect.Descriptor instead.
+func (*NBTBlock) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NBTBlock) GetBlockName() string {
+	if x != nil {
+		return x.BlockName
+	}
+	return ""
+}
+
+func (x *NBTBlock) GetBlockStatesString() string {
+	if x != nil {
+		return x.BlockStatesString
+	}
+	return ""
+}
+
+func (x *NBTBlock) GetBlockNbt() []byte {
+	if x != nil {
+		return x.BlockNbt
+	}
+	return nil
+}
+
+// Error 描述放置失败的原因
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    ErrorCode `protobuf:"varint,1,opt,name=code,proto3,enum=std_server.v1.ErrorCode" json:"code,omitempty"`
+	Message string    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Error) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_ERROR_CODE_OK
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// PlaceNBTBlockResult 是单个 NBT 方块的放置结果。
+// 放置失败时 error 不为空，且其余字段均为零值
+type PlaceNBTBlockResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error *Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// 方块是否可以直接通过 setblock 放置
+	CanFast bool `protobuf:"varint,2,opt,name=can_fast,json=canFast,proto3" json:"can_fast,omitempty"`
+	// 方块所在结构的唯一标识
+	StructureUniqueId string `protobuf:"bytes,3,opt,name=structure_unique_id,json=structureUniqueId,proto3" json:"structure_unique_id,omitempty"`
+	// 方块所在结构的名称
+	StructureName string `protobuf:"bytes,4,opt,name=structure_name,json=structureName,proto3" json:"structure_name,omitempty"`
+	// 相邻的可能的方块相对于此方块的偏移，
+	// 例如床的尾方块相对于头方块的偏移。
+	//
+	// 结构的原点是此方块与相邻方块在各轴上的较小者，例如床的
+	// 头方块与尾方块中坐标较小的一方。因此，若要使此方块位于
+	// pos 处，则应当在 pos + min(offset, 0) (逐轴计算) 处加载结构
+	OffsetX int32 `protobuf:"varint,5,opt,name=offset_x,json=offsetX,proto3" json:"offset_x,omitempty"`
+	OffsetY int32 `protobuf:"varint,6,opt,name=offset_y,json=offsetY,proto3" json:"offset_y,omitempty"`
+	OffsetZ int32 `protobuf:"varint,7,opt,name=offset_z,json=offsetZ,proto3" json:"offset_z,omitempty"`
+	// 方块是否总是随其相邻的方块一同被制作，
+	// 例如床尾总是随床头一同被制作。此时不应
+	// 放置此方块，否则它将覆盖已放置的相邻方块
+	PlacedByNeighbor bool `protobuf:"varint,8,opt,name=placed_by_neighbor,json=placedByNeighbor,proto3" json:"placed_by_neighbor,omitempty"`
+	// 方块实体数据或方块状态中没有被还原的字段的名称，
+	// 例如刷怪蛋不存在时刷怪笼的 EntityIdentifier
+	DroppedFields []string `protobuf:"bytes,9,rep,name=dropped_fields,json=droppedFields,proto3" json:"dropped_fields,omitempty"`
+	// 方块所装有的，没有被还原的物品
+	DroppedItems []*DroppedItem `protobuf:"bytes,10,rep,name=dropped_items,json=droppedItems,proto3" json:"dropped_items,omitempty"`
+	// 方块所装有的，只被近似还原的物品
+	LossyItems []*DroppedItem `protobuf:"bytes,11,rep,name=lossy_items,json=lossyItems,proto3" json:"lossy_items,omitempty"`
+}
+
+func (x *PlaceNBTBlockResult) Reset() {
+	*x = PlaceNBTBlockResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceNBTBlockResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceNBTBlockResult) ProtoMessage() {}
+
+func (x *PlaceNBTBlockResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceNBTBlockResult.ProtoReflect.Descriptor instead.
+func (*PlaceNBTBlockResult) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlaceNBTBlockResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *PlaceNBTBlockResult) GetCanFast() bool {
+	if x != nil {
+		return x.CanFast
+	}
+	return false
+}
+
+func (x *PlaceNBTBlockResult) GetStructureUniqueId() string {
+	if x != nil {
+		return x.StructureUniqueId
+	}
+	return ""
+}
+
+func (x *PlaceNBTBlockResult) GetStructureName() string {
+	if x != nil {
+		return x.StructureName
+	}
+	return ""
+}
+
+func (x *PlaceNBTBlockResult) GetOffsetX() int32 {
+	if x != nil {
+		return x.OffsetX
+	}
+	return 0
+}
+
+func (x *PlaceNBTBlockResult) GetOffsetY() int32 {
+	if x != nil {
+		return x.OffsetY
+	}
+	return 0
+}
+
+func (x *PlaceNBTBlockResult) GetOffsetZ() int32 {
+	if x != nil {
+		return x.OffsetZ
+	}
+	return 0
+}
+
+func (x *PlaceNBTBlockResult) GetPlacedByNeighbor() bool {
+	if x != nil {
+		return x.PlacedByNeighbor
+	}
+	return false
+}
+
+func (x *PlaceNBTBlockResult) GetDroppedFields() []string {
+	if x != nil {
+		return x.DroppedFields
+	}
+	return nil
+}
+
+func (x *PlaceNBTBlockResult) GetDroppedItems() []*DroppedItem {
+	if x != nil {
+		return x.DroppedItems
+	}
+	return nil
+}
+
+func (x *PlaceNBTBlockResult) GetLossyItems() []*DroppedItem {
+	if x != nil {
+		return x.LossyItems
+	}
+	return nil
+}
+
+// DroppedItem 是没有被还原或只被近似还原的物品
+type DroppedItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 物品所在的槽位。如果物品位于嵌套的容器中，
+	// 则依次记载了每一层的槽位
+	Path []uint32 `protobuf:"varint,1,rep,packed,name=path,proto3" json:"path,omitempty"`
+	// 物品的名称
+	ItemName string `protobuf:"bytes,2,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
+	// 物品没有被还原 (或只被近似还原) 的原因
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *DroppedItem) Reset() {
+	*x = DroppedItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DroppedItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DroppedItem) ProtoMessage() {}
+
+func (x *DroppedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DroppedItem.ProtoReflect.Descriptor instead.
+func (*DroppedItem) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DroppedItem) GetPath() []uint32 {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *DroppedItem) GetItemName() string {
+	if x != nil {
+		return x.ItemName
+	}
+	return ""
+}
+
+func (x *DroppedItem) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type PlaceNBTBlockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Block *NBTBlock `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *PlaceNBTBlockRequest) Reset() {
+	*x = PlaceNBTBlockRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceNBTBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceNBTBlockRequest) ProtoMessage() {}
+
+func (x *PlaceNBTBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceNBTBlockRequest.ProtoReflect.Descriptor instead.
+func (*PlaceNBTBlockRequest) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PlaceNBTBlockRequest) GetBlock() *NBTBlock {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+type PlaceNBTBlockResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *PlaceNBTBlockResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *PlaceNBTBlockResponse) Reset() {
+	*x = PlaceNBTBlockResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceNBTBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceNBTBlockResponse) ProtoMessage() {}
+
+func (x *PlaceNBTBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceNBTBlockResponse.ProtoReflect.Descriptor instead.
+func (*PlaceNBTBlockResponse) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlaceNBTBlockResponse) GetResult() *PlaceNBTBlockResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type PlaceNBTBlocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blocks []*NBTBlock `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *PlaceNBTBlocksRequest) Reset() {
+	*x = PlaceNBTBlocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceNBTBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceNBTBlocksRequest) ProtoMessage() {}
+
+func (x *PlaceNBTBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceNBTBlocksRequest.ProtoReflect.Descriptor instead.
+func (*PlaceNBTBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PlaceNBTBlocksRequest) GetBlocks() []*NBTBlock {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+// PlaceNBTBlocksProgress 是批量放置的进度。
+//
+// 相同的方块只会被制作一次，因此一条进度
+// 可能同时给出多个方块的放置结果
+type PlaceNBTBlocksProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 本条进度所对应的方块在请求中的下标
+	Indexes []uint32             `protobuf:"varint,1,rep,packed,name=indexes,proto3" json:"indexes,omitempty"`
+	Result  *PlaceNBTBlockResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	// 已得到结果的方块数量和方块总数
+	Done  uint32 `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	Total uint32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *PlaceNBTBlocksProgress) Reset() {
+	*x = PlaceNBTBlocksProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_std_server_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceNBTBlocksProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceNBTBlocksProgress) ProtoMessage() {}
+
+func (x *PlaceNBTBlocksProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_std_server_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceNBTBlocksProgress.ProtoReflect.Descriptor instead.
+func (*PlaceNBTBlocksProgress) Descriptor() ([]byte, []int) {
+	return file_pb_std_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PlaceNBTBlocksProgress) GetIndexes() []uint32 {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+func (x *PlaceNBTBlocksProgress) GetResult() *PlaceNBTBlockResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *PlaceNBTBlocksProgress) GetDone() uint32 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *PlaceNBTBlocksProgress) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_pb_std_server_proto protoreflect.FileDescriptor
+
+var file_pb_std_server_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x62, 0x2f, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x22, 0x76, 0x0a, 0x08, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x2e, 0x0a, 0x13, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x5f,
+	0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12,
+	0x1b, 0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x62, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x62, 0x74, 0x22, 0x4f, 0x0a, 0x05,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2c, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xd7, 0x03,
+	0x0a, 0x13, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x61, 0x6e, 0x5f, 0x66, 0x61, 0x73, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x61, 0x6e, 0x46, 0x61, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x13,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x75, 0x72, 0x65, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x5f, 0x78, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x58, 0x12, 0x19,
+	0x0a, 0x08, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x5f, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x59, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x5f, 0x7a, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x5a, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x5f, 0x62,
+	0x79, 0x5f, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x42, 0x79, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62,
+	0x6f, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x64, 0x72, 0x6f, 0x70,
+	0x70, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x3f, 0x0a, 0x0d, 0x64, 0x72, 0x6f,
+	0x70, 0x70, 0x65, 0x64, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0c, 0x64, 0x72,
+	0x6f, 0x70, 0x70, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x6c, 0x6f,
+	0x73, 0x73, 0x79, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0a, 0x6c, 0x6f, 0x73,
+	0x73, 0x79, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x56, 0x0a, 0x0b, 0x44, 0x72, 0x6f, 0x70, 0x70,
+	0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x74,
+	0x65, 0x6d, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69,
+	0x74, 0x65, 0x6d, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22,
+	0x45, 0x0a, 0x14, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x53, 0x0a, 0x15, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e,
+	0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3a, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x48, 0x0a, 0x15, 0x50,
+	0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x06, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x98, 0x01, 0x0a, 0x16, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e,
+	0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0d, 0x52, 0x07, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x74, 0x64,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65,
+	0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x2a, 0x75, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x11, 0x0a,
+	0x0d, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4f, 0x4b, 0x10, 0x00,
+	0x12, 0x1a, 0x0a, 0x16, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49,
+	0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4e, 0x42, 0x54, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x55, 0x4e, 0x54, 0x49,
+	0x4d, 0x45, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x4e, 0x4e, 0x45,
+	0x43, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x32, 0xce, 0x01, 0x0a, 0x0f, 0x4e, 0x42, 0x54, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5a, 0x0a, 0x0d, 0x50,
+	0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x23, 0x2e, 0x73,
+	0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61,
+	0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x24, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x0e, 0x50, 0x6c, 0x61, 0x63, 0x65,
+	0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x24, 0x2e, 0x73, 0x74, 0x64, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e,
+	0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x25, 0x2e, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x6c, 0x61, 0x63, 0x65, 0x4e, 0x42, 0x54, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x30, 0x01, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f, 0x6d, 0x69, 0x6e, 0x65, 0x44, 0x65, 0x76, 0x2f,
+	0x66, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x2d, 0x66, 0x6f, 0x72, 0x2d, 0x6d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x73, 0x2f, 0x73, 0x74, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_std_server_proto_rawDescOnce sync.Once
+	file_pb_std_server_proto_rawDescData = file_pb_std_server_proto_rawDesc
+)
+
+func file_pb_std_server_proto_rawDescGZIP() []byte {
+	file_pb_std_server_proto_rawDescOnce.Do(func() {
+		file_pb_std_server_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_std_server_proto_rawDescData)
+	})
+	return file_pb_std_server_proto_rawDescData
+}
+
+var file_pb_std_server_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pb_std_server_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_pb_std_server_proto_goTypes = []interface{}{
+	(ErrorCode)(0),                 // 0: std_server.v1.ErrorCode
+	(*NBTBlock)(nil),               // 1: std_server.v1.NBTBlock
+	(*Error)(nil),                  // 2: std_server.v1.Error
+	(*PlaceNBTBlockResult)(nil),    // 3: std_server.v1.PlaceNBTBlockResult
+	(*DroppedItem)(nil),            // 4: std_server.v1.DroppedItem
+	(*PlaceNBTBlockRequest)(nil),   // 5: std_server.v1.PlaceNBTBlockRequest
+	(*PlaceNBTBlockResponse)(nil),  // 6: std_server.v1.PlaceNBTBlockResponse
+	(*PlaceNBTBlocksRequest)(nil),  // 7: std_server.v1.PlaceNBTBlocksRequest
+	(*PlaceNBTBlocksProgress)(nil), // 8: std_server.v1.PlaceNBTBlocksProgress
+}
+var file_pb_std_server_proto_depIdxs = []int32{
+	0,  // 0: std_server.v1.Error.code:type_name -> std_server.v1.ErrorCode
+	2,  // 1: std_server.v1.PlaceNBTBlockResult.error:type_name -> std_server.v1.Error
+	4,  // 2: std_server.v1.PlaceNBTBlockResult.dropped_items:type_name -> std_server.v1.DroppedItem
+	4,  // 3: std_server.v1.PlaceNBTBlockResult.lossy_items:type_name -> std_server.v1.DroppedItem
+	1,  // 4: std_server.v1.PlaceNBTBlockRequest.block:type_name -> std_server.v1.NBTBlock
+	3,  // 5: std_server.v1.PlaceNBTBlockResponse.result:type_name -> std_server.v1.PlaceNBTBlockResult
+	1,  // 6: std_server.v1.PlaceNBTBlocksRequest.blocks:type_name -> std_server.v1.NBTBlock
+	3,  // 7: std_server.v1.PlaceNBTBlocksProgress.result:type_name -> std_server.v1.PlaceNBTBlockResult
+	5,  // 8: std_server.v1.NBTBlockService.PlaceNBTBlock:input_type -> std_server.v1.PlaceNBTBlockRequest
+	7,  // 9: std_server.v1.NBTBlockService.PlaceNBTBlocks:input_type -> std_server.v1.PlaceNBTBlocksRequest
+	6,  // 10: std_server.v1.NBTBlockService.PlaceNBTBlock:output_type -> std_server.v1.PlaceNBTBlockResponse
+	8,  // 11: std_server.v1.NBTBlockService.PlaceNBTBlocks:output_type -> std_server.v1.PlaceNBTBlocksProgress
+	10, // [10:12] is the sub-list for method output_type
+	8,  // [8:10] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_pb_std_server_proto_init() }
+func file_pb_std_server_proto_init() {
+	if File_pb_std_server_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pb_std_server_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NBTBlock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Error); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceNBTBlockResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DroppedItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceNBTBlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceNBTBlockResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceNBTBlocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_std_server_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceNBTBlocksProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pb_std_server_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pb_std_server_proto_goTypes,
+		DependencyIndexes: file_pb_std_server_proto_depIdxs,
+		EnumInfos:         file_pb_std_server_proto_enumTypes,
+		MessageInfos:      file_pb_std_server_proto_msgTypes,
+	}.Build()
+	File_pb_std_server_proto = out.File
+	file_pb_std_server_proto_rawDesc = nil
+	file_pb_std_server_proto_goTypes = nil
+	file_pb_std_server_proto_depIdxs = nil
+}
@@ -0,0 +1,181 @@
+// 标准服务器的 gRPC 接口。
+//
+// 它与 JSON HTTP API 提供相同的 NBT 方块放置能力，
+// 但直接以字节传输小端序 NBT，并提供结构化的错误码。
+//
+// 修改本文件后，在 std_server 目录下执行 go generate 以重新生成
+// pb 包中的代码 (需要 protoc, protoc-gen-go 和 protoc-gen-go-grpc)
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.1
+// source: pb/std_server.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NBTBlockService_PlaceNBTBlock_FullMethodName  = "/std_server.v1.NBTBlockService/PlaceNBTBlock"
+	NBTBlockService_PlaceNBTBlocks_FullMethodName = "/std_server.v1.NBTBlockService/PlaceNBTBlocks"
+)
+
+// NBTBlockServiceClient is the client API for NBTBlockService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NBTBlockService 提供 NBT 方块的放置
+type NBTBlockServiceClient interface {
+	// PlaceNBTBlock 放置单个 NBT 方块
+	PlaceNBTBlock(ctx context.Context, in *PlaceNBTBlockRequest, opts ...grpc.CallOption) (*PlaceNBTBlockResponse, error)
+	// PlaceNBTBlocks 按顺序放置多个 NBT 方块，
+	// 并在每得到一个放置结果时发送一条进度
+	PlaceNBTBlocks(ctx context.Context, in *PlaceNBTBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PlaceNBTBlocksProgress], error)
+}
+
+type nBTBlockServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNBTBlockServiceClient(cc grpc.ClientConnInterface) NBTBlockServiceClient {
+	return &nBTBlockServiceClient{cc}
+}
+
+func (c *nBTBlockServiceClient) PlaceNBTBlock(ctx context.Context, in *PlaceNBTBlockRequest, opts ...grpc.CallOption) (*PlaceNBTBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlaceNBTBlockResponse)
+	err := c.cc.Invoke(ctx, NBTBlockService_PlaceNBTBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nBTBlockServiceClient) PlaceNBTBlocks(ctx context.Context, in *PlaceNBTBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PlaceNBTBlocksProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NBTBlockService_ServiceDesc.Streams[0], NBTBlockService_PlaceNBTBlocks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PlaceNBTBlocksRequest, PlaceNBTBlocksProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NBTBlockService_PlaceNBTBlocksClient = grpc.ServerStreamingClient[PlaceNBTBlocksProgress]
+
+// NBTBlockServiceServer is the server API for NBTBlockService service.
+// All implementations must embed UnimplementedNBTBlockServiceServer
+// for forward compatibility.
+//
+// NBTBlockService 提供 NBT 方块的放置
+type NBTBlockServiceServer interface {
+	// PlaceNBTBlock 放置单个 NBT 方块
+	PlaceNBTBlock(context.Context, *PlaceNBTBlockRequest) (*PlaceNBTBlockResponse, error)
+	// PlaceNBTBlocks 按顺序放置多个 NBT 方块，
+	// 并在每得到一个放置结果时发送一条进度
+	PlaceNBTBlocks(*PlaceNBTBlocksRequest, grpc.ServerStreamingServer[PlaceNBTBlocksProgress]) error
+	mustEmbedUnimplementedNBTBlockServiceServer()
+}
+
+// UnimplementedNBTBlockServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNBTBlockServiceServer struct{}
+
+func (UnimplementedNBTBlockServiceServer) PlaceNBTBlock(context.Context, *PlaceNBTBlockRequest) (*PlaceNBTBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceNBTBlock not implemented")
+}
+func (UnimplementedNBTBlockServiceServer) PlaceNBTBlocks(*PlaceNBTBlocksRequest, grpc.ServerStreamingServer[PlaceNBTBlocksProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method PlaceNBTBlocks not implemented")
+}
+func (UnimplementedNBTBlockServiceServer) mustEmbedUnimplementedNBTBlockServiceServer() {}
+func (UnimplementedNBTBlockServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeNBTBlockServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NBTBlockServiceServer will
+// result in compilation errors.
+type UnsafeNBTBlockServiceServer interface {
+	mustEmbedUnimplementedNBTBlockServiceServer()
+}
+
+func RegisterNBTBlockServiceServer(s grpc.ServiceRegistrar, srv NBTBlockServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNBTBlockServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NBTBlockService_ServiceDesc, srv)
+}
+
+func _NBTBlockService_PlaceNBTBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceNBTBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NBTBlockServiceServer).PlaceNBTBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NBTBlockService_PlaceNBTBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NBTBlockServiceServer).PlaceNBTBlock(ctx, req.(*PlaceNBTBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NBTBlockService_PlaceNBTBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PlaceNBTBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NBTBlockServiceServer).PlaceNBTBlocks(m, &grpc.GenericServerStream[PlaceNBTBlocksRequest, PlaceNBTBlocksProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NBTBlockService_PlaceNBTBlocksServer = grpc.ServerStreamingServer[PlaceNBTBlocksProgress]
+
+// NBTBlockService_ServiceDesc is the grpc.ServiceDesc for NBTBlockService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NBTBlockService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "std_server.v1.NBTBlockService",
+	HandlerType: (*NBTBlockServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceNBTBlock",
+			Handler:    _NBTBlockService_PlaceNBTBlock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PlaceNBTBlocks",
+			Handler:       _NBTBlockService_PlaceNBTBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pb/std_server.proto",
+}
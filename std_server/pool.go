@@ -0,0 +1,126 @@
+package main
+
+import (
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+)
+
+// Bot 是工作池中的单个机器人
+type Bot struct {
+	name       string
+	supervisor *Supervisor
+	assigner   *nbt_assigner.NBTAssigner
+}
+
+// NewBot 基于 supervisor 和 assigner 创建并返回一个名为 name 的机器人。
+// assigner 应当使用 supervisor 所维护的连接的操作台
+func NewBot(name string, supervisor *Supervisor, assigner *nbt_assigner.NBTAssigner) *Bot {
+	return &Bot{
+		name:       name,
+		supervisor: supervisor,
+		assigner:   assigner,
+	}
+}
+
+// Name 返回机器人的名称
+func (b *Bot) Name() string {
+	return b.name
+}
+
+// BotPool 是共享同一个缓存命中系统的一组机器人。
+//
+// 请求总是被分派到空闲的机器人，因此多个机器人
+// 可以同时制作不同的 NBT 方块。正在重新连接的
+// 机器人会被移出轮转，直到它重新上线
+type BotPool struct {
+	bots []*Bot
+	// idle 是空闲的机器人，其容量与机器人的数量相同，
+	// 因此向其归还机器人永远不会阻塞
+	idle chan *Bot
+}
+
+// NewBotPool 基于 bots 创建并返回一个新的机器人工作池
+func NewBotPool(bots []*Bot) *BotPool {
+	p := &BotPool{
+		bots: bots,
+		idle: make(chan *Bot, len(bots)),
+	}
+	for _, bot := range bots {
+		p.idle <- bot
+	}
+	return p
+}
+
+// Bots 返回工作池中的全部机器人
+func (p *BotPool) Bots() []*Bot {
+	return p.bots
+}
+
+// Online 返回当前在线的机器人的数量
+func (p *BotPool) Online() (online int) {
+	for _, bot := range p.bots {
+		if _, err := bot.supervisor.Session(); err == nil {
+			online++
+		}
+	}
+	return
+}
+
+// Idle 返回当前未被占用的机器人的数量
+func (p *BotPool) Idle() int {
+	return len(p.idle)
+}
+
+// Acquire 占用一个空闲且在线的机器人，并返回它当前的连接。
+// 如果所有机器人都正忙，则等待其中一个被归还。
+//
+// 如果没有任何机器人在线，则返回 ErrReconnecting。
+// 使用完毕后，应当通过 Release 归还机器人
+func (p *BotPool) Acquire() (bot *Bot, session *Session, err error) {
+	for {
+		if p.Online() == 0 {
+			return nil, nil, ErrReconnecting
+		}
+		if bot, session, ok := p.acquire(); ok {
+			return bot, session, nil
+		}
+	}
+}
+
+// AcquireWait 与 Acquire 相同，但在没有任何机器人在线时，
+// 它会一直等待，直到某个机器人重新上线
+func (p *BotPool) AcquireWait() (bot *Bot, session *Session) {
+	for {
+		if bot, session, ok := p.acquire(); ok {
+			return bot, session
+		}
+	}
+}
+
+// acquire 取出一个空闲的机器人。如果该机器人
+// 不在线，则将它移出轮转，并使 ok 为假
+func (p *BotPool) acquire() (bot *Bot, session *Session, ok bool) {
+	bot = <-p.idle
+	session, err := bot.supervisor.Session()
+	if err != nil {
+		go p.park(bot)
+		return nil, nil, false
+	}
+	return bot, session, true
+}
+
+// Release 归还先前占用的机器人 bot。
+// 如果 bot 已经断开连接，则它会在重新上线后才回到轮转
+func (p *BotPool) Release(bot *Bot) {
+	if _, err := bot.supervisor.Session(); err != nil {
+		go p.park(bot)
+		return
+	}
+	p.idle <- bot
+}
+
+// park 等待正在重新连接的机器人 bot 重新上线，
+// 然后将其放回轮转
+func (p *BotPool) park(bot *Bot) {
+	<-bot.supervisor.Ready()
+	p.idle <- bot
+}
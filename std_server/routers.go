@@ -1,18 +1,65 @@
 package main
 
 import (
-	"fmt"
+	"log"
+	"net"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pterm/pterm"
 )
 
+// route 描述一个 HTTP API 及调用它默认所需的最低角色
+type route struct {
+	method  string
+	path    string
+	handler gin.HandlerFunc
+	role    string
+}
+
+// routes 是标准服务器提供的全部 HTTP API
+var routes = []route{
+	{http.MethodGet, "/", CheckAlive, RolePublic},
+	{http.MethodGet, "/metrics", Metrics, RoleUser},
+	{http.MethodGet, "/process_exit", ProcessExist, RoleAdmin},
+	{http.MethodPost, "/place_nbt_block", PlaceNBTBlock, RoleUser},
+	{http.MethodPost, "/place_nbt_blocks", PlaceNBTBlocks, RoleUser},
+	{http.MethodPost, "/register_filled_maps", RegisterFilledMaps, RoleUser},
+	{http.MethodPost, "/validate_nbt_block", ValidateNBTBlock, RoleUser},
+	{http.MethodPost, "/export_structure", ExportStructure, RoleUser},
+	{http.MethodGet, "/jobs", GetJobQueue, RoleUser},
+	{http.MethodPost, "/jobs", SubmitJob, RoleUser},
+	{http.MethodGet, "/jobs/:id", GetJob, RoleUser},
+	{http.MethodDelete, "/jobs/:id", CancelJob, RoleUser},
+	{http.MethodGet, "/cache", ListCache, RoleUser},
+	{http.MethodDelete, "/cache", PurgeCache, RoleAdmin},
+	{http.MethodDelete, "/cache/:kind/:hash", EvictCache, RoleAdmin},
+	{http.MethodPost, "/cache/evict_older_than", EvictCacheOlderThan, RoleAdmin},
+	{http.MethodPost, "/cache/evict_lru", EvictCacheLRU, RoleAdmin},
+}
+
+// routeKey 返回 API 在 AuthConfig.RouteRoles 中的键
+func routeKey(method string, path string) string {
+	return method + " " + path
+}
+
+// routeByKey 查找键为 key 的 API
+func routeByKey(key string) (r route, ok bool) {
+	for _, r := range routes {
+		if routeKey(r.method, r.path) == key {
+			return r, true
+		}
+	}
+	return route{}, false
+}
+
 func InitRouter() *gin.Engine {
 	router := gin.Default()
 
-	router.GET("/", CheckAlive)
-	router.GET("/process_exit", ProcessExist)
-	router.POST("/place_nbt_block", PlaceNBTBlock)
+	router.Use(AuthMiddleware(config.HTTP.Auth))
+	for _, r := range routes {
+		router.Handle(r.method, r.path, r.handler)
+	}
 	router.NoRoute(func(c *gin.Context) {
 		c.AbortWithStatus(http.StatusNotFound)
 	})
@@ -22,5 +69,19 @@ func InitRouter() *gin.Engine {
 
 func RunServer() {
 	router := InitRouter()
-	router.Run(fmt.Sprintf(":%d", *standardServerPort))
+
+	host, _, _ := net.SplitHostPort(config.HTTP.ListenAddress)
+	if ip := net.ParseIP(host); !config.HTTP.Auth.Enabled && (ip == nil || !ip.IsLoopback()) && host != "localhost" {
+		pterm.Warning.Printfln("HTTP API 未启用身份验证，且监听于 %s，网络中的任何人都可以调用它", config.HTTP.ListenAddress)
+	}
+
+	var err error
+	if len(config.HTTP.TLSCertFile) > 0 {
+		err = router.RunTLS(config.HTTP.ListenAddress, config.HTTP.TLSCertFile, config.HTTP.TLSKeyFile)
+	} else {
+		err = router.Run(config.HTTP.ListenAddress)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
 }
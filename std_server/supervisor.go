@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/client"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/pterm/pterm"
+)
+
+const (
+	// ReconnectMinBackoff 是重新登录失败后的初始等待时间
+	ReconnectMinBackoff = time.Second
+	// ReconnectMaxBackoff 是重新登录失败后的最长等待时间
+	ReconnectMaxBackoff = time.Minute
+)
+
+// ErrReconnecting 指示机器人与租赁服的连接已断开，
+// 并且正在重新连接
+var ErrReconnecting = errors.New("the bot is reconnecting to the rental server")
+
+// Session 是机器人与租赁服的单次连接
+type Session struct {
+	Client        *client.Client
+	Resources     *resources_control.Resources
+	GameInterface *game_interface.GameInterface
+	Console       *nbt_console.Console
+}
+
+// Closed 检查该连接是否已经断开
+func (s *Session) Closed() bool {
+	select {
+	case <-s.Resources.Closed():
+		return true
+	default:
+		return false
+	}
+}
+
+// Supervisor 维护机器人与租赁服的连接。
+// 当连接断开或机器人被踢出时，它会以指数退避的方式重新登录，
+// 并重建资源中心、游戏交互器和操作台
+type Supervisor struct {
+	mu *sync.RWMutex
+	// name 是机器人的名称，它仅用于日志
+	name   string
+	cfg    client.Config
+	center protocol.BlockPos
+	// session 是当前的连接
+	session *Session
+	// ready 在机器人在线时是已关闭的，
+	// 而在重新连接期间是未关闭的
+	ready chan struct{}
+}
+
+// NewSupervisor 根据登录配置 cfg 和操作台中心 center
+// 创建并返回一个维护名为 name 的机器人的 Supervisor
+func NewSupervisor(name string, cfg client.Config, center protocol.BlockPos) *Supervisor {
+	return &Supervisor{
+		mu:     new(sync.RWMutex),
+		name:   name,
+		cfg:    cfg,
+		center: center,
+		ready:  make(chan struct{}),
+	}
+}
+
+// login 登录到租赁服，并基于新的连接创建资源中心、游戏交互器和操作台
+func (s *Supervisor) login() (*Session, error) {
+	c, err := client.LoginRentalServer(s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("login: %v", err)
+	}
+
+	resources := resources_control.NewResourcesControl(c)
+	gameInterface := game_interface.NewGameInterface(resources)
+	err = requestPermission(gameInterface)
+	if err != nil {
+		_ = c.Conn().Close()
+		return nil, fmt.Errorf("login: %v", err)
+	}
+
+	console, err := nbt_console.NewConsole(gameInterface, s.center)
+	if err != nil {
+		_ = c.Conn().Close()
+		return nil, fmt.Errorf("login: %v", err)
+	}
+
+	return &Session{
+		Client:        c,
+		Resources:     resources,
+		GameInterface: gameInterface,
+		Console:       console,
+	}, nil
+}
+
+// Start 进行首次登录。如果租赁服提示机器人被踢出，
+// 则会立即重试，否则返回遇到的错误
+func (s *Supervisor) Start() (*Session, error) {
+	for {
+		session, err := s.login()
+		if err != nil {
+			if strings.Contains(fmt.Sprintf("%v", err), "netease.report.kick.hint") {
+				continue
+			}
+			return nil, fmt.Errorf("Start: %v", err)
+		}
+
+		s.mu.Lock()
+		s.session = session
+		close(s.ready)
+		s.mu.Unlock()
+
+		return session, nil
+	}
+}
+
+// Run 持续监视当前连接。连接断开后，它会不断尝试重新登录，
+// 并在成功后以新的连接调用 onReconnect，然后才将机器人标记
+// 为在线。
+//
+// Run 是阻塞的，它应当在 Start 成功后被调用
+func (s *Supervisor) Run(onReconnect func(session *Session)) {
+	for {
+		s.mu.RLock()
+		session := s.session
+		s.mu.RUnlock()
+		<-session.Resources.Closed()
+
+		s.mu.Lock()
+		s.ready = make(chan struct{})
+		s.mu.Unlock()
+
+		pterm.Warning.Printfln("[%s] 与租赁服的连接已断开，正在重新连接; err = %v", s.name, session.Resources.CloseError())
+		_ = session.Client.Conn().Close()
+
+		backoff := ReconnectMinBackoff
+		for {
+			newSession, err := s.login()
+			if err == nil {
+				session = newSession
+				break
+			}
+			pterm.Error.Printfln("[%s] 重新连接失败，将在 %v 后重试; err = %v", s.name, backoff, err)
+			time.Sleep(backoff)
+			backoff = min(backoff*2, ReconnectMaxBackoff)
+		}
+
+		onReconnect(session)
+
+		s.mu.Lock()
+		s.session = session
+		close(s.ready)
+		s.mu.Unlock()
+
+		pterm.Success.Printfln("[%s] 已重新连接到租赁服", s.name)
+	}
+}
+
+// Session 返回当前的连接。
+// 如果机器人正在重新连接，则返回 ErrReconnecting
+func (s *Supervisor) Session() (*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	select {
+	case <-s.ready:
+		return s.session, nil
+	default:
+		return nil, ErrReconnecting
+	}
+}
+
+// Ready 返回一个在机器人在线时被关闭的通道，
+// 可用于等待重新连接完成
+func (s *Supervisor) Ready() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ready
+}
+
+// WrapError 检查在 session 上执行的操作所返回的错误 err。
+// 如果 session 已经断开，则将 err 包装为 ErrReconnecting
+func (s *Supervisor) WrapError(session *Session, err error) error {
+	if err == nil || !session.Closed() {
+		return err
+	}
+	return fmt.Errorf("%w; err = %v", ErrReconnecting, err)
+}
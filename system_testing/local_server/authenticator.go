@@ -0,0 +1,116 @@
+package local_server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/bunker/auth"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/login"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/google/uuid"
+)
+
+// Authenticator 是一个不进行任何验证的 minecraft.Authenticator 实现。
+// 它自行签发一条可被本地服务器接受的链请求，
+// 从而使客户端无需验证服务器即可登录到本地服务器
+type Authenticator struct {
+	// Address 是本地服务器的地址
+	Address string
+	// BotName 是机器人的名称
+	BotName string
+}
+
+// NewAuthenticator 创建并返回一个新的 Authenticator，
+// 它将使名为 botName 的机器人登录到位于 address 的本地服务器
+func NewAuthenticator(address string, botName string) *Authenticator {
+	return &Authenticator{Address: address, BotName: botName}
+}
+
+// signToken 使用 key 签发一个载荷为 claims 的令牌
+func signToken(key *ecdsa.PrivateKey, claims map[string]any) (string, error) {
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("signToken: %v", err)
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Key: key, Algorithm: jose.ES384},
+		&jose.SignerOptions{
+			ExtraHeaders: map[jose.HeaderKey]any{
+				"x5u": base64.StdEncoding.EncodeToString(publicKey),
+			},
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("signToken: %v", err)
+	}
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		return "", fmt.Errorf("signToken: %v", err)
+	}
+	return token, nil
+}
+
+// GetAccess 实现 minecraft.Authenticator。
+// 它签发一条由两个令牌构成的链，其中第二个令牌
+// 持有客户端的公钥 publicKey 和机器人的身份信息
+func (a *Authenticator) GetAccess(ctx context.Context, publicKey []byte) (auth.AuthResponse, error) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return auth.AuthResponse{}, fmt.Errorf("GetAccess: %v", err)
+	}
+	identityKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return auth.AuthResponse{}, fmt.Errorf("GetAccess: %v", err)
+	}
+	identityPublicKey, err := x509.MarshalPKIXPublicKey(&identityKey.PublicKey)
+	if err != nil {
+		return auth.AuthResponse{}, fmt.Errorf("GetAccess: %v", err)
+	}
+
+	now := time.Now()
+	expiry := jwt.NewNumericDate(now.Add(time.Hour * 24))
+	notBefore := jwt.NewNumericDate(now.Add(-time.Hour))
+
+	rootToken, err := signToken(rootKey, map[string]any{
+		"iss":               "NetEase",
+		"exp":               expiry,
+		"nbf":               notBefore,
+		"identityPublicKey": base64.StdEncoding.EncodeToString(identityPublicKey),
+	})
+	if err != nil {
+		return auth.AuthResponse{}, fmt.Errorf("GetAccess: %v", err)
+	}
+	identityToken, err := signToken(identityKey, map[string]any{
+		"iss":               "NetEase",
+		"exp":               expiry,
+		"nbf":               notBefore,
+		"identityPublicKey": base64.StdEncoding.EncodeToString(publicKey),
+		"extraData": login.IdentityData{
+			Identity:    uuid.NewString(),
+			DisplayName: a.BotName,
+		},
+	})
+	if err != nil {
+		return auth.AuthResponse{}, fmt.Errorf("GetAccess: %v", err)
+	}
+
+	chain, _ := json.Marshal(map[string]any{
+		"chain": []string{rootToken, identityToken},
+	})
+	return auth.AuthResponse{
+		SuccessStates:  true,
+		RentalServerIP: a.Address,
+		ChainInfo:      string(chain),
+	}, nil
+}
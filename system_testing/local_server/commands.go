@@ -0,0 +1,643 @@
+package local_server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pterm/pterm"
+)
+
+// commandOutput 是单条命令的执行结果
+type commandOutput struct {
+	SuccessCount uint32
+	Message      protocol.CommandOutputMessage
+}
+
+// success 返回一个成功的执行结果
+func success(message string, parameters ...string) commandOutput {
+	return commandOutput{
+		SuccessCount: 1,
+		Message: protocol.CommandOutputMessage{
+			Success:    true,
+			Message:    message,
+			Parameters: parameters,
+		},
+	}
+}
+
+// failure 返回一个失败的执行结果
+func failure(message string, parameters ...string) commandOutput {
+	return commandOutput{
+		Message: protocol.CommandOutputMessage{
+			Message:    message,
+			Parameters: parameters,
+		},
+	}
+}
+
+// commandContext 是命令的执行环境
+type commandContext struct {
+	player   *player
+	position mgl32.Vec3
+}
+
+// commandReader 是命令行的简单词法分析器
+type commandReader struct {
+	line string
+	ptr  int
+}
+
+// skipSpace ..
+func (r *commandReader) skipSpace() {
+	for r.ptr < len(r.line) && r.line[r.ptr] == ' ' {
+		r.ptr++
+	}
+}
+
+// Next 读取下一个参数。引号内的内容和配对的方括号
+// 或花括号内的内容被视为同一个参数的一部分
+func (r *commandReader) Next() string {
+	r.skipSpace()
+	start := r.ptr
+	depth, inQuote := 0, false
+
+	for ; r.ptr < len(r.line); r.ptr++ {
+		c := r.line[r.ptr]
+		switch {
+		case inQuote:
+			if c == '\\' {
+				r.ptr++
+			} else if c == '"' {
+				inQuote = false
+			}
+		case c == '"':
+			inQuote = true
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ' ' && depth <= 0:
+			return r.line[start:r.ptr]
+		}
+	}
+
+	return r.line[start:]
+}
+
+// Peek 返回下一个参数，但不移动读取位置
+func (r *commandReader) Peek() string {
+	ptr := r.ptr
+	result := r.Next()
+	r.ptr = ptr
+	return result
+}
+
+// Rest 返回尚未读取的全部内容
+func (r *commandReader) Rest() string {
+	r.skipSpace()
+	result := r.line[r.ptr:]
+	r.ptr = len(r.line)
+	return strings.TrimSpace(result)
+}
+
+// floorPos 返回 pos 所在的方块坐标
+func floorPos(pos mgl32.Vec3) protocol.BlockPos {
+	return protocol.BlockPos{
+		int32(math.Floor(float64(pos[0]))),
+		int32(math.Floor(float64(pos[1]))),
+		int32(math.Floor(float64(pos[2]))),
+	}
+}
+
+// parseCoordinate 以 base 为基准解析单个坐标分量 token
+func parseCoordinate(token string, base float32) (float32, error) {
+	if strings.HasPrefix(token, "~") {
+		if len(token) == 1 {
+			return base, nil
+		}
+		offset, err := strconv.ParseFloat(token[1:], 32)
+		if err != nil {
+			return 0, fmt.Errorf("parseCoordinate: %v", err)
+		}
+		return base + float32(offset), nil
+	}
+	value, err := strconv.ParseFloat(token, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parseCoordinate: %v", err)
+	}
+	return float32(value), nil
+}
+
+// readPosition 从 r 读取三个坐标分量
+func (ctx commandContext) readPosition(r *commandReader) (mgl32.Vec3, error) {
+	var result mgl32.Vec3
+	for i := range 3 {
+		value, err := parseCoordinate(r.Next(), ctx.position[i])
+		if err != nil {
+			return mgl32.Vec3{}, fmt.Errorf("readPosition: %v", err)
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+// readBlockPos 从 r 读取三个坐标分量，并返回它们所在的方块坐标
+func (ctx commandContext) readBlockPos(r *commandReader) (protocol.BlockPos, error) {
+	pos, err := ctx.readPosition(r)
+	if err != nil {
+		return protocol.BlockPos{}, fmt.Errorf("readBlockPos: %v", err)
+	}
+	return floorPos(pos), nil
+}
+
+// selectorMatch 检查目标选择器 selector 是否选中了玩家 p。
+// 本地服务器中只有机器人自己，因此只检查 name 参数
+func selectorMatch(selector string, p *player) bool {
+	if !strings.HasPrefix(selector, "@") {
+		return strings.Trim(selector, `"`) == p.name
+	}
+	start, end := strings.Index(selector, "["), strings.LastIndex(selector, "]")
+	if start == -1 || end <= start {
+		return true
+	}
+	for _, argument := range strings.Split(selector[start+1:end], ",") {
+		key, value, ok := strings.Cut(argument, "=")
+		if !ok || strings.TrimSpace(key) != "name" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if strings.HasPrefix(value, "!") {
+			return value[1:] != p.name
+		}
+		return value == p.name
+	}
+	return true
+}
+
+// structureName 返回结构名 name 的完整形式
+func structureName(name string) string {
+	name = strings.Trim(name, `"`)
+	if !strings.Contains(name, ":") {
+		name = "mystructure:" + name
+	}
+	return name
+}
+
+// logCommand 在需要时打印玩家 p 发送的命令
+func (s *Server) logCommand(p *player, command string) {
+	if s.cfg.Verbose {
+		pterm.Info.Printfln("[%s] %s", p.name, command)
+	}
+}
+
+// execute 以玩家 p 的身份执行命令 command
+func (p *player) execute(command string) commandOutput {
+	ctx := commandContext{player: p, position: p.position}
+	return ctx.execute(strings.TrimPrefix(strings.TrimSpace(command), "/"))
+}
+
+// execute 在 ctx 下执行命令 command
+func (ctx commandContext) execute(command string) commandOutput {
+	r := &commandReader{line: command}
+	name := strings.ToLower(r.Next())
+
+	switch name {
+	case "":
+		return failure("commands.generic.syntax")
+	case "execute":
+		return ctx.commandExecute(r)
+	case "tp", "teleport":
+		return ctx.commandTeleport(r)
+	case "testforblock":
+		return ctx.commandTestForBlock(r)
+	case "setblock":
+		return ctx.commandSetBlock(r)
+	case "fill":
+		return ctx.commandFill(r)
+	case "structure":
+		return ctx.commandStructure(r)
+	case "replaceitem":
+		return ctx.commandReplaceitem(r)
+	case "give":
+		return ctx.commandGive(r)
+	case "clear":
+		return ctx.commandClear(r)
+	case "enchant":
+		return ctx.commandEnchant(r)
+	case "querytarget":
+		return ctx.commandQuerytarget(r)
+	case "list":
+		return success("commands.players.list", "1", "1", ctx.player.name)
+	case "gamemode", "gamerule", "op", "deop", "say", "tell", "msg", "w",
+		"tellraw", "titleraw", "title", "time", "weather", "difficulty",
+		"effect", "kill", "setworldspawn", "spawnpoint", "tickingarea":
+		return success("commands.generic.success")
+	}
+
+	return failure("commands.generic.unknown", name)
+}
+
+// commandExecute 实现 execute 命令的 as、at、positioned、in 和 run 子命令
+func (ctx commandContext) commandExecute(r *commandReader) commandOutput {
+	for {
+		switch subcommand := r.Next(); subcommand {
+		case "as":
+			if !selectorMatch(r.Next(), ctx.player) {
+				return failure("commands.generic.noTargetMatch")
+			}
+		case "at":
+			if !selectorMatch(r.Next(), ctx.player) {
+				return failure("commands.generic.noTargetMatch")
+			}
+			ctx.position = ctx.player.position
+		case "positioned":
+			pos, err := ctx.readPosition(r)
+			if err != nil {
+				return failure("commands.generic.syntax")
+			}
+			ctx.position = pos
+		case "in":
+			_ = r.Next()
+		case "run":
+			return ctx.execute(r.Rest())
+		default:
+			return failure("commands.generic.syntax", subcommand)
+		}
+	}
+}
+
+// commandTeleport 实现 tp 命令
+func (ctx commandContext) commandTeleport(r *commandReader) commandOutput {
+	if strings.HasPrefix(r.Peek(), "@") || ctx.player.name == strings.Trim(r.Peek(), `"`) {
+		if !selectorMatch(r.Next(), ctx.player) {
+			return failure("commands.generic.noTargetMatch")
+		}
+	}
+	pos, err := ctx.readPosition(r)
+	if err != nil {
+		return failure("commands.generic.syntax")
+	}
+	ctx.player.teleport(pos)
+	return success("commands.tp.success.coordinates", ctx.player.name)
+}
+
+// readBlock 从 r 读取方块名称和可选的方块状态
+func readBlock(r *commandReader) (*Block, bool) {
+	name := r.Next()
+	states := map[string]any{}
+	if strings.HasPrefix(r.Peek(), "[") {
+		states = utils.ParseBlockStatesString(r.Next())
+		if states == nil {
+			return nil, false
+		}
+	}
+	return NewBlock(name, states)
+}
+
+// sameBlock 检查 a 和 b 是否具有相同的名称和方块状态
+func sameBlock(a *Block, b *Block) bool {
+	if a.IsAir() || b.IsAir() {
+		return a.IsAir() && b.IsAir()
+	}
+	return a.Name == b.Name && utils.MarshalBlockStates(a.States) == utils.MarshalBlockStates(b.States)
+}
+
+// bedPartner 返回位于 pos 处的床的方块 b 的另一半所在的位置。
+// 如果 b 不是床，或者它的另一半不存在，则 ok 为假
+func bedPartner(world *World, b *Block, pos protocol.BlockPos) (partner protocol.BlockPos, ok bool) {
+	if b.IsAir() || b.Name != "minecraft:bed" {
+		return partner, false
+	}
+	direction, _ := b.States["direction"].(int32)
+	headPieceBit, _ := b.States["head_piece_bit"].(byte)
+
+	// 床头位于床尾的 bedFacing 偏移处
+	facing := bedFacing[direction&3]
+	if headPieceBit != 0 {
+		partner = protocol.BlockPos{pos[0] - facing[0], pos[1] - facing[1], pos[2] - facing[2]}
+	} else {
+		partner = protocol.BlockPos{pos[0] + facing[0], pos[1] + facing[1], pos[2] + facing[2]}
+	}
+
+	other := world.Block(partner)
+	if other.IsAir() || other.Name != b.Name {
+		return partner, false
+	}
+	otherHeadPieceBit, _ := other.States["head_piece_bit"].(byte)
+	return partner, (otherHeadPieceBit != 0) != (headPieceBit != 0)
+}
+
+// commandTestForBlock 实现 testforblock 命令
+func (ctx commandContext) commandTestForBlock(r *commandReader) commandOutput {
+	pos, err := ctx.readBlockPos(r)
+	if err != nil {
+		return failure("commands.generic.syntax")
+	}
+	name := normalizeName(r.Next())
+	var states map[string]any
+	if strings.HasPrefix(r.Peek(), "[") {
+		states = utils.ParseBlockStatesString(r.Next())
+	}
+
+	current := ctx.player.server.world.Block(pos)
+	currentName := "minecraft:air"
+	if !current.IsAir() {
+		currentName = current.Name
+	}
+	if currentName != name {
+		return failure("commands.testforblock.failed.tile")
+	}
+	for key, value := range states {
+		if current == nil || fmt.Sprint(current.States[key]) != fmt.Sprint(value) {
+			return failure("commands.testforblock.failed.data")
+		}
+	}
+	return success("commands.testforblock.success")
+}
+
+// commandSetBlock 实现 setblock 命令
+func (ctx commandContext) commandSetBlock(r *commandReader) commandOutput {
+	world := ctx.player.server.world
+
+	pos, err := ctx.readBlockPos(r)
+	if err != nil {
+		return failure("commands.generic.syntax")
+	}
+	newBlock, found := readBlock(r)
+	if !found {
+		return failure("commands.setblock.notFound")
+	}
+
+	current := world.Block(pos)
+	if r.Next() == "keep" && !current.IsAir() {
+		return failure("commands.setblock.noChange")
+	}
+	if sameBlock(current, newBlock) {
+		return failure("commands.setblock.noChange")
+	}
+	// 床的一半被替换时，另一半也会被破坏
+	if partner, ok := bedPartner(world, current, pos); ok {
+		world.SetBlock(partner, nil)
+	}
+	// 只改变方块状态时，方块实体数据会被保留
+	if !current.IsAir() && current.Name == newBlock.Name {
+		newBlock.Items = current.Items
+		newBlock.CustomName = current.CustomName
+		newBlock.DisabledSlots = current.DisabledSlots
+		newBlock.NBT = current.NBT
+	}
+
+	world.SetBlock(pos, newBlock)
+	return success("commands.setblock.success")
+}
+
+// commandFill 实现 fill 命令
+func (ctx commandContext) commandFill(r *commandReader) commandOutput {
+	start, err := ctx.readBlockPos(r)
+	if err != nil {
+		return failure("commands.generic.syntax")
+	}
+	end, err := ctx.readBlockPos(r)
+	if err != nil {
+		return failure("commands.generic.syntax")
+	}
+	newBlock, found := readBlock(r)
+	if !found {
+		return failure("commands.fill.failed")
+	}
+
+	count, err := ctx.player.server.world.Fill(start, end, newBlock)
+	if err != nil {
+		return failure("commands.fill.tooManyBlocks")
+	}
+	return success("commands.fill.success", strconv.Itoa(count))
+}
+
+// commandStructure 实现 structure 命令的 save、load 和 delete 子命令
+func (ctx commandContext) commandStructure(r *commandReader) commandOutput {
+	world := ctx.player.server.world
+	mode := r.Next()
+	name := structureName(r.Next())
+
+	switch mode {
+	case "save":
+		start, err := ctx.readBlockPos(r)
+		if err != nil {
+			return failure("commands.generic.syntax")
+		}
+		end, err := ctx.readBlockPos(r)
+		if err != nil {
+			return failure("commands.generic.syntax")
+		}
+		if err = world.SaveStructure(name, start, end); err != nil {
+			return failure("commands.structure.save.tooBig")
+		}
+		return success("commands.structure.save.success", name)
+	case "load":
+		pos, err := ctx.readBlockPos(r)
+		if err != nil {
+			return failure("commands.generic.syntax")
+		}
+		if !world.LoadStructure(name, pos) {
+			return failure("commands.structure.load.notFound", name)
+		}
+		return success("commands.structure.load.success", name)
+	case "delete":
+		if !world.DeleteStructure(name) {
+			return failure("commands.structure.delete.notFound", name)
+		}
+		return success("commands.structure.delete.success", name)
+	}
+
+	return failure("commands.generic.syntax", mode)
+}
+
+// readItem 从 r 读取物品名称、数量、数据值和物品组件
+func (ctx commandContext) readItem(r *commandReader) (item protocol.ItemStack, found bool) {
+	name := r.Next()
+	if name == "keep" || name == "destroy" {
+		name = r.Next()
+	}
+
+	count, metadata := uint64(1), uint64(0)
+	if token := r.Peek(); len(token) > 0 && !strings.HasPrefix(token, "{") {
+		count, _ = strconv.ParseUint(r.Next(), 10, 16)
+	}
+	if token := r.Peek(); len(token) > 0 && !strings.HasPrefix(token, "{") {
+		metadata, _ = strconv.ParseUint(r.Next(), 10, 32)
+	}
+
+	if !ctx.player.server.items.CanGetByCommand(name) {
+		return protocol.ItemStack{}, false
+	}
+	return ctx.player.server.items.NewItem(name, uint16(count), uint32(metadata), r.Rest())
+}
+
+// commandReplaceitem 实现 replaceitem 命令
+func (ctx commandContext) commandReplaceitem(r *commandReader) commandOutput {
+	p := ctx.player
+
+	switch r.Next() {
+	case "entity":
+		if !selectorMatch(r.Next(), p) {
+			return failure("commands.generic.noTargetMatch")
+		}
+		path := r.Next()
+		slot, err := strconv.Atoi(r.Next())
+		if err != nil {
+			return failure("commands.generic.syntax")
+		}
+		switch path {
+		case "slot.hotbar":
+		case "slot.inventory":
+			slot += 9
+		case "slot.weapon.mainhand":
+			slot = int(p.heldSlot)
+		default:
+			return failure("commands.replaceitem.badSlotNumber")
+		}
+		if slot < 0 || slot >= InventorySize {
+			return failure("commands.replaceitem.badSlotNumber")
+		}
+		item, found := ctx.readItem(r)
+		if !found {
+			return failure("commands.replaceitem.failed")
+		}
+		p.setInventorySlot(slot, item)
+		return success("commands.replaceitem.success.entity")
+	case "block":
+		pos, err := ctx.readBlockPos(r)
+		if err != nil {
+			return failure("commands.generic.syntax")
+		}
+		if r.Next() != "slot.container" {
+			return failure("commands.replaceitem.badSlotNumber")
+		}
+		slot, err := strconv.Atoi(r.Next())
+		if err != nil {
+			return failure("commands.generic.syntax")
+		}
+		b := p.server.world.Block(pos)
+		info, ok := containerInfo{}, false
+		if !b.IsAir() {
+			info, ok = containerInfoOf(b)
+		}
+		if !ok || b.Items == nil || slot < 0 || slot >= info.Size {
+			return failure("commands.replaceitem.noContainer")
+		}
+		item, found := ctx.readItem(r)
+		if !found {
+			return failure("commands.replaceitem.failed")
+		}
+		p.setContainerSlot(pos, b, byte(slot), item)
+		return success("commands.replaceitem.success")
+	}
+
+	return failure("commands.generic.syntax")
+}
+
+// commandGive 实现 give 命令
+func (ctx commandContext) commandGive(r *commandReader) commandOutput {
+	p := ctx.player
+	if !selectorMatch(r.Next(), p) {
+		return failure("commands.generic.noTargetMatch")
+	}
+	item, found := ctx.readItem(r)
+	if !found {
+		return failure("commands.give.item.notFound")
+	}
+	for slot := range InventorySize {
+		if p.inventory[slot].Stack.NetworkID == 0 {
+			p.setInventorySlot(slot, item)
+			return success("commands.give.success")
+		}
+	}
+	return failure("commands.give.failed")
+}
+
+// commandClear 实现 clear 命令
+func (ctx commandContext) commandClear(r *commandReader) commandOutput {
+	p := ctx.player
+	if target := r.Next(); len(target) > 0 && !selectorMatch(target, p) {
+		return failure("commands.generic.noTargetMatch")
+	}
+
+	count := 0
+	for slot := range InventorySize {
+		if p.inventory[slot].Stack.NetworkID != 0 {
+			count += int(p.inventory[slot].Stack.Count)
+		}
+		p.inventory[slot] = protocol.ItemInstance{}
+	}
+	p.syncInventory()
+
+	if count == 0 {
+		return failure("commands.clear.failure.no.items", p.name)
+	}
+	return success("commands.clear.success", p.name, strconv.Itoa(count))
+}
+
+// commandEnchant 实现 enchant 命令。
+// 附魔 ID 只能以数字的形式给出
+func (ctx commandContext) commandEnchant(r *commandReader) commandOutput {
+	p := ctx.player
+	if !selectorMatch(r.Next(), p) {
+		return failure("commands.generic.noTargetMatch")
+	}
+	id, err := strconv.Atoi(r.Next())
+	if err != nil {
+		return failure("commands.enchant.notFound")
+	}
+	level := 1
+	if token := r.Next(); len(token) > 0 {
+		if level, err = strconv.Atoi(token); err != nil {
+			return failure("commands.generic.syntax")
+		}
+	}
+
+	item := cloneItem(p.inventory[p.heldSlot]).Stack
+	if item.NetworkID == 0 {
+		return failure("commands.enchant.noItem")
+	}
+	if item.NBTData == nil {
+		item.NBTData = make(map[string]any)
+	}
+	ench, _ := item.NBTData["ench"].([]any)
+	item.NBTData["ench"] = append(ench, map[string]any{
+		"id":  int16(id),
+		"lvl": int16(level),
+	})
+	p.setInventorySlot(int(p.heldSlot), item)
+
+	return success("commands.enchant.success")
+}
+
+// commandQuerytarget 实现 querytarget 命令
+func (ctx commandContext) commandQuerytarget(r *commandReader) commandOutput {
+	p := ctx.player
+	if !selectorMatch(r.Next(), p) {
+		return failure("commands.generic.noTargetMatch")
+	}
+
+	result, _ := json.Marshal([]map[string]any{
+		{
+			"dimension": 0,
+			"id":        p.entityID,
+			"position": map[string]float32{
+				"x": p.position[0],
+				"y": p.position[1] + 1.62,
+				"z": p.position[2],
+			},
+			"uniqueId": p.uniqueID.String(),
+			"yRot":     0,
+		},
+	})
+	return success("commands.querytarget.success", string(result))
+}
@@ -0,0 +1,312 @@
+package local_server
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/google/uuid"
+)
+
+// 本地服务器下发的配方的网络 ID
+const (
+	// RecipeNetworkIDFireworks 是烟花相关合成的特殊配方的网络 ID
+	RecipeNetworkIDFireworks uint32 = iota + 1
+	// RecipeNetworkIDSuspiciousStew 是使用矢车菊合成谜之炖菜的配方的网络 ID
+	RecipeNetworkIDSuspiciousStew
+	// RecipeNetworkIDSmithingTrim 是锻造台盔甲纹饰配方的网络 ID
+	RecipeNetworkIDSmithingTrim
+)
+
+// shapelessRecipe 是本地服务器支持的工作台无序配方。
+// 它的每种材料都只需要一个，且产物只有一个
+type shapelessRecipe struct {
+	input          []string
+	output         string
+	outputMetadata uint32
+}
+
+// shapelessRecipes 是本地服务器支持的全部无序配方，
+// 其键是配方的网络 ID
+var shapelessRecipes = map[uint32]shapelessRecipe{
+	RecipeNetworkIDSuspiciousStew: {
+		input: []string{
+			"minecraft:bowl",
+			"minecraft:brown_mushroom",
+			"minecraft:red_mushroom",
+			"minecraft:cornflower",
+		},
+		output:         "minecraft:suspicious_stew",
+		outputMetadata: 1,
+	},
+}
+
+// fireworkRocketResultCount 是单次合成得到的烟花火箭数量
+const fireworkRocketResultCount = 3
+
+// dyeNameToColor 是染料物品名到其颜色的映射
+var dyeNameToColor = make(map[string]byte)
+
+// shapeItemToFireworkType 是合成烟花之星时
+// 使用的形状物品到烟花之星形状的映射
+var shapeItemToFireworkType = make(map[mapping.FireworkShapeItem]byte)
+
+// templateNameToTrimPattern 是锻造模板物品名到盔甲纹饰图案的映射
+var templateNameToTrimPattern = make(map[string]string)
+
+// itemNameToTrimMaterial 是纹饰材料物品名到盔甲纹饰材料的映射
+var itemNameToTrimMaterial = make(map[string]string)
+
+func init() {
+	for color, name := range mapping.BannerColorToDyeName {
+		dyeNameToColor[name] = byte(color)
+	}
+	for fireworkType, item := range mapping.FireworkTypeToShapeItem {
+		shapeItemToFireworkType[item] = fireworkType
+	}
+	for pattern, name := range mapping.ArmorTrimPatternToTemplateName {
+		templateNameToTrimPattern[name] = pattern
+	}
+	for material, name := range mapping.ArmorTrimMaterialToItemName {
+		itemNameToTrimMaterial[name] = material
+	}
+}
+
+// sendCraftingData 向客户端下发本地服务器支持的全部配方
+func (p *player) sendCraftingData() {
+	items := p.server.items
+	recipes := []protocol.Recipe{
+		&protocol.MultiRecipe{
+			UUID:            uuid.MustParse(mapping.MultiRecipeFireworks),
+			RecipeNetworkID: RecipeNetworkIDFireworks,
+		},
+		&protocol.SmithingTrimRecipe{
+			RecipeNetworkID: RecipeNetworkIDSmithingTrim,
+			RecipeID:        "minecraft:smithing_armor_trim",
+			Template: protocol.ItemDescriptorCount{
+				Descriptor: &protocol.ItemTagItemDescriptor{Tag: "minecraft:trim_templates"},
+				Count:      1,
+			},
+			Base: protocol.ItemDescriptorCount{
+				Descriptor: &protocol.ItemTagItemDescriptor{Tag: "minecraft:trimmable_armors"},
+				Count:      1,
+			},
+			Addition: protocol.ItemDescriptorCount{
+				Descriptor: &protocol.ItemTagItemDescriptor{Tag: "minecraft:trim_materials"},
+				Count:      1,
+			},
+			Block: "smithing_table",
+		},
+	}
+
+	for networkID, recipe := range shapelessRecipes {
+		input := make([]protocol.ItemDescriptorCount, 0, len(recipe.input))
+		for _, name := range recipe.input {
+			itemNetworkID, _ := items.NetworkID(name)
+			input = append(input, protocol.ItemDescriptorCount{
+				Descriptor: &protocol.DefaultItemDescriptor{NetworkID: int16(itemNetworkID)},
+				Count:      1,
+			})
+		}
+		output, _ := items.NewItem(recipe.output, 1, recipe.outputMetadata, "")
+		recipes = append(recipes, &protocol.ShapelessRecipe{
+			RecipeID:        fmt.Sprintf("local_server:recipe_%d", networkID),
+			Input:           input,
+			Output:          []protocol.ItemStack{output},
+			Block:           "crafting_table",
+			RecipeNetworkID: networkID,
+		})
+	}
+
+	_ = p.conn.WritePacket(&packet.CraftingData{
+		Recipes:      recipes,
+		ClearRecipes: true,
+	})
+}
+
+// craftingGrid 按槽位顺序返回合成栏中的全部物品
+func (p *player) craftingGrid() (result []protocol.ItemInstance) {
+	keys := make([]slotKey, 0)
+	for key, item := range p.ui {
+		if key.ContainerID == protocol.ContainerCraftingInput && item.Stack.NetworkID != 0 {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a slotKey, b slotKey) int {
+		return int(a.Slot) - int(b.Slot)
+	})
+	for _, key := range keys {
+		result = append(result, p.ui[key])
+	}
+	return
+}
+
+// craft 使用网络 ID 为 recipeNetworkID 的配方进行合成，
+// 并将结果放入合成输出槽位
+func (t *stackTransaction) craft(recipeNetworkID uint32) error {
+	p := t.player
+	containerType := byte(protocol.ContainerTypeWorkbench)
+	if recipeNetworkID == RecipeNetworkIDSmithingTrim {
+		containerType = protocol.ContainerTypeSmithingTable
+	}
+	if p.window == nil || p.window.ContainerType != containerType {
+		return fmt.Errorf("craft: The block used by recipe %d was not opened", recipeNetworkID)
+	}
+
+	var result protocol.ItemStack
+	var err error
+	switch recipeNetworkID {
+	case RecipeNetworkIDSmithingTrim:
+		result, err = p.craftTrim()
+	case RecipeNetworkIDFireworks:
+		result, err = p.craftFireworks(p.craftingGrid())
+	default:
+		recipe, ok := shapelessRecipes[recipeNetworkID]
+		if !ok {
+			err = fmt.Errorf("Unknown recipe %d", recipeNetworkID)
+			break
+		}
+		result, err = p.craftShapeless(recipe, p.craftingGrid())
+	}
+	if err != nil {
+		return fmt.Errorf("craft: %v", err)
+	}
+
+	t.store(slotKey{ContainerID: protocol.ContainerCreatedOutput}, protocol.ItemInstance{
+		StackNetworkID: p.server.newStackNetworkID(),
+		Stack:          result,
+	})
+	return nil
+}
+
+// craftFireworks 使用合成栏中的物品 grid 合成烟花火箭或烟花之星，
+// 或为烟花之星添加淡出颜色
+func (p *player) craftFireworks(grid []protocol.ItemInstance) (result protocol.ItemStack, err error) {
+	var paper, gunpowder int
+	var stars []map[string]any
+	var colors []byte
+	var trail, flicker byte
+	fireworkType := mapping.FireworkTypeSmallBall
+
+	for _, item := range grid {
+		name, _ := p.server.items.Name(item.Stack.NetworkID)
+		shapeItem := mapping.FireworkShapeItem{Name: name, Metadata: int16(item.Stack.MetadataValue)}
+
+		switch name {
+		case "minecraft:paper":
+			paper++
+		case "minecraft:gunpowder":
+			gunpowder++
+		case "minecraft:firework_star":
+			star, _ := item.Stack.NBTData["FireworksItem"].(map[string]any)
+			stars = append(stars, star)
+		case "minecraft:diamond":
+			trail = 1
+		case "minecraft:glowstone_dust":
+			flicker = 1
+		default:
+			if color, ok := dyeNameToColor[name]; ok {
+				colors = append(colors, color)
+			} else if shape, ok := shapeItemToFireworkType[shapeItem]; ok {
+				fireworkType = shape
+			} else {
+				return protocol.ItemStack{}, fmt.Errorf("craftFireworks: Unexpected ingredient %s", name)
+			}
+		}
+	}
+
+	switch {
+	case paper == 1 && gunpowder >= int(mapping.FireworkMinFlight) && gunpowder <= int(mapping.FireworkMaxFlight):
+		explosions := make([]any, 0)
+		for _, star := range stars {
+			if star != nil {
+				explosions = append(explosions, utils.DeepCopyNBT(star))
+			}
+		}
+		result, _ = p.server.items.NewItem("minecraft:firework_rocket", fireworkRocketResultCount, 0, "")
+		result.NBTData = map[string]any{
+			"Fireworks": map[string]any{
+				"Explosions": explosions,
+				"Flight":     byte(gunpowder),
+			},
+		}
+	case paper == 0 && gunpowder == 1 && len(stars) == 0 && len(colors) > 0:
+		result, _ = p.server.items.NewItem("minecraft:firework_star", 1, 0, "")
+		result.NBTData = map[string]any{
+			"FireworksItem": map[string]any{
+				"FireworkColor":   colors,
+				"FireworkFade":    []byte{},
+				"FireworkFlicker": flicker,
+				"FireworkTrail":   trail,
+				"FireworkType":    fireworkType,
+			},
+		}
+	case paper == 0 && gunpowder == 0 && len(stars) == 1 && stars[0] != nil && len(colors) > 0:
+		explosion := utils.DeepCopyNBT(stars[0])
+		explosion["FireworkFade"] = colors
+		result, _ = p.server.items.NewItem("minecraft:firework_star", 1, 0, "")
+		result.NBTData = map[string]any{"FireworksItem": explosion}
+	default:
+		return protocol.ItemStack{}, fmt.Errorf("craftFireworks: Ingredients do not match any firework recipe")
+	}
+
+	return result, nil
+}
+
+// craftShapeless 使用合成栏中的物品 grid 按无序配方 recipe 进行合成
+func (p *player) craftShapeless(recipe shapelessRecipe, grid []protocol.ItemInstance) (result protocol.ItemStack, err error) {
+	names := make([]string, 0, len(grid))
+	for _, item := range grid {
+		name, _ := p.server.items.Name(item.Stack.NetworkID)
+		names = append(names, name)
+	}
+
+	want := slices.Clone(recipe.input)
+	slices.Sort(names)
+	slices.Sort(want)
+	if !slices.Equal(names, want) {
+		return protocol.ItemStack{}, fmt.Errorf("craftShapeless: Ingredients %v do not match the recipe", names)
+	}
+
+	result, _ = p.server.items.NewItem(recipe.output, 1, recipe.outputMetadata, "")
+	return result, nil
+}
+
+// craftTrim 使用锻造台中的锻造模板和纹饰材料为盔甲添加纹饰
+func (p *player) craftTrim() (result protocol.ItemStack, err error) {
+	items := p.server.items
+	template := p.ui[slotKey{ContainerID: protocol.ContainerSmithingTableTemplate, Slot: 53}].Stack
+	base := p.ui[slotKey{ContainerID: protocol.ContainerSmithingTableInput, Slot: 51}].Stack
+	addition := p.ui[slotKey{ContainerID: protocol.ContainerSmithingTableMaterial, Slot: 52}].Stack
+
+	templateName, _ := items.Name(template.NetworkID)
+	baseName, _ := items.Name(base.NetworkID)
+	additionName, _ := items.Name(addition.NetworkID)
+
+	pattern, ok := templateNameToTrimPattern[templateName]
+	if !ok {
+		return protocol.ItemStack{}, fmt.Errorf("craftTrim: Unexpected template %q", templateName)
+	}
+	material, ok := itemNameToTrimMaterial[additionName]
+	if !ok {
+		return protocol.ItemStack{}, fmt.Errorf("craftTrim: Unexpected material %q", additionName)
+	}
+	if !mapping.TrimmableArmor[baseName] {
+		return protocol.ItemStack{}, fmt.Errorf("craftTrim: %q can not be trimmed", baseName)
+	}
+
+	result = utils.DeepCopyItemStack(base)
+	result.Count = 1
+	if result.NBTData == nil {
+		result.NBTData = make(map[string]any)
+	}
+	result.NBTData["Trim"] = map[string]any{
+		"Material": material,
+		"Pattern":  pattern,
+	}
+	return result, nil
+}
@@ -0,0 +1,246 @@
+package local_server
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/mcstructure"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+)
+
+// bedFacing 是床头相对于床尾的偏移，
+// 其索引是床的朝向 (0 为南，1 为西，2 为北，3 为东)
+var bedFacing = []protocol.BlockPos{
+	{0, 0, 1},
+	{-1, 0, 0},
+	{0, 0, -1},
+	{1, 0, 0},
+}
+
+// chiseledBookshelfFace 是雕纹书架正面所对应的方块面，
+// 其索引是雕纹书架的朝向
+var chiseledBookshelfFace = []int32{3, 4, 2, 5}
+
+// bookshelfItems 是可以放入雕纹书架的物品
+var bookshelfItems = map[string]bool{
+	"minecraft:book":           true,
+	"minecraft:writable_book":  true,
+	"minecraft:written_book":   true,
+	"minecraft:enchanted_book": true,
+	"minecraft:knowledge_book": true,
+}
+
+// dyeNameToLeatherColor 是染料物品名到皮革盔甲
+// 在被该染料染色的炼药锅中染色后的颜色的映射
+var dyeNameToLeatherColor = make(map[string][3]uint8)
+
+func init() {
+	for color, name := range mapping.LeatherArmorColorToDyeName {
+		dyeNameToLeatherColor[name] = color
+	}
+}
+
+// yawDirection 将偏航角 yaw 转换为 0 到 3 之间的水平朝向，
+// 其中 0 为南，1 为西，2 为北，3 为东
+func yawDirection(yaw float32) int32 {
+	direction := int32(math.Round(float64(yaw)/90)) % 4
+	if direction < 0 {
+		direction += 4
+	}
+	return direction
+}
+
+// interactBlock 使玩家手持 held 点击方块 b。
+// 如果该次点击被方块 b 处理，则返回真
+func (p *player) interactBlock(
+	b *Block,
+	held protocol.ItemStack,
+	data *protocol.UseItemTransactionData,
+) bool {
+	items := p.server.items
+	name, _ := items.Name(held.NetworkID)
+
+	switch b.Name {
+	case "minecraft:flower_pot":
+		if _, ok := b.NBT["PlantBlock"]; ok {
+			return true
+		}
+		plantName, plantStates, found := block.RuntimeIDToState(uint32(held.BlockRuntimeID))
+		if held.BlockRuntimeID == 0 || !found {
+			return true
+		}
+		b.NBT["PlantBlock"] = map[string]any{
+			"name":    plantName,
+			"states":  plantStates,
+			"version": mcstructure.DefaultBlockVersion,
+		}
+	case "minecraft:decorated_pot":
+		current, ok := b.NBT["item"].(map[string]any)
+		if !ok {
+			b.NBT["item"] = items.ItemToNBT(protocol.ItemStack{
+				ItemType:       held.ItemType,
+				BlockRuntimeID: held.BlockRuntimeID,
+				Count:          1,
+				NBTData:        held.NBTData,
+			}, 0)
+			return true
+		}
+		if current["Name"] == name && current["Damage"] == int16(held.MetadataValue) {
+			count, _ := current["Count"].(byte)
+			current["Count"] = min(count+1, 64)
+		}
+	case "minecraft:mob_spawner":
+		if entity, ok := strings.CutSuffix(name, "_spawn_egg"); ok {
+			b.NBT["EntityIdentifier"] = entity
+		}
+	case "minecraft:campfire", "minecraft:soul_campfire":
+		extinguished, _ := b.States["extinguished"].(byte)
+		if extinguished != 0 || !mapping.CampfireCookableItems[name] {
+			return true
+		}
+		// 物品总是放置在第一个空槽位
+		for index := range mapping.CampfireSlotCount {
+			key := fmt.Sprintf("Item%d", index+1)
+			if _, ok := b.NBT[key]; ok {
+				continue
+			}
+			item := items.ItemToNBT(held, 0)
+			item["Count"] = byte(1)
+			b.NBT[key] = item
+			break
+		}
+	case "minecraft:cauldron":
+		fillLevel, _ := b.States["fill_level"].(int32)
+		if b.States["cauldron_liquid"] != "water" || fillLevel == 0 {
+			return true
+		}
+		// 炼药锅中的水只会具有最后一次使用的染料的颜色
+		if color, ok := dyeNameToLeatherColor[name]; ok {
+			b.NBT["CustomColor"] = utils.EncodeVarRGBA(color[0], color[1], color[2], 0xff)
+			return true
+		}
+		customColor, ok := b.NBT["CustomColor"].(int32)
+		if !ok || !mapping.LeatherArmor[name] {
+			return true
+		}
+		dyed := utils.DeepCopyItemStack(held)
+		if dyed.NBTData == nil {
+			dyed.NBTData = make(map[string]any)
+		}
+		dyed.NBTData["customColor"] = customColor
+		p.setInventorySlot(int(data.HotBarSlot), dyed)
+	case "minecraft:chiseled_bookshelf":
+		if !bookshelfItems[name] {
+			return true
+		}
+		direction, _ := b.States["direction"].(int32)
+		if direction < 0 || direction > 3 || data.BlockFace != chiseledBookshelfFace[direction] {
+			return true
+		}
+		slot := chiseledBookshelfSlot(direction, data.ClickedPosition)
+
+		list, _ := b.NBT["Items"].([]any)
+		if slot >= len(list) {
+			return true
+		}
+		if current, _ := list[slot].(map[string]any); current["Count"] != byte(0) {
+			return true
+		}
+		item := items.ItemToNBT(held, 0)
+		item["Count"] = byte(1)
+		delete(item, "Slot")
+		list[slot] = item
+
+		booksStored, _ := b.States["books_stored"].(int32)
+		b.States["books_stored"] = booksStored | 1<<slot
+		b.NBT["LastInteractedSlot"] = int32(slot + 1)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// chiseledBookshelfSlot 返回点击朝向为 direction 的雕纹书架的正面时，
+// 点击位置 clickPos 所对应的槽位。clickPos 是相对于书架的坐标
+func chiseledBookshelfSlot(direction int32, clickPos [3]float32) int {
+	var horizontal float32
+	switch direction {
+	case 0:
+		horizontal = clickPos[0]
+	case 1:
+		horizontal = clickPos[2]
+	case 2:
+		horizontal = 1 - clickPos[0]
+	default:
+		horizontal = 1 - clickPos[2]
+	}
+
+	column := min(max(int(horizontal*3), 0), 2)
+	if clickPos[1] >= 0.5 {
+		return column
+	}
+	return column + 3
+}
+
+// placeHeldBlock 使玩家点击 data 所指示的方块的方块面，
+// 并在相邻的 target 处放置手持的物品 held 所对应的方块
+func (p *player) placeHeldBlock(target protocol.BlockPos, held protocol.ItemStack, data *protocol.UseItemTransactionData) {
+	world := p.server.world
+	name, _ := p.server.items.Name(held.NetworkID)
+	if !world.Block(target).IsAir() {
+		return
+	}
+
+	newBlock, found := NewBlock(name, nil)
+	if !found {
+		return
+	}
+	if newBlock.Items != nil {
+		newBlock.CustomName = CustomName(held)
+	}
+
+	switch newBlock.Name {
+	case "minecraft:bed":
+		// 床尾位于被放置处，而床头位于玩家所朝向的方向
+		direction := yawDirection(p.yaw)
+		headPos := protocol.BlockPos{
+			target[0] + bedFacing[direction][0],
+			target[1] + bedFacing[direction][1],
+			target[2] + bedFacing[direction][2],
+		}
+		if !world.Block(headPos).IsAir() {
+			return
+		}
+		newBlock.States["direction"] = direction
+		newBlock.NBT["color"] = byte(held.MetadataValue)
+
+		head := newBlock.Clone()
+		head.States["head_piece_bit"] = byte(1)
+		world.SetBlock(headPos, head)
+	case "minecraft:skull":
+		newBlock.NBT["SkullType"] = byte(held.MetadataValue)
+		newBlock.NBT["MouthMoving"] = byte(0)
+		newBlock.NBT["Rotation"] = float32(0)
+		newBlock.States["facing_direction"] = data.BlockFace
+		if data.BlockFace == 1 {
+			// 放置在地面上的头颅朝向玩家
+			rotation := math.Mod(float64(p.yaw)+180, 360)
+			if rotation < 0 {
+				rotation += 360
+			}
+			newBlock.NBT["Rotation"] = float32(rotation)
+		}
+	case "minecraft:decorated_pot":
+		if sherds, ok := held.NBTData["sherds"].([]any); ok {
+			newBlock.NBT["sherds"] = sherds
+		}
+	}
+
+	world.SetBlock(target, newBlock)
+}
@@ -0,0 +1,330 @@
+package local_server
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
+)
+
+// blockContainerIDs 记载了指向已打开的容器方块的容器 ID
+var blockContainerIDs = map[byte]bool{
+	protocol.ContainerLevelEntity:            true,
+	protocol.ContainerBarrel:                 true,
+	protocol.ContainerShulkerBox:             true,
+	protocol.ContainerFurnaceIngredient:      true,
+	protocol.ContainerFurnaceFuel:            true,
+	protocol.ContainerFurnaceResult:          true,
+	protocol.ContainerBlastFurnaceIngredient: true,
+	protocol.ContainerSmokerIngredient:       true,
+	protocol.ContainerBrewingStandInput:      true,
+	protocol.ContainerBrewingStandResult:     true,
+	protocol.ContainerBrewingStandFuel:       true,
+}
+
+// stackTransaction 是单个物品堆栈请求的执行环境。
+// 它记录了被修改的槽位的原始物品，以便在请求失败时回滚
+type stackTransaction struct {
+	player   *player
+	request  protocol.ItemStackRequest
+	touched  []slotKey
+	original map[slotKey]protocol.ItemInstance
+}
+
+// handleItemStackRequest 依次执行 pk 中的每个物品堆栈请求，
+// 并将它们的结果返回给客户端
+func (p *player) handleItemStackRequest(pk *packet.ItemStackRequest) {
+	responses := make([]protocol.ItemStackResponse, 0, len(pk.Requests))
+	for _, request := range pk.Requests {
+		t := &stackTransaction{
+			player:   p,
+			request:  request,
+			original: make(map[slotKey]protocol.ItemInstance),
+		}
+		responses = append(responses, t.execute())
+	}
+	_ = p.conn.WritePacket(&packet.ItemStackResponse{Responses: responses})
+}
+
+// item 返回槽位 key 处的物品
+func (p *player) item(key slotKey) (item protocol.ItemInstance, err error) {
+	switch key.ContainerID {
+	case protocol.ContainerCombinedHotBarAndInventory, protocol.ContainerHotBar, protocol.ContainerInventory:
+		if int(key.Slot) >= InventorySize {
+			return protocol.ItemInstance{}, fmt.Errorf("item: Slot %d is out of the inventory", key.Slot)
+		}
+		return p.inventory[key.Slot], nil
+	case protocol.ContainerCreatedOutput:
+		return p.created, nil
+	}
+
+	if blockContainerIDs[key.ContainerID] {
+		if p.window == nil || p.window.Block == nil || p.window.Block.Items == nil {
+			return protocol.ItemInstance{}, fmt.Errorf("item: No container was opened")
+		}
+		info, _ := containerInfoOf(p.window.Block)
+		if int(key.Slot) >= info.Size {
+			return protocol.ItemInstance{}, fmt.Errorf("item: Slot %d is out of the container", key.Slot)
+		}
+		return p.window.Block.Items[key.Slot], nil
+	}
+
+	return p.ui[key], nil
+}
+
+// setItem 将槽位 key 处的物品设置为 item
+func (p *player) setItem(key slotKey, item protocol.ItemInstance) {
+	if item.Stack.NetworkID == 0 || item.Stack.Count == 0 {
+		item = protocol.ItemInstance{}
+	}
+
+	switch key.ContainerID {
+	case protocol.ContainerCombinedHotBarAndInventory, protocol.ContainerHotBar, protocol.ContainerInventory:
+		p.inventory[key.Slot] = item
+		return
+	case protocol.ContainerCreatedOutput:
+		p.created = item
+		return
+	}
+
+	if blockContainerIDs[key.ContainerID] {
+		if item.Stack.NetworkID == 0 {
+			delete(p.window.Block.Items, key.Slot)
+		} else {
+			p.window.Block.Items[key.Slot] = item
+		}
+		return
+	}
+
+	if item.Stack.NetworkID == 0 {
+		delete(p.ui, key)
+	} else {
+		p.ui[key] = item
+	}
+}
+
+// load 返回槽位 slot 处的物品，并检查客户端所假定的物品堆栈网络 ID
+func (t *stackTransaction) load(slot protocol.StackRequestSlotInfo) (protocol.ItemInstance, error) {
+	key := slotKey{ContainerID: slot.ContainerID, Slot: slot.Slot}
+	item, err := t.player.item(key)
+	if err != nil {
+		return protocol.ItemInstance{}, fmt.Errorf("load: %v", err)
+	}
+	// 正数的网络 ID 是服务器分配的，负数的网络 ID 则是
+	// 客户端在同一请求中预测的，后者无需检查
+	if slot.StackNetworkID > 0 && item.StackNetworkID != slot.StackNetworkID {
+		return protocol.ItemInstance{}, fmt.Errorf(
+			"load: Stack network ID mismatch at %#v (expected = %d, got = %d)",
+			key, slot.StackNetworkID, item.StackNetworkID,
+		)
+	}
+	return item, nil
+}
+
+// store 将槽位 key 处的物品设置为 item，
+// 并在首次修改该槽位时记录它的原始物品
+func (t *stackTransaction) store(key slotKey, item protocol.ItemInstance) {
+	if _, ok := t.original[key]; !ok {
+		original, _ := t.player.item(key)
+		t.original[key] = original
+		t.touched = append(t.touched, key)
+	}
+	t.player.setItem(key, item)
+}
+
+// rollback 撤销此请求对所有槽位的修改
+func (t *stackTransaction) rollback() {
+	for _, key := range t.touched {
+		t.player.setItem(key, t.original[key])
+	}
+}
+
+// sameItem 检查 a 和 b 是否是可以堆叠在一起的相同物品
+func sameItem(a protocol.ItemStack, b protocol.ItemStack) bool {
+	return a.NetworkID == b.NetworkID &&
+		a.MetadataValue == b.MetadataValue &&
+		a.BlockRuntimeID == b.BlockRuntimeID &&
+		reflect.DeepEqual(a.NBTData, b.NBTData) &&
+		reflect.DeepEqual(a.CanBePlacedOn, b.CanBePlacedOn) &&
+		reflect.DeepEqual(a.CanBreak, b.CanBreak)
+}
+
+// execute 执行物品堆栈请求，并返回对应的响应。
+// 如果任何一个动作失败，则整个请求都会被回滚
+func (t *stackTransaction) execute() protocol.ItemStackResponse {
+	for _, action := range t.request.Actions {
+		if err := t.handleAction(action); err != nil {
+			t.rollback()
+			return protocol.ItemStackResponse{
+				Status:    protocol.ItemStackResponseStatusError,
+				RequestID: t.request.RequestID,
+			}
+		}
+	}
+	return protocol.ItemStackResponse{
+		Status:        protocol.ItemStackResponseStatusOK,
+		RequestID:     t.request.RequestID,
+		ContainerInfo: t.containerInfo(),
+	}
+}
+
+// handleAction 执行单个物品堆栈请求动作 action
+func (t *stackTransaction) handleAction(action protocol.StackRequestAction) error {
+	switch action := action.(type) {
+	case *protocol.TakeStackRequestAction:
+		return t.transfer(action.Count, action.Source, action.Destination)
+	case *protocol.PlaceStackRequestAction:
+		return t.transfer(action.Count, action.Source, action.Destination)
+	case *protocol.PlaceInContainerStackRequestAction:
+		return t.transfer(action.Count, action.Source, action.Destination)
+	case *protocol.TakeOutContainerStackRequestAction:
+		return t.transfer(action.Count, action.Source, action.Destination)
+	case *protocol.SwapStackRequestAction:
+		return t.swap(action.Source, action.Destination)
+	case *protocol.DropStackRequestAction:
+		return t.destroy(action.Count, action.Source)
+	case *protocol.DestroyStackRequestAction:
+		return t.destroy(action.Count, action.Source)
+	case *protocol.ConsumeStackRequestAction:
+		return t.destroy(action.Count, action.Source)
+	case *protocol.CraftRecipeStackRequestAction:
+		return t.craft(action.RecipeNetworkID)
+	case *protocol.CraftRecipeOptionalStackRequestAction:
+		return t.rename(action.FilterStringIndex)
+	case *protocol.CraftResultsDeprecatedStackRequestAction:
+		return nil
+	}
+	return fmt.Errorf("handleAction: Unsupported action %T", action)
+}
+
+// transfer 将 source 处的 count 个物品移动到 destination
+func (t *stackTransaction) transfer(
+	count byte,
+	source protocol.StackRequestSlotInfo,
+	destination protocol.StackRequestSlotInfo,
+) error {
+	srcKey := slotKey{ContainerID: source.ContainerID, Slot: source.Slot}
+	dstKey := slotKey{ContainerID: destination.ContainerID, Slot: destination.Slot}
+	if srcKey == dstKey {
+		return fmt.Errorf("transfer: Source is equal to destination")
+	}
+
+	srcItem, err := t.load(source)
+	if err != nil {
+		return fmt.Errorf("transfer: %v", err)
+	}
+	dstItem, err := t.load(destination)
+	if err != nil {
+		return fmt.Errorf("transfer: %v", err)
+	}
+	if srcItem.Stack.NetworkID == 0 || count == 0 || srcItem.Stack.Count < uint16(count) {
+		return fmt.Errorf("transfer: Not enough items at %#v", srcKey)
+	}
+
+	if dstItem.Stack.NetworkID == 0 {
+		dstItem = cloneItem(srcItem)
+		dstItem.StackNetworkID = t.player.server.newStackNetworkID()
+		dstItem.Stack.Count = 0
+	} else if !sameItem(srcItem.Stack, dstItem.Stack) {
+		return fmt.Errorf("transfer: Can not merge different items at %#v", dstKey)
+	}
+	dstItem.Stack.Count += uint16(count)
+	srcItem.Stack.Count -= uint16(count)
+
+	t.store(srcKey, srcItem)
+	t.store(dstKey, dstItem)
+	return nil
+}
+
+// swap 交换 source 和 destination 处的物品
+func (t *stackTransaction) swap(source protocol.StackRequestSlotInfo, destination protocol.StackRequestSlotInfo) error {
+	srcItem, err := t.load(source)
+	if err != nil {
+		return fmt.Errorf("swap: %v", err)
+	}
+	dstItem, err := t.load(destination)
+	if err != nil {
+		return fmt.Errorf("swap: %v", err)
+	}
+	t.store(slotKey{ContainerID: source.ContainerID, Slot: source.Slot}, dstItem)
+	t.store(slotKey{ContainerID: destination.ContainerID, Slot: destination.Slot}, srcItem)
+	return nil
+}
+
+// destroy 移除 source 处的 count 个物品
+func (t *stackTransaction) destroy(count byte, source protocol.StackRequestSlotInfo) error {
+	item, err := t.load(source)
+	if err != nil {
+		return fmt.Errorf("destroy: %v", err)
+	}
+	if item.Stack.NetworkID == 0 || item.Stack.Count < uint16(count) {
+		return fmt.Errorf("destroy: Not enough items at %#v", source)
+	}
+	item.Stack.Count -= uint16(count)
+	t.store(slotKey{ContainerID: source.ContainerID, Slot: source.Slot}, item)
+	return nil
+}
+
+// rename 将铁砧输入槽位中的物品重命名为请求中的第 index 个过滤字符串，
+// 并将结果放入合成输出槽位
+func (t *stackTransaction) rename(index int32) error {
+	if index < 0 || int(index) >= len(t.request.FilterStrings) {
+		return fmt.Errorf("rename: Filter string index %d is out of range", index)
+	}
+
+	key := slotKey{ContainerID: protocol.ContainerAnvilInput, Slot: 1}
+	item, err := t.player.item(key)
+	if err != nil {
+		return fmt.Errorf("rename: %v", err)
+	}
+	if item.Stack.NetworkID == 0 {
+		return fmt.Errorf("rename: Nothing to rename")
+	}
+
+	result := cloneItem(item)
+	if result.Stack.NBTData == nil {
+		result.Stack.NBTData = make(map[string]any)
+	}
+	display, _ := result.Stack.NBTData["display"].(map[string]any)
+	if display == nil {
+		display = make(map[string]any)
+	}
+	display["Name"] = t.request.FilterStrings[index]
+	result.Stack.NBTData["display"] = display
+	result.StackNetworkID = t.player.server.newStackNetworkID()
+
+	t.store(slotKey{ContainerID: protocol.ContainerCreatedOutput}, result)
+	return nil
+}
+
+// containerInfo 按首次修改的顺序返回此请求修改的所有槽位的最终状态。
+// 合成输出槽位不会被客户端绑定，因此不包含在内
+func (t *stackTransaction) containerInfo() []protocol.StackResponseContainerInfo {
+	result := make([]protocol.StackResponseContainerInfo, 0)
+	containerIndex := make(map[byte]int)
+
+	for _, key := range t.touched {
+		if key.ContainerID == protocol.ContainerCreatedOutput {
+			continue
+		}
+
+		index, ok := containerIndex[key.ContainerID]
+		if !ok {
+			index = len(result)
+			containerIndex[key.ContainerID] = index
+			result = append(result, protocol.StackResponseContainerInfo{ContainerID: key.ContainerID})
+		}
+
+		item, _ := t.player.item(key)
+		result[index].SlotInfo = append(result[index].SlotInfo, protocol.StackResponseSlotInfo{
+			Slot:           key.Slot,
+			HotbarSlot:     key.Slot,
+			Count:          byte(item.Stack.Count),
+			StackNetworkID: item.StackNetworkID,
+			CustomName:     CustomName(item.Stack),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,321 @@
+package local_server
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mcstructure"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+)
+
+// DefaultItems 是本地服务器默认注册的物品。
+// 它们涵盖了操作台和各个系统测试所使用的物品，
+// 更多的物品可以通过 Config.ExtraItems 注册
+var DefaultItems = []string{
+	// 操作台和基础方块
+	"minecraft:sea_lantern",
+	"minecraft:emerald_block",
+	"minecraft:polished_andesite",
+	"minecraft:diamond_ore",
+	"minecraft:stone",
+	"minecraft:dirt",
+	"minecraft:glass",
+	"minecraft:anvil",
+	"minecraft:cauldron",
+	"minecraft:smithing_table",
+	"minecraft:loom",
+	"minecraft:crafting_table",
+	"minecraft:grindstone",
+	// 容器
+	"minecraft:chest",
+	"minecraft:trapped_chest",
+	"minecraft:barrel",
+	"minecraft:hopper",
+	"minecraft:dispenser",
+	"minecraft:dropper",
+	"minecraft:furnace",
+	"minecraft:blast_furnace",
+	"minecraft:smoker",
+	"minecraft:brewing_stand",
+	"minecraft:crafter",
+	"minecraft:undyed_shulker_box",
+	"minecraft:white_shulker_box",
+	"minecraft:orange_shulker_box",
+	"minecraft:magenta_shulker_box",
+	"minecraft:light_blue_shulker_box",
+	"minecraft:yellow_shulker_box",
+	"minecraft:lime_shulker_box",
+	"minecraft:pink_shulker_box",
+	"minecraft:gray_shulker_box",
+	"minecraft:light_gray_shulker_box",
+	"minecraft:cyan_shulker_box",
+	"minecraft:purple_shulker_box",
+	"minecraft:blue_shulker_box",
+	"minecraft:brown_shulker_box",
+	"minecraft:green_shulker_box",
+	"minecraft:red_shulker_box",
+	"minecraft:black_shulker_box",
+	// 普通物品
+	"minecraft:apple",
+	"minecraft:bread",
+	"minecraft:stick",
+	"minecraft:diamond",
+	"minecraft:emerald",
+	"minecraft:iron_ingot",
+	"minecraft:gold_ingot",
+	"minecraft:redstone",
+	"minecraft:paper",
+	"minecraft:book",
+	"minecraft:enchanted_book",
+	"minecraft:written_book",
+	"minecraft:writable_book",
+	"minecraft:name_tag",
+	"minecraft:diamond_sword",
+	"minecraft:diamond_pickaxe",
+	"minecraft:bow",
+	"minecraft:arrow",
+	"minecraft:shield",
+	"minecraft:banner",
+	"minecraft:white_dye",
+	"minecraft:black_dye",
+	"minecraft:red_dye",
+	"minecraft:leather_helmet",
+	"minecraft:leather_chestplate",
+	"minecraft:leather_leggings",
+	"minecraft:leather_boots",
+	"minecraft:iron_chestplate",
+	"minecraft:diamond_chestplate",
+	"minecraft:filled_map",
+	// 合成材料和合成结果
+	"minecraft:gunpowder",
+	"minecraft:glowstone_dust",
+	"minecraft:fire_charge",
+	"minecraft:gold_nugget",
+	"minecraft:feather",
+	"minecraft:blue_dye",
+	"minecraft:yellow_dye",
+	"minecraft:firework_rocket",
+	"minecraft:firework_star",
+	"minecraft:bowl",
+	"minecraft:brown_mushroom",
+	"minecraft:red_mushroom",
+	"minecraft:cornflower",
+	"minecraft:potion",
+	"minecraft:splash_potion",
+	"minecraft:suspicious_stew",
+	"minecraft:coast_armor_trim_smithing_template",
+	// 通过交互放置或放入的物品
+	"minecraft:bed",
+	"minecraft:skull",
+	"minecraft:flower_pot",
+	"minecraft:decorated_pot",
+	"minecraft:chiseled_bookshelf",
+	"minecraft:mob_spawner",
+	"minecraft:campfire",
+	"minecraft:soul_campfire",
+	"minecraft:poppy",
+	"minecraft:beef",
+	"minecraft:porkchop",
+	"minecraft:zombie_spawn_egg",
+}
+
+// NonCommandItems 是已注册但无法通过命令获取的物品。
+// 它们不会出现在命令的物品枚举中，只能通过合成等方式得到
+var NonCommandItems = []string{
+	"minecraft:suspicious_stew",
+}
+
+// itemRegistry 是本地服务器的物品注册表
+type itemRegistry struct {
+	entries    []protocol.ItemEntry
+	byName     map[string]int32
+	byID       map[int32]string
+	nonCommand map[string]bool
+}
+
+// normalizeName 返回名称 name 的带命名空间的小写形式
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasPrefix(name, "minecraft:") {
+		name = "minecraft:" + name
+	}
+	return name
+}
+
+// newItemRegistry 基于 DefaultItems 和 extraItems 创建并返回一个新的物品注册表。
+// 物品的网络 ID 从 1 开始依次分配，0 被保留给空气
+func newItemRegistry(extraItems []string) *itemRegistry {
+	r := &itemRegistry{
+		byName:     make(map[string]int32),
+		byID:       make(map[int32]string),
+		nonCommand: make(map[string]bool),
+	}
+	for _, name := range NonCommandItems {
+		r.nonCommand[normalizeName(name)] = true
+	}
+	for _, name := range append(DefaultItems, extraItems...) {
+		name = normalizeName(name)
+		if _, ok := r.byName[name]; ok {
+			continue
+		}
+		networkID := int32(len(r.entries) + 1)
+		r.entries = append(r.entries, protocol.ItemEntry{
+			Name:      name,
+			RuntimeID: int16(networkID),
+		})
+		r.byName[name] = networkID
+		r.byID[networkID] = name
+	}
+	return r
+}
+
+// NetworkID 返回名为 name 的物品的网络 ID
+func (r *itemRegistry) NetworkID(name string) (networkID int32, found bool) {
+	networkID, found = r.byName[normalizeName(name)]
+	return
+}
+
+// CanGetByCommand 检查名为 name 的物品能否通过命令获取
+func (r *itemRegistry) CanGetByCommand(name string) bool {
+	name = normalizeName(name)
+	_, found := r.byName[name]
+	return found && !r.nonCommand[name]
+}
+
+// Name 返回网络 ID 为 networkID 的物品的名称
+func (r *itemRegistry) Name(networkID int32) (name string, found bool) {
+	name, found = r.byID[networkID]
+	return
+}
+
+// NewItem 创建一个名为 name 的物品。
+// components 是 JSON 格式的物品组件，它可以为空。
+// 如果该物品没有被注册，则 found 为假
+func (r *itemRegistry) NewItem(
+	name string,
+	count uint16,
+	metadata uint32,
+	components string,
+) (item protocol.ItemStack, found bool) {
+	networkID, found := r.NetworkID(name)
+	if !found {
+		return protocol.ItemStack{}, false
+	}
+
+	item = protocol.ItemStack{
+		ItemType: protocol.ItemType{
+			NetworkID:     networkID,
+			MetadataValue: metadata,
+		},
+		Count: count,
+	}
+	if rid, ok := block.StateToRuntimeID(normalizeName(name), map[string]any{}); ok {
+		item.BlockRuntimeID = int32(rid)
+	}
+
+	if len(components) > 0 {
+		var component map[string]map[string]any
+		_ = json.Unmarshal([]byte(components), &component)
+		for key, value := range component {
+			blocks, _ := value["blocks"].([]any)
+			names := make([]string, 0)
+			for _, val := range blocks {
+				if blockName, ok := val.(string); ok {
+					names = append(names, blockName)
+				}
+			}
+			switch key {
+			case "can_place_on", "minecraft:can_place_on":
+				item.CanBePlacedOn = names
+			case "can_destroy", "minecraft:can_destroy":
+				item.CanBreak = names
+			case "item_lock", "minecraft:item_lock":
+				mode, _ := value["mode"].(string)
+				item.NBTData = setItemLock(item.NBTData, mode)
+			case "keep_on_death", "minecraft:keep_on_death":
+				if item.NBTData == nil {
+					item.NBTData = make(map[string]any)
+				}
+				item.NBTData["minecraft:keep_on_death"] = byte(1)
+			}
+		}
+	}
+
+	return item, true
+}
+
+// setItemLock 将物品锁定模式 mode 写入 nbtData 并返回
+func setItemLock(nbtData map[string]any, mode string) map[string]any {
+	if nbtData == nil {
+		nbtData = make(map[string]any)
+	}
+	switch mode {
+	case "lock_in_slot":
+		nbtData["minecraft:item_lock"] = byte(1)
+	case "lock_in_inventory":
+		nbtData["minecraft:item_lock"] = byte(2)
+	}
+	return nbtData
+}
+
+// ItemToNBT 将物品 item 转换为其在方块实体数据中的形式。
+// slot 是该物品所在的槽位
+func (r *itemRegistry) ItemToNBT(item protocol.ItemStack, slot byte) map[string]any {
+	name, _ := r.Name(item.NetworkID)
+	result := map[string]any{
+		"Name":        name,
+		"Count":       byte(item.Count),
+		"Damage":      int16(item.MetadataValue),
+		"Slot":        slot,
+		"WasPickedUp": byte(0),
+	}
+	if len(item.NBTData) > 0 {
+		result["tag"] = utils.DeepCopyNBT(item.NBTData)
+	}
+	if len(item.CanBePlacedOn) > 0 {
+		result["CanPlaceOn"] = stringsToAny(item.CanBePlacedOn)
+	}
+	if len(item.CanBreak) > 0 {
+		result["CanDestroy"] = stringsToAny(item.CanBreak)
+	}
+	if item.BlockRuntimeID != 0 {
+		if blockName, states, found := block.RuntimeIDToState(uint32(item.BlockRuntimeID)); found {
+			result["Block"] = map[string]any{
+				"name":    blockName,
+				"states":  states,
+				"version": mcstructure.DefaultBlockVersion,
+			}
+		}
+	}
+	return result
+}
+
+// cloneItem 返回物品 item 的深拷贝
+func cloneItem(item protocol.ItemInstance) protocol.ItemInstance {
+	if item.Stack.NBTData != nil {
+		item.Stack.NBTData = utils.DeepCopyNBT(item.Stack.NBTData)
+	}
+	item.Stack.CanBePlacedOn = slices.Clone(item.Stack.CanBePlacedOn)
+	item.Stack.CanBreak = slices.Clone(item.Stack.CanBreak)
+	return item
+}
+
+// CustomName 返回物品 item 的自定义名称
+func CustomName(item protocol.ItemStack) string {
+	display, _ := item.NBTData["display"].(map[string]any)
+	name, _ := display["Name"].(string)
+	return name
+}
+
+// stringsToAny ..
+func stringsToAny(input []string) []any {
+	result := make([]any, len(input))
+	for index, value := range input {
+		result[index] = value
+	}
+	return result
+}
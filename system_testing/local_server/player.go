@@ -0,0 +1,505 @@
+package local_server
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// InventorySize 是玩家背包的大小
+const InventorySize = 36
+
+// containerTypeInventory 是背包的容器类型
+// (protocol.ContainerTypeInventory) 在网络上的表示
+const containerTypeInventory byte = 0xff
+
+// containerInfo 描述了一种可以被打开的方块
+type containerInfo struct {
+	ContainerType byte
+	// Size 是该容器的槽位数量，
+	// 为 0 表示该容器不存放物品
+	Size int
+}
+
+// openableBlocks 记载了可以被打开的方块
+var openableBlocks = map[string]containerInfo{
+	"minecraft:chest":          {ContainerType: protocol.ContainerTypeContainer, Size: 27},
+	"minecraft:trapped_chest":  {ContainerType: protocol.ContainerTypeContainer, Size: 27},
+	"minecraft:barrel":         {ContainerType: protocol.ContainerTypeContainer, Size: 27},
+	"minecraft:hopper":         {ContainerType: protocol.ContainerTypeHopper, Size: 5},
+	"minecraft:dispenser":      {ContainerType: protocol.ContainerTypeDispenser, Size: 9},
+	"minecraft:dropper":        {ContainerType: protocol.ContainerTypeDropper, Size: 9},
+	"minecraft:furnace":        {ContainerType: protocol.ContainerTypeFurnace, Size: 3},
+	"minecraft:lit_furnace":    {ContainerType: protocol.ContainerTypeFurnace, Size: 3},
+	"minecraft:blast_furnace":  {ContainerType: protocol.ContainerTypeBlastFurnace, Size: 3},
+	"minecraft:smoker":         {ContainerType: protocol.ContainerTypeSmoker, Size: 3},
+	"minecraft:brewing_stand":  {ContainerType: protocol.ContainerTypeBrewingStand, Size: 5},
+	"minecraft:crafter":        {ContainerType: protocol.ContainerTypeCrafter, Size: 9},
+	"minecraft:anvil":          {ContainerType: protocol.ContainerTypeAnvil},
+	"minecraft:smithing_table": {ContainerType: protocol.ContainerTypeSmithingTable},
+	"minecraft:loom":           {ContainerType: protocol.ContainerTypeLoom},
+	"minecraft:crafting_table": {ContainerType: protocol.ContainerTypeWorkbench},
+	"minecraft:grindstone":     {ContainerType: protocol.ContainerTypeGrindstone},
+}
+
+// containerInfoOf 返回方块 b 的容器信息
+func containerInfoOf(b *Block) (info containerInfo, ok bool) {
+	if strings.Contains(b.Name, "shulker_box") {
+		return containerInfo{ContainerType: protocol.ContainerTypeContainer, Size: 27}, true
+	}
+	info, ok = openableBlocks[b.Name]
+	return
+}
+
+// window 是玩家已打开的容器
+type window struct {
+	WindowID      byte
+	ContainerType byte
+	Position      protocol.BlockPos
+	// Block 是被打开的方块。
+	// 如果打开的是背包，则为空值
+	Block *Block
+}
+
+// slotKey 是物品堆栈请求中的槽位
+type slotKey struct {
+	ContainerID byte
+	Slot        byte
+}
+
+// player 是连接到本地服务器的单个客户端
+type player struct {
+	server   *Server
+	conn     *minecraft.Conn
+	name     string
+	entityID int64
+	uniqueID uuid.UUID
+
+	position mgl32.Vec3
+	// yaw 是玩家最近一次上报的偏航角，
+	// 它决定了床等方块被放置时的朝向
+	yaw       float32
+	heldSlot  uint32
+	inventory [InventorySize]protocol.ItemInstance
+
+	// window 是玩家当前打开的容器
+	window   *window
+	windowID byte
+	// ui 是铁砧、锻造台等界面中的物品
+	ui map[slotKey]protocol.ItemInstance
+	// created 是合成或重命名所产生的物品
+	created protocol.ItemInstance
+}
+
+// newPlayer 基于 conn 创建并返回一个新的玩家
+func newPlayer(s *Server, conn *minecraft.Conn, entityID int64) *player {
+	return &player{
+		server:   s,
+		conn:     conn,
+		name:     conn.IdentityData().DisplayName,
+		entityID: entityID,
+		uniqueID: uuid.New(),
+		position: s.cfg.SpawnPosition,
+		ui:       make(map[slotKey]protocol.ItemInstance),
+	}
+}
+
+// blockPos 返回玩家所在的方块坐标
+func (p *player) blockPos() protocol.BlockPos {
+	return floorPos(p.position)
+}
+
+// syncInventory 将玩家背包的全部内容同步到客户端
+func (p *player) syncInventory() {
+	content := make([]protocol.ItemInstance, InventorySize)
+	copy(content, p.inventory[:])
+	_ = p.conn.WritePacket(&packet.InventoryContent{
+		WindowID: protocol.WindowIDInventory,
+		Content:  content,
+	})
+}
+
+// setInventorySlot 将玩家背包 slot 处的物品设置为 item，
+// 并将其同步到客户端
+func (p *player) setInventorySlot(slot int, item protocol.ItemStack) {
+	instance := protocol.ItemInstance{}
+	if item.NetworkID != 0 && item.Count > 0 {
+		instance = protocol.ItemInstance{
+			StackNetworkID: p.server.newStackNetworkID(),
+			Stack:          item,
+		}
+	}
+	p.inventory[slot] = instance
+	_ = p.conn.WritePacket(&packet.InventorySlot{
+		WindowID: protocol.WindowIDInventory,
+		Slot:     uint32(slot),
+		NewItem:  instance,
+	})
+}
+
+// setContainerSlot 将 pos 处容器方块 b 的 slot 处的物品设置为 item。
+// 如果玩家正打开着该容器，则将更改同步到客户端
+func (p *player) setContainerSlot(pos protocol.BlockPos, b *Block, slot byte, item protocol.ItemStack) {
+	instance := protocol.ItemInstance{}
+	if item.NetworkID != 0 && item.Count > 0 {
+		instance = protocol.ItemInstance{
+			StackNetworkID: p.server.newStackNetworkID(),
+			Stack:          item,
+		}
+		b.Items[slot] = instance
+	} else {
+		delete(b.Items, slot)
+	}
+
+	if p.window != nil && p.window.Block == b && p.window.Position == pos {
+		_ = p.conn.WritePacket(&packet.InventorySlot{
+			WindowID: uint32(p.window.WindowID),
+			Slot:     uint32(slot),
+			NewItem:  instance,
+		})
+	}
+}
+
+// teleport 将玩家传送到 pos
+func (p *player) teleport(pos mgl32.Vec3) {
+	p.position = pos
+	_ = p.conn.WritePacket(&packet.MovePlayer{
+		EntityRuntimeID: uint64(p.entityID),
+		Position:        pos.Add(mgl32.Vec3{0, 1.62, 0}),
+		Mode:            packet.MoveModeTeleport,
+		OnGround:        true,
+	})
+}
+
+// handlePacket 处理客户端发送的数据包 pk
+func (p *player) handlePacket(pk packet.Packet) {
+	switch pk := pk.(type) {
+	case *packet.CommandRequest:
+		p.handleCommandRequest(pk)
+	case *packet.SettingsCommand:
+		p.server.logCommand(p, pk.CommandLine)
+		_ = p.execute(pk.CommandLine)
+	case *packet.PlayerAuthInput:
+		p.yaw = pk.Yaw
+	case *packet.PlayerHotBar:
+		if pk.SelectHotBarSlot && pk.SelectedHotBarSlot < 9 {
+			p.heldSlot = pk.SelectedHotBarSlot
+		}
+	case *packet.InventoryTransaction:
+		if data, ok := pk.TransactionData.(*protocol.UseItemTransactionData); ok {
+			if data.ActionType == protocol.UseItemActionClickBlock {
+				p.handleClickBlock(data)
+			}
+		}
+	case *packet.Interact:
+		if pk.ActionType == packet.InteractActionOpenInventory {
+			p.openInventory()
+		}
+	case *packet.ContainerClose:
+		p.handleContainerClose(pk)
+	case *packet.ItemStackRequest:
+		p.handleItemStackRequest(pk)
+	case *packet.StructureTemplateDataRequest:
+		p.handleStructureTemplateDataRequest(pk)
+	case *packet.SubChunkRequest:
+		p.handleSubChunkRequest(pk)
+	case *packet.BlockPickRequest:
+		p.handleBlockPickRequest(pk)
+	case *packet.PlayerToggleCrafterSlotRequest:
+		p.handleToggleCrafterSlot(pk)
+	case *packet.PyRpc:
+		p.handlePyRpc(pk)
+	}
+}
+
+// handleCommandRequest 执行命令请求，并将结果返回给请求者
+func (p *player) handleCommandRequest(pk *packet.CommandRequest) {
+	p.server.logCommand(p, pk.CommandLine)
+	output := p.execute(pk.CommandLine)
+
+	resp := &packet.CommandOutput{
+		CommandOrigin:  pk.CommandOrigin,
+		OutputType:     packet.CommandOutputTypeAllOutput,
+		SuccessCount:   output.SuccessCount,
+		OutputMessages: []protocol.CommandOutputMessage{output.Message},
+	}
+	_ = p.conn.WritePacket(resp)
+}
+
+// nextWindowID 返回一个新的窗口 ID
+func (p *player) nextWindowID() byte {
+	p.windowID = p.windowID%100 + 1
+	return p.windowID
+}
+
+// openInventory 为玩家打开背包
+func (p *player) openInventory() {
+	if p.window != nil {
+		return
+	}
+	p.window = &window{
+		WindowID:      protocol.WindowIDInventory,
+		ContainerType: containerTypeInventory,
+		Position:      p.blockPos(),
+	}
+	_ = p.conn.WritePacket(&packet.ContainerOpen{
+		WindowID:                p.window.WindowID,
+		ContainerType:           p.window.ContainerType,
+		ContainerPosition:       p.window.Position,
+		ContainerEntityUniqueID: p.entityID,
+	})
+}
+
+// openContainer 为玩家打开 pos 处的方块 b
+func (p *player) openContainer(pos protocol.BlockPos, b *Block, info containerInfo) {
+	p.window = &window{
+		WindowID:      p.nextWindowID(),
+		ContainerType: info.ContainerType,
+		Position:      pos,
+		Block:         b,
+	}
+	_ = p.conn.WritePacket(&packet.ContainerOpen{
+		WindowID:                p.window.WindowID,
+		ContainerType:           p.window.ContainerType,
+		ContainerPosition:       pos,
+		ContainerEntityUniqueID: -1,
+	})
+
+	if info.Size > 0 && b.Items != nil {
+		content := make([]protocol.ItemInstance, info.Size)
+		for slot, item := range b.Items {
+			if int(slot) < info.Size {
+				content[slot] = item
+			}
+		}
+		_ = p.conn.WritePacket(&packet.InventoryContent{
+			WindowID: uint32(p.window.WindowID),
+			Content:  content,
+		})
+	}
+}
+
+// handleContainerClose 关闭玩家已打开的容器
+func (p *player) handleContainerClose(pk *packet.ContainerClose) {
+	if p.window == nil || p.window.WindowID != pk.WindowID {
+		_ = p.conn.WritePacket(&packet.ContainerClose{WindowID: pk.WindowID})
+		return
+	}
+	_ = p.conn.WritePacket(&packet.ContainerClose{
+		WindowID:      p.window.WindowID,
+		ContainerType: p.window.ContainerType,
+	})
+	p.window = nil
+	p.ui = make(map[slotKey]protocol.ItemInstance)
+	p.created = protocol.ItemInstance{}
+}
+
+// handleClickBlock 处理玩家对方块的点击。
+// 如果被点击的方块可以打开，则打开它；否则，如果
+// 被点击的方块可以与手持物品交互 (例如将植物种入
+// 花盆)，则进行交互；否则，如果玩家手持方块物品，
+// 则放置该方块
+func (p *player) handleClickBlock(data *protocol.UseItemTransactionData) {
+	world := p.server.world
+	clicked := world.Block(data.BlockPosition)
+	if clicked.IsAir() || p.window != nil {
+		return
+	}
+
+	if info, ok := containerInfoOf(clicked); ok {
+		p.openContainer(data.BlockPosition, clicked, info)
+		return
+	}
+
+	if data.HotBarSlot < 0 || data.HotBarSlot > 8 {
+		return
+	}
+	held := p.inventory[data.HotBarSlot].Stack
+	if held.NetworkID == 0 {
+		return
+	}
+	if p.interactBlock(clicked, held, data) {
+		return
+	}
+
+	offset := faceOffset(data.BlockFace)
+	target := protocol.BlockPos{
+		data.BlockPosition[0] + offset[0],
+		data.BlockPosition[1] + offset[1],
+		data.BlockPosition[2] + offset[2],
+	}
+	p.placeHeldBlock(target, held, data)
+}
+
+// faceOffset 返回方块面 face 所指向的方块相对于被点击方块的偏移
+func faceOffset(face int32) protocol.BlockPos {
+	switch face {
+	case 0:
+		return protocol.BlockPos{0, -1, 0}
+	case 1:
+		return protocol.BlockPos{0, 1, 0}
+	case 2:
+		return protocol.BlockPos{0, 0, -1}
+	case 3:
+		return protocol.BlockPos{0, 0, 1}
+	case 4:
+		return protocol.BlockPos{-1, 0, 0}
+	case 5:
+		return protocol.BlockPos{1, 0, 0}
+	}
+	return protocol.BlockPos{}
+}
+
+// handleToggleCrafterSlot 启用或禁用玩家已打开的合成器的物品栏
+func (p *player) handleToggleCrafterSlot(pk *packet.PlayerToggleCrafterSlotRequest) {
+	pos := protocol.BlockPos{pk.PosX, pk.PosY, pk.PosZ}
+	if p.window == nil || p.window.Position != pos || pk.Slot > 8 {
+		return
+	}
+	b := p.window.Block
+	if b.Name != "minecraft:crafter" {
+		return
+	}
+	if pk.Disabled {
+		b.DisabledSlots |= 1 << pk.Slot
+	} else {
+		b.DisabledSlots &^= 1 << pk.Slot
+	}
+}
+
+// handleStructureTemplateDataRequest 导出已保存的结构或世界中的区域
+func (p *player) handleStructureTemplateDataRequest(pk *packet.StructureTemplateDataRequest) {
+	var structure *Structure
+	var found bool
+	world := p.server.world
+
+	switch pk.RequestType {
+	case packet.StructureTemplateRequestExportFromSave:
+		size := pk.Settings.Size
+		if size[0] > 0 && size[1] > 0 && size[2] > 0 {
+			end := protocol.BlockPos{
+				pk.Position[0] + size[0] - 1,
+				pk.Position[1] + size[1] - 1,
+				pk.Position[2] + size[2] - 1,
+			}
+			if world.SaveStructure(pk.StructureName, pk.Position, end) == nil {
+				structure, found = world.Structure(pk.StructureName)
+				world.DeleteStructure(pk.StructureName)
+			}
+		}
+	case packet.StructureTemplateRequestExportFromLoad:
+		if p.server.ignoreStructureExports {
+			return
+		}
+		structure, found = world.Structure(structureName(pk.StructureName))
+	}
+
+	resp := &packet.StructureTemplateDataResponse{
+		StructureName: pk.StructureName,
+		ResponseType:  packet.StructureTemplateResponseExport,
+	}
+	if found {
+		template, err := structure.Template(p.server.items)
+		if err == nil {
+			resp.Success = true
+			resp.StructureTemplate = template
+		}
+	}
+	_ = p.conn.WritePacket(resp)
+}
+
+// handleSubChunkRequest 返回被请求的子区块。
+// 本地服务器只模拟主世界
+func (p *player) handleSubChunkRequest(pk *packet.SubChunkRequest) {
+	dimensionRange := define.Dimension(pk.Dimension).Range()
+	resp := &packet.SubChunk{
+		Dimension: pk.Dimension,
+		Position:  pk.Position,
+	}
+
+	for _, offset := range pk.Offsets {
+		subY := pk.Position[1] + int32(offset[1])
+		entry := protocol.SubChunkEntry{
+			Offset:        offset,
+			HeightMapType: protocol.HeightMapDataNone,
+		}
+
+		switch {
+		case pk.Dimension != 0:
+			entry.Result = protocol.SubChunkResultInvalidDimension
+		case int(subY) < dimensionRange.Min()>>4 || int(subY) > dimensionRange.Max()>>4:
+			entry.Result = protocol.SubChunkResultIndexOutOfBounds
+		default:
+			origin := protocol.BlockPos{
+				(pk.Position[0] + int32(offset[0])) << 4,
+				subY << 4,
+				(pk.Position[2] + int32(offset[2])) << 4,
+			}
+			subChunk, blockNBTs, allAir := p.server.world.SubChunk(p.server.items, origin)
+			if allAir {
+				entry.Result = protocol.SubChunkResultSuccessAllAir
+				break
+			}
+
+			index := int(subY) - dimensionRange.Min()>>4
+			buf := bytes.NewBuffer(chunk.EncodeSubChunk(subChunk, dimensionRange, index, chunk.NetworkEncoding))
+			for _, blockNBT := range blockNBTs {
+				_ = nbt.NewEncoderWithEncoding(buf, nbt.NetworkLittleEndian).Encode(blockNBT)
+			}
+			entry.Result = protocol.SubChunkResultSuccess
+			entry.RawPayload = buf.Bytes()
+		}
+
+		resp.SubChunkEntries = append(resp.SubChunkEntries, entry)
+	}
+
+	_ = p.conn.WritePacket(resp)
+}
+
+// handleBlockPickRequest 将被选取的方块放入玩家的物品栏
+func (p *player) handleBlockPickRequest(pk *packet.BlockPickRequest) {
+	b := p.server.world.Block(pk.Position)
+	if b.IsAir() {
+		return
+	}
+	item, found := p.server.items.NewItem(b.Name, 1, 0, "")
+	if !found {
+		return
+	}
+	if pk.AddBlockNBT {
+		if blockNBT := b.BlockNBT(p.server.items, pk.Position); blockNBT != nil {
+			item.NBTData = utils.DeepCopyNBT(blockNBT)
+			delete(item.NBTData, "x")
+			delete(item.NBTData, "y")
+			delete(item.NBTData, "z")
+		}
+	}
+
+	slot := p.heldSlot
+	if p.inventory[slot].Stack.NetworkID != 0 {
+		for index := range uint32(9) {
+			if p.inventory[index].Stack.NetworkID == 0 {
+				slot = index
+				break
+			}
+		}
+	}
+
+	p.setInventorySlot(int(slot), item)
+	p.heldSlot = slot
+	_ = p.conn.WritePacket(&packet.PlayerHotBar{
+		SelectedHotBarSlot: slot,
+		WindowID:           protocol.WindowIDInventory,
+		SelectHotBarSlot:   true,
+	})
+}
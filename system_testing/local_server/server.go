@@ -0,0 +1,340 @@
+package local_server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol/packet"
+	"github.com/OmineDev/flowers-for-machines/core/py_rpc"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pterm/pterm"
+)
+
+// DefaultAddress 是本地服务器默认监听的地址。
+// 端口为 0 意味着由系统分配一个空闲的端口
+const DefaultAddress = "127.0.0.1:0"
+
+// Config 是本地服务器的配置
+type Config struct {
+	// Address 是本地服务器监听的地址。
+	// 为空时使用 DefaultAddress
+	Address string
+	// ExtraItems 是除 DefaultItems 外，
+	// 还需要注册的物品的名称
+	ExtraItems []string
+	// SpawnPosition 是机器人登录后所处的位置
+	SpawnPosition mgl32.Vec3
+	// Verbose 指示是否在控制台打印
+	// 本地服务器收到的命令
+	Verbose bool
+	// MCPCheckChallenge 指示是否在登录后向客户端
+	// 发出 GetStartType 和 GetMCPCheckNum 挑战。
+	// 它用于测试经由验证服务器的登录流程
+	MCPCheckChallenge bool
+}
+
+// Server 是基于 minecraft.Listener 的本地替身服务器。
+// 它只模拟驱动机器人所需的最少的世界行为，
+// 包括命令、容器、物品堆栈请求和结构的保存与加载，
+// 从而使系统测试可以在没有租赁服和验证服务器的情况下运行
+type Server struct {
+	mu       *sync.Mutex
+	cfg      Config
+	listener *minecraft.Listener
+	items    *itemRegistry
+	world    *World
+	players  map[*player]bool
+	closed   chan struct{}
+	// stackNetworkID 是目前物品堆栈网络 ID 的累计计数
+	stackNetworkID int32
+	// entityID 是目前实体 ID 的累计计数
+	entityID int64
+	// challengeAnswers 是客户端对挑战的回答
+	challengeAnswers []py_rpc.PyRpc
+	// ignoreStructureExports 指示是否不回应
+	// 导出已保存的结构的请求
+	ignoreStructureExports bool
+}
+
+// NewServer 根据 cfg 创建并启动一个新的本地服务器
+func NewServer(cfg Config) (*Server, error) {
+	if len(cfg.Address) == 0 {
+		cfg.Address = DefaultAddress
+	}
+
+	listener, err := minecraft.ListenConfig{
+		AuthenticationDisabled: true,
+		AllowUnknownPackets:    true,
+		AllowInvalidPackets:    true,
+	}.Listen("raknet", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("NewServer: %v", err)
+	}
+
+	s := &Server{
+		mu:       new(sync.Mutex),
+		cfg:      cfg,
+		listener: listener,
+		items:    newItemRegistry(cfg.ExtraItems),
+		world:    NewWorld(),
+		players:  make(map[*player]bool),
+		closed:   make(chan struct{}),
+	}
+	go s.accept()
+
+	return s, nil
+}
+
+// Addr 返回本地服务器实际监听的地址
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Authenticator 返回可以使名为 botName
+// 的机器人登录到此服务器的 Authenticator
+func (s *Server) Authenticator(botName string) *Authenticator {
+	return NewAuthenticator(s.Addr(), botName)
+}
+
+// Close 关闭本地服务器，
+// 这也会断开所有已连接的客户端
+func (s *Server) Close() error {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+		s.mu.Unlock()
+		return nil
+	default:
+		close(s.closed)
+	}
+	for p := range s.players {
+		_ = p.conn.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	if err != nil {
+		return fmt.Errorf("Close: %v", err)
+	}
+	return nil
+}
+
+// Block 返回 pos 处方块的副本。
+// 如果该处是空气，则返回空值
+func (s *Server) Block(pos protocol.BlockPos) *Block {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.world.Block(pos); b != nil {
+		return b.Clone()
+	}
+	return nil
+}
+
+// SetBlock 将 pos 处的方块设置为 b
+func (s *Server) SetBlock(pos protocol.BlockPos, b *Block) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.world.SetBlock(pos, b.Clone())
+}
+
+// NewItem 返回数量为 count 且附加数据为 nbtData 的名为 name 的物品。
+// 它可以被直接放入通过 SetBlock 设置的容器中
+func (s *Server) NewItem(name string, count uint16, nbtData map[string]any) (item protocol.ItemInstance, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stack, found := s.items.NewItem(name, count, 0, "")
+	if !found {
+		return protocol.ItemInstance{}, false
+	}
+	stack.NBTData = nbtData
+	return protocol.ItemInstance{
+		StackNetworkID: s.newStackNetworkID(),
+		Stack:          stack,
+	}, true
+}
+
+// BlockNBT 返回 pos 处方块的方块实体数据
+func (s *Server) BlockNBT(pos protocol.BlockPos) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.world.Block(pos); b != nil {
+		return b.BlockNBT(s.items, pos)
+	}
+	return nil
+}
+
+// ChallengeAnswers 返回目前客户端对 MCPC 检查挑战的全部回答，
+// 它们是 SetStartType 或 SetMCPCheckNum
+func (s *Server) ChallengeAnswers() []py_rpc.PyRpc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]py_rpc.PyRpc(nil), s.challengeAnswers...)
+}
+
+// SetIgnoreStructureExports 设置本地服务器是否不回应
+// 导出已保存的结构的请求。它可用于模拟导出结构超时
+func (s *Server) SetIgnoreStructureExports(ignore bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignoreStructureExports = ignore
+}
+
+// KickAll 断开所有已连接的客户端。
+// 它可用于测试机器人的重新连接
+func (s *Server) KickAll(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p := range s.players {
+		_ = s.listener.Disconnect(p.conn, message)
+	}
+}
+
+// newStackNetworkID 返回一个新的物品堆栈网络 ID
+func (s *Server) newStackNetworkID() int32 {
+	s.stackNetworkID++
+	return s.stackNetworkID
+}
+
+// accept 持续接受新的客户端
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+			default:
+				pterm.Warning.Printfln("accept: %v", err)
+			}
+			return
+		}
+		go s.handleConn(conn.(*minecraft.Conn))
+	}
+}
+
+// handleConn 完成 conn 的登录序列，
+// 然后持续处理 conn 发送的数据包
+func (s *Server) handleConn(conn *minecraft.Conn) {
+	defer conn.Close()
+
+	s.mu.Lock()
+	s.entityID++
+	p := newPlayer(s, conn, s.entityID)
+	s.mu.Unlock()
+
+	err := conn.StartGame(minecraft.GameData{
+		WorldName:                    "flowers-for-machines local server",
+		EntityUniqueID:               p.entityID,
+		EntityRuntimeID:              uint64(p.entityID),
+		PlayerGameMode:               1,
+		WorldGameMode:                1,
+		PlayerPosition:               s.cfg.SpawnPosition,
+		BaseGameVersion:              "*",
+		Items:                        s.items.entries,
+		ServerAuthoritativeInventory: true,
+		PlayerPermissions:            2,
+		ChunkRadius:                  4,
+		UseBlockNetworkIDHashes:      true,
+	})
+	if err != nil {
+		pterm.Warning.Printfln("handleConn: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.players[p] = true
+	p.sendLoginPackets()
+	if s.cfg.MCPCheckChallenge {
+		p.sendChallenges()
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.players, p)
+		s.mu.Unlock()
+	}()
+
+	for {
+		pk, err := conn.ReadPacket()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		p.handlePacket(pk)
+		s.mu.Unlock()
+	}
+}
+
+// sendLoginPackets 发送客户端在登录后需要的常量数据包和库存数据
+func (p *player) sendLoginPackets() {
+	enumValues := make([]string, 0, len(p.server.items.entries))
+	valueIndices := make([]uint, 0, len(p.server.items.entries))
+	for _, entry := range p.server.items.entries {
+		if !p.server.items.CanGetByCommand(entry.Name) {
+			continue
+		}
+		valueIndices = append(valueIndices, uint(len(enumValues)))
+		enumValues = append(enumValues, entry.Name)
+	}
+	_ = p.conn.WritePacket(&packet.AvailableCommands{
+		EnumValues: enumValues,
+		Enums: []protocol.CommandEnum{
+			{Type: "Item", ValueIndices: valueIndices},
+		},
+	})
+	_ = p.conn.WritePacket(&packet.CreativeContent{})
+	p.sendCraftingData()
+
+	p.syncInventory()
+	_ = p.conn.WritePacket(&packet.InventoryContent{
+		WindowID: protocol.WindowIDOffHand,
+		Content:  make([]protocol.ItemInstance, 1),
+	})
+	_ = p.conn.WritePacket(&packet.InventoryContent{
+		WindowID: protocol.WindowIDArmour,
+		Content:  make([]protocol.ItemInstance, 4),
+	})
+}
+
+// sendChallenges 向客户端发出 GetStartType 和 GetMCPCheckNum 挑战
+func (p *player) sendChallenges() {
+	_ = p.conn.WritePacket(&packet.PyRpc{
+		Value: py_rpc.Marshal(&py_rpc.StartType{
+			Content: p.uniqueID.String(),
+			Type:    py_rpc.StartTypeRequest,
+		}),
+		OperationType: packet.PyRpcOperationTypeSend,
+	})
+	_ = p.conn.WritePacket(&packet.PyRpc{
+		Value: py_rpc.Marshal(&py_rpc.GetMCPCheckNum{
+			FirstArg: p.name,
+			SecondArg: py_rpc.GetMCPCheckNumSecondArg{
+				Arg:             p.uniqueID.String()[:10],
+				FirstExtraData:  []any{},
+				SecondExtraData: []any{},
+			},
+		}),
+		OperationType: packet.PyRpcOperationTypeSend,
+	})
+}
+
+// handlePyRpc 记录客户端对挑战的回答
+func (p *player) handlePyRpc(pk *packet.PyRpc) {
+	if pk.Value == nil {
+		return
+	}
+	content, err := py_rpc.Unmarshal(pk.Value)
+	if err != nil {
+		return
+	}
+	switch c := content.(type) {
+	case *py_rpc.StartType:
+		if c.Type == py_rpc.StartTypeResponse {
+			p.server.challengeAnswers = append(p.server.challengeAnswers, c)
+		}
+	case *py_rpc.SetMCPCheckNum:
+		p.server.challengeAnswers = append(p.server.challengeAnswers, c)
+	}
+}
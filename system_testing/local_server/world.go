@@ -0,0 +1,392 @@
+package local_server
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/nbt"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/mcstructure"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+)
+
+// MaxFillVolume 是 fill 和 structure save 命令可以操作的最大方块数量
+const MaxFillVolume = 32768
+
+// blockEntityID 记载了具有方块实体的方块的方块实体 ID
+var blockEntityID = map[string]string{
+	"minecraft:chest":         "Chest",
+	"minecraft:trapped_chest": "Chest",
+	"minecraft:barrel":        "Barrel",
+	"minecraft:hopper":        "Hopper",
+	"minecraft:dispenser":     "Dispenser",
+	"minecraft:dropper":       "Dropper",
+	"minecraft:furnace":       "Furnace",
+	"minecraft:lit_furnace":   "Furnace",
+	"minecraft:blast_furnace": "BlastFurnace",
+	"minecraft:smoker":        "Smoker",
+	"minecraft:brewing_stand": "BrewingStand",
+	"minecraft:crafter":       "Crafter",
+
+	"minecraft:bed":                "Bed",
+	"minecraft:skull":              "Skull",
+	"minecraft:flower_pot":         "FlowerPot",
+	"minecraft:decorated_pot":      "DecoratedPot",
+	"minecraft:chiseled_bookshelf": "ChiseledBookshelf",
+	"minecraft:mob_spawner":        "MobSpawner",
+	"minecraft:campfire":           "Campfire",
+	"minecraft:soul_campfire":      "Campfire",
+	"minecraft:cauldron":           "Cauldron",
+}
+
+// Block 是本地服务器中的单个方块
+type Block struct {
+	Name   string
+	States map[string]any
+	// CustomName 是容器的自定义名称
+	CustomName string
+	// Items 是容器中的物品，
+	// 它只对容器方块有效
+	Items map[byte]protocol.ItemInstance
+	// DisabledSlots 是合成器被禁用的物品栏，
+	// 它的第 i 位指示第 i 个物品栏是否被禁用
+	DisabledSlots int16
+	// NBT 是非容器方块的方块实体数据，
+	// 它不包含方块实体 ID 和坐标
+	NBT map[string]any
+}
+
+// NewBlock 创建一个名为 name 的方块。
+// states 会覆盖该方块默认的方块状态。
+// 如果该方块不存在，则 found 为假
+func NewBlock(name string, states map[string]any) (result *Block, found bool) {
+	name = normalizeName(name)
+	rid, found := block.StateToRuntimeID(name, map[string]any{})
+	if !found {
+		return nil, false
+	}
+	_, defaultStates, _ := block.RuntimeIDToState(rid)
+
+	result = &Block{
+		Name:   name,
+		States: make(map[string]any),
+	}
+	maps.Copy(result.States, defaultStates)
+	maps.Copy(result.States, states)
+	if IsContainer(name) {
+		result.Items = make(map[byte]protocol.ItemInstance)
+	} else if _, ok := blockEntityID[name]; ok {
+		result.NBT = defaultBlockNBT(name)
+	}
+	return result, true
+}
+
+// defaultBlockNBT 返回名为 name 的非容器方块被放置时的方块实体数据
+func defaultBlockNBT(name string) map[string]any {
+	result := make(map[string]any)
+	if name == "minecraft:chiseled_bookshelf" {
+		// 雕纹书架的物品列表总是包含所有槽位
+		items := make([]any, mapping.ChiseledBookshelfSlotCount)
+		for index := range items {
+			items[index] = map[string]any{
+				"Name":   "",
+				"Count":  byte(0),
+				"Damage": int16(0),
+			}
+		}
+		result["Items"] = items
+		result["LastInteractedSlot"] = int32(0)
+	}
+	return result
+}
+
+// Clone 返回方块 b 的深拷贝
+func (b *Block) Clone() *Block {
+	result := &Block{
+		Name:          b.Name,
+		States:        maps.Clone(b.States),
+		CustomName:    b.CustomName,
+		DisabledSlots: b.DisabledSlots,
+	}
+	if b.Items != nil {
+		result.Items = make(map[byte]protocol.ItemInstance)
+		for slot, item := range b.Items {
+			result.Items[slot] = cloneItem(item)
+		}
+	}
+	if b.NBT != nil {
+		result.NBT = utils.DeepCopyNBT(b.NBT)
+	}
+	return result
+}
+
+// IsAir 检查方块 b 是否是空气
+func (b *Block) IsAir() bool {
+	return b == nil || b.Name == "minecraft:air"
+}
+
+// IsContainer 检查名为 name 的方块是否是可以存放物品的容器
+func IsContainer(name string) bool {
+	_, ok := mapping.ContainerStorageKey[normalizeName(name)]
+	return ok
+}
+
+// entityID 返回方块 b 的方块实体 ID
+func (b *Block) entityID() string {
+	if strings.Contains(b.Name, "shulker_box") {
+		return "ShulkerBox"
+	}
+	return blockEntityID[b.Name]
+}
+
+// BlockNBT 返回位于 pos 的方块 b 的方块实体数据。
+// 如果该方块没有方块实体，则返回空值
+func (b *Block) BlockNBT(items *itemRegistry, pos protocol.BlockPos) map[string]any {
+	if b.Items == nil && b.NBT == nil {
+		return nil
+	}
+
+	result := map[string]any{
+		"id":        b.entityID(),
+		"x":         pos[0],
+		"y":         pos[1],
+		"z":         pos[2],
+		"isMovable": byte(1),
+	}
+	if b.NBT != nil {
+		maps.Copy(result, utils.DeepCopyNBT(b.NBT))
+		return result
+	}
+
+	itemList := make([]any, 0)
+	for slot := range byte(255) {
+		item, ok := b.Items[slot]
+		if !ok || item.Stack.NetworkID == 0 {
+			continue
+		}
+		itemList = append(itemList, items.ItemToNBT(item.Stack, slot))
+	}
+	result["Items"] = itemList
+	if len(b.CustomName) > 0 {
+		result["CustomName"] = b.CustomName
+	}
+	if b.Name == "minecraft:crafter" {
+		result["disabled_slots"] = b.DisabledSlots
+	}
+	return result
+}
+
+// Structure 是通过 structure save 命令保存的结构
+type Structure struct {
+	Origin protocol.BlockPos
+	Size   protocol.BlockPos
+	// Blocks 是结构中相对坐标到方块的映射，
+	// 不存在的方块视为空气
+	Blocks map[protocol.BlockPos]*Block
+}
+
+// World 是本地服务器所模拟的世界
+type World struct {
+	blocks     map[protocol.BlockPos]*Block
+	structures map[string]*Structure
+}
+
+// NewWorld 创建并返回一个新的空世界
+func NewWorld() *World {
+	return &World{
+		blocks:     make(map[protocol.BlockPos]*Block),
+		structures: make(map[string]*Structure),
+	}
+}
+
+// Block 返回 pos 处的方块。
+// 如果该处是空气，则返回空值
+func (w *World) Block(pos protocol.BlockPos) *Block {
+	return w.blocks[pos]
+}
+
+// SetBlock 将 pos 处的方块设置为 b
+func (w *World) SetBlock(pos protocol.BlockPos, b *Block) {
+	if b.IsAir() {
+		delete(w.blocks, pos)
+		return
+	}
+	w.blocks[pos] = b
+}
+
+// SubChunk 返回原点为 origin 的子区块中的全部方块，
+// 以及这些方块的方块实体数据。如果该子区块中只有空气，
+// 则 allAir 为真
+func (w *World) SubChunk(items *itemRegistry, origin protocol.BlockPos) (
+	subChunk *chunk.SubChunk,
+	blockNBTs []map[string]any,
+	allAir bool,
+) {
+	subChunk = chunk.NewSubChunk(block.AirRuntimeID)
+	allAir = true
+
+	for pos, b := range w.blocks {
+		if pos[0]>>4 != origin[0]>>4 || pos[1]>>4 != origin[1]>>4 || pos[2]>>4 != origin[2]>>4 {
+			continue
+		}
+		rid, found := block.StateToRuntimeID(b.Name, b.States)
+		if !found {
+			continue
+		}
+		subChunk.SetBlock(byte(pos[0]-origin[0]), byte(pos[1]-origin[1]), byte(pos[2]-origin[2]), 0, rid)
+		if blockNBT := b.BlockNBT(items, pos); blockNBT != nil {
+			blockNBTs = append(blockNBTs, blockNBT)
+		}
+		allAir = false
+	}
+
+	return
+}
+
+// regionOf 返回由 start 和 end 描述的区域的起点和尺寸
+func regionOf(start protocol.BlockPos, end protocol.BlockPos) (origin protocol.BlockPos, size protocol.BlockPos) {
+	for i := range 3 {
+		origin[i] = min(start[i], end[i])
+		size[i] = max(start[i], end[i]) - origin[i] + 1
+	}
+	return
+}
+
+// Fill 将 start 到 end 的区域填充为 b。
+// 如果区域过大，则返回错误
+func (w *World) Fill(start protocol.BlockPos, end protocol.BlockPos, b *Block) (count int, err error) {
+	origin, size := regionOf(start, end)
+	if int(size[0])*int(size[1])*int(size[2]) > MaxFillVolume {
+		return 0, fmt.Errorf("Fill: Too many blocks in the specified area")
+	}
+	for x := range size[0] {
+		for y := range size[1] {
+			for z := range size[2] {
+				w.SetBlock(protocol.BlockPos{origin[0] + x, origin[1] + y, origin[2] + z}, b.Clone())
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// SaveStructure 将 start 到 end 的区域保存为名为 name 的结构
+func (w *World) SaveStructure(name string, start protocol.BlockPos, end protocol.BlockPos) error {
+	origin, size := regionOf(start, end)
+	if int(size[0])*int(size[1])*int(size[2]) > MaxFillVolume {
+		return fmt.Errorf("SaveStructure: Too many blocks in the specified area")
+	}
+
+	structure := &Structure{
+		Origin: origin,
+		Size:   size,
+		Blocks: make(map[protocol.BlockPos]*Block),
+	}
+	for x := range size[0] {
+		for y := range size[1] {
+			for z := range size[2] {
+				b := w.Block(protocol.BlockPos{origin[0] + x, origin[1] + y, origin[2] + z})
+				if !b.IsAir() {
+					structure.Blocks[protocol.BlockPos{x, y, z}] = b.Clone()
+				}
+			}
+		}
+	}
+
+	w.structures[name] = structure
+	return nil
+}
+
+// LoadStructure 将名为 name 的结构加载到 pos 处。
+// 如果结构不存在，则 found 为假
+func (w *World) LoadStructure(name string, pos protocol.BlockPos) (found bool) {
+	structure, found := w.structures[name]
+	if !found {
+		return false
+	}
+	for x := range structure.Size[0] {
+		for y := range structure.Size[1] {
+			for z := range structure.Size[2] {
+				target := protocol.BlockPos{pos[0] + x, pos[1] + y, pos[2] + z}
+				b, ok := structure.Blocks[protocol.BlockPos{x, y, z}]
+				if !ok {
+					delete(w.blocks, target)
+					continue
+				}
+				w.blocks[target] = b.Clone()
+			}
+		}
+	}
+	return true
+}
+
+// DeleteStructure 删除名为 name 的结构
+func (w *World) DeleteStructure(name string) (found bool) {
+	_, found = w.structures[name]
+	delete(w.structures, name)
+	return
+}
+
+// Structure 返回名为 name 的结构
+func (w *World) Structure(name string) (structure *Structure, found bool) {
+	structure, found = w.structures[name]
+	return
+}
+
+// Template 将结构 s 编码为 StructureTemplateDataResponse 所使用的模板
+func (s *Structure) Template(items *itemRegistry) (map[string]any, error) {
+	result := mcstructure.NewStructure(s.Size)
+	result.Origin = s.Origin
+
+	paletteIndex := make(map[uint32]int32)
+	for x := range s.Size[0] {
+		for y := range s.Size[1] {
+			for z := range s.Size[2] {
+				relativePos := protocol.BlockPos{x, y, z}
+				index := result.Index(relativePos)
+
+				b, ok := s.Blocks[relativePos]
+				if !ok {
+					b = &Block{Name: "minecraft:air", States: map[string]any{}}
+				}
+
+				rid, _ := block.StateToRuntimeID(b.Name, b.States)
+				if _, ok := paletteIndex[rid]; !ok {
+					paletteIndex[rid] = int32(len(result.Palette))
+					result.Palette = append(result.Palette, mcstructure.PaletteBlock{
+						Name:    b.Name,
+						States:  b.States,
+						Version: mcstructure.DefaultBlockVersion,
+					})
+				}
+				result.BlockIndices[mcstructure.LayerPrimary][index] = paletteIndex[rid]
+
+				worldPos := protocol.BlockPos{s.Origin[0] + x, s.Origin[1] + y, s.Origin[2] + z}
+				if blockNBT := b.BlockNBT(items, worldPos); blockNBT != nil {
+					result.BlockPositionData[index] = map[string]any{
+						"block_entity_data": blockNBT,
+					}
+				}
+			}
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err := mcstructure.Encode(buf, result)
+	if err != nil {
+		return nil, fmt.Errorf("Template: %v", err)
+	}
+
+	var template map[string]any
+	err = nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian).Decode(&template)
+	if err != nil {
+		return nil, fmt.Errorf("Template: %v", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,245 @@
+package mock_auth_server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/OmineDev/flowers-for-machines/core/bunker/auth"
+	"github.com/OmineDev/flowers-for-machines/system_testing/local_server"
+
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+// DefaultAddress 是模拟验证服务器默认监听的地址。
+// 端口为 0 意味着由系统分配一个空闲的端口
+const DefaultAddress = "127.0.0.1:0"
+
+// Config 是模拟验证服务器的配置。
+// 除 RentalServerIP 外，所有字段都可以为空
+type Config struct {
+	// Address 是模拟验证服务器监听的地址。
+	// 为空时使用 DefaultAddress
+	Address string
+	// RentalServerIP 是登录成功后返回的租赁服地址，
+	// 它通常是本地服务器的地址
+	RentalServerIP string
+
+	// ServerCode 和 ServerPassword 非空时，
+	// 登录请求中的租赁服号和密码必须与之相同
+	ServerCode     string
+	ServerPassword string
+
+	// BotName 是机器人的名称，为空时使用 "MockBot"
+	BotName string
+	// BotLevel 是机器人的等级
+	BotLevel int
+	// BotComponent 是机器人已加载的组件及其附加值
+	BotComponent map[string]*int
+	// MasterName 是机器人主人的游戏名称
+	MasterName string
+
+	// StartTypeAnswer 是 GetStartType 挑战的固定答案。
+	// 为空时原样返回挑战的内容
+	StartTypeAnswer string
+	// CheckNumAnswer 是 GetMCPCheckNum 挑战的固定答案
+	CheckNumAnswer []any
+}
+
+// Server 是实现了验证服务器 HTTP API 的本地模拟服务器。
+// 它返回固定的链请求、挑战答案和机器人组件数据，
+// 从而使登录流程和 MCPC 检查挑战的处理无需网络即可测试
+type Server struct {
+	mu       *sync.Mutex
+	cfg      Config
+	listener net.Listener
+	server   *http.Server
+	secret   string
+
+	startTypeRequests []string
+	checkNumRequests  []string
+}
+
+// NewServer 根据 cfg 创建并启动一个新的模拟验证服务器
+func NewServer(cfg Config) (*Server, error) {
+	if len(cfg.Address) == 0 {
+		cfg.Address = DefaultAddress
+	}
+	if len(cfg.BotName) == 0 {
+		cfg.BotName = "MockBot"
+	}
+
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("NewServer: %v", err)
+	}
+
+	s := &Server{
+		mu:       new(sync.Mutex),
+		cfg:      cfg,
+		listener: listener,
+		secret:   uuid.NewString(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/new", s.handleNew)
+	mux.HandleFunc("POST /api/phoenix/login", s.handleLogin)
+	mux.HandleFunc("GET /api/phoenix/transfer_start_type", s.handleTransferStartType)
+	mux.HandleFunc("POST /api/phoenix/transfer_check_num", s.handleTransferCheckNum)
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		err := s.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			pterm.Warning.Printfln("NewServer: %v", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Address 返回可以作为 client.Config.AuthServerAddress 使用的地址
+func (s *Server) Address() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+// Close 关闭模拟验证服务器
+func (s *Server) Close() error {
+	err := s.server.Close()
+	if err != nil {
+		return fmt.Errorf("Close: %v", err)
+	}
+	return nil
+}
+
+// StartTypeRequests 返回目前收到的所有 GetStartType 挑战的内容
+func (s *Server) StartTypeRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.startTypeRequests...)
+}
+
+// CheckNumRequests 返回目前收到的所有 GetMCPCheckNum 挑战的数据
+func (s *Server) CheckNumRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.checkNumRequests...)
+}
+
+// writeError 以验证服务器的格式写入错误信息
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.WriteHeader(statusCode)
+	_, _ = fmt.Fprintf(w, "%d %s\n\n%s\n", statusCode, http.StatusText(statusCode), message)
+}
+
+// writeJSON 将 value 以 JSON 格式写入
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// authorized 检查请求 r 是否携带了 /api/new 所签发的密钥
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") != "Bearer "+s.secret {
+		writeError(w, http.StatusUnauthorized, "Invalid secret")
+		return false
+	}
+	return true
+}
+
+// handleNew 签发客户端在后续请求中使用的密钥
+func (s *Server) handleNew(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(s.secret))
+}
+
+// handleLogin 处理登录请求，并返回由本地签发的链请求
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+
+	var request auth.AuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if (len(s.cfg.ServerCode) > 0 && request.ServerCode != s.cfg.ServerCode) ||
+		(len(s.cfg.ServerPassword) > 0 && request.ServerPassword != s.cfg.ServerPassword) {
+		writeJSON(w, auth.AuthResponse{
+			Message: auth.Message{Information: "Incorrect server code or passcode", Translation: -1},
+		})
+		return
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(request.ClientPublicKey)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authenticator := local_server.NewAuthenticator(s.cfg.RentalServerIP, s.cfg.BotName)
+	resp, err := authenticator.GetAccess(r.Context(), publicKey)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Message = auth.Message{Information: "well down", Translation: -1}
+	resp.BotLevel = s.cfg.BotLevel
+	resp.BotComponent = s.cfg.BotComponent
+	resp.MasterName = s.cfg.MasterName
+	writeJSON(w, resp)
+}
+
+// handleTransferStartType 回答 GetStartType 挑战
+func (s *Server) handleTransferStartType(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+
+	content := r.URL.Query().Get("content")
+	s.mu.Lock()
+	s.startTypeRequests = append(s.startTypeRequests, content)
+	s.mu.Unlock()
+
+	answer := content
+	if len(s.cfg.StartTypeAnswer) > 0 {
+		answer = s.cfg.StartTypeAnswer
+	}
+	writeJSON(w, map[string]any{
+		"success": true,
+		"data":    answer,
+	})
+}
+
+// handleTransferCheckNum 回答 GetMCPCheckNum 挑战
+func (s *Server) handleTransferCheckNum(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+
+	var request auth.FNumRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	s.checkNumRequests = append(s.checkNumRequests, request.Data)
+	s.mu.Unlock()
+
+	answer := s.cfg.CheckNumAnswer
+	if answer == nil {
+		answer = []any{}
+	}
+	value, _ := json.Marshal(answer)
+	writeJSON(w, map[string]any{
+		"success": true,
+		"value":   string(value),
+	})
+}
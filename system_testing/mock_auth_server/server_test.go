@@ -0,0 +1,138 @@
+package mock_auth_server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"reflect"
+	"testing"
+
+	"github.com/OmineDev/flowers-for-machines/core/bunker/auth"
+)
+
+// newTestClient 启动一个使用 cfg 的模拟验证服务器，
+// 并返回连接到该服务器的验证客户端
+func newTestClient(t *testing.T, cfg Config) (*Server, *auth.Client) {
+	t.Helper()
+
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	authClient, err := auth.CreateClient(&auth.ClientOptions{AuthServer: s.Address()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, authClient
+}
+
+// newPublicKey 返回一个新生成的，以 PKIX 格式编码的公钥
+func newPublicKey(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return publicKey
+}
+
+func TestTransferData(t *testing.T) {
+	// 没有设置固定答案时原样返回挑战的内容
+	s, authClient := newTestClient(t, Config{})
+	answer, err := authClient.TransferData("challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "challenge" {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+
+	s, authClient = newTestClient(t, Config{StartTypeAnswer: "mock start type"})
+	answer, err = authClient.TransferData("challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "mock start type" {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+	if requests := s.StartTypeRequests(); !reflect.DeepEqual(requests, []string{"challenge"}) {
+		t.Fatalf("unexpected start type requests %#v", requests)
+	}
+}
+
+func TestTransferCheckNum(t *testing.T) {
+	s, authClient := newTestClient(t, Config{CheckNumAnswer: []any{"mock", float64(1)}})
+	answer, err := authClient.TransferCheckNum(`["data"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != `["mock",1]` {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+	if requests := s.CheckNumRequests(); !reflect.DeepEqual(requests, []string{`["data"]`}) {
+		t.Fatalf("unexpected check num requests %#v", requests)
+	}
+
+	// 没有设置固定答案时返回空列表
+	_, authClient = newTestClient(t, Config{})
+	answer, err = authClient.TransferCheckNum(`["data"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "[]" {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, authClient := newTestClient(t, Config{})
+	other, _ := newTestClient(t, Config{})
+
+	// 携带其他服务器所签发的密钥的请求应当被拒绝
+	authClient.AuthServer = other.Address()
+	if _, err := authClient.TransferData("challenge"); err == nil {
+		t.Fatal("TransferData with a foreign secret should fail")
+	}
+	if _, err := authClient.TransferCheckNum("[]"); err == nil {
+		t.Fatal("TransferCheckNum with a foreign secret should fail")
+	}
+}
+
+func TestLogin(t *testing.T) {
+	_, authClient := newTestClient(t, Config{
+		RentalServerIP: "127.0.0.1:19132",
+		ServerCode:     "123456",
+		ServerPassword: "654321",
+		BotName:        "MockBot",
+		BotLevel:       7,
+		MasterName:     "Master",
+	})
+	publicKey := newPublicKey(t)
+
+	// 密码错误时登录失败
+	wrongPasscode := auth.NewAccessWrapper(authClient, "123456", "000000", "", "", "")
+	if _, err := wrongPasscode.GetAccess(context.Background(), publicKey); err == nil {
+		t.Fatal("login with wrong passcode should fail")
+	}
+
+	accessWrapper := auth.NewAccessWrapper(authClient, "123456", "654321", "", "", "")
+	resp, err := accessWrapper.GetAccess(context.Background(), publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.SuccessStates || resp.RentalServerIP != "127.0.0.1:19132" || len(resp.ChainInfo) == 0 {
+		t.Fatalf("unexpected auth response %#v", resp)
+	}
+	if authClient.GrowthLevel != 7 || authClient.RespondTo != "Master" {
+		t.Fatalf("unexpected client info %#v", authClient.ClientInfo)
+	}
+}
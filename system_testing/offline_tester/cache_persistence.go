@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingCachePersistence() {
+	tA := time.Now()
+
+	dir, err := os.MkdirTemp("", "cache_persistence")
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to create temp dir due to %v", err))
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache.json")
+
+	console, err := nbt_console.NewConsole(api, protocol.BlockPos{64, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to create console due to %v", err))
+	}
+	cache := nbt_cache.NewNBTCacheSystem(console)
+	if _, _, err = cache.SetPersistentFile(path); err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to set persistent file due to %v", err))
+	}
+
+	assigner := nbt_assigner.NewNBTAssigner(console, cache)
+	_, _, _, err = assigner.PlaceNBTBlock(
+		"minecraft:chest",
+		map[string]any{"minecraft:cardinal_direction": "north"},
+		map[string]any{
+			"id":         "Chest",
+			"CustomName": "Persistent Chest",
+			"Items": []any{
+				map[string]any{"Name": "minecraft:apple", "Count": byte(3), "Damage": int16(0), "Slot": byte(0)},
+			},
+		},
+	)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to place NBT block due to %v", err))
+	}
+	if err = cache.Sync(); err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to sync cache due to %v", err))
+	}
+
+	// 追加一条标识符无法解析的记录和一条结构不存在的记录
+	var file map[string]any
+	fileBytes, err := os.ReadFile(path)
+	if err == nil {
+		// 哈希校验和超出了 float64 的精度
+		decoder := json.NewDecoder(bytes.NewReader(fileBytes))
+		decoder.UseNumber()
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to read cache file due to %v", err))
+	}
+	saved := 0
+	for _, key := range []string{"base_container", "nbt_block", "filled_map"} {
+		records, _ := file[key].([]any)
+		saved += len(records)
+	}
+	records, _ := file["nbt_block"].([]any)
+	file["nbt_block"] = append(
+		records,
+		map[string]any{"unique_id": "broken", "hash_number": 1},
+		map[string]any{"unique_id": uuid.New().String(), "hash_number": 2},
+	)
+	fileBytes, _ = json.Marshal(file)
+	if err = os.WriteFile(path, fileBytes, 0644); err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to write cache file due to %v", err))
+	}
+
+	restored, discarded, err := nbt_cache.NewNBTCacheSystem(console).SetPersistentFile(path)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to restore cache due to %v", err))
+	}
+	if saved == 0 || restored != saved || discarded != 2 {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Unexpected restored %d and discarded %d (saved %d)", restored, discarded, saved))
+	}
+
+	// 导出结构超时的记录被丢弃，而不是使恢复失败
+	timeout := game_interface.DefaultTimeoutCommandRequest
+	game_interface.DefaultTimeoutCommandRequest = 200 * time.Millisecond
+	server.SetIgnoreStructureExports(true)
+	restored, discarded, err = nbt_cache.NewNBTCacheSystem(console).SetPersistentFile(path)
+	server.SetIgnoreStructureExports(false)
+	game_interface.DefaultTimeoutCommandRequest = timeout
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Failed to restore cache with timeouts due to %v", err))
+	}
+	if restored != 0 || discarded != saved {
+		panic(fmt.Sprintf("SystemTestingCachePersistence: Unexpected restored %d and discarded %d with timeouts (saved %d)", restored, discarded, saved))
+	}
+
+	pterm.Success.Printfln("SystemTestingCachePersistence: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingContainer() {
+	tA := time.Now()
+
+	// OpenInventory
+	{
+		success, err := api.ContainerOpenAndClose().OpenInventory()
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingContainer: `OpenInventory` failed due to %v", err))
+		}
+		if !success {
+			panic("SystemTestingContainer: `OpenInventory` failed on test round 1")
+		}
+		err = api.ContainerOpenAndClose().CloseContainer()
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingContainer: `CloseContainer` failed due to %v", err))
+		}
+	}
+
+	// OpenContainer
+	{
+		api.BotClick().ChangeSelectedHotbarSlot(5)
+		api.Commands().SendSettingsCommand("tp 64 89 64", true)
+		api.Commands().SendSettingsCommand(`setblock 64 89 64 chest ["minecraft:cardinal_direction"="east"]`, true)
+		api.Commands().AwaitChangesGeneral()
+
+		success, err := api.ContainerOpenAndClose().OpenContainer(
+			game_interface.UseItemOnBlocks{
+				HotbarSlotID: 8,
+				BotPos:       mgl32.Vec3{64, 89, 64},
+				BlockPos:     [3]int32{64, 89, 64},
+				BlockName:    "chest",
+				BlockStates: map[string]any{
+					"minecraft:cardinal_direction": "east",
+				},
+			},
+			true,
+		)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingContainer: `OpenContainer` failed due to %v", err))
+		}
+		if !success {
+			panic("SystemTestingContainer: `OpenContainer` failed on test round 1")
+		}
+		err = api.ContainerOpenAndClose().CloseContainer()
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingContainer: `CloseContainer` failed due to %v", err))
+		}
+	}
+
+	api.SetBlock().SetBlock([3]int32{64, 89, 64}, "air", "[]")
+	pterm.Success.Printfln("SystemTestingContainer: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/client"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/system_testing/local_server"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingLogin() {
+	var err error
+	tA := time.Now()
+
+	server, err = local_server.NewServer(local_server.Config{
+		SpawnPosition: mgl32.Vec3{64, 89, 64},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	c, err = client.LoginLocalServer(server.Authenticator("LocalBot"))
+	if err != nil {
+		panic(err)
+	}
+	resources = resources_control.NewResourcesControl(c)
+	api = game_interface.NewGameInterface(resources)
+
+	pterm.Success.Printfln("SystemTestingLogin: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/client"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/system_testing/local_server"
+
+	"github.com/pterm/pterm"
+)
+
+var (
+	server    *local_server.Server
+	c         *client.Client
+	resources *resources_control.Resources
+	api       *game_interface.GameInterface
+)
+
+// scenarios 是登录后依次执行的全部系统测试。
+// 后面的测试可能依赖于前面的测试所留下的状态，
+// 因此它们必须按顺序执行
+var scenarios = []struct {
+	name string
+	run  func()
+}{
+	{"Setblock", SystemTestingSetblock},
+	{"StructrueBackup", SystemTestingStructrueBackup},
+	{"Container", SystemTestingContainer},
+	{"NBTAssigner", SystemTestingNBTAssigner},
+	{"NBTBlocks", SystemTestingNBTBlocks},
+	{"NBTItems", SystemTestingNBTItems},
+	{"MCStructure", SystemTestingMCStructure},
+	{"SharedCache", SystemTestingSharedCache},
+	{"CachePersistence", SystemTestingCachePersistence},
+	{"Metrics", SystemTestingMetrics},
+	{"MockAuth", SystemTestingMockAuth},
+}
+
+// closeConnection 断开机器人与本地服务器的连接，并关闭本地服务器
+func closeConnection() {
+	c.Conn().Close()
+	server.Close()
+	time.Sleep(time.Second)
+}
+
+func main() {
+	tA := time.Now()
+
+	SystemTestingLogin()
+	defer closeConnection()
+
+	for _, scenario := range scenarios {
+		scenario.run()
+	}
+
+	pterm.Success.Printfln("System Testing: ALL PASS (Time used = %v)", time.Since(tA))
+}
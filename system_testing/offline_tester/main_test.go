@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+)
+
+// runScenario 执行系统测试 run，
+// 并将其引发的 panic 报告为测试失败
+func runScenario(t *testing.T, run func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal(r)
+		}
+	}()
+	run()
+}
+
+func TestSystem(t *testing.T) {
+	if testing.Short() {
+		t.Skip("system testing is skipped in short mode")
+	}
+
+	if !t.Run("Login", func(t *testing.T) { runScenario(t, SystemTestingLogin) }) {
+		t.FailNow()
+	}
+	t.Cleanup(closeConnection)
+
+	for _, scenario := range scenarios {
+		if !t.Run(scenario.name, func(t *testing.T) { runScenario(t, scenario.run) }) {
+			t.FailNow()
+		}
+	}
+}
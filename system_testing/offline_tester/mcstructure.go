@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mcstructure"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingMCStructure() {
+	tA := time.Now()
+
+	console, err := nbt_console.NewConsole(api, protocol.BlockPos{64, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to create console due to %v", err))
+	}
+	assigner := nbt_assigner.NewNBTAssigner(console, nbt_cache.NewNBTCacheSystem(console))
+
+	// 结构的 X 轴上依次是石头、箱子、床尾、床头和含水的栅栏
+	structure := mcstructure.NewStructure(protocol.BlockPos{5, 1, 1})
+	structure.Palette = []mcstructure.PaletteBlock{
+		{Name: "minecraft:stone", States: map[string]any{}, Version: mcstructure.DefaultBlockVersion},
+		{
+			Name:    "minecraft:chest",
+			States:  map[string]any{"minecraft:cardinal_direction": "south"},
+			Version: mcstructure.DefaultBlockVersion,
+		},
+		{
+			Name:    "minecraft:bed",
+			States:  map[string]any{"direction": int32(3), "head_piece_bit": byte(0), "occupied_bit": byte(0)},
+			Version: mcstructure.DefaultBlockVersion,
+		},
+		{
+			Name:    "minecraft:bed",
+			States:  map[string]any{"direction": int32(3), "head_piece_bit": byte(1), "occupied_bit": byte(0)},
+			Version: mcstructure.DefaultBlockVersion,
+		},
+		{Name: "minecraft:oak_fence", States: map[string]any{}, Version: mcstructure.DefaultBlockVersion},
+		{Name: "minecraft:water", States: map[string]any{"liquid_depth": int32(0)}, Version: mcstructure.DefaultBlockVersion},
+	}
+	for index := range int32(5) {
+		structure.BlockIndices[mcstructure.LayerPrimary][structure.Index(protocol.BlockPos{index, 0, 0})] = index
+	}
+	structure.BlockIndices[mcstructure.LayerSecondary][structure.Index(protocol.BlockPos{4, 0, 0})] = 5
+	structure.BlockPositionData[structure.Index(protocol.BlockPos{1, 0, 0})] = map[string]any{
+		"block_entity_data": map[string]any{
+			"id":         "Chest",
+			"CustomName": "Structure Chest",
+			"Items": []any{
+				map[string]any{"Name": "minecraft:bread", "Count": byte(5), "Damage": int16(0), "Slot": byte(7)},
+			},
+		},
+	}
+	structure.BlockPositionData[structure.Index(protocol.BlockPos{3, 0, 0})] = map[string]any{
+		"block_entity_data": map[string]any{"id": "Bed", "color": byte(11)},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = mcstructure.Encode(buf, structure)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to encode structure due to %v", err))
+	}
+	decoded, err := mcstructure.Decode(buf)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to decode structure due to %v", err))
+	}
+
+	origin := protocol.BlockPos{70, 89, 90}
+	skipped := make([]mcstructure.SkippedBlock, 0)
+	_, err = mcstructure.NewImporter(api, assigner).Import(decoded, origin, mcstructure.ImportOptions{
+		OnSkip: func(block mcstructure.SkippedBlock) { skipped = append(skipped, block) },
+	})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to import structure due to %v", err))
+	}
+
+	// 含水方块中的水无法被还原，因此它被跳过，而栅栏本身不会被水覆盖
+	if len(skipped) != 1 ||
+		skipped[0].Index != structure.Index(protocol.BlockPos{4, 0, 0}) ||
+		skipped[0].Layer != mcstructure.LayerSecondary ||
+		skipped[0].Block.Name != "minecraft:water" {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected skipped blocks %#v", skipped))
+	}
+
+	for index, want := range []string{"minecraft:stone", "minecraft:chest", "minecraft:bed", "minecraft:bed", "minecraft:oak_fence"} {
+		pos := protocol.BlockPos{origin[0] + int32(index), origin[1], origin[2]}
+		if b := server.Block(pos); b == nil || b.Name != want {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected block %#v at %v", b, pos))
+		}
+	}
+
+	chestNBT := server.BlockNBT(protocol.BlockPos{origin[0] + 1, origin[1], origin[2]})
+	items, _ := chestNBT["Items"].([]any)
+	if chestNBT["CustomName"] != "Structure Chest" || len(items) != 1 {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected chest %#v", chestNBT))
+	}
+	if name, count := itemNameAndCount(items[0]); name != "minecraft:bread" || count != 5 {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected chest item %#v", items[0]))
+	}
+
+	head := server.Block(protocol.BlockPos{origin[0] + 3, origin[1], origin[2]})
+	if head.States["head_piece_bit"] != byte(1) {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected bed head %#v", head))
+	}
+	bedNBT := server.BlockNBT(protocol.BlockPos{origin[0] + 3, origin[1], origin[2]})
+	if bedNBT["color"] != byte(11) {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected bed %#v", bedNBT))
+	}
+
+	// 将导入的区域导出，并检查导出的结构能否被解析为相同的 NBT 方块
+	exported, err := mcstructure.NewExporter(api).Export(
+		origin,
+		protocol.BlockPos{origin[0] + structure.Size[0] - 1, origin[1], origin[2]},
+		mcstructure.ExportOptions{},
+	)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to export structure due to %v", err))
+	}
+	buf.Reset()
+	err = mcstructure.Encode(buf, exported)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to encode exported structure due to %v", err))
+	}
+	exported, err = mcstructure.Decode(buf)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to decode exported structure due to %v", err))
+	}
+
+	nameChecker := api.Resources().ConstantPacket().ItemCanGetByCommand
+	for index := range structure.Volume() {
+		want, _ := structure.Block(mcstructure.LayerPrimary, index)
+		got, exist := exported.Block(mcstructure.LayerPrimary, index)
+		if !exist || got.Name != want.Name || got.Version != mcstructure.DefaultBlockVersion {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected exported block %#v at index %d", got, index))
+		}
+
+		wantNBT, ok := structure.BlockEntityData(index)
+		if !ok {
+			continue
+		}
+		gotNBT, ok := exported.BlockEntityData(index)
+		if !ok {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Block entity data at index %d is not exported", index))
+		}
+		wantBlock, err := nbt_parser_interface.ParseBlock(nameChecker, want.Name, want.States, wantNBT)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Failed to parse block at index %d due to %v", index, err))
+		}
+		gotBlock, err := nbt_parser_interface.ParseBlock(nameChecker, got.Name, got.States, gotNBT)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Failed to parse exported block at index %d due to %v", index, err))
+		}
+		if nbt_hash.NBTBlockFullHash(gotBlock) != nbt_hash.NBTBlockFullHash(wantBlock) {
+			panic(fmt.Sprintf(
+				"SystemTestingMCStructure: Exported block at index %d differs\ngot:\n%s\nwant:\n%s",
+				index, gotBlock.Format(""), wantBlock.Format(""),
+			))
+		}
+	}
+
+	systemTestingMCStructureBeds(assigner)
+
+	pterm.Success.Printfln("SystemTestingMCStructure: PASS (Time used = %v)", time.Since(tA))
+}
+
+// systemTestingMCStructureBeds 导入四个朝向各不相同的床。
+// 朝向为 1 和 2 的床的床头先于床尾被导入，因此床尾
+// 必须被跳过，否则它将覆盖随床头一同放置的床尾。
+//
+// 结构中床尾的 occupied_bit 与随床头放置的床尾不同，
+// 因此错误地放置床尾将替换已放置的床尾，并破坏床头
+func systemTestingMCStructureBeds(assigner *nbt_assigner.NBTAssigner) {
+	// 第 0 和 1 列分别是沿 Z 轴朝向 0 和 2 的床，
+	// 而第 2 和 3 列的两行分别是沿 X 轴朝向 1 和 3 的床
+	heads := []protocol.BlockPos{{0, 0, 1}, {2, 0, 0}, {1, 0, 0}, {3, 0, 1}}
+	footOffsets := []protocol.BlockPos{{0, 0, -1}, {1, 0, 0}, {0, 0, 1}, {-1, 0, 0}}
+
+	structure := mcstructure.NewStructure(protocol.BlockPos{4, 1, 2})
+	for direction := range int32(4) {
+		head := heads[direction]
+		foot := protocol.BlockPos{
+			head[0] + footOffsets[direction][0],
+			head[1] + footOffsets[direction][1],
+			head[2] + footOffsets[direction][2],
+		}
+		for headPieceBit, pos := range []protocol.BlockPos{foot, head} {
+			structure.Palette = append(structure.Palette, mcstructure.PaletteBlock{
+				Name: "minecraft:bed",
+				States: map[string]any{
+					"direction":      direction,
+					"head_piece_bit": byte(headPieceBit),
+					"occupied_bit":   byte(1 - headPieceBit),
+				},
+				Version: mcstructure.DefaultBlockVersion,
+			})
+			index := structure.Index(pos)
+			structure.BlockIndices[mcstructure.LayerPrimary][index] = int32(len(structure.Palette) - 1)
+			structure.BlockPositionData[index] = map[string]any{
+				"block_entity_data": map[string]any{"id": "Bed", "color": byte(direction + 1)},
+			}
+		}
+	}
+
+	origin := protocol.BlockPos{80, 89, 90}
+	_, err := mcstructure.NewImporter(api, assigner).Import(structure, origin, mcstructure.ImportOptions{})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMCStructure: Failed to import beds due to %v", err))
+	}
+
+	for index := range structure.Volume() {
+		want, _ := structure.Block(mcstructure.LayerPrimary, index)
+		relative := structure.Position(index)
+		pos := protocol.BlockPos{origin[0] + relative[0], origin[1] + relative[1], origin[2] + relative[2]}
+
+		b := server.Block(pos)
+		if b == nil || b.Name != want.Name ||
+			b.States["direction"] != want.States["direction"] ||
+			b.States["head_piece_bit"] != want.States["head_piece_bit"] {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected bed %#v at %v", b, pos))
+		}
+		if bedNBT := server.BlockNBT(pos); bedNBT["color"] != byte(want.States["direction"].(int32)+1) {
+			panic(fmt.Sprintf("SystemTestingMCStructure: Unexpected bed %#v at %v", bedNBT, pos))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/metrics"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingMetrics() {
+	tA := time.Now()
+
+	console, err := nbt_console.NewConsole(api, protocol.BlockPos{64, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMetrics: Failed to create console due to %v", err))
+	}
+	assigner := nbt_assigner.NewNBTAssigner(console, nbt_cache.NewNBTCacheSystem(console))
+
+	// 第一次制作时缓存未命中，而第二次制作时缓存命中
+	for range 2 {
+		_, _, _, err = assigner.PlaceNBTBlock(
+			"minecraft:chest",
+			map[string]any{"minecraft:cardinal_direction": "north"},
+			map[string]any{
+				"id":         "Chest",
+				"CustomName": "Metrics Chest",
+				"Items": []any{
+					map[string]any{"Name": "minecraft:apple", "Count": byte(2), "Damage": int16(0), "Slot": byte(0)},
+				},
+			},
+		)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingMetrics: Failed to place NBT block due to %v", err))
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = metrics.DefaultRegistry.WriteText(buf)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingMetrics: Failed to write metrics due to %v", err))
+	}
+
+	series := make(map[string]float64)
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		index := strings.LastIndex(line, " ")
+		if index < 0 {
+			panic(fmt.Sprintf("SystemTestingMetrics: Malformed line %#v", line))
+		}
+		value, err := strconv.ParseFloat(line[index+1:], 64)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingMetrics: Malformed line %#v", line))
+		}
+		series[line[:index]] = value
+	}
+
+	for _, name := range []string{
+		`flowers_for_machines_command_duration_seconds_count{origin="automation_player"}`,
+		`flowers_for_machines_nbt_block_cache_lookups_total{result="miss"}`,
+		`flowers_for_machines_nbt_block_cache_lookups_total{result="hit"}`,
+		`flowers_for_machines_place_nbt_block_duration_seconds_count{block_type="container",result="success"}`,
+		`flowers_for_machines_place_nbt_block_duration_seconds_bucket{block_type="container",result="success",le="+Inf"}`,
+	} {
+		if series[name] <= 0 {
+			panic(fmt.Sprintf("SystemTestingMetrics: Series %s is missing or zero", name))
+		}
+	}
+
+	pterm.Success.Printfln("SystemTestingMetrics: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/client"
+	"github.com/OmineDev/flowers-for-machines/core/py_rpc"
+	"github.com/OmineDev/flowers-for-machines/system_testing/local_server"
+	"github.com/OmineDev/flowers-for-machines/system_testing/mock_auth_server"
+
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingMockAuth() {
+	tA := time.Now()
+
+	challengeServer, err := local_server.NewServer(local_server.Config{MCPCheckChallenge: true})
+	if err != nil {
+		panic(err)
+	}
+	defer challengeServer.Close()
+
+	authServer, err := mock_auth_server.NewServer(mock_auth_server.Config{
+		RentalServerIP:  challengeServer.Addr(),
+		ServerCode:      "123456",
+		ServerPassword:  "654321",
+		BotName:         "MockBot",
+		StartTypeAnswer: "mock start type",
+		CheckNumAnswer:  []any{"mock", float64(1)},
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer authServer.Close()
+
+	// Wrong passcode
+	{
+		_, err := client.LoginRentalServer(client.Config{
+			AuthServerAddress:    authServer.Address(),
+			RentalServerCode:     "123456",
+			RentalServerPasscode: "000000",
+		})
+		if err == nil {
+			panic("SystemTestingMockAuth: Login with wrong passcode should fail")
+		}
+	}
+
+	// Login and challenges
+	{
+		mockClient, err := client.LoginRentalServer(client.Config{
+			AuthServerAddress:    authServer.Address(),
+			RentalServerCode:     "123456",
+			RentalServerPasscode: "654321",
+		})
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingMockAuth: Login failed due to %v", err))
+		}
+		defer mockClient.Conn().Close()
+
+		if name := mockClient.Conn().IdentityData().DisplayName; name != "MockBot" {
+			panic(fmt.Sprintf("SystemTestingMockAuth: Unexpected bot name %s", name))
+		}
+		if len(authServer.StartTypeRequests()) != 1 || len(authServer.CheckNumRequests()) != 1 {
+			panic("SystemTestingMockAuth: Challenges were not forwarded to the auth server")
+		}
+
+		answers := challengeServer.ChallengeAnswers()
+		if len(answers) != 2 {
+			panic(fmt.Sprintf("SystemTestingMockAuth: Unexpected challenge answers %#v", answers))
+		}
+		if startType, ok := answers[0].(*py_rpc.StartType); !ok || startType.Content != "mock start type" {
+			panic(fmt.Sprintf("SystemTestingMockAuth: Unexpected start type answer %#v", answers[0]))
+		}
+		if checkNum, ok := answers[1].(*py_rpc.SetMCPCheckNum); !ok || len(*checkNum) != 2 || (*checkNum)[0] != "mock" {
+			panic(fmt.Sprintf("SystemTestingMockAuth: Unexpected check num answer %#v", answers[1]))
+		}
+	}
+
+	pterm.Success.Printfln("SystemTestingMockAuth: PASS (Time used = %v)", time.Since(tA))
+}
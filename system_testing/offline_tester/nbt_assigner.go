@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingNBTAssigner() {
+	tA := time.Now()
+
+	center := protocol.BlockPos{64, 89, 64}
+	console, err := nbt_console.NewConsole(api, center)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Failed to create console due to %v", err))
+	}
+	assigner := nbt_assigner.NewNBTAssigner(console, nbt_cache.NewNBTCacheSystem(console))
+
+	blockNBT := map[string]any{
+		"id":         "Chest",
+		"CustomName": "Local Chest",
+		"Items": []any{
+			map[string]any{
+				"Name":   "minecraft:apple",
+				"Count":  byte(16),
+				"Damage": int16(0),
+				"Slot":   byte(0),
+			},
+			map[string]any{
+				"Name":   "minecraft:diamond_sword",
+				"Count":  byte(1),
+				"Damage": int16(0),
+				"Slot":   byte(3),
+				"tag": map[string]any{
+					"display": map[string]any{"Name": "Local Sword"},
+				},
+			},
+		},
+	}
+
+	canFast, uniqueID, _, err := assigner.PlaceNBTBlock(
+		"minecraft:chest",
+		map[string]any{"minecraft:cardinal_direction": "north"},
+		blockNBT,
+	)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Failed to place chest due to %v", err))
+	}
+	if canFast {
+		panic("SystemTestingNBTAssigner: A chest with items should not be placed by setblock")
+	}
+
+	err = api.StructureBackup().RevertStructure(uniqueID, protocol.BlockPos{70, 89, 70})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Failed to load placed chest due to %v", err))
+	}
+
+	result := server.BlockNBT(protocol.BlockPos{70, 89, 70})
+	if result == nil {
+		panic("SystemTestingNBTAssigner: Placed chest not found")
+	}
+	if result["CustomName"] != "Local Chest" {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected chest name %#v", result["CustomName"]))
+	}
+
+	items, _ := result["Items"].([]any)
+	if len(items) != 2 {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected chest items %#v", items))
+	}
+	for _, value := range items {
+		item := value.(map[string]any)
+		switch item["Slot"] {
+		case byte(0):
+			if item["Name"] != "minecraft:apple" || item["Count"] != byte(16) {
+				panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected item %#v", item))
+			}
+		case byte(3):
+			tag, _ := item["tag"].(map[string]any)
+			display, _ := tag["display"].(map[string]any)
+			if item["Name"] != "minecraft:diamond_sword" || display["Name"] != "Local Sword" {
+				panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected item %#v", item))
+			}
+		default:
+			panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected item %#v", item))
+		}
+	}
+
+	validateResult, err := assigner.ValidateNBTBlock(
+		"minecraft:chest",
+		map[string]any{"minecraft:cardinal_direction": "north"},
+		map[string]any{
+			"id": "Chest",
+			"Items": []any{
+				map[string]any{
+					"Name":   "minecraft:undyed_shulker_box",
+					"Count":  byte(1),
+					"Damage": int16(0),
+					"Slot":   byte(1),
+					"tag": map[string]any{
+						"Items": []any{
+							map[string]any{
+								"Name":   "minecraft:not_a_real_item",
+								"Count":  byte(1),
+								"Damage": int16(0),
+								"Slot":   byte(2),
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Failed to validate chest due to %v", err))
+	}
+	if !validateResult.NeedSpecialHandle || !validateResult.IsSupported {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected validate result %#v", validateResult))
+	}
+	if len(validateResult.DroppedItems) != 1 ||
+		fmt.Sprint(validateResult.DroppedItems[0].Path) != "[1 2]" ||
+		validateResult.DroppedItems[0].Reason != nbt_assigner.DropReasonCanNotGetByCommand {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected dropped items %#v", validateResult.DroppedItems))
+	}
+
+	var chestEntry *nbt_cache.CacheEntry
+	entries := assigner.ListCache()
+	for index, entry := range entries {
+		if entry.Kind == nbt_cache.CacheKindNBTBlock && entry.UniqueID == uniqueID {
+			chestEntry = &entries[index]
+		}
+	}
+	if chestEntry == nil || chestEntry.BlockName != "minecraft:chest" || chestEntry.CreatedAt.IsZero() {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Placed chest not found in cache entries %#v", entries))
+	}
+
+	evicted, err := assigner.PurgeCache()
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Failed to purge cache due to %v", err))
+	}
+	if evicted != len(entries) || len(assigner.ListCache()) != 0 {
+		panic(fmt.Sprintf("SystemTestingNBTAssigner: Unexpected purge result (evicted = %d, entries = %d)", evicted, len(entries)))
+	}
+	err = api.StructureBackup().RevertStructure(uniqueID, protocol.BlockPos{70, 89, 70})
+	if err == nil {
+		panic("SystemTestingNBTAssigner: Structure of purged cache still exists")
+	}
+
+	pterm.Success.Printfln("SystemTestingNBTAssigner: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/pterm/pterm"
+)
+
+// nbtBlockCase 是 SystemTestingNBTBlocks 中的单个测试用例
+type nbtBlockCase struct {
+	name        string
+	blockName   string
+	blockStates map[string]any
+	blockNBT    map[string]any
+	// check 检查加载到世界中的方块实体数据 result
+	check func(result map[string]any) error
+}
+
+// placeAndLoad 通过 assigner 制作 testCase 所指示的方块，
+// 并将其加载到 pos 处，然后返回 pos 处的方块实体数据
+func placeAndLoad(assigner *nbt_assigner.NBTAssigner, testCase nbtBlockCase, pos protocol.BlockPos) (map[string]any, error) {
+	canFast, uniqueID, offset, err := assigner.PlaceNBTBlock(testCase.blockName, testCase.blockStates, testCase.blockNBT)
+	if err != nil {
+		return nil, fmt.Errorf("placeAndLoad: %v", err)
+	}
+	if canFast {
+		return nil, fmt.Errorf("placeAndLoad: %s should not be placed by setblock", testCase.name)
+	}
+
+	loadPos := pos
+	for axis := range 3 {
+		loadPos[axis] += min(offset[axis], 0)
+	}
+	err = api.StructureBackup().RevertStructure(uniqueID, loadPos)
+	if err != nil {
+		return nil, fmt.Errorf("placeAndLoad: %v", err)
+	}
+
+	b := server.Block(pos)
+	if b == nil || b.Name != testCase.blockName {
+		return nil, fmt.Errorf("placeAndLoad: Unexpected block %#v at %v", b, pos)
+	}
+	for key, value := range testCase.blockStates {
+		if fmt.Sprint(b.States[key]) != fmt.Sprint(value) {
+			return nil, fmt.Errorf("placeAndLoad: Unexpected block states %#v", b.States)
+		}
+	}
+
+	result := server.BlockNBT(pos)
+	if result == nil {
+		return nil, fmt.Errorf("placeAndLoad: Block entity data of %s not found", testCase.name)
+	}
+	return result, nil
+}
+
+// itemNameAndCount 返回物品 value 的名称和数量
+func itemNameAndCount(value any) (name string, count byte) {
+	item, _ := value.(map[string]any)
+	name, _ = item["Name"].(string)
+	count, _ = item["Count"].(byte)
+	return
+}
+
+func SystemTestingNBTBlocks() {
+	tA := time.Now()
+
+	console, err := nbt_console.NewConsole(api, protocol.BlockPos{64, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to create console due to %v", err))
+	}
+	assigner := nbt_assigner.NewNBTAssigner(console, nbt_cache.NewNBTCacheSystem(console))
+
+	testCases := []nbtBlockCase{
+		{
+			name:        "bed",
+			blockName:   "minecraft:bed",
+			blockStates: map[string]any{"direction": int32(1), "head_piece_bit": byte(1), "occupied_bit": byte(0)},
+			blockNBT:    map[string]any{"id": "Bed", "color": byte(14)},
+			check: func(result map[string]any) error {
+				if result["color"] != byte(14) {
+					return fmt.Errorf("unexpected bed color %#v", result["color"])
+				}
+				return nil
+			},
+		},
+		{
+			name:        "standing skull",
+			blockName:   "minecraft:skull",
+			blockStates: map[string]any{"facing_direction": int32(1)},
+			blockNBT:    map[string]any{"id": "Skull", "SkullType": byte(1), "Rotation": float32(45), "MouthMoving": byte(0)},
+			check: func(result map[string]any) error {
+				if result["SkullType"] != byte(1) || result["Rotation"] != float32(45) {
+					return fmt.Errorf("unexpected skull %#v", result)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "wall skull",
+			blockName:   "minecraft:skull",
+			blockStates: map[string]any{"facing_direction": int32(3)},
+			blockNBT:    map[string]any{"id": "Skull", "SkullType": byte(4), "Rotation": float32(0), "MouthMoving": byte(0)},
+			check: func(result map[string]any) error {
+				if result["SkullType"] != byte(4) {
+					return fmt.Errorf("unexpected skull %#v", result)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "flower pot",
+			blockName:   "minecraft:flower_pot",
+			blockStates: map[string]any{"update_bit": byte(0)},
+			blockNBT: map[string]any{
+				"id": "FlowerPot",
+				"PlantBlock": map[string]any{
+					"name":    "minecraft:poppy",
+					"states":  map[string]any{},
+					"version": int32(18153475),
+				},
+			},
+			check: func(result map[string]any) error {
+				plantBlock, _ := result["PlantBlock"].(map[string]any)
+				if plantBlock["name"] != "minecraft:poppy" {
+					return fmt.Errorf("unexpected plant %#v", result["PlantBlock"])
+				}
+				return nil
+			},
+		},
+		{
+			name:        "decorated pot",
+			blockName:   "minecraft:decorated_pot",
+			blockStates: map[string]any{"direction": int32(2)},
+			blockNBT: map[string]any{
+				"id":   "DecoratedPot",
+				"item": map[string]any{"Name": "minecraft:apple", "Count": byte(3), "Damage": int16(0)},
+			},
+			check: func(result map[string]any) error {
+				if name, count := itemNameAndCount(result["item"]); name != "minecraft:apple" || count != 3 {
+					return fmt.Errorf("unexpected pot item %#v", result["item"])
+				}
+				return nil
+			},
+		},
+		{
+			name:        "chiseled bookshelf",
+			blockName:   "minecraft:chiseled_bookshelf",
+			blockStates: map[string]any{"direction": int32(1), "books_stored": int32(0b010001)},
+			blockNBT: map[string]any{
+				"id": "ChiseledBookshelf",
+				"Items": []any{
+					map[string]any{"Name": "minecraft:book", "Count": byte(1), "Damage": int16(0)},
+					map[string]any{"Name": "", "Count": byte(0), "Damage": int16(0)},
+					map[string]any{"Name": "", "Count": byte(0), "Damage": int16(0)},
+					map[string]any{"Name": "", "Count": byte(0), "Damage": int16(0)},
+					map[string]any{"Name": "minecraft:book", "Count": byte(1), "Damage": int16(0)},
+					map[string]any{"Name": "", "Count": byte(0), "Damage": int16(0)},
+				},
+				"LastInteractedSlot": int32(5),
+			},
+			check: func(result map[string]any) error {
+				items, _ := result["Items"].([]any)
+				if len(items) != 6 || result["LastInteractedSlot"] != int32(5) {
+					return fmt.Errorf("unexpected bookshelf %#v", result)
+				}
+				for index, value := range items {
+					name, count := itemNameAndCount(value)
+					wantBook := index == 0 || index == 4
+					if wantBook != (name == "minecraft:book" && count == 1) {
+						return fmt.Errorf("unexpected bookshelf slot %d %#v", index, value)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name:        "mob spawner",
+			blockName:   "minecraft:mob_spawner",
+			blockStates: map[string]any{},
+			blockNBT:    map[string]any{"id": "MobSpawner", "EntityIdentifier": "minecraft:zombie"},
+			check: func(result map[string]any) error {
+				if result["EntityIdentifier"] != "minecraft:zombie" {
+					return fmt.Errorf("unexpected spawner %#v", result)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "crafter",
+			blockName:   "minecraft:crafter",
+			blockStates: map[string]any{"orientation": "north_up", "crafting": byte(0), "triggered_bit": byte(0)},
+			blockNBT: map[string]any{
+				"id":             "Crafter",
+				"disabled_slots": int16(0b100000001),
+				"Items": []any{
+					map[string]any{"Name": "minecraft:stick", "Count": byte(2), "Damage": int16(0), "Slot": byte(4)},
+				},
+			},
+			check: func(result map[string]any) error {
+				if result["disabled_slots"] != int16(0b100000001) {
+					return fmt.Errorf("unexpected disabled slots %#v", result["disabled_slots"])
+				}
+				items, _ := result["Items"].([]any)
+				if len(items) != 1 {
+					return fmt.Errorf("unexpected crafter items %#v", result["Items"])
+				}
+				item, _ := items[0].(map[string]any)
+				if name, count := itemNameAndCount(item); name != "minecraft:stick" || count != 2 || item["Slot"] != byte(4) {
+					return fmt.Errorf("unexpected crafter item %#v", item)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "campfire",
+			blockName:   "minecraft:campfire",
+			blockStates: map[string]any{"extinguished": byte(1), "minecraft:cardinal_direction": "north"},
+			blockNBT: map[string]any{
+				"id":    "Campfire",
+				"Item1": map[string]any{"Name": "minecraft:beef", "Count": byte(1), "Damage": int16(0)},
+				"Item3": map[string]any{"Name": "minecraft:porkchop", "Count": byte(1), "Damage": int16(0)},
+			},
+			check: func(result map[string]any) error {
+				// 营火上的物品总是占用第一个空槽位
+				if name, _ := itemNameAndCount(result["Item1"]); name != "minecraft:beef" {
+					return fmt.Errorf("unexpected campfire slot 1 %#v", result["Item1"])
+				}
+				if name, _ := itemNameAndCount(result["Item2"]); name != "minecraft:porkchop" {
+					return fmt.Errorf("unexpected campfire slot 2 %#v", result["Item2"])
+				}
+				return nil
+			},
+		},
+	}
+
+	for index, testCase := range testCases {
+		pos := protocol.BlockPos{70 + int32(index)*3, 89, 80}
+		result, err := placeAndLoad(assigner, testCase, pos)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to place %s due to %v", testCase.name, err))
+		}
+		if err = testCase.check(result); err != nil {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to check %s due to %v", testCase.name, err))
+		}
+	}
+
+	// 床尾随床头一同被制作，因此床尾不应被单独放置。
+	// 在全部四个朝向上检查床尾位于床头的偏移处
+	for direction, footOffset := range []protocol.BlockPos{{0, 0, -1}, {1, 0, 0}, {0, 0, 1}, {-1, 0, 0}} {
+		color := byte(direction + 1)
+		blocks := make([]nbt_assigner.NBTBlock, 0)
+		for _, headPieceBit := range []byte{1, 0} {
+			blocks = append(blocks, nbt_assigner.NBTBlock{
+				BlockName: "minecraft:bed",
+				BlockStates: map[string]any{
+					"direction":      int32(direction),
+					"head_piece_bit": headPieceBit,
+					"occupied_bit":   byte(0),
+				},
+				BlockNBT: map[string]any{"id": "Bed", "color": color},
+			})
+		}
+
+		validateResult, err := assigner.ValidateNBTBlock(blocks[1].BlockName, blocks[1].BlockStates, blocks[1].BlockNBT)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to validate bed foot due to %v", err))
+		}
+		if !validateResult.PlacedByNeighbor || validateResult.NeedSpecialHandle {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected bed foot validation %#v", validateResult))
+		}
+
+		results := assigner.PlaceNBTBlocks(blocks)
+		head, foot := results[0], results[1]
+		if head.Err != nil || head.CanFast || head.PlacedByNeighbor || head.Offset != footOffset {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected bed head placement %#v in direction %d", head, direction))
+		}
+		if foot.Err != nil || foot.CanFast || !foot.PlacedByNeighbor {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected bed foot placement %#v in direction %d", foot, direction))
+		}
+
+		headPos := protocol.BlockPos{70 + int32(direction)*3, 89, 76}
+		loadPos := headPos
+		for axis := range 3 {
+			loadPos[axis] += min(head.Offset[axis], 0)
+		}
+		err = api.StructureBackup().RevertStructure(head.UniqueID, loadPos)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to load bed due to %v", err))
+		}
+
+		footPos := protocol.BlockPos{headPos[0] + footOffset[0], headPos[1] + footOffset[1], headPos[2] + footOffset[2]}
+		for _, pos := range []protocol.BlockPos{headPos, footPos} {
+			b := server.Block(pos)
+			wantHeadPieceBit := byte(0)
+			if pos == headPos {
+				wantHeadPieceBit = 1
+			}
+			if b == nil || b.Name != "minecraft:bed" ||
+				b.States["head_piece_bit"] != wantHeadPieceBit ||
+				b.States["direction"] != int32(direction) {
+				panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected bed %#v at %v in direction %d", b, pos, direction))
+			}
+			if bedNBT := server.BlockNBT(pos); bedNBT["color"] != color {
+				panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected bed %#v at %v in direction %d", bedNBT, pos, direction))
+			}
+		}
+	}
+
+	// 饰纹陶罐中数量多于 1 的复杂物品将被丢弃，
+	// 因此该饰纹陶罐可以直接通过 setblock 放置
+	stackedPot := map[string]any{
+		"id": "DecoratedPot",
+		"item": map[string]any{
+			"Name":   "minecraft:writable_book",
+			"Count":  byte(2),
+			"Damage": int16(0),
+			"tag": map[string]any{
+				"pages": []any{map[string]any{"text": "Stacked Book"}},
+			},
+		},
+	}
+	validateResult, err := assigner.ValidateNBTBlock("minecraft:decorated_pot", map[string]any{"direction": int32(2)}, stackedPot)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to validate decorated pot due to %v", err))
+	}
+	if len(validateResult.DroppedItems) != 1 ||
+		validateResult.DroppedItems[0].Reason != nbt_assigner.DropReasonCanNotPlaceByInteraction {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected dropped items %#v", validateResult.DroppedItems))
+	}
+	canFast, _, _, err := assigner.PlaceNBTBlock("minecraft:decorated_pot", map[string]any{"direction": int32(2)}, stackedPot)
+	if err != nil || !canFast {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected decorated pot placement (canFast = %v, err = %v)", canFast, err))
+	}
+
+	// 附魔书所储存的附魔无法通过命令还原，因此它将被丢弃
+	validateResult, err = assigner.ValidateNBTBlock(
+		"minecraft:chiseled_bookshelf",
+		map[string]any{"direction": int32(0), "books_stored": int32(0b101)},
+		map[string]any{
+			"id": "ChiseledBookshelf",
+			"Items": []any{
+				map[string]any{"Name": "minecraft:book", "Count": byte(1), "Damage": int16(0)},
+				map[string]any{"Name": "", "Count": byte(0), "Damage": int16(0)},
+				map[string]any{
+					"Name":   "minecraft:enchanted_book",
+					"Count":  byte(1),
+					"Damage": int16(0),
+					"tag": map[string]any{
+						"ench": []any{map[string]any{"id": int16(0), "lvl": int16(4)}},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to validate chiseled bookshelf due to %v", err))
+	}
+	if len(validateResult.DroppedItems) != 1 ||
+		fmt.Sprint(validateResult.DroppedItems[0].Path) != "[2]" ||
+		validateResult.DroppedItems[0].Reason != nbt_assigner.DropReasonEnchantmentsNotReproducible {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected dropped items %#v", validateResult.DroppedItems))
+	}
+	if validateResult.BlockStates["books_stored"] != int32(0b1) {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected bookshelf states %#v", validateResult.BlockStates))
+	}
+
+	// 营火上无法烹饪的物品将被丢弃，
+	// 而其后的物品将被前移到空出的槽位
+	campfireStates := map[string]any{"extinguished": byte(1), "minecraft:cardinal_direction": "north"}
+	campfireNBT := map[string]any{
+		"id":    "Campfire",
+		"Item1": map[string]any{"Name": "minecraft:beef", "Count": byte(1), "Damage": int16(0)},
+		"Item2": map[string]any{"Name": "minecraft:poppy", "Count": byte(1), "Damage": int16(0)},
+		"Item3": map[string]any{"Name": "minecraft:porkchop", "Count": byte(1), "Damage": int16(0)},
+	}
+	validateResult, err = assigner.ValidateNBTBlock("minecraft:campfire", campfireStates, campfireNBT)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to validate campfire due to %v", err))
+	}
+	if len(validateResult.DroppedItems) != 1 ||
+		fmt.Sprint(validateResult.DroppedItems[0].Path) != "[1]" ||
+		validateResult.DroppedItems[0].Reason != nbt_assigner.DropReasonCanNotPlaceByInteraction {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected dropped items %#v", validateResult.DroppedItems))
+	}
+	if len(validateResult.LossyItems) != 1 ||
+		fmt.Sprint(validateResult.LossyItems[0].Path) != "[2]" ||
+		validateResult.LossyItems[0].Reason != nbt_assigner.LossReasonSlotNotPreserved {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected lossy items %#v", validateResult.LossyItems))
+	}
+	// 放置结果报告与校验结果相同的物品
+	results := assigner.PlaceNBTBlocks([]nbt_assigner.NBTBlock{
+		{BlockName: "minecraft:campfire", BlockStates: campfireStates, BlockNBT: campfireNBT},
+	})
+	if results[0].Err != nil ||
+		fmt.Sprint(results[0].DroppedItems) != fmt.Sprint(validateResult.DroppedItems) ||
+		fmt.Sprint(results[0].LossyItems) != fmt.Sprint(validateResult.LossyItems) {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected campfire placement %#v", results[0]))
+	}
+
+	// 刷怪蛋不存在时放置空的刷怪笼，并报告实体类型无法被还原
+	pigSpawner := map[string]any{"id": "MobSpawner", "EntityIdentifier": "minecraft:pig"}
+	validateResult, err = assigner.ValidateNBTBlock("minecraft:mob_spawner", map[string]any{}, pigSpawner)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to validate mob spawner due to %v", err))
+	}
+	if fmt.Sprint(validateResult.DroppedFields) != "[EntityIdentifier]" {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected dropped fields %#v", validateResult.DroppedFields))
+	}
+	results = assigner.PlaceNBTBlocks([]nbt_assigner.NBTBlock{
+		{BlockName: "minecraft:mob_spawner", BlockStates: map[string]any{}, BlockNBT: pigSpawner},
+	})
+	if results[0].Err != nil || !results[0].CanFast || fmt.Sprint(results[0].DroppedFields) != "[EntityIdentifier]" {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected mob spawner placement %#v", results[0]))
+	}
+
+	// 制作时不会提供红石信号，因此被触发的合成器将被报告。
+	// 它与未被触发的合成器被去重，但二者的结果被分别报告
+	crafterNBT := map[string]any{"id": "Crafter", "disabled_slots": int16(1), "Items": []any{}}
+	triggeredStates := map[string]any{"orientation": "north_up", "crafting": byte(0), "triggered_bit": byte(1)}
+	validateResult, err = assigner.ValidateNBTBlock("minecraft:crafter", triggeredStates, crafterNBT)
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Failed to validate crafter due to %v", err))
+	}
+	if fmt.Sprint(validateResult.DroppedFields) != "[triggered_bit]" || validateResult.BlockStates["triggered_bit"] != byte(0) {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected crafter validation %#v", validateResult))
+	}
+	progress := make([][]int, 0)
+	results = assigner.PlaceNBTBlocksWithProgress(
+		[]nbt_assigner.NBTBlock{
+			{BlockName: "minecraft:crafter", BlockStates: triggeredStates, BlockNBT: crafterNBT},
+			{
+				BlockName:   "minecraft:crafter",
+				BlockStates: map[string]any{"orientation": "north_up", "crafting": byte(0), "triggered_bit": byte(0)},
+				BlockNBT:    crafterNBT,
+			},
+		},
+		func(indexes []int, _ nbt_assigner.PlaceNBTBlockResult) {
+			progress = append(progress, indexes)
+		},
+	)
+	if results[0].Err != nil || results[1].Err != nil || results[0].UniqueID != results[1].UniqueID {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected crafter placement %#v", results))
+	}
+	if fmt.Sprint(results[0].DroppedFields) != "[triggered_bit]" || len(results[1].DroppedFields) != 0 {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected crafter dropped fields %#v", results))
+	}
+	if fmt.Sprint(progress) != "[[0] [1]]" {
+		panic(fmt.Sprintf("SystemTestingNBTBlocks: Unexpected crafter progress %v", progress))
+	}
+
+	pterm.Success.Printfln("SystemTestingNBTBlocks: PASS (Time used = %v)", time.Since(tA))
+}
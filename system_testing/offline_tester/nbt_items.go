@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/mapping"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+	nbt_hash "github.com/OmineDev/flowers-for-machines/nbt_parser/hash"
+	nbt_parser_interface "github.com/OmineDev/flowers-for-machines/nbt_parser/interface"
+	"github.com/OmineDev/flowers-for-machines/system_testing/local_server"
+	"github.com/OmineDev/flowers-for-machines/utils"
+
+	"github.com/pterm/pterm"
+)
+
+// chestWithItems 返回装有物品 items 的箱子的方块实体数据。
+// 每个物品依次放置在从 0 开始的槽位
+func chestWithItems(items ...map[string]any) map[string]any {
+	list := make([]any, 0, len(items))
+	for index, item := range items {
+		item["Slot"] = byte(index)
+		list = append(list, item)
+	}
+	return map[string]any{"id": "Chest", "Items": list}
+}
+
+// containerItemHashes 解析容器的方块实体数据 blockNBT 中的全部物品，
+// 并返回每个槽位上的物品的完整哈希
+func containerItemHashes(blockNBT map[string]any) (map[byte]uint64, error) {
+	result := make(map[byte]uint64)
+	list, _ := blockNBT["Items"].([]any)
+	for _, value := range list {
+		itemMap, _ := value.(map[string]any)
+		item, _, err := nbt_parser_interface.ParseItemNormal(nil, itemMap)
+		if err != nil {
+			return nil, fmt.Errorf("containerItemHashes: %v", err)
+		}
+		slot, _ := itemMap["Slot"].(byte)
+		result[slot] = nbt_hash.NBTItemFullHash(item)
+	}
+	return result, nil
+}
+
+// sameItemsCheck 返回一个检查函数，它检查加载到世界中的容器
+// 是否与方块实体数据 blockNBT 在每个槽位上装有相同的物品
+func sameItemsCheck(blockNBT map[string]any) func(result map[string]any) error {
+	return func(result map[string]any) error {
+		want, err := containerItemHashes(blockNBT)
+		if err != nil {
+			return err
+		}
+		got, err := containerItemHashes(result)
+		if err != nil {
+			return err
+		}
+		if len(got) != len(want) {
+			return fmt.Errorf("unexpected items %#v", result["Items"])
+		}
+		for slot, hash := range want {
+			if got[slot] != hash {
+				return fmt.Errorf("unexpected item at slot %d in %#v", slot, result["Items"])
+			}
+		}
+		return nil
+	}
+}
+
+func SystemTestingNBTItems() {
+	tA := time.Now()
+
+	console, err := nbt_console.NewConsole(api, protocol.BlockPos{64, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingNBTItems: Failed to create console due to %v", err))
+	}
+	assigner := nbt_assigner.NewNBTAssigner(console, nbt_cache.NewNBTCacheSystem(console))
+
+	fireworkChest := chestWithItems(
+		map[string]any{
+			"Name":   "minecraft:firework_rocket",
+			"Count":  byte(3),
+			"Damage": int16(0),
+			"tag": map[string]any{
+				"Fireworks": map[string]any{
+					"Explosions": []any{
+						map[string]any{
+							"FireworkColor":   []byte{1, 4},
+							"FireworkFade":    []byte{11},
+							"FireworkFlicker": byte(1),
+							"FireworkTrail":   byte(0),
+							"FireworkType":    mapping.FireworkTypeBurst,
+						},
+					},
+					"Flight": byte(2),
+				},
+			},
+		},
+		map[string]any{
+			"Name":   "minecraft:firework_star",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag": map[string]any{
+				"FireworksItem": map[string]any{
+					"FireworkColor":   []byte{15},
+					"FireworkFade":    []byte{},
+					"FireworkFlicker": byte(0),
+					"FireworkTrail":   byte(1),
+					"FireworkType":    mapping.FireworkTypeStar,
+				},
+			},
+		},
+	)
+
+	// 谜之炖菜无法通过命令获取，因此它将在工作台上合成
+	potionChest := chestWithItems(
+		map[string]any{
+			"Name":   "minecraft:potion",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag":    map[string]any{"Potion": "minecraft:strong_healing"},
+		},
+		map[string]any{
+			"Name":   "minecraft:splash_potion",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag": map[string]any{
+				"CustomPotionEffects": []any{
+					map[string]any{"Id": byte(1), "Amplifier": byte(1), "Duration": int32(1800)},
+				},
+			},
+		},
+		map[string]any{
+			"Name":   "minecraft:suspicious_stew",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag": map[string]any{
+				"Effects": []any{map[string]any{"EffectId": byte(8), "EffectDuration": int32(120)}},
+			},
+		},
+	)
+
+	// 皮革盔甲在炼药锅中染色，纹饰则在锻造台上添加
+	red := utils.EncodeVarRGBA(176, 46, 38, 0xff)
+	coastGold := map[string]any{"Material": "gold", "Pattern": "coast"}
+	armorChest := chestWithItems(
+		map[string]any{
+			"Name":   "minecraft:leather_chestplate",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag":    map[string]any{"customColor": red},
+		},
+		map[string]any{
+			"Name":   "minecraft:iron_chestplate",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag":    map[string]any{"Trim": coastGold},
+		},
+		map[string]any{
+			"Name":   "minecraft:leather_boots",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag":    map[string]any{"customColor": red, "Trim": coastGold},
+		},
+	)
+
+	// 地图只能从事先登记的容器中复制得到
+	const mapUUID int64 = -4294967281
+	mapSource, _ := local_server.NewBlock("minecraft:chest", map[string]any{"minecraft:cardinal_direction": "north"})
+	mapSource.Items[0], _ = server.NewItem("minecraft:filled_map", 1, map[string]any{"map_uuid": mapUUID})
+	mapSourcePos := protocol.BlockPos{70, 89, 90}
+	server.SetBlock(mapSourcePos, mapSource)
+	mapUUIDs, err := assigner.RegisterFilledMaps(mapSourcePos)
+	if err != nil || len(mapUUIDs) != 1 || mapUUIDs[0] != mapUUID {
+		panic(fmt.Sprintf("SystemTestingNBTItems: Failed to register filled maps (mapUUIDs = %v, err = %v)", mapUUIDs, err))
+	}
+	mapChest := chestWithItems(
+		map[string]any{
+			"Name":   "minecraft:filled_map",
+			"Count":  byte(1),
+			"Damage": int16(0),
+			"tag":    map[string]any{"map_uuid": mapUUID},
+		},
+	)
+
+	testCases := []nbtBlockCase{
+		{
+			name:        "fireworks",
+			blockName:   "minecraft:chest",
+			blockStates: map[string]any{"minecraft:cardinal_direction": "north"},
+			blockNBT:    fireworkChest,
+			check:       sameItemsCheck(fireworkChest),
+		},
+		{
+			name:        "potions",
+			blockName:   "minecraft:chest",
+			blockStates: map[string]any{"minecraft:cardinal_direction": "north"},
+			blockNBT:    potionChest,
+			check:       sameItemsCheck(potionChest),
+		},
+		{
+			name:        "armor",
+			blockName:   "minecraft:chest",
+			blockStates: map[string]any{"minecraft:cardinal_direction": "north"},
+			blockNBT:    armorChest,
+			check:       sameItemsCheck(armorChest),
+		},
+		{
+			name:        "filled map",
+			blockName:   "minecraft:chest",
+			blockStates: map[string]any{"minecraft:cardinal_direction": "north"},
+			blockNBT:    mapChest,
+			check:       sameItemsCheck(mapChest),
+		},
+	}
+
+	for index, testCase := range testCases {
+		pos := protocol.BlockPos{70 + int32(index)*3, 89, 86}
+		result, err := placeAndLoad(assigner, testCase, pos)
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingNBTItems: Failed to place %s due to %v", testCase.name, err))
+		}
+		if err = testCase.check(result); err != nil {
+			panic(fmt.Sprintf("SystemTestingNBTItems: Failed to check %s due to %v", testCase.name, err))
+		}
+	}
+
+	pterm.Success.Printfln("SystemTestingNBTItems: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingSetblock() {
+	tA := time.Now()
+
+	// SetBlock & Test round 1
+	{
+		api.Commands().SendSettingsCommand("tp 64 89 64", true)
+		api.Commands().AwaitChangesGeneral()
+
+		api.SetBlock().SetBlock([3]int32{64, 89, 64}, "diamond_ore", "[]")
+		resp, err := api.Commands().SendWSCommandWithResp(
+			`execute as @s at @s positioned 64 ~ ~ positioned ~ 89 ~ positioned ~ ~ 64 run testforblock ~ ~ ~ diamond_ore`,
+		)
+		if err != nil || resp.SuccessCount == 0 {
+			panic("SystemTestingSetblock: Test round 1 failed")
+		}
+	}
+
+	// SetBlockAsync (Test round 2)
+	{
+		api.SetBlock().SetBlockAsync([3]int32{64, 88, 64}, "stone", "[]")
+		api.Commands().AwaitChangesGeneral()
+		if b := server.Block(protocol.BlockPos{64, 88, 64}); b == nil || b.Name != "minecraft:stone" {
+			panic("SystemTestingSetblock: Test round 2 failed")
+		}
+	}
+
+	// Clean up
+	{
+		api.SetBlock().SetBlock([3]int32{64, 89, 64}, "air", "[]")
+		api.SetBlock().SetBlock([3]int32{64, 88, 64}, "air", "[]")
+		if server.Block(protocol.BlockPos{64, 89, 64}) != nil {
+			panic("SystemTestingSetblock: Test round 3 failed")
+		}
+	}
+
+	pterm.Success.Printfln("SystemTestingSetblock: PASS (Time used = %v)", time.Since(tA))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/client"
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+	"github.com/OmineDev/flowers-for-machines/game_control/game_interface"
+	"github.com/OmineDev/flowers-for-machines/game_control/resources_control"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_cache"
+	"github.com/OmineDev/flowers-for-machines/nbt_assigner/nbt_console"
+
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingSharedCache() {
+	tA := time.Now()
+
+	secondClient, err := client.LoginLocalServer(server.Authenticator("LocalBot2"))
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Failed to login the second bot due to %v", err))
+	}
+	defer secondClient.Conn().Close()
+	secondAPI := game_interface.NewGameInterface(resources_control.NewResourcesControl(secondClient))
+
+	firstConsole, err := nbt_console.NewConsole(api, protocol.BlockPos{64, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Failed to create the first console due to %v", err))
+	}
+	secondConsole, err := nbt_console.NewConsole(secondAPI, protocol.BlockPos{96, 89, 64})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Failed to create the second console due to %v", err))
+	}
+
+	cache := nbt_cache.NewNBTCacheSystem(firstConsole)
+	assigners := []*nbt_assigner.NBTAssigner{
+		nbt_assigner.NewNBTAssigner(firstConsole, cache),
+		nbt_assigner.NewNBTAssigner(secondConsole, cache.ShareWith(secondConsole)),
+	}
+
+	blockNBT := map[string]any{
+		"id":         "Chest",
+		"CustomName": "Shared Chest",
+		"Items": []any{
+			map[string]any{
+				"Name":   "minecraft:apple",
+				"Count":  byte(8),
+				"Damage": int16(0),
+				"Slot":   byte(0),
+			},
+		},
+	}
+
+	// Both bots place the same block at the same time
+	uniqueIDs := make([]uuid.UUID, len(assigners))
+	errs := make([]error, len(assigners))
+	waiter := new(sync.WaitGroup)
+	for index, assigner := range assigners {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+			_, uniqueIDs[index], _, errs[index] = assigner.PlaceNBTBlock(
+				"minecraft:chest",
+				map[string]any{"minecraft:cardinal_direction": "north"},
+				blockNBT,
+			)
+		}()
+	}
+	waiter.Wait()
+
+	for index, err := range errs {
+		if err != nil {
+			panic(fmt.Sprintf("SystemTestingSharedCache: Bot %d failed to place chest due to %v", index, err))
+		}
+	}
+	if uniqueIDs[0] != uniqueIDs[1] {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Bots placed the same chest into different structures (%v and %v)", uniqueIDs[0], uniqueIDs[1]))
+	}
+
+	// The cache made by one bot can be used by the other one
+	entries := cache.ListCache()
+	if len(entries) != len(cache.ShareWith(secondConsole).ListCache()) {
+		panic("SystemTestingSharedCache: Shared caches have different entries")
+	}
+	found := 0
+	for _, entry := range entries {
+		if entry.Kind == nbt_cache.CacheKindNBTBlock && entry.BlockName == "minecraft:chest" {
+			found++
+		}
+	}
+	if found != 1 {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Expected exactly one chest in cache, but got %d; entries = %#v", found, entries))
+	}
+
+	err = secondAPI.StructureBackup().RevertStructure(uniqueIDs[0], protocol.BlockPos{100, 89, 70})
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingSharedCache: The second bot failed to load the shared chest due to %v", err))
+	}
+	result := server.BlockNBT(protocol.BlockPos{100, 89, 70})
+	if result == nil || result["CustomName"] != "Shared Chest" {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Unexpected shared chest %#v", result))
+	}
+
+	_, err = assigners[1].PurgeCache()
+	if err != nil {
+		panic(fmt.Sprintf("SystemTestingSharedCache: Failed to purge cache due to %v", err))
+	}
+	if len(cache.ListCache()) != 0 {
+		panic("SystemTestingSharedCache: Purge by one bot did not clear the shared cache")
+	}
+
+	pterm.Success.Printfln("SystemTestingSharedCache: PASS (Time used = %v)", time.Since(tA))
+}
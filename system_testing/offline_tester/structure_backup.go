@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"github.com/OmineDev/flowers-for-machines/core/minecraft/protocol"
+
+	"github.com/pterm/pterm"
+)
+
+func SystemTestingStructrueBackup() {
+	tA := time.Now()
+
+	api.SetBlock().SetBlock([3]int32{64, 89, 64}, "diamond_ore", "[]")
+
+	uniqueID, err := api.StructureBackup().BackupStructure([3]int32{64, 89, 64})
+	if err != nil {
+		panic("SystemTestingStructrueBackup: Failed on stage 1")
+	}
+
+	api.SetBlock().SetBlock([3]int32{64, 89, 64}, "air", "[]")
+	err = api.StructureBackup().RevertStructure(uniqueID, [3]int32{64, 89, 64})
+	if err != nil {
+		panic("SystemTestingStructrueBackup: Failed on stage 2")
+	}
+	if b := server.Block(protocol.BlockPos{64, 89, 64}); b == nil || b.Name != "minecraft:diamond_ore" {
+		panic("SystemTestingStructrueBackup: Failed on stage 3")
+	}
+
+	err = api.StructureBackup().DeleteStructure(uniqueID)
+	if err != nil {
+		panic("SystemTestingStructrueBackup: Failed on stage 4")
+	}
+	api.Commands().AwaitChangesGeneral()
+
+	err = api.StructureBackup().RevertStructure(uniqueID, [3]int32{64, 89, 64})
+	if err == nil {
+		panic("SystemTestingStructrueBackup: Failed on stage 5")
+	}
+
+	api.SetBlock().SetBlock([3]int32{64, 89, 64}, "air", "[]")
+	pterm.Success.Printfln("SystemTestingStructrueBackup: PASS (Time used = %v)", time.Since(tA))
+}